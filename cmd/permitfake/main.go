@@ -0,0 +1,41 @@
+// Command permitfake serves the in-memory Permit API stand-in for local development.
+//
+// Start it, export the printed environment variables and run the server against it:
+//
+//	go run ./cmd/permitfake -addr localhost:7766
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"iam_services_main_v1/internal/permit/permittest"
+	"iam_services_main_v1/pkg/logger"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:7766", "address to listen on")
+	flag.Parse()
+
+	logger.InitLogger()
+
+	fake := permittest.New()
+	fake.URL = "http://" + *addr
+
+	env := fake.Env()
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("%s=%s\n", key, env[key])
+	}
+
+	logger.LogInfo("Permit fake listening", "addr", *addr)
+	if err := http.ListenAndServe(*addr, fake); err != nil {
+		logger.LogFatal("Permit fake stopped", "error", err)
+	}
+}
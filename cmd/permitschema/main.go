@@ -0,0 +1,67 @@
+// Command permitschema brings the Permit environment named by PERMIT_PDP_ENDPOINT,
+// PERMIT_PROJECT, PERMIT_ENV and PERMIT_TOKEN in line with a schema file.
+//
+// Print the plan without changing anything, then apply it:
+//
+//	go run ./cmd/permitschema -file config/permit_schema.json -dry-run
+//	go run ./cmd/permitschema -file config/permit_schema.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permitschema"
+	"iam_services_main_v1/pkg/logger"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	file := flag.String("file", "config/permit_schema.json", "schema file to apply")
+	dryRun := flag.Bool("dry-run", false, "print the plan without applying it")
+	timeout := flag.Duration("timeout", 2*time.Minute, "time allowed for the whole run")
+	flag.Parse()
+
+	logger.InitLogger()
+	// A .env file is optional here; the variables may come from the shell.
+	_ = godotenv.Load()
+
+	baseURL := os.Getenv("PERMIT_PDP_ENDPOINT")
+	projectID := os.Getenv("PERMIT_PROJECT")
+	envID := os.Getenv("PERMIT_ENV")
+	apiKey := os.Getenv("PERMIT_TOKEN")
+	if baseURL == "" || projectID == "" || envID == "" || apiKey == "" {
+		logger.LogFatal("PERMIT_PDP_ENDPOINT, PERMIT_PROJECT, PERMIT_ENV and PERMIT_TOKEN must be set")
+	}
+
+	schema, err := permitschema.Load(*file)
+	if err != nil {
+		logger.LogFatal("Failed to load Permit schema", "error", err)
+	}
+
+	svc := permit.NewPermitServiceImpl(&permit.PermitClient{
+		FactsURL:  fmt.Sprintf("%s/v2/facts/%s/%s", baseURL, projectID, envID),
+		SchemaURL: fmt.Sprintf("%s/v2/schema/%s/%s", baseURL, projectID, envID),
+		Headers: map[string]string{
+			"Authorization": fmt.Sprintf("Bearer %s", apiKey),
+			"Content-Type":  "application/json",
+		},
+		Client: &http.Client{Timeout: 30 * time.Second},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	plan, err := permitschema.Bootstrap(ctx, svc, schema, *dryRun)
+	if plan != nil || err == nil {
+		fmt.Println(plan)
+	}
+	if err != nil {
+		logger.LogFatal("Failed to apply Permit schema", "error", err)
+	}
+}
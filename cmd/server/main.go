@@ -2,19 +2,28 @@ package main
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql"
 	"iam_services_main_v1/gql/generated"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/events"
 	"iam_services_main_v1/internal/healthchecks"
 	"iam_services_main_v1/internal/middlewares"
 	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permitschema"
+	"iam_services_main_v1/internal/tagsearch"
+	"iam_services_main_v1/pkg/auth/jwt"
+	"iam_services_main_v1/pkg/httpclient"
 	"iam_services_main_v1/pkg/logger"
 	"net/http"
 	"os"
 	"os/signal"
 	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -22,6 +31,7 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	permitsdkcfg "github.com/permitio/permit-golang/pkg/config"
 	permitsdk "github.com/permitio/permit-golang/pkg/permit"
 )
@@ -53,28 +63,65 @@ func setupMiddleware(router *gin.Engine) {
 
 // setupRoutes configures all routes for the server
 func setupRoutes(router *gin.Engine) {
-	// Initialize Permit SDK service
-	permitSdkService := initPermitSdkService()
-	if permitSdkService == nil {
-		logger.LogError("Failed to create Permit SDK service")
+	// Initialize the authorizer and the client used to fetch the JWT signing keys
+	authorizer := initAuthorizer()
+	outbound, err := outboundHTTP()
+	if authorizer == nil || err != nil {
+		logger.LogError("Failed to create authorizer or outbound HTTP client", "error", err)
 		router.GET("/error", func(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Server configuration error"})
 		})
 		return
 	}
-	healthHandler := &healthchecks.HealthHandler{}
+	healthHandler := newHealthHandler(authorizer)
 	router.GET("/status", healthHandler.SimpleStatus)
 	router.GET("/health/live", healthHandler.LivenessCheck)
 	router.GET("/health/ready", healthHandler.ReadinessCheck)
+	queries, err := persistedQueriesFromEnv()
+	if err != nil {
+		// Never fall back to running any query when the allowlist is unusable
+		logger.LogError("Failed to load the persisted query allowlist", "error", err)
+		router.GET("/error", func(c *gin.Context) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Server configuration error"})
+		})
+		return
+	}
 	router.Use(middlewares.RequestLogger())
 	router.Use(middlewares.GinContextToContextMiddleware())
-	router.GET("/playground", gin.WrapH(playground.Handler("GraphQL playground", "/graphql")))
-	router.Use(middlewares.AuthMiddleware())
-	router.POST("/graphql", middlewares.GraphQLAuthMiddleware(permitSdkService), graphqlHandler(permitSdkService))
+	if playgroundEnabled, _ := graphqlToolsFromEnv(); playgroundEnabled {
+		router.GET("/playground", gin.WrapH(playground.Handler("GraphQL playground", "/graphql")))
+	}
+	authClient := outbound.Client(10 * time.Second)
+	ws := &middlewares.WebSocketAuth{
+		Authenticator:  func() (*jwt.Authenticator, error) { return middlewares.AuthenticatorFromEnv(authClient) },
+		AllowedOrigins: allowedOriginsFromEnv(),
+	}
+	gqlHandler := graphqlHandler(authorizer, queries, healthHandler, ws)
+	// WebSocket connections authenticate with the token of their
+	// connection_init message, as browsers cannot set headers on them
+	router.GET("/graphql", middlewares.WebSocketUpgrade(), gqlHandler)
+	router.Use(middlewares.AuthMiddlewareWithHTTPClient(authClient))
+	// Every root field is authorized by its @authorize directive
+	router.POST("/graphql", gqlHandler)
 }
 
-// graphqlHandler creates and returns the GraphQL handler
-func graphqlHandler(permitSdkService *permit.PermitSdkService) gin.HandlerFunc {
+// newHealthHandler returns the health handler, which reports the Permit
+// circuit breakers and, when authorizer is the Permit PDP client, the counters
+// of its decision cache.
+func newHealthHandler(authorizer authz.Authorizer) *healthchecks.HealthHandler {
+	apiBreaker, pdpBreaker := permitBreakers()
+	healthHandler := &healthchecks.HealthHandler{Breakers: []*permit.CircuitBreaker{apiBreaker, pdpBreaker}}
+	if service, ok := authorizer.(*permit.PermitSdkService); ok {
+		healthHandler.Decisions = service
+	}
+	return healthHandler
+}
+
+// graphqlHandler creates and returns the GraphQL handler. queries, if not
+// nil, serves persisted queries. health, if not nil, reports the retry
+// counters of the Permit API client the handler uses. ws, if not nil, serves
+// subscriptions over WebSocket connections it authenticates.
+func graphqlHandler(authorizer authz.Authorizer, queries *middlewares.PersistedQueries, health *healthchecks.HealthHandler, ws *middlewares.WebSocketAuth) gin.HandlerFunc {
 	// Create permit service with panic recovery
 	permitclint := NewPermitClient()
 	if permitclint == nil {
@@ -84,23 +131,49 @@ func graphqlHandler(permitSdkService *permit.PermitSdkService) gin.HandlerFunc {
 		}
 	}
 
-	permitService := permit.NewPermitServiceImpl(permitclint)
+	permitAPI := permit.NewPermitServiceImpl(permitclint)
+	if impl, ok := permitAPI.(*permit.PermitServiceImpl); ok && health != nil {
+		health.Retries = impl
+	}
+	permitService := permit.NewCachedPermitService(permitAPI, cachePolicyFromEnv())
+	tagIndex := tagsearch.NewIndex(permitService, tagIndexPolicyFromEnv())
+	permitService = tagsearch.NewIndexingService(permitService, tagIndex)
+	bootstrapPermitSchema(permitService)
 
+	fieldAuthorization := middlewares.FieldAuthorization{Authorizer: authorizer}
 	config := generated.Config{
-		Resolvers: &gql.Resolver{PC: permitService, PSC: permitSdkService},
+		Resolvers:  &gql.Resolver{PC: permitService, Authorizer: authorizer, TagIndex: tagIndex, Events: events.NewBus(events.DefaultBuffer)},
+		Directives: generated.DirectiveRoot{Authorize: fieldAuthorization.Directive},
 	}
 
 	// Create handler using preferred constructor
 	h := handler.New(generated.NewExecutableSchema(config))
 
-	// Enable introspection
-	h.Use(extension.Introspection{})
+	if _, introspection := graphqlToolsFromEnv(); introspection {
+		h.Use(extension.Introspection{})
+	}
+	if queries != nil {
+		h.Use(queries)
+	}
+	// Operations over the limits are rejected before they are authorized
+	h.Use(queryLimitsFromEnv())
+	h.Use(fieldAuthorization)
+	h.Use(middlewares.StaleReads{})
+	h.Use(middlewares.Subscriptions{})
+	h.SetErrorPresenter(errorcatalog.PresentError)
 
 	// Add all supported transports
 	h.AddTransport(transport.Options{})
 	h.AddTransport(transport.GET{})
 	h.AddTransport(transport.POST{})
 	h.AddTransport(transport.MultipartForm{})
+	if ws != nil {
+		h.AddTransport(transport.Websocket{
+			KeepAlivePingInterval: 10 * time.Second,
+			Upgrader:              websocket.Upgrader{CheckOrigin: ws.CheckOrigin},
+			InitFunc:              ws.Init,
+		})
+	}
 
 	// Wrap with recovery to prevent application crashes
 	return func(c *gin.Context) {
@@ -201,19 +274,455 @@ func NewPermitClient() *permit.PermitClient {
 		return nil
 	}
 
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true, // Disable certificate verification
-		},
+	outbound, err := outboundHTTP()
+	if err != nil {
+		logger.LogError("Failed to configure outbound TLS", "error", err)
+		return nil
 	}
 
+	apiBreaker, _ := permitBreakers()
 	return &permit.PermitClient{
-		BaseURL: fmt.Sprintf("%s/v2/facts/%s/%s", baseURL, projectID, envID),
+		FactsURL:  fmt.Sprintf("%s/v2/facts/%s/%s", baseURL, projectID, envID),
+		SchemaURL: fmt.Sprintf("%s/v2/schema/%s/%s", baseURL, projectID, envID),
 		Headers: map[string]string{
 			"Authorization": fmt.Sprintf("Bearer %s", apiKey),
 			"Content-Type":  "application/json",
 		},
-		Client: &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		Client:    outbound.Client(30 * time.Second),
+		Retry:     retryPolicyFromEnv(),
+		Paging:    pagingPolicyFromEnv(),
+		RateLimit: rateLimitPolicyFromEnv(),
+		Breaker:   apiBreaker,
+	}
+}
+
+// permitSchemaFromEnv returns the schema file named by PERMIT_SCHEMA_FILE and
+// whether PERMIT_SCHEMA_DRY_RUN asks to only log the plan for it.
+func permitSchemaFromEnv() (path string, dryRun bool) {
+	path = os.Getenv("PERMIT_SCHEMA_FILE")
+	if v := os.Getenv("PERMIT_SCHEMA_DRY_RUN"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			logger.LogError("Invalid PERMIT_SCHEMA_DRY_RUN, using default", "value", v, "error", err)
+		}
+		dryRun = b
+	}
+	return path, dryRun
+}
+
+// bootstrapPermitSchema brings Permit in line with the schema file named by
+// PERMIT_SCHEMA_FILE, if any. Failures are logged; the server still starts and
+// the bootstrap can be retried with cmd/permitschema.
+func bootstrapPermitSchema(svc permit.PermitService) {
+	path, dryRun := permitSchemaFromEnv()
+	if path == "" {
+		return
+	}
+	schema, err := permitschema.Load(path)
+	if err != nil {
+		logger.LogError("Failed to load Permit schema", "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	plan, err := permitschema.Bootstrap(ctx, svc, schema, dryRun)
+	if err != nil {
+		logger.LogError("Failed to bootstrap Permit schema", "path", path, "error", err)
+		return
+	}
+	logger.LogInfo("Permit schema bootstrapped", "path", path, "dryRun", dryRun, "plan", plan.String())
+}
+
+var (
+	breakersOnce     sync.Once
+	permitAPIBreaker *permit.CircuitBreaker
+	permitPDPBreaker *permit.CircuitBreaker
+)
+
+// permitBreakers returns the circuit breakers in front of the Permit API and
+// the PDP. They are created once so that every client shares them and the
+// readiness check reports their state.
+func permitBreakers() (api, pdp *permit.CircuitBreaker) {
+	breakersOnce.Do(func() {
+		policy := breakerPolicyFromEnv()
+		permitAPIBreaker = permit.NewCircuitBreaker("permit-api", policy)
+		permitPDPBreaker = permit.NewCircuitBreaker("permit-pdp", policy)
+	})
+	return permitAPIBreaker, permitPDPBreaker
+}
+
+// breakerPolicyFromEnv builds the Permit circuit breaker policy, overriding the
+// defaults with PERMIT_BREAKER_FAILURE_THRESHOLD and PERMIT_BREAKER_OPEN_TIMEOUT
+// when they are set. A threshold of 0 disables the breakers.
+func breakerPolicyFromEnv() permit.BreakerPolicy {
+	policy := permit.DefaultBreakerPolicy()
+
+	if v := os.Getenv("PERMIT_BREAKER_FAILURE_THRESHOLD"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			policy.FailureThreshold = n
+		} else {
+			logger.LogError("Invalid PERMIT_BREAKER_FAILURE_THRESHOLD, using default", "value", v, "error", err)
+		}
+	}
+	if v := os.Getenv("PERMIT_BREAKER_OPEN_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			policy.OpenTimeout = d
+		} else {
+			logger.LogError("Invalid PERMIT_BREAKER_OPEN_TIMEOUT, using default", "value", v, "error", err)
+		}
+	}
+
+	return policy
+}
+
+var (
+	outboundMu      sync.Mutex
+	outbound        *httpclient.Factory
+	outboundOptions httpclient.Options
+)
+
+// outboundHTTP returns the factory for the HTTP clients used to reach Permit and
+// the identity provider. One factory is shared for as long as the TLS settings
+// in the environment stay the same.
+func outboundHTTP() (*httpclient.Factory, error) {
+	options, err := outboundTLSOptionsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	outboundMu.Lock()
+	defer outboundMu.Unlock()
+	if outbound != nil && options == outboundOptions {
+		return outbound, nil
+	}
+	factory, err := httpclient.NewFactory(options)
+	if err != nil {
+		return nil, err
+	}
+	outbound, outboundOptions = factory, options
+	return factory, nil
+}
+
+// outboundTLSOptionsFromEnv reads the outbound TLS settings from
+// OUTBOUND_TLS_CA_FILE, OUTBOUND_TLS_CERT_FILE, OUTBOUND_TLS_KEY_FILE,
+// OUTBOUND_TLS_MIN_VERSION ("1.2" or "1.3") and OUTBOUND_TLS_RELOAD_INTERVAL.
+func outboundTLSOptionsFromEnv() (httpclient.Options, error) {
+	options := httpclient.Options{
+		CAFile:         os.Getenv("OUTBOUND_TLS_CA_FILE"),
+		CertFile:       os.Getenv("OUTBOUND_TLS_CERT_FILE"),
+		KeyFile:        os.Getenv("OUTBOUND_TLS_KEY_FILE"),
+		ReloadInterval: httpclient.DefaultReloadInterval,
+	}
+
+	version, err := httpclient.ParseTLSVersion(os.Getenv("OUTBOUND_TLS_MIN_VERSION"))
+	if err != nil {
+		return httpclient.Options{}, err
+	}
+	options.MinVersion = version
+
+	if v := os.Getenv("OUTBOUND_TLS_RELOAD_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			logger.LogError("Invalid OUTBOUND_TLS_RELOAD_INTERVAL, using default", "value", v, "error", err)
+		} else {
+			options.ReloadInterval = d
+		}
+	}
+
+	return options, nil
+}
+
+// cachePolicyFromEnv builds the Permit cache policy, overriding the defaults with
+// PERMIT_CACHE_TTL, PERMIT_CACHE_SCHEMA_TTL and PERMIT_CACHE_MAX_ENTRIES when they
+// are set. A PERMIT_CACHE_TTL of 0 disables the cache.
+func cachePolicyFromEnv() permit.CachePolicy {
+	policy := permit.DefaultCachePolicy()
+
+	durations := map[string]*time.Duration{
+		"PERMIT_CACHE_TTL":        &policy.TTL,
+		"PERMIT_CACHE_SCHEMA_TTL": &policy.SchemaTTL,
+	}
+	for key, field := range durations {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			logger.LogError("Invalid cache duration, using default", "key", key, "value", v, "error", err)
+			continue
+		}
+		*field = d
+	}
+	if v := os.Getenv("PERMIT_CACHE_MAX_ENTRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			policy.MaxEntries = n
+		} else {
+			logger.LogError("Invalid PERMIT_CACHE_MAX_ENTRIES, using default", "value", v, "error", err)
+		}
+	}
+
+	return policy
+}
+
+// tagIndexPolicyFromEnv builds the tag index policy, overriding the default TTL
+// with TAG_INDEX_TTL when it is set.
+func tagIndexPolicyFromEnv() tagsearch.Policy {
+	policy := tagsearch.DefaultPolicy()
+	if v := os.Getenv("TAG_INDEX_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			policy.TTL = d
+		} else {
+			logger.LogError("Invalid TAG_INDEX_TTL, using default", "value", v, "error", err)
+		}
+	}
+	return policy
+}
+
+// allowedOriginsFromEnv returns the origins allowed to open WebSocket
+// connections, from the comma-separated WEBSOCKET_ALLOWED_ORIGINS. Browsers
+// of any other origin cannot subscribe.
+func allowedOriginsFromEnv() []string {
+	var origins []string
+	for _, origin := range strings.Split(os.Getenv("WEBSOCKET_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// graphqlToolsFromEnv reports whether the GraphQL playground and schema
+// introspection are enabled. Both are off, so that a deployment that does not
+// configure them does not expose them, unless GRAPHQL_PLAYGROUND or
+// GRAPHQL_INTROSPECTION is set to true.
+func graphqlToolsFromEnv() (playground, introspection bool) {
+	bools := map[string]*bool{
+		"GRAPHQL_PLAYGROUND":    &playground,
+		"GRAPHQL_INTROSPECTION": &introspection,
+	}
+	for key, field := range bools {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			logger.LogError("Invalid GraphQL tool setting, using default", "key", key, "value", v, "error", err)
+			continue
+		}
+		*field = b
+	}
+	return playground, introspection
+}
+
+// persistedQueriesFromEnv builds the persisted queries of the GraphQL handler.
+// When GRAPHQL_ALLOWLIST_FILE is set only the queries of that allowlist run;
+// otherwise automatic persisted queries are cached in an LRU of
+// GRAPHQL_APQ_CACHE_SIZE entries.
+func persistedQueriesFromEnv() (*middlewares.PersistedQueries, error) {
+	if path := os.Getenv("GRAPHQL_ALLOWLIST_FILE"); path != "" {
+		allowlist, err := middlewares.LoadAllowlist(path)
+		if err != nil {
+			return nil, err
+		}
+		logger.LogInfo("Only running allowlisted GraphQL queries", "path", path, "queries", len(allowlist))
+		return middlewares.NewPersistedQueries(0, allowlist), nil
+	}
+
+	size := middlewares.DefaultPersistedQueryCacheSize
+	if v := os.Getenv("GRAPHQL_APQ_CACHE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			size = n
+		} else {
+			logger.LogError("Invalid GRAPHQL_APQ_CACHE_SIZE, using default", "value", v, "error", err)
+		}
+	}
+	return middlewares.NewPersistedQueries(size, nil), nil
+}
+
+// queryLimitsFromEnv builds the GraphQL query limits, overriding the defaults
+// with GRAPHQL_MAX_DEPTH and GRAPHQL_MAX_COMPLEXITY when they are set. A limit
+// of 0 disables it.
+func queryLimitsFromEnv() middlewares.QueryLimits {
+	limits := middlewares.DefaultQueryLimits()
+
+	ints := map[string]*int{
+		"GRAPHQL_MAX_DEPTH":      &limits.MaxDepth,
+		"GRAPHQL_MAX_COMPLEXITY": &limits.MaxComplexity,
+	}
+	for key, field := range ints {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			logger.LogError("Invalid query limit, using default", "key", key, "value", v, "error", err)
+			continue
+		}
+		*field = n
+	}
+	return limits
+}
+
+// decisionCachePolicyFromEnv builds the PDP decision cache policy, overriding the
+// defaults with PERMIT_DECISION_ALLOW_TTL, PERMIT_DECISION_DENY_TTL and
+// PERMIT_DECISION_CACHE_MAX_ENTRIES when they are set. A TTL of 0 disables
+// caching of that kind of decision.
+func decisionCachePolicyFromEnv() permit.DecisionCachePolicy {
+	policy := permit.DefaultDecisionCachePolicy()
+
+	durations := map[string]*time.Duration{
+		"PERMIT_DECISION_ALLOW_TTL": &policy.AllowTTL,
+		"PERMIT_DECISION_DENY_TTL":  &policy.DenyTTL,
+	}
+	for key, field := range durations {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			logger.LogError("Invalid decision cache duration, using default", "key", key, "value", v, "error", err)
+			continue
+		}
+		*field = d
+	}
+	if v := os.Getenv("PERMIT_DECISION_CACHE_MAX_ENTRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			policy.MaxEntries = n
+		} else {
+			logger.LogError("Invalid PERMIT_DECISION_CACHE_MAX_ENTRIES, using default", "value", v, "error", err)
+		}
+	}
+
+	return policy
+}
+
+// pagingPolicyFromEnv builds the Permit paging policy, overriding the defaults with
+// PERMIT_PAGE_SIZE and PERMIT_MAX_LIST_ITEMS when they are set.
+func pagingPolicyFromEnv() *permit.PagingPolicy {
+	policy := permit.DefaultPagingPolicy()
+
+	ints := map[string]*int{
+		"PERMIT_PAGE_SIZE":      &policy.PerPage,
+		"PERMIT_MAX_LIST_ITEMS": &policy.MaxItems,
+	}
+	for key, field := range ints {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			logger.LogError("Invalid paging setting, using default", "key", key, "value", v, "error", err)
+			continue
+		}
+		*field = n
+	}
+
+	return &policy
+}
+
+// retryPolicyFromEnv builds the Permit retry policy, overriding the defaults with
+// PERMIT_RETRY_MAX_ATTEMPTS, PERMIT_RETRY_INITIAL_INTERVAL, PERMIT_RETRY_MAX_INTERVAL
+// and PERMIT_RETRY_MAX_ELAPSED_TIME when they are set.
+func retryPolicyFromEnv() *permit.RetryPolicy {
+	policy := permit.DefaultRetryPolicy()
+
+	if v := os.Getenv("PERMIT_RETRY_MAX_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			policy.MaxAttempts = n
+		} else {
+			logger.LogError("Invalid PERMIT_RETRY_MAX_ATTEMPTS, using default", "value", v, "error", err)
+		}
+	}
+	durations := map[string]*time.Duration{
+		"PERMIT_RETRY_INITIAL_INTERVAL": &policy.InitialInterval,
+		"PERMIT_RETRY_MAX_INTERVAL":     &policy.MaxInterval,
+		"PERMIT_RETRY_MAX_ELAPSED_TIME": &policy.MaxElapsedTime,
+	}
+	for key, field := range durations {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			logger.LogError("Invalid retry duration, using default", "key", key, "value", v, "error", err)
+			continue
+		}
+		*field = d
+	}
+
+	return &policy
+}
+
+// rateLimitPolicyFromEnv builds the Permit client-side rate limit policy,
+// overriding the defaults with PERMIT_RATE_LIMIT and PERMIT_RATE_BURST for all
+// requests and PERMIT_RATE_LIMIT_<CLASS> and PERMIT_RATE_BURST_<CLASS> for the
+// FACTS_READ, FACTS_WRITE and SCHEMA endpoint classes. Limits are in requests
+// per second; a limit of 0 disables it.
+func rateLimitPolicyFromEnv() *permit.RateLimitPolicy {
+	policy := permit.DefaultRateLimitPolicy()
+
+	limits := map[string]*permit.RateLimit{
+		"":             &policy.Global,
+		"_FACTS_READ":  &policy.FactsRead,
+		"_FACTS_WRITE": &policy.FactsWrite,
+		"_SCHEMA":      &policy.Schema,
+	}
+	for suffix, limit := range limits {
+		if v := os.Getenv("PERMIT_RATE_LIMIT" + suffix); v != "" {
+			if rate, err := strconv.ParseFloat(v, 64); err == nil {
+				limit.Rate = rate
+			} else {
+				logger.LogError("Invalid rate limit, using default", "key", "PERMIT_RATE_LIMIT"+suffix, "value", v, "error", err)
+			}
+		}
+		if v := os.Getenv("PERMIT_RATE_BURST" + suffix); v != "" {
+			if burst, err := strconv.Atoi(v); err == nil {
+				limit.Burst = burst
+			} else {
+				logger.LogError("Invalid rate burst, using default", "key", "PERMIT_RATE_BURST"+suffix, "value", v, "error", err)
+			}
+		}
+	}
+
+	return &policy
+}
+
+// initAuthorizer builds the authorizer selected by AUTHORIZER: "permit" (the
+// default) asks the Permit.io PDP, "allow-all" and "deny-all" give a fixed
+// decision, and "policy" applies the rules in AUTHORIZER_POLICY_FILE. It
+// returns nil when the authorizer cannot be built.
+func initAuthorizer() authz.Authorizer {
+	switch mode := os.Getenv("AUTHORIZER"); mode {
+	case "", "permit":
+		// Avoid returning a typed nil when the Permit service cannot be built.
+		if service := initPermitSdkService(); service != nil {
+			return service
+		}
+		return nil
+	case "allow-all":
+		logger.LogWarn("Authorization is disabled, every request is allowed")
+		return authz.AllowAll()
+	case "deny-all":
+		return authz.DenyAll()
+	case "policy":
+		path := os.Getenv("AUTHORIZER_POLICY_FILE")
+		policy, err := authz.LoadPolicy(path)
+		if err != nil {
+			logger.LogError("Failed to load authorization policy", "path", path, "error", err)
+			return nil
+		}
+		logger.LogInfo("Authorization policy loaded", "path", path, "rules", len(policy.Rules))
+		return policy
+	default:
+		logger.LogError("Unknown AUTHORIZER", "value", mode)
+		return nil
 	}
 }
 
@@ -225,25 +734,26 @@ func initPermitSdkService() *permit.PermitSdkService {
 		logger.LogFatal("One or more required environment variables are not set")
 		return nil
 	}
-	// Create a custom HTTP client that skips TLS verification
-	customHttpClient := &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // Disable TLS cert verification
-		},
+	outbound, err := outboundHTTP()
+	if err != nil {
+		logger.LogError("Failed to configure outbound TLS", "error", err)
+		return nil
 	}
 
 	// Configure Permit client
 	permitConfig := permitsdkcfg.NewConfigBuilder(apiKey).
 		WithPdpUrl(pdpUrl).
-		WithHTTPClient(customHttpClient). // set the custom client
+		WithHTTPClient(outbound.Client(10 * time.Second)).
 		Build()
 
 	// Create permit client instance
 	permitClient := permitsdk.New(permitConfig)
 
 	// Create permit service
-	service := permit.NewPermitSdkService(permitClient)
+	_, pdpBreaker := permitBreakers()
+	service := permit.NewPermitSdkService(permitClient).
+		WithDecisionCache(decisionCachePolicyFromEnv()).
+		WithCircuitBreaker(pdpBreaker)
 	logger.LogInfo("Permit.io service initialized")
 
 	return service
@@ -2,22 +2,41 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/generated"
+	"iam_services_main_v1/internal/authz"
 	"iam_services_main_v1/internal/healthchecks"
+	"iam_services_main_v1/internal/middlewares"
 	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permit/permittest"
+	"iam_services_main_v1/internal/permitschema"
+	"iam_services_main_v1/internal/tagsearch"
+	"iam_services_main_v1/internal/utils"
+	"iam_services_main_v1/pkg/auth/jwt"
+	"iam_services_main_v1/pkg/auth/jwt/jwttest"
+	"iam_services_main_v1/pkg/httpclient"
 	"iam_services_main_v1/pkg/logger"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 func initGraphQLServer() *handler.Server {
@@ -340,7 +359,7 @@ func TestGraphQLHandler(t *testing.T) {
 	mockPermitSdkService := &permit.PermitSdkService{}
 
 	// Test the handler
-	handlerFunc := graphqlHandler(mockPermitSdkService)
+	handlerFunc := graphqlHandler(mockPermitSdkService, nil, nil, nil)
 	assert.NotNil(t, handlerFunc)
 
 	// Create a test context
@@ -383,7 +402,7 @@ func TestGraphQLHandlerMissingEnvVars(t *testing.T) {
 	mockPermitSdkService := &permit.PermitSdkService{}
 
 	// Test the handler with missing environment variables
-	handlerFunc := graphqlHandler(mockPermitSdkService)
+	handlerFunc := graphqlHandler(mockPermitSdkService, nil, nil, nil)
 	assert.NotNil(t, handlerFunc)
 
 	// Create a test context
@@ -569,11 +588,16 @@ func TestNewPermitClient(t *testing.T) {
 				assert.NotNil(t, client, tc.description)
 				// Verify client configuration
 				if client != nil {
-					expectedBaseURL := fmt.Sprintf("%s/v2/facts/%s/%s",
+					expectedFactsURL := fmt.Sprintf("%s/v2/facts/%s/%s",
 						tc.envVars["PERMIT_PDP_ENDPOINT"],
 						tc.envVars["PERMIT_PROJECT"],
 						tc.envVars["PERMIT_ENV"])
-					assert.Equal(t, expectedBaseURL, client.BaseURL)
+					expectedSchemaURL := fmt.Sprintf("%s/v2/schema/%s/%s",
+						tc.envVars["PERMIT_PDP_ENDPOINT"],
+						tc.envVars["PERMIT_PROJECT"],
+						tc.envVars["PERMIT_ENV"])
+					assert.Equal(t, expectedFactsURL, client.FactsURL)
+					assert.Equal(t, expectedSchemaURL, client.SchemaURL)
 					assert.Equal(t, fmt.Sprintf("Bearer %s", tc.envVars["PERMIT_TOKEN"]), client.Headers["Authorization"])
 					assert.NotNil(t, client.Client)
 					assert.NotNil(t, client.Client.Transport)
@@ -656,3 +680,968 @@ func TestInitPermitSdkService(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryPolicyFromEnv(t *testing.T) {
+	keys := []string{
+		"PERMIT_RETRY_MAX_ATTEMPTS",
+		"PERMIT_RETRY_INITIAL_INTERVAL",
+		"PERMIT_RETRY_MAX_INTERVAL",
+		"PERMIT_RETRY_MAX_ELAPSED_TIME",
+	}
+	for _, key := range keys {
+		t.Setenv(key, "")
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		assert.Equal(t, permit.DefaultRetryPolicy(), *retryPolicyFromEnv())
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("PERMIT_RETRY_MAX_ATTEMPTS", "6")
+		t.Setenv("PERMIT_RETRY_INITIAL_INTERVAL", "50ms")
+		t.Setenv("PERMIT_RETRY_MAX_INTERVAL", "2s")
+		t.Setenv("PERMIT_RETRY_MAX_ELAPSED_TIME", "10s")
+
+		policy := retryPolicyFromEnv()
+		assert.Equal(t, 6, policy.MaxAttempts)
+		assert.Equal(t, 50*time.Millisecond, policy.InitialInterval)
+		assert.Equal(t, 2*time.Second, policy.MaxInterval)
+		assert.Equal(t, 10*time.Second, policy.MaxElapsedTime)
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_RETRY_MAX_ATTEMPTS", "many")
+		t.Setenv("PERMIT_RETRY_MAX_INTERVAL", "soon")
+
+		policy := retryPolicyFromEnv()
+		assert.Equal(t, permit.DefaultRetryPolicy().MaxAttempts, policy.MaxAttempts)
+		assert.Equal(t, permit.DefaultRetryPolicy().MaxInterval, policy.MaxInterval)
+	})
+}
+
+func TestRateLimitPolicyFromEnv(t *testing.T) {
+	for _, class := range []string{"", "_FACTS_READ", "_FACTS_WRITE", "_SCHEMA"} {
+		t.Setenv("PERMIT_RATE_LIMIT"+class, "")
+		t.Setenv("PERMIT_RATE_BURST"+class, "")
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		assert.Equal(t, permit.DefaultRateLimitPolicy(), *rateLimitPolicyFromEnv())
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("PERMIT_RATE_LIMIT", "0")
+		t.Setenv("PERMIT_RATE_LIMIT_FACTS_READ", "12.5")
+		t.Setenv("PERMIT_RATE_BURST_FACTS_READ", "40")
+		t.Setenv("PERMIT_RATE_LIMIT_SCHEMA", "2")
+
+		policy := rateLimitPolicyFromEnv()
+		assert.Equal(t, 0.0, policy.Global.Rate)
+		assert.Equal(t, permit.RateLimit{Rate: 12.5, Burst: 40}, policy.FactsRead)
+		assert.Equal(t, permit.RateLimit{}, policy.FactsWrite)
+		assert.Equal(t, permit.RateLimit{Rate: 2}, policy.Schema)
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_RATE_LIMIT", "fast")
+		t.Setenv("PERMIT_RATE_BURST", "1.5")
+
+		assert.Equal(t, permit.DefaultRateLimitPolicy(), *rateLimitPolicyFromEnv())
+	})
+}
+
+func TestBreakerPolicyFromEnv(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_BREAKER_FAILURE_THRESHOLD", "")
+		t.Setenv("PERMIT_BREAKER_OPEN_TIMEOUT", "")
+		assert.Equal(t, permit.DefaultBreakerPolicy(), breakerPolicyFromEnv())
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("PERMIT_BREAKER_FAILURE_THRESHOLD", "3")
+		t.Setenv("PERMIT_BREAKER_OPEN_TIMEOUT", "10s")
+
+		policy := breakerPolicyFromEnv()
+		assert.Equal(t, 3, policy.FailureThreshold)
+		assert.Equal(t, 10*time.Second, policy.OpenTimeout)
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_BREAKER_FAILURE_THRESHOLD", "few")
+		t.Setenv("PERMIT_BREAKER_OPEN_TIMEOUT", "later")
+
+		assert.Equal(t, permit.DefaultBreakerPolicy(), breakerPolicyFromEnv())
+	})
+}
+
+func TestPermitSchemaFromEnv(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_SCHEMA_FILE", "")
+		t.Setenv("PERMIT_SCHEMA_DRY_RUN", "")
+
+		path, dryRun := permitSchemaFromEnv()
+		assert.Empty(t, path)
+		assert.False(t, dryRun)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("PERMIT_SCHEMA_FILE", "config/permit_schema.json")
+		t.Setenv("PERMIT_SCHEMA_DRY_RUN", "true")
+
+		path, dryRun := permitSchemaFromEnv()
+		assert.Equal(t, "config/permit_schema.json", path)
+		assert.True(t, dryRun)
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_SCHEMA_FILE", "")
+		t.Setenv("PERMIT_SCHEMA_DRY_RUN", "maybe")
+
+		_, dryRun := permitSchemaFromEnv()
+		assert.False(t, dryRun)
+	})
+}
+
+func TestBootstrapPermitSchema(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	svc := permit.NewPermitServiceImpl(fake.Client())
+	t.Setenv("PERMIT_SCHEMA_FILE", "../../config/permit_schema.json")
+
+	t.Run("Dry run", func(t *testing.T) {
+		t.Setenv("PERMIT_SCHEMA_DRY_RUN", "true")
+		bootstrapPermitSchema(svc)
+		_, exists := fake.Resource(config.TenantResourceTypeID)
+		assert.False(t, exists)
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		t.Setenv("PERMIT_SCHEMA_DRY_RUN", "")
+		bootstrapPermitSchema(svc)
+		tenant, exists := fake.Resource(config.TenantResourceTypeID)
+		assert.True(t, exists)
+		assert.Contains(t, tenant.Actions, "createtenant")
+		assert.Len(t, fake.Relations(config.TenantResourceTypeID), 2)
+	})
+}
+
+func TestPagingPolicyFromEnv(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_PAGE_SIZE", "")
+		t.Setenv("PERMIT_MAX_LIST_ITEMS", "")
+		assert.Equal(t, permit.DefaultPagingPolicy(), *pagingPolicyFromEnv())
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("PERMIT_PAGE_SIZE", "50")
+		t.Setenv("PERMIT_MAX_LIST_ITEMS", "500")
+
+		policy := pagingPolicyFromEnv()
+		assert.Equal(t, 50, policy.PerPage)
+		assert.Equal(t, 500, policy.MaxItems)
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_PAGE_SIZE", "big")
+		t.Setenv("PERMIT_MAX_LIST_ITEMS", "")
+
+		assert.Equal(t, permit.DefaultPagingPolicy(), *pagingPolicyFromEnv())
+	})
+}
+
+func TestGraphQLHandlerAgainstPermitFake(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	for key, value := range fake.Env() {
+		t.Setenv(key, value)
+	}
+
+	tenantID := uuid.New()
+	fake.AddTenant(permit.Tenant{
+		Entity: permit.Entity{
+			Key: tenantID.String(),
+			Attributes: map[string]interface{}{
+				"name":        "Fake Tenant",
+				"contactInfo": map[string]interface{}{"email": "owner@example.com"},
+			},
+		},
+		Name: "Fake Tenant",
+	})
+	fake.AddResource(permit.Resource{
+		Key:   "tenant",
+		Roles: map[string]permit.Role{"viewer": {Key: "viewer", Permissions: []string{"read"}}},
+	})
+	fake.AddRoleAssignment(permit.RoleAssignment{User: "alice", Role: "viewer", Tenant: tenantID.String()})
+	userID := uuid.New()
+	fake.AddResource(permit.Resource{
+		Key:   config.TenantResourceTypeID,
+		Roles: map[string]permit.Role{"lister": {Key: "lister", Permissions: []string{"tenants"}}},
+	})
+	fake.AddRoleAssignment(permit.RoleAssignment{User: userID.String(), Role: "lister", Tenant: tenantID.String()})
+
+	sdkService := initPermitSdkService()
+	assert.NotNil(t, sdkService)
+	allowed, err := sdkService.Check(context.Background(), "alice", "read", "tenant", "*", tenantID.String())
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	health := newHealthHandler(sdkService)
+	handlerFunc := graphqlHandler(sdkService, nil, health, nil)
+	newContext := func(w http.ResponseWriter, query string) *gin.Context {
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID.String())
+		c.Set("tenantID", tenantID.String())
+		c.Request = httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query))
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), config.GinContextKey, c))
+		c.Request.Header.Set("Content-Type", "application/json")
+		return c
+	}
+	w := httptest.NewRecorder()
+	query := `{"query":"{ tenants { ... on SuccessResponse { totalCount data { ... on Tenant { id name } } } } }"}`
+	c := newContext(w, query)
+
+	handlerFunc(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"totalCount":1`)
+	assert.Contains(t, w.Body.String(), `"name":"Fake Tenant"`)
+	assert.Contains(t, w.Body.String(), tenantID.String())
+
+	w = httptest.NewRecorder()
+	query = `{"query":"{ tenantsConnection(first: 1) { ... on Connection { totalCount edges { cursor node { ... on Tenant { id } } } pageInfo { hasNextPage endCursor } } } }"}`
+	c = newContext(w, query)
+
+	handlerFunc(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"totalCount":1`)
+	assert.Contains(t, w.Body.String(), `"hasNextPage":false`)
+	assert.Contains(t, w.Body.String(), `"endCursor":"`+utils.EncodeCursor(0)+`"`)
+	assert.Contains(t, w.Body.String(), tenantID.String())
+
+	// The readiness check reports the retry counters of the handler's Permit
+	// client and the decision cache counters of the PDP client
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	health.ReadinessCheck(c)
+	var ready healthchecks.HealthResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &ready))
+	if assert.NotNil(t, ready.Retries) {
+		assert.NotZero(t, ready.Retries.Requests)
+		assert.NotZero(t, ready.Retries.Succeeded)
+		assert.Zero(t, ready.Retries.Failed)
+	}
+	if assert.NotNil(t, ready.DecisionCache) {
+		assert.NotZero(t, ready.DecisionCache.Hits+ready.DecisionCache.Misses)
+	}
+	assert.Nil(t, newHealthHandler(authz.AllowAll()).Decisions)
+}
+
+func TestCachePolicyFromEnv(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_CACHE_TTL", "")
+		t.Setenv("PERMIT_CACHE_SCHEMA_TTL", "")
+		t.Setenv("PERMIT_CACHE_MAX_ENTRIES", "")
+		assert.Equal(t, permit.DefaultCachePolicy(), cachePolicyFromEnv())
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("PERMIT_CACHE_TTL", "0")
+		t.Setenv("PERMIT_CACHE_SCHEMA_TTL", "1m")
+		t.Setenv("PERMIT_CACHE_MAX_ENTRIES", "50")
+
+		policy := cachePolicyFromEnv()
+		assert.Equal(t, time.Duration(0), policy.TTL)
+		assert.Equal(t, time.Minute, policy.SchemaTTL)
+		assert.Equal(t, 50, policy.MaxEntries)
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_CACHE_TTL", "forever")
+		t.Setenv("PERMIT_CACHE_SCHEMA_TTL", "")
+		t.Setenv("PERMIT_CACHE_MAX_ENTRIES", "lots")
+
+		assert.Equal(t, permit.DefaultCachePolicy(), cachePolicyFromEnv())
+	})
+}
+
+func TestTagIndexPolicyFromEnv(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("TAG_INDEX_TTL", "")
+		assert.Equal(t, tagsearch.DefaultPolicy(), tagIndexPolicyFromEnv())
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("TAG_INDEX_TTL", "30s")
+		assert.Equal(t, 30*time.Second, tagIndexPolicyFromEnv().TTL)
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("TAG_INDEX_TTL", "soon")
+		assert.Equal(t, tagsearch.DefaultPolicy(), tagIndexPolicyFromEnv())
+	})
+}
+
+func TestQueryLimitsFromEnv(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("GRAPHQL_MAX_DEPTH", "")
+		t.Setenv("GRAPHQL_MAX_COMPLEXITY", "")
+		assert.Equal(t, middlewares.DefaultQueryLimits(), queryLimitsFromEnv())
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("GRAPHQL_MAX_DEPTH", "4")
+		t.Setenv("GRAPHQL_MAX_COMPLEXITY", "0")
+		assert.Equal(t, middlewares.QueryLimits{MaxDepth: 4, MaxComplexity: 0}, queryLimitsFromEnv())
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("GRAPHQL_MAX_DEPTH", "deep")
+		t.Setenv("GRAPHQL_MAX_COMPLEXITY", "")
+		assert.Equal(t, middlewares.DefaultQueryLimits(), queryLimitsFromEnv())
+	})
+}
+
+func TestGraphQLQueryLimits(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	for key, value := range fake.Env() {
+		t.Setenv(key, value)
+	}
+	t.Setenv("GRAPHQL_MAX_DEPTH", "")
+	t.Setenv("GRAPHQL_MAX_COMPLEXITY", "20")
+	handlerFunc := graphqlHandler(authz.AllowAll(), nil, nil, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	query := `{"query":"{ accounts { ... on SuccessResponse { data { ... on Account { parentOrg { id } tenant { accountOwner { id } } } } } } }"}`
+	c.Request = httptest.NewRequest("POST", "/graphql", strings.NewReader(query))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handlerFunc(c)
+
+	var res struct {
+		Errors []struct {
+			Message    string                 `json:"message"`
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, "operation has complexity 28, which exceeds the limit of 20", res.Errors[0].Message)
+		assert.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", res.Errors[0].Extensions["code"])
+		assert.EqualValues(t, 28, res.Errors[0].Extensions["complexity"])
+		assert.EqualValues(t, 5, res.Errors[0].Extensions["depth"])
+		assert.Equal(t, map[string]interface{}{
+			"Query.accounts":       10.0,
+			"SuccessResponse.data": 1.0,
+			"Account.parentOrg":    5.0,
+			"Organization.id":      1.0,
+			"Account.tenant":       5.0,
+			"Tenant.accountOwner":  5.0,
+			"User.id":              1.0,
+		}, res.Errors[0].Extensions["fieldCosts"])
+	}
+	assert.Empty(t, fake.Requests(), "rejected operations must not reach Permit")
+}
+
+func TestGraphQLToolsFromEnv(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("GRAPHQL_PLAYGROUND", "")
+		t.Setenv("GRAPHQL_INTROSPECTION", "")
+		playground, introspection := graphqlToolsFromEnv()
+		assert.False(t, playground)
+		assert.False(t, introspection)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("GRAPHQL_PLAYGROUND", "true")
+		t.Setenv("GRAPHQL_INTROSPECTION", "1")
+		playground, introspection := graphqlToolsFromEnv()
+		assert.True(t, playground)
+		assert.True(t, introspection)
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("GRAPHQL_PLAYGROUND", "on")
+		t.Setenv("GRAPHQL_INTROSPECTION", "")
+		playground, introspection := graphqlToolsFromEnv()
+		assert.False(t, playground)
+		assert.False(t, introspection)
+	})
+}
+
+func TestPersistedQueriesFromEnv(t *testing.T) {
+	query := `{ tenants { __typename } }`
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("GRAPHQL_ALLOWLIST_FILE", "")
+		t.Setenv("GRAPHQL_APQ_CACHE_SIZE", "")
+		queries, err := persistedQueriesFromEnv()
+		assert.NoError(t, err)
+		assert.False(t, queries.Strict())
+	})
+
+	t.Run("Allowlist", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "allowlist.json")
+		data, _ := json.Marshal(map[string]string{hash: query})
+		assert.NoError(t, os.WriteFile(path, data, 0o600))
+		t.Setenv("GRAPHQL_ALLOWLIST_FILE", path)
+		queries, err := persistedQueriesFromEnv()
+		assert.NoError(t, err)
+		assert.True(t, queries.Strict())
+	})
+
+	t.Run("An unusable allowlist is an error", func(t *testing.T) {
+		t.Setenv("GRAPHQL_ALLOWLIST_FILE", filepath.Join(t.TempDir(), "missing.json"))
+		_, err := persistedQueriesFromEnv()
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("GRAPHQL_ALLOWLIST_FILE", "")
+		t.Setenv("GRAPHQL_APQ_CACHE_SIZE", "many")
+		queries, err := persistedQueriesFromEnv()
+		assert.NoError(t, err)
+		assert.False(t, queries.Strict())
+	})
+}
+
+func TestGraphQLPersistedQueries(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	for key, value := range fake.Env() {
+		t.Setenv(key, value)
+	}
+	t.Setenv("GRAPHQL_INTROSPECTION", "false")
+	userID, tenantID := uuid.NewString(), uuid.NewString()
+	fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: tenantID, Attributes: map[string]interface{}{"name": "Acme"}}, Name: "Acme"})
+
+	query := `query Tenants { tenants { __typename } }`
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+
+	serve := func(queries *middlewares.PersistedQueries, body string) string {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Set("tenantID", tenantID)
+		})
+		router.Use(middlewares.GinContextToContextMiddleware())
+		router.POST("/graphql", graphqlHandler(authz.AllowAll(), queries, nil, nil))
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return w.Body.String()
+	}
+	hashOnly := `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}}`
+	// The query ran, whatever its result
+	ran := `{"data":{"tenants":{"__typename":`
+	full := `{"query":"query Tenants { tenants { __typename } }","extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}}`
+
+	t.Run("Automatic persisted queries", func(t *testing.T) {
+		queries := middlewares.NewPersistedQueries(0, nil)
+		assert.Contains(t, serve(queries, hashOnly), "PERSISTED_QUERY_NOT_FOUND")
+		assert.Contains(t, serve(queries, full), ran)
+		assert.Contains(t, serve(queries, hashOnly), ran)
+	})
+
+	t.Run("Allowlist", func(t *testing.T) {
+		queries := middlewares.NewPersistedQueries(0, map[string]string{hash: query})
+		assert.Contains(t, serve(queries, hashOnly), ran)
+		assert.Contains(t, serve(queries, `{"query":"{ accounts { __typename } }"}`), "PERSISTED_QUERY_NOT_ALLOWED")
+	})
+
+	t.Run("Introspection can be disabled", func(t *testing.T) {
+		assert.Contains(t, serve(nil, `{"query":"{ __schema { queryType { name } } }"}`), "introspection disabled")
+	})
+}
+
+func TestGraphQLFederation(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	for key, value := range fake.Env() {
+		t.Setenv(key, value)
+	}
+	userID, tenantID := uuid.NewString(), uuid.NewString()
+	accountID, roleID, bindingID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: tenantID, Attributes: map[string]interface{}{"name": "Acme", "contactInfo": map[string]interface{}{}}}, Name: "Acme"})
+	fake.AddResourceInstance(permit.ResourceInstance{
+		Entity:   permit.Entity{Key: accountID, Attributes: map[string]interface{}{"name": "Billing"}},
+		Tenant:   tenantID,
+		Resource: config.AccountResourceTypeID,
+	})
+	fake.AddUser(permit.User{Entity: permit.Entity{Key: userID}, FirstName: "Ada", AssociatedTenants: []permit.UserTenant{{Tenant: tenantID}}})
+	fake.AddResource(permit.Resource{
+		ID:    uuid.NewString(),
+		Key:   config.AccountResourceTypeID,
+		Roles: map[string]permit.Role{"viewer": {Key: roleID, Name: "Viewer", Attributes: map[string]interface{}{"roleType": "DEFAULT"}}},
+	})
+	fake.AddRoleAssignment(permit.RoleAssignment{ID: bindingID, User: userID, Role: roleID, Tenant: tenantID})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("tenantID", tenantID)
+	})
+	router.Use(middlewares.GinContextToContextMiddleware())
+	// _service is only served with introspection
+	t.Setenv("GRAPHQL_INTROSPECTION", "true")
+	router.POST("/graphql", graphqlHandler(authz.AllowAll(), nil, nil, nil))
+	post := func(body interface{}) map[string]interface{} {
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/graphql", strings.NewReader(string(data)))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res
+	}
+
+	t.Run("Service SDL", func(t *testing.T) {
+		res := post(map[string]string{"query": "{ _service { sdl } }"})
+		sdl, _ := res["data"].(map[string]interface{})["_service"].(map[string]interface{})["sdl"].(string)
+		assert.Contains(t, sdl, `type Tenant implements Organization & Resource & Taggable @key(fields: "id")`)
+		assert.Contains(t, sdl, `type Binding @key(fields: "id")`)
+	})
+
+	t.Run("Entities", func(t *testing.T) {
+		res := post(map[string]interface{}{
+			"query": `query($representations: [_Any!]!) { _entities(representations: $representations) {
+				__typename
+				... on Tenant { id name }
+				... on Account { id name }
+				... on User { id firstName }
+				... on Role { id name }
+				... on Binding { id role { name } }
+			} }`,
+			"variables": map[string]interface{}{"representations": []map[string]string{
+				{"__typename": "Tenant", "id": tenantID},
+				{"__typename": "Account", "id": accountID},
+				{"__typename": "User", "id": userID},
+				{"__typename": "Role", "id": roleID},
+				{"__typename": "Binding", "id": bindingID},
+				{"__typename": "Account", "id": uuid.NewString()},
+			}},
+		})
+		assert.Nil(t, res["errors"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"__typename": "Tenant", "id": tenantID, "name": "Acme"},
+			map[string]interface{}{"__typename": "Account", "id": accountID, "name": "Billing"},
+			map[string]interface{}{"__typename": "User", "id": userID, "firstName": "Ada"},
+			map[string]interface{}{"__typename": "Role", "id": roleID, "name": "Viewer"},
+			map[string]interface{}{"__typename": "Binding", "id": bindingID, "role": map[string]interface{}{"name": "Viewer"}},
+			nil,
+		}, res["data"].(map[string]interface{})["_entities"])
+	})
+
+	t.Run("Other root fields are still authorized", func(t *testing.T) {
+		denied := gin.New()
+		denied.Use(func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Set("tenantID", tenantID)
+		})
+		denied.Use(middlewares.GinContextToContextMiddleware())
+		denied.POST("/graphql", graphqlHandler(authz.DenyAll(), nil, nil, nil))
+
+		data, _ := json.Marshal(map[string]string{"query": `{ _service { sdl } tenants { __typename } }`})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/graphql", strings.NewReader(string(data)))
+		req.Header.Set("Content-Type", "application/json")
+		denied.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.NotContains(t, w.Body.String(), `"sdl"`)
+		assert.Contains(t, w.Body.String(), `"code":"FORBIDDEN"`)
+		assert.Contains(t, w.Body.String(), `"errorCode":"IAM-AUTHZ-403"`)
+		assert.Contains(t, w.Body.String(), `"deniedFields":[{"field":"tenants","name":"tenants"`)
+	})
+}
+
+func TestGraphQLFieldAuthorization(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	for key, value := range fake.Env() {
+		t.Setenv(key, value)
+	}
+
+	tenantID, otherID, userID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	for _, id := range []string{tenantID, otherID} {
+		fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: id, Attributes: map[string]interface{}{"name": "Acme"}}, Name: "Acme"})
+	}
+	policy := &authz.Policy{Rules: []authz.Rule{{
+		Actions:      []string{"tenant"},
+		ResourceType: config.TenantResourceTypeID,
+		ResourceID:   tenantID,
+	}}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("tenantID", tenantID)
+	})
+	router.Use(middlewares.GinContextToContextMiddleware())
+	router.POST("/graphql", graphqlHandler(policy, nil, nil, nil))
+	post := func(query string, variables map[string]interface{}) map[string]interface{} {
+		data, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/graphql", strings.NewReader(string(data)))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res
+	}
+	errorCode := func(res map[string]interface{}) interface{} {
+		errs, _ := res["errors"].([]interface{})
+		if len(errs) != 1 {
+			return nil
+		}
+		extensions, _ := errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+		return extensions["code"]
+	}
+	query := `query($id: UUID!) { tenant(id: $id) { __typename } }`
+
+	t.Run("Instance-level checks use the ID argument", func(t *testing.T) {
+		res := post(query, map[string]interface{}{"id": tenantID})
+		assert.Nil(t, res["errors"])
+		assert.NotNil(t, res["data"].(map[string]interface{})["tenant"])
+
+		res = post(query, map[string]interface{}{"id": otherID})
+		assert.Equal(t, "FORBIDDEN", errorCode(res))
+		assert.Nil(t, res["data"])
+	})
+
+	t.Run("Operation names do not select the action", func(t *testing.T) {
+		res := post(`query tenant { tenants { __typename } }`, nil)
+		assert.Equal(t, "FORBIDDEN", errorCode(res))
+	})
+
+	t.Run("Root fields without a directive are denied", func(t *testing.T) {
+		res := post(`{ organizations { __typename } }`, nil)
+		assert.Equal(t, "FORBIDDEN", errorCode(res))
+	})
+
+	t.Run("Documents are rejected when any root field is denied", func(t *testing.T) {
+		res := post(`query($id: UUID!) { mine: tenant(id: $id) { __typename } ...Lists } fragment Lists on Query { all: bindings { __typename } }`, map[string]interface{}{"id": tenantID})
+		assert.Equal(t, "FORBIDDEN", errorCode(res))
+		assert.Nil(t, res["data"])
+		extensions := res["errors"].([]interface{})[0].(map[string]interface{})["extensions"].(map[string]interface{})
+		assert.Equal(t, []interface{}{map[string]interface{}{
+			"field":  "all",
+			"name":   "bindings",
+			"reason": "permission denied",
+			"checks": []interface{}{map[string]interface{}{"action": "bindings", "resourceType": "Binding", "resourceId": "*"}},
+		}}, extensions["deniedFields"])
+	})
+}
+
+func TestSchemaRootFieldsAreAuthorized(t *testing.T) {
+	// The federation fields are authorized by the entity resolvers.
+	federation := map[string]bool{"_entities": true, "_service": true}
+
+	declared, err := permitschema.Load("../../config/permit_schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := map[string]map[string]permitschema.ActionSpec{}
+	for _, resource := range declared.Resources {
+		actions[resource.Name] = resource.Actions
+	}
+
+	schema := generated.NewExecutableSchema(generated.Config{}).Schema()
+	for _, root := range []*ast.Definition{schema.Query, schema.Mutation, schema.Subscription} {
+		for _, field := range root.Fields {
+			if strings.HasPrefix(field.Name, "__") || federation[field.Name] {
+				continue
+			}
+			directives := field.Directives.ForNames("authorize")
+			assert.NotEmpty(t, directives, "%s.%s has no @authorize directive, so it is denied to every caller", root.Name, field.Name)
+			for _, directive := range directives {
+				resourceType := directive.Arguments.ForName("resourceType").Value.Raw
+				action := strings.ToLower(directive.Arguments.ForName("action").Value.Raw)
+				assert.Contains(t, actions[resourceType], action, "%s.%s checks an action the Permit schema does not declare", root.Name, field.Name)
+			}
+		}
+	}
+}
+
+func TestDecisionCachePolicyFromEnv(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_DECISION_ALLOW_TTL", "")
+		t.Setenv("PERMIT_DECISION_DENY_TTL", "")
+		t.Setenv("PERMIT_DECISION_CACHE_MAX_ENTRIES", "")
+		assert.Equal(t, permit.DefaultDecisionCachePolicy(), decisionCachePolicyFromEnv())
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("PERMIT_DECISION_ALLOW_TTL", "1m")
+		t.Setenv("PERMIT_DECISION_DENY_TTL", "0")
+		t.Setenv("PERMIT_DECISION_CACHE_MAX_ENTRIES", "100")
+
+		policy := decisionCachePolicyFromEnv()
+		assert.Equal(t, time.Minute, policy.AllowTTL)
+		assert.Equal(t, time.Duration(0), policy.DenyTTL)
+		assert.Equal(t, 100, policy.MaxEntries)
+	})
+
+	t.Run("Invalid values keep defaults", func(t *testing.T) {
+		t.Setenv("PERMIT_DECISION_ALLOW_TTL", "long")
+		t.Setenv("PERMIT_DECISION_DENY_TTL", "")
+		t.Setenv("PERMIT_DECISION_CACHE_MAX_ENTRIES", "x")
+
+		assert.Equal(t, permit.DefaultDecisionCachePolicy(), decisionCachePolicyFromEnv())
+	})
+}
+
+func TestInitAuthorizer(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	assert.NoError(t, os.WriteFile(policyPath, []byte(`{"rules":[{"user":"alice","actions":["read"]}]}`), 0o600))
+
+	t.Run("Permit by default", func(t *testing.T) {
+		fake := permittest.NewServer()
+		defer fake.Close()
+		for key, value := range fake.Env() {
+			t.Setenv(key, value)
+		}
+		t.Setenv("AUTHORIZER", "")
+		assert.IsType(t, &permit.PermitSdkService{}, initAuthorizer())
+	})
+
+	t.Run("Permit without configuration", func(t *testing.T) {
+		t.Setenv("AUTHORIZER", "permit")
+		t.Setenv("PERMIT_PDP_URL", "")
+		assert.Nil(t, initAuthorizer())
+	})
+
+	t.Run("Allow all and deny all", func(t *testing.T) {
+		t.Setenv("AUTHORIZER", "allow-all")
+		allowed, _ := initAuthorizer().Check(context.Background(), "alice", "delete", "tenant", "", "t1")
+		assert.True(t, allowed)
+
+		t.Setenv("AUTHORIZER", "deny-all")
+		allowed, _ = initAuthorizer().Check(context.Background(), "alice", "read", "tenant", "", "t1")
+		assert.False(t, allowed)
+	})
+
+	t.Run("Policy file", func(t *testing.T) {
+		t.Setenv("AUTHORIZER", "policy")
+		t.Setenv("AUTHORIZER_POLICY_FILE", policyPath)
+		authorizer := initAuthorizer()
+		assert.IsType(t, &authz.Policy{}, authorizer)
+		allowed, _ := authorizer.Check(context.Background(), "alice", "read", "tenant", "", "t1")
+		assert.True(t, allowed)
+	})
+
+	t.Run("Missing policy file", func(t *testing.T) {
+		t.Setenv("AUTHORIZER", "policy")
+		t.Setenv("AUTHORIZER_POLICY_FILE", filepath.Join(t.TempDir(), "missing.json"))
+		assert.Nil(t, initAuthorizer())
+	})
+
+	t.Run("Unknown mode", func(t *testing.T) {
+		t.Setenv("AUTHORIZER", "opa")
+		assert.Nil(t, initAuthorizer())
+	})
+}
+
+func TestOutboundTLSOptionsFromEnv(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		for _, key := range []string{"OUTBOUND_TLS_CA_FILE", "OUTBOUND_TLS_CERT_FILE", "OUTBOUND_TLS_KEY_FILE", "OUTBOUND_TLS_MIN_VERSION", "OUTBOUND_TLS_RELOAD_INTERVAL"} {
+			t.Setenv(key, "")
+		}
+		options, err := outboundTLSOptionsFromEnv()
+		assert.NoError(t, err)
+		assert.Equal(t, httpclient.Options{ReloadInterval: httpclient.DefaultReloadInterval}, options)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("OUTBOUND_TLS_CA_FILE", "/etc/ssl/corp-ca.pem")
+		t.Setenv("OUTBOUND_TLS_CERT_FILE", "/etc/ssl/client.pem")
+		t.Setenv("OUTBOUND_TLS_KEY_FILE", "/etc/ssl/client-key.pem")
+		t.Setenv("OUTBOUND_TLS_MIN_VERSION", "1.3")
+		t.Setenv("OUTBOUND_TLS_RELOAD_INTERVAL", "5m")
+		options, err := outboundTLSOptionsFromEnv()
+		assert.NoError(t, err)
+		assert.Equal(t, httpclient.Options{
+			CAFile:         "/etc/ssl/corp-ca.pem",
+			CertFile:       "/etc/ssl/client.pem",
+			KeyFile:        "/etc/ssl/client-key.pem",
+			MinVersion:     tls.VersionTLS13,
+			ReloadInterval: 5 * time.Minute,
+		}, options)
+	})
+
+	t.Run("Invalid minimum version", func(t *testing.T) {
+		t.Setenv("OUTBOUND_TLS_MIN_VERSION", "ssl3")
+		_, err := outboundTLSOptionsFromEnv()
+		assert.Error(t, err)
+	})
+
+	t.Run("Factory is shared while settings are unchanged", func(t *testing.T) {
+		t.Setenv("OUTBOUND_TLS_MIN_VERSION", "")
+		first, err := outboundHTTP()
+		assert.NoError(t, err)
+		second, err := outboundHTTP()
+		assert.NoError(t, err)
+		assert.Same(t, first, second)
+
+		t.Setenv("OUTBOUND_TLS_MIN_VERSION", "1.3")
+		third, err := outboundHTTP()
+		assert.NoError(t, err)
+		assert.NotSame(t, first, third)
+	})
+}
+
+func TestGraphQLSubscriptions(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	for key, value := range fake.Env() {
+		t.Setenv(key, value)
+	}
+	userID, tenantID := uuid.NewString(), uuid.NewString()
+	fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: tenantID, Attributes: map[string]interface{}{"name": "Acme"}}, Name: "Acme"})
+
+	provider := jwttest.NewServer()
+	defer provider.Close()
+	const portal = "https://portal.example.com"
+	ws := &middlewares.WebSocketAuth{
+		Authenticator:  func() (*jwt.Authenticator, error) { return provider.Authenticator(), nil },
+		AllowedOrigins: []string{portal},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middlewares.GinContextToContextMiddleware())
+	gqlHandler := graphqlHandler(authz.AllowAll(), nil, nil, ws)
+	router.GET("/graphql", middlewares.WebSocketUpgrade(), gqlHandler)
+	router.POST("/graphql", func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("tenantID", tenantID)
+	}, gqlHandler)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	type message struct {
+		ID      string          `json:"id,omitempty"`
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload,omitempty"`
+	}
+	initPayload := func(token string) json.RawMessage {
+		payload, _ := json.Marshal(map[string]string{"Authorization": "Bearer " + token, "X-Tenant-ID": tenantID})
+		return payload
+	}
+	dial := func(origin string) (*websocket.Conn, error) {
+		dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+		conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/graphql", http.Header{"Origin": {origin}})
+		return conn, err
+	}
+
+	t.Run("Pages of other origins cannot connect", func(t *testing.T) {
+		conn, err := dial("https://evil.example.com")
+		if err == nil {
+			conn.Close()
+		}
+		assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+	})
+
+	t.Run("Connections without a valid token are closed", func(t *testing.T) {
+		for _, payload := range []json.RawMessage{nil, initPayload("not-a-token")} {
+			conn, err := dial(portal)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, conn.WriteJSON(message{Type: "connection_init", Payload: payload}))
+			var m message
+			for err == nil && m.Type != "connection_ack" {
+				err = conn.ReadJSON(&m)
+			}
+			assert.Error(t, err)
+			assert.NotEqual(t, "connection_ack", m.Type)
+			conn.Close()
+		}
+	})
+
+	conn, err := dial(portal)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	messages := make(chan message, 16)
+	go func() {
+		defer close(messages)
+		for {
+			var m message
+			if err := conn.ReadJSON(&m); err != nil {
+				return
+			}
+			if m.Type != "ping" && m.Type != "pong" {
+				messages <- m
+			}
+		}
+	}()
+	next := func(timeout time.Duration) (message, bool) {
+		select {
+		case m, ok := <-messages:
+			return m, ok
+		case <-time.After(timeout):
+			return message{}, false
+		}
+	}
+	send := func(m message) {
+		assert.NoError(t, conn.WriteJSON(m))
+	}
+	operation := func(query string) json.RawMessage {
+		payload, _ := json.Marshal(map[string]string{"query": query})
+		return payload
+	}
+
+	// Browsers send the token in the connection_init payload
+	send(message{Type: "connection_init", Payload: initPayload(provider.Token(userID))})
+	ack, _ := next(time.Second)
+	assert.Equal(t, "connection_ack", ack.Type)
+
+	t.Run("Queries are not served over WebSocket", func(t *testing.T) {
+		send(message{ID: "query", Type: "subscribe", Payload: operation(`{ tenants { __typename } }`)})
+		m, ok := next(time.Second)
+		assert.True(t, ok)
+		assert.Equal(t, "query", m.ID)
+		assert.Contains(t, string(m.Payload), "only subscriptions are served over WebSocket")
+		for m.Type != "complete" && ok {
+			m, ok = next(time.Second)
+		}
+	})
+
+	t.Run("Mutations are reported to subscribers", func(t *testing.T) {
+		send(message{ID: "tenant", Type: "subscribe", Payload: operation(`subscription { tenantChanged { change tenantId } }`)})
+
+		// The subscription starts asynchronously, so update the tenant until
+		// the change is reported.
+		mutation := fmt.Sprintf(`{"query":"mutation { updateTenant(input: {id: \"%s\", name: \"Acme Corp\"}) { __typename } }"}`, tenantID)
+		for i := 0; i < 20; i++ {
+			res, err := http.Post(server.URL+"/graphql", "application/json", strings.NewReader(mutation))
+			if !assert.NoError(t, err) {
+				return
+			}
+			res.Body.Close()
+
+			if m, ok := next(100 * time.Millisecond); ok {
+				assert.Equal(t, "tenant", m.ID)
+				assert.Equal(t, "next", m.Type)
+				assert.JSONEq(t, fmt.Sprintf(`{"data":{"tenantChanged":{"change":"UPDATED","tenantId":"%s"}}}`, tenantID), string(m.Payload))
+				return
+			}
+		}
+		t.Fatal("the tenant change was not reported")
+	})
+}
@@ -14,6 +14,14 @@ const (
 	SubgraphName        = "mcp_iam_o"
 )
 
+// UpstreamUnavailableErrorMessage is the client-facing message of errors
+// caused by an unavailable upstream.
+const UpstreamUnavailableErrorMessage = "The authorization service is temporarily unavailable. Please try again later."
+
+// StaleDataMessage is the message of a successful query answered from cached
+// data while the authorization service is unavailable.
+const StaleDataMessage = "Operation successful (stale: the authorization service is unavailable, so the data may be out of date)"
+
 // Permit configuration variables
 
 const (
@@ -3,8 +3,10 @@ package gql
 import (
 	"iam_services_main_v1/gql/generated"
 	"iam_services_main_v1/internal/accounts"
+	"iam_services_main_v1/internal/authz"
 	"iam_services_main_v1/internal/bindings"
 	"iam_services_main_v1/internal/clientorganizationunits"
+	"iam_services_main_v1/internal/events"
 	"iam_services_main_v1/internal/groups"
 	"iam_services_main_v1/internal/organizations"
 	"iam_services_main_v1/internal/permissions"
@@ -13,30 +15,38 @@ import (
 	resourcetypes "iam_services_main_v1/internal/resourcetypes"
 	role "iam_services_main_v1/internal/roles"
 	"iam_services_main_v1/internal/root"
+	"iam_services_main_v1/internal/tagsearch"
 	"iam_services_main_v1/internal/tenants"
+	"iam_services_main_v1/internal/users"
 )
 
 // Resolver holds references to the DB and acts as a central resolver
 type Resolver struct {
-	PC  permit.PermitService
-	PSC *permit.PermitSdkService
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
+	// TagIndex answers searchByTags. PC should be wrapped with
+	// tagsearch.NewIndexingService so writes keep it up to date.
+	TagIndex *tagsearch.Index
+	// Events carries the changes made by mutations to subscriptions.
+	Events *events.Bus
 }
 
 // Query returns the root query resolvers, delegating to feature-based resolvers
 func (r *Resolver) Query() generated.QueryResolver {
 	return &queryResolver{
 
-		TenantQueryResolver:                 &tenants.TenantQueryResolver{PC: r.PC, PSC: r.PSC},
+		TenantQueryResolver:                 &tenants.TenantQueryResolver{PC: r.PC, Authorizer: r.Authorizer},
 		AccountQueryResolver:                &accounts.AccountQueryResolver{PC: r.PC},
 		ClientOrganizationUnitQueryResolver: &clientorganizationunits.ClientOrganizationUnitQueryResolver{PC: r.PC},
 		RoleQueryResolver:                   &role.RoleQueryResolver{PC: r.PC},
 		PermissionQueryResolver:             &permissions.PermissionQueryResolver{},
 		BindingsQueryResolver:               &bindings.BindingsQueryResolver{PC: r.PC},
 		ResourceTypeQueryResolver:           &resourcetypes.ResourceTypeQueryResolver{PC: r.PC},
-		ResourceQueryResolver:               &resources.ResourceQueryResolver{PSC: r.PSC},
+		ResourceQueryResolver:               &resources.ResourceQueryResolver{Authorizer: r.Authorizer},
 		GroupQueryResolver:                  &groups.GroupQueryResolver{},
 		OrganizationQueryResolver:           &organizations.OrganizationQueryResolver{},
 		RootQueryResolver:                   &root.RootQueryResolver{},
+		TagSearchQueryResolver:              &tagsearch.TagSearchQueryResolver{PC: r.PC, Authorizer: r.Authorizer, Index: r.TagIndex},
 	}
 }
 
@@ -44,18 +54,42 @@ func (r *Resolver) Query() generated.QueryResolver {
 func (r *Resolver) Mutation() generated.MutationResolver {
 	return &mutationResolver{
 
-		AccountMutationResolver:                &accounts.AccountMutationResolver{PC: r.PC},
-		TenantMutationResolver:                 &tenants.TenantMutationResolver{PC: r.PC, PSC: r.PSC},
-		ClientOrganizationUnitMutationResolver: &clientorganizationunits.ClientOrganizationUnitMutationResolver{PC: r.PC},
-		RoleMutationResolver:                   &role.RoleMutationResolver{PC: r.PC},
+		AccountMutationResolver:                &accounts.AccountMutationResolver{PC: r.PC, Events: r.Events},
+		TenantMutationResolver:                 &tenants.TenantMutationResolver{PC: r.PC, Authorizer: r.Authorizer, Events: r.Events},
+		ClientOrganizationUnitMutationResolver: &clientorganizationunits.ClientOrganizationUnitMutationResolver{PC: r.PC, Events: r.Events},
+		RoleMutationResolver:                   &role.RoleMutationResolver{PC: r.PC, Authorizer: r.Authorizer},
 		PermissionMutationResolver:             &permissions.PermissionMutationResolver{PC: r.PC},
-		BindingsMutationResolver:               &bindings.BindingsMutationResolver{PC: r.PC},
+		BindingsMutationResolver:               &bindings.BindingsMutationResolver{PC: r.PC, Authorizer: r.Authorizer, Events: r.Events},
 		RootMutationResolver:                   &root.RootMutationResolver{},
 		ResourceTypeMutationResolver:           &resourcetypes.ResourceTypeMutationResolver{PC: r.PC},
 	}
 }
 
-// Root resolvers for Query and Mutation
+// Subscription returns the root subscription resolvers, delegating to feature-based resolvers
+func (r *Resolver) Subscription() generated.SubscriptionResolver {
+	return &subscriptionResolver{
+
+		TenantSubscriptionResolver:       &tenants.TenantSubscriptionResolver{Events: r.Events, Authorizer: r.Authorizer},
+		OrganizationSubscriptionResolver: &organizations.OrganizationSubscriptionResolver{Events: r.Events, Authorizer: r.Authorizer},
+		BindingsSubscriptionResolver:     &bindings.BindingsSubscriptionResolver{Events: r.Events, Authorizer: r.Authorizer},
+	}
+}
+
+// Entity returns the resolvers of the entities other subgraphs reference,
+// delegating to feature-based resolvers
+func (r *Resolver) Entity() generated.EntityResolver {
+	return &entityResolver{
+
+		TenantEntityResolver:                 &tenants.TenantEntityResolver{PC: r.PC, Authorizer: r.Authorizer},
+		AccountEntityResolver:                &accounts.AccountEntityResolver{PC: r.PC, Authorizer: r.Authorizer},
+		ClientOrganizationUnitEntityResolver: &clientorganizationunits.ClientOrganizationUnitEntityResolver{PC: r.PC, Authorizer: r.Authorizer},
+		RoleEntityResolver:                   &role.RoleEntityResolver{PC: r.PC, Authorizer: r.Authorizer},
+		UserEntityResolver:                   &users.UserEntityResolver{PC: r.PC, Authorizer: r.Authorizer},
+		BindingsEntityResolver:               &bindings.BindingsEntityResolver{PC: r.PC, Authorizer: r.Authorizer},
+	}
+}
+
+// Root resolvers for Query, Mutation, Subscription and Entity
 type queryResolver struct {
 	*tenants.TenantQueryResolver
 	*accounts.AccountQueryResolver
@@ -68,6 +102,7 @@ type queryResolver struct {
 	*groups.GroupQueryResolver
 	*organizations.OrganizationQueryResolver
 	*root.RootQueryResolver
+	*tagsearch.TagSearchQueryResolver
 }
 
 type mutationResolver struct {
@@ -81,6 +116,21 @@ type mutationResolver struct {
 	*resourcetypes.ResourceTypeMutationResolver
 }
 
+type subscriptionResolver struct {
+	*tenants.TenantSubscriptionResolver
+	*organizations.OrganizationSubscriptionResolver
+	*bindings.BindingsSubscriptionResolver
+}
+
+type entityResolver struct {
+	*tenants.TenantEntityResolver
+	*accounts.AccountEntityResolver
+	*clientorganizationunits.ClientOrganizationUnitEntityResolver
+	*role.RoleEntityResolver
+	*users.UserEntityResolver
+	*bindings.BindingsEntityResolver
+}
+
 // Account resolves fields for the Account type
 func (r *Resolver) Account() generated.AccountResolver {
 	return &accounts.AccountFieldResolver{PC: r.PC}
@@ -122,6 +122,11 @@ func TestResolver_DependencyInjection(t *testing.T) {
 		assert.Equal(t, ts.mockPermit, mutationR.AccountMutationResolver.PC)
 		assert.Equal(t, ts.mockPermit, mutationR.TenantMutationResolver.PC)
 
+		// Entity resolvers
+		entityR := ts.resolver.Entity().(*entityResolver)
+		assert.Equal(t, ts.mockPermit, entityR.TenantEntityResolver.PC)
+		assert.Equal(t, ts.mockPermit, entityR.BindingsEntityResolver.PC)
+
 		// Field resolvers
 		account := ts.resolver.Account()
 		accountR := account.(*accounts.AccountFieldResolver)
@@ -132,5 +137,6 @@ func TestResolver_DependencyInjection(t *testing.T) {
 func TestResolver_InterfaceImplementation(t *testing.T) {
 	var _ generated.QueryResolver = (*queryResolver)(nil)
 	var _ generated.MutationResolver = (*mutationResolver)(nil)
+	var _ generated.EntityResolver = (*entityResolver)(nil)
 	var _ generated.AccountResolver = (*accounts.AccountFieldResolver)(nil)
 }
@@ -0,0 +1,39 @@
+package accounts
+
+import (
+	"context"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/internal/federation"
+	"iam_services_main_v1/internal/permit"
+)
+
+// AccountEntityResolver resolves the accounts other subgraphs reference.
+type AccountEntityResolver struct {
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
+}
+
+// FindManyAccountByIDs resolves a batch of account references, in order. It
+// needs the account action, like the account query. References to accounts
+// of other tenants resolve to null.
+func (r *AccountEntityResolver) FindManyAccountByIDs(ctx context.Context, reps []*models.AccountByIDsInput) ([]*models.Account, error) {
+	tenantID, err := federation.Authorize(ctx, r.Authorizer, "account", config.AccountResourceTypeID)
+	if err != nil {
+		return nil, err
+	}
+	loaders := dataloaders.For(ctx, r.PC)
+	return federation.Resolve(ctx, reps, func(ctx context.Context, rep *models.AccountByIDsInput) (*models.Account, error) {
+		instance, err := loaders.ResourceInstances.Load(ctx, rep.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if instance.Resource != config.AccountResourceTypeID || instance.Tenant != tenantID.String() {
+			return nil, nil
+		}
+		return mapAccountData(instance)
+	})
+}
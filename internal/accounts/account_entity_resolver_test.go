@@ -0,0 +1,60 @@
+package accounts
+
+import (
+	"context"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/federation"
+	"iam_services_main_v1/internal/permit"
+	mocks "iam_services_main_v1/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindManyAccountByIDs(t *testing.T) {
+	tenantID := uuid.New()
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantID.String())
+	ginCtx.Set("userID", uuid.New().String())
+	ctx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+	instance := func(id uuid.UUID, tenant, resource string) *permit.ResourceInstance {
+		return &permit.ResourceInstance{
+			Entity:   permit.Entity{Key: id.String(), Attributes: map[string]interface{}{"name": "Billing"}},
+			Tenant:   tenant,
+			Resource: resource,
+		}
+	}
+
+	t.Run("Resolves the accounts of the caller's tenant", func(t *testing.T) {
+		ctrl := mock.NewController(t)
+		defer ctrl.Finish()
+		own, foreign, unit := uuid.New(), uuid.New(), uuid.New()
+		mockPC := mocks.NewMockPermitService(ctrl)
+		mockPC.EXPECT().GetResourceInstance(mock.Any(), own.String()).Return(instance(own, tenantID.String(), config.AccountResourceTypeID), nil)
+		mockPC.EXPECT().GetResourceInstance(mock.Any(), foreign.String()).Return(instance(foreign, uuid.NewString(), config.AccountResourceTypeID), nil)
+		mockPC.EXPECT().GetResourceInstance(mock.Any(), unit.String()).Return(instance(unit, tenantID.String(), config.ClientOrgUnitResourceTypeID), nil)
+
+		resolver := AccountEntityResolver{PC: mockPC, Authorizer: authz.AllowAll()}
+		accounts, err := resolver.FindManyAccountByIDs(ctx, []*models.AccountByIDsInput{{ID: own}, {ID: foreign}, {ID: unit}})
+		assert.NoError(t, err)
+		if assert.Len(t, accounts, 3) {
+			assert.Equal(t, own, accounts[0].ID)
+			assert.Equal(t, "Billing", accounts[0].Name)
+			assert.Nil(t, accounts[1], "accounts of other tenants must not resolve")
+			assert.Nil(t, accounts[2], "other organizations must not resolve as accounts")
+		}
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		ctrl := mock.NewController(t)
+		defer ctrl.Finish()
+		resolver := AccountEntityResolver{PC: mocks.NewMockPermitService(ctrl), Authorizer: authz.DenyAll()}
+		_, err := resolver.FindManyAccountByIDs(ctx, []*models.AccountByIDsInput{{ID: uuid.New()}})
+		assert.ErrorIs(t, err, federation.ErrForbidden)
+	})
+}
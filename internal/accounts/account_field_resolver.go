@@ -6,11 +6,11 @@ import (
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
 	"iam_services_main_v1/internal/clientorganizationunits"
+	"iam_services_main_v1/internal/dataloaders"
 	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/tenants"
 	"iam_services_main_v1/internal/users"
 	"iam_services_main_v1/pkg/logger"
-	"log"
 )
 
 // AccountFieldResolver provides database operations for resolving Account fields
@@ -20,41 +20,34 @@ type AccountFieldResolver struct {
 
 // ParentOrg resolves the ParentOrg field on the Account type
 func (r *AccountFieldResolver) ParentOrg(ctx context.Context, account *models.Account) (models.Organization, error) {
-	url := fmt.Sprintf("resource_instances/%s", account.ParentOrg.GetID())
-	resourceResponse, err := r.PC.GetSingleResource(ctx, "GET", url)
+	resourceResponse, err := dataloaders.For(ctx, r.PC).ResourceInstances.Load(ctx, account.ParentOrg.GetID().String())
 	if err != nil {
 		logger.LogError("error fetching parent org", "error", err)
 		return nil, err
 	}
-	log.Printf("ParentOrg: %v", resourceResponse)
-	attributes, ok := resourceResponse["attributes"].(map[string]interface{})
-	if !ok {
+	if resourceResponse.Attributes == nil {
 		logger.LogError("failed to get attributes map from Tenant data", "error", "missing or invalid map for key: attributes")
 		return nil, fmt.Errorf("missing or invalid map for key: attributes")
 	}
-	resourceType := helpers.GetString(attributes, "type")
+	resourceType := helpers.GetString(resourceResponse.Attributes, "type")
 	if resourceType == "ClientOrganizationUnit" {
-		clientOrg := clientorganizationunits.BuildOrgUnit(resourceResponse)
-		return clientOrg, nil
-
-	} else {
-		tenant, err := tenants.MapTenantData(resourceResponse)
-		if err != nil {
-			logger.LogError("error mapping Tenant data in MapTenantResponseToStruct", "error", err)
-			return nil, err
-		}
-		return tenant, nil
+		return clientorganizationunits.BuildOrgUnit(resourceResponse)
 	}
+	tenant, err := tenants.MapTenantData(&resourceResponse.Entity)
+	if err != nil {
+		logger.LogError("error mapping Tenant data in MapTenantResponseToStruct", "error", err)
+		return nil, err
+	}
+	return tenant, nil
 }
 
-// ParentOrg resolves the ParentOrg field on the Account type
+// Tenant resolves the Tenant field on the Account type
 func (r *AccountFieldResolver) Tenant(ctx context.Context, account *models.Account) (*models.Tenant, error) {
-	tenantResolver := &tenants.TenantQueryResolver{PC: r.PC}
-	resourceResponse, err := tenantResolver.FetchTenant(ctx, account.Tenant.GetID())
+	resourceResponse, err := dataloaders.For(ctx, r.PC).Tenants.Load(ctx, account.Tenant.GetID().String())
 	if err != nil {
 		return nil, err
 	}
-	tenant, err := tenants.MapTenantData(resourceResponse)
+	tenant, err := tenants.MapTenantData(&resourceResponse.Entity)
 	if err != nil {
 		logger.LogError("error mapping Tenant data in MapTenantResponseToStruct", "error", err)
 		return nil, err
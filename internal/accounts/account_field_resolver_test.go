@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/mocks"
 	"testing"
 	"time"
@@ -41,7 +42,7 @@ func TestAccountFieldResolver_ParentOrg(t *testing.T) {
 
 		// Set up expected behavior
 		mockPermitService.EXPECT().
-			GetSingleResource(ctx, "GET", gomock.Any()).
+			GetResourceInstance(ctx, account.ParentOrg.GetID().String()).
 			Return(mockResponse, nil).MaxTimes(1)
 
 		// Call method being tested
@@ -55,12 +56,12 @@ func TestAccountFieldResolver_ParentOrg(t *testing.T) {
 
 	t.Run("Success_With_Tenant", func(t *testing.T) {
 		// Mock response data with tenant type
-		mockResponse := buildTestTenantData(account.ParentOrg.GetID())
-		mockResponse["attributes"].(map[string]interface{})["type"] = "Tenant"
+		mockResponse := &permit.ResourceInstance{Entity: buildTestTenantData(account.ParentOrg.GetID()).Entity}
+		mockResponse.Attributes["type"] = "Tenant"
 
 		// Set up expected behavior
 		mockPermitService.EXPECT().
-			GetSingleResource(ctx, "GET", gomock.Any()).
+			GetResourceInstance(ctx, account.ParentOrg.GetID().String()).
 			Return(mockResponse, nil).MaxTimes(1)
 
 		// Call method being tested
@@ -76,7 +77,7 @@ func TestAccountFieldResolver_ParentOrg(t *testing.T) {
 		// Set up expected behavior for error case
 		expectedError := errors.New("failed to fetch resource")
 		mockPermitService.EXPECT().
-			GetSingleResource(ctx, "GET", gomock.Any()).
+			GetResourceInstance(ctx, account.ParentOrg.GetID().String()).
 			Return(nil, expectedError).MaxTimes(1)
 
 		// Call method being tested
@@ -90,14 +91,13 @@ func TestAccountFieldResolver_ParentOrg(t *testing.T) {
 
 	t.Run("Error_Invalid_Attributes", func(t *testing.T) {
 		// Mock response data without attributes
-		mockResponse := map[string]interface{}{
-			"key":  account.ParentOrg.GetID().String(),
-			"name": "Test Org",
-		}
+		mockResponse := &permit.ResourceInstance{Entity: permit.Entity{
+			Key: account.ParentOrg.GetID().String(),
+		}}
 
 		// Set up expected behavior
 		mockPermitService.EXPECT().
-			GetSingleResource(ctx, "GET", gomock.Any()).
+			GetResourceInstance(ctx, account.ParentOrg.GetID().String()).
 			Return(mockResponse, nil).MaxTimes(1)
 
 		// Call method being tested
@@ -139,7 +139,7 @@ func TestAccountFieldResolver_Tenant(t *testing.T) {
 
 		// Set up expected behavior
 		mockPermitService.EXPECT().
-			SendRequest(gomock.Any(), "GET", "tenants/"+tenantID.String(), nil).
+			GetTenant(gomock.Any(), tenantID.String()).
 			Return(mockTenantResponse, nil).MaxTimes(1)
 
 		// Call method being tested
@@ -157,7 +157,7 @@ func TestAccountFieldResolver_Tenant(t *testing.T) {
 		// Set up expected behavior for error case
 		expectedError := errors.New("failed to fetch tenant")
 		mockPermitService.EXPECT().
-			SendRequest(gomock.Any(), "GET", "tenants/"+tenantID.String(), nil).
+			GetTenant(gomock.Any(), tenantID.String()).
 			Return(nil, expectedError).MaxTimes(1)
 
 		// Call method being tested
@@ -171,14 +171,14 @@ func TestAccountFieldResolver_Tenant(t *testing.T) {
 
 	t.Run("Error_Mapping_Tenant", func(t *testing.T) {
 		// Mock response data with invalid format to cause mapping error
-		mockInvalidResponse := map[string]interface{}{
-			"id":   "not-a-valid-uuid", // This will cause mapping error
-			"name": "Invalid Tenant",
+		mockInvalidResponse := &permit.Tenant{
+			Entity: permit.Entity{ID: "not-a-valid-uuid"}, // Missing key will cause mapping error
+			Name:   "Invalid Tenant",
 		}
 
 		// Set up expected behavior
 		mockPermitService.EXPECT().
-			SendRequest(gomock.Any(), "GET", "tenants/"+tenantID.String(), nil).
+			GetTenant(gomock.Any(), tenantID.String()).
 			Return(mockInvalidResponse, nil).MaxTimes(1)
 
 		// Call method being tested
@@ -218,9 +218,9 @@ func TestAccountFieldResolver_AccountOwner(t *testing.T) {
 		// Mock response data for user
 		mockUserResponse := buildTestUserData(ownerID)
 
-		// Set up expected behavior - use GetSingleResource instead of SendRequest
+		// Set up expected behavior
 		mockPermitService.EXPECT().
-			GetSingleResource(ctx, "GET", "users/"+ownerID.String()).
+			GetUser(ctx, ownerID.String()).
 			Return(mockUserResponse, nil).MaxTimes(1)
 
 		// Call method being tested
@@ -235,10 +235,10 @@ func TestAccountFieldResolver_AccountOwner(t *testing.T) {
 	})
 
 	t.Run("Error_Fetching_User", func(t *testing.T) {
-		// Set up expected behavior for error case - use GetSingleResource instead of SendRequest
+		// Set up expected behavior for error case
 		expectedError := errors.New("failed to fetch user")
 		mockPermitService.EXPECT().
-			GetSingleResource(ctx, "GET", "users/"+ownerID.String()).
+			GetUser(ctx, ownerID.String()).
 			Return(nil, expectedError).MaxTimes(1)
 
 		// Call method being tested
@@ -252,37 +252,38 @@ func TestAccountFieldResolver_AccountOwner(t *testing.T) {
 }
 
 // Helper function to build test tenant data
-func buildTestTenantData(id uuid.UUID) map[string]interface{} {
-	return map[string]interface{}{
-		"key":        id.String(),
-		"name":       "Test Tenant",
-		"created_at": time.Now().String(),
-		"updated_at": time.Now().String(),
-		"attributes": map[string]interface{}{
-			"id":          id.String(),
-			"name":        "Test Tenant",
-			"description": "Test Tenant Description",
-			"tenantId":    id.String(),
-			"parentId":    uuid.New().String(),
-			"createdBy":   uuid.New().String(),
-			"updatedBy":   uuid.New().String(),
-			"contactInfo": map[string]interface{}{
-				"email":       "test@example.com",
-				"phoneNumber": "123-456-7890",
-				"address": map[string]interface{}{
-					"street":  "123 Test St",
-					"city":    "Test City",
-					"state":   "TS",
-					"zipcode": "12345",
-					"country": "US",
+func buildTestTenantData(id uuid.UUID) *permit.Tenant {
+	return &permit.Tenant{
+		Entity: permit.Entity{
+			Key:       id.String(),
+			CreatedAt: time.Now().String(),
+			UpdatedAt: time.Now().String(),
+			Attributes: map[string]interface{}{
+				"id":          id.String(),
+				"name":        "Test Tenant",
+				"description": "Test Tenant Description",
+				"tenantId":    id.String(),
+				"parentId":    uuid.New().String(),
+				"createdBy":   uuid.New().String(),
+				"updatedBy":   uuid.New().String(),
+				"contactInfo": map[string]interface{}{
+					"email":       "test@example.com",
+					"phoneNumber": "123-456-7890",
+					"address": map[string]interface{}{
+						"street":  "123 Test St",
+						"city":    "Test City",
+						"state":   "TS",
+						"zipcode": "12345",
+						"country": "US",
+					},
 				},
 			},
 		},
+		Name: "Test Tenant",
 	}
 }
 
-func buildTestClientOrganizationData(id uuid.UUID) map[string]interface{} {
-	result := make(map[string]interface{}, 0)
+func buildTestClientOrganizationData(id uuid.UUID) *permit.ResourceInstance {
 	attributes := make(map[string]interface{}, 0)
 	attributes["tenantId"] = id.String()
 	attributes["parentOrgId"] = "parentId"
@@ -297,26 +298,26 @@ func buildTestClientOrganizationData(id uuid.UUID) map[string]interface{} {
 	attributes["name"] = "Test Org"
 	attributes["type"] = "ClientOrganizationUnit"
 	attributes["key"] = id.String()
-	result["key"] = id.String()
-	result["name"] = "Test Org"
-	result["attributes"] = attributes
 
-	return result
+	return &permit.ResourceInstance{Entity: permit.Entity{
+		Key:        id.String(),
+		Attributes: attributes,
+	}}
 }
 
 // Helper function to build test user data
-func buildTestUserData(id uuid.UUID) map[string]interface{} {
-	return map[string]interface{}{
-		"key":        id.String(),
-		"first_name": "Test",
-		"last_name":  "User",
-		"email":      "test@example.com",
-		"created_at": time.Now().String(),
-		"updated_at": time.Now().String(),
-		"associated_tenants": []interface{}{
-			map[string]interface{}{
-				"tenant": uuid.New().String(),
-			},
+func buildTestUserData(id uuid.UUID) *permit.User {
+	return &permit.User{
+		Entity: permit.Entity{
+			Key:       id.String(),
+			CreatedAt: time.Now().String(),
+			UpdatedAt: time.Now().String(),
+		},
+		FirstName: "Test",
+		LastName:  "User",
+		Email:     "test@example.com",
+		AssociatedTenants: []permit.UserTenant{
+			{Tenant: uuid.New().String()},
 		},
 	}
 }
@@ -5,26 +5,18 @@ import (
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/tags"
 	"iam_services_main_v1/pkg/logger"
-)
 
-// MapAccountsResponseToStruct maps a response containing resource data to a slice of models.Data structs.
-func MapAccountsResponseToStruct(resourcesResponse map[string]interface{}) ([]models.Data, error) {
-	rawData, ok := resourcesResponse["data"].([]interface{})
-	if !ok {
-		logger.LogError("invalid data field in resourcesResponse")
-		return nil, fmt.Errorf("missing or invalid data field")
-	}
+	"github.com/google/uuid"
+)
 
+// MapAccountsResponseToStruct maps a list of Permit resource instances to a slice of models.Data structs.
+func MapAccountsResponseToStruct(resourcesResponse []permit.ResourceInstance) ([]models.Data, error) {
 	var accounts []models.Data
-	for _, item := range rawData {
-		accountData, ok := item.(map[string]interface{})
-		if !ok {
-			logger.LogError("invalid account data format in response")
-			continue
-		}
-		account, err := mapAccountData(accountData)
+	for i := range resourcesResponse {
+		account, err := mapAccountData(&resourcesResponse[i])
 		if err != nil {
 			logger.LogError("error mapping account data", "error", err)
 			return nil, err
@@ -34,8 +26,8 @@ func MapAccountsResponseToStruct(resourcesResponse map[string]interface{}) ([]mo
 	return accounts, nil
 }
 
-// MapAccountResponseToStruct maps a resource response represented as a map to a slice of models.Data.
-func MapAccountResponseToStruct(resourceResponse map[string]interface{}) ([]models.Data, error) {
+// MapAccountResponseToStruct maps a single Permit resource instance to a slice of models.Data.
+func MapAccountResponseToStruct(resourceResponse *permit.ResourceInstance) ([]models.Data, error) {
 	account, err := mapAccountData(resourceResponse)
 	if err != nil {
 		logger.LogError("error mapping account data in MapAccountResponseToStruct", "error", err)
@@ -44,18 +36,21 @@ func MapAccountResponseToStruct(resourceResponse map[string]interface{}) ([]mode
 	return []models.Data{account}, nil
 }
 
-// mapAccountData maps a resource response to an Account model.
-func mapAccountData(accountData map[string]interface{}) (*models.Account, error) {
-	id, err := helpers.GetUUID(accountData, "key")
+// mapAccountData maps a resource instance to an Account model.
+func mapAccountData(accountData *permit.ResourceInstance) (*models.Account, error) {
+	if accountData == nil {
+		return nil, fmt.Errorf("missing account data")
+	}
+	id, err := uuid.Parse(accountData.Key)
 	if err != nil {
 		logger.LogError("failed to get UUID from account data", "error", err)
-		return nil, err
+		return nil, fmt.Errorf("invalid or missing UUID for key: key")
 	}
 
-	attributes, err := helpers.GetMap(accountData, "attributes")
-	if err != nil {
-		logger.LogError("failed to get attributes map from account data", "error", err)
-		return nil, err
+	attributes := accountData.Attributes
+	if attributes == nil {
+		logger.LogError("failed to get attributes map from account data", "error", "missing or invalid map for key: attributes")
+		return nil, fmt.Errorf("missing or invalid map for key: attributes")
 	}
 	accountOwnerId, _ := helpers.GetUUID(attributes, "accountOwnerId")
 	parentId, _ := helpers.GetUUID(attributes, "parentId")
@@ -74,10 +69,8 @@ func mapAccountData(accountData map[string]interface{}) (*models.Account, error)
 	} else {
 		parentOrg = &models.ClientOrganizationUnit{ID: parentId}
 	}
-	createdAt := helpers.GetString(accountData, "created_at")
-	createdAt, _ = helpers.ConvertToZFormat(createdAt)
-	updatedAt := helpers.GetString(accountData, "updated_at")
-	updatedAt, _ = helpers.ConvertToZFormat(updatedAt)
+	createdAt, _ := helpers.ConvertToZFormat(accountData.CreatedAt)
+	updatedAt, _ := helpers.ConvertToZFormat(accountData.UpdatedAt)
 	return &models.Account{
 		ID:           id,
 		Type:         config.Account,
@@ -1,6 +1,7 @@
 package accounts
 
 import (
+	"iam_services_main_v1/internal/permit"
 	"testing"
 	"time"
 
@@ -11,15 +12,11 @@ import (
 func TestMapAccountsResponseToStruct(t *testing.T) {
 	// Create valid test data
 	validID := uuid.New()
-	validData := map[string]interface{}{
-		"data": []interface{}{
-			buildTestAccountData(validID),
-		},
-	}
+	validData := []permit.ResourceInstance{*buildTestAccountData(validID)}
 
 	tests := []struct {
 		name    string
-		input   map[string]interface{}
+		input   []permit.ResourceInstance
 		wantErr bool
 	}{
 		{
@@ -30,27 +27,18 @@ func TestMapAccountsResponseToStruct(t *testing.T) {
 		{
 			name:    "Nil input",
 			input:   nil,
-			wantErr: true,
-		},
-		{
-			name: "Missing data field",
-			input: map[string]interface{}{
-				"wrong": "data",
-			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
-			name: "Invalid data format",
-			input: map[string]interface{}{
-				"data": "not an array",
+			name: "Invalid key field",
+			input: []permit.ResourceInstance{
+				{Entity: permit.Entity{Key: "not-a-uuid"}},
 			},
 			wantErr: true,
 		},
 		{
-			name: "Empty data array",
-			input: map[string]interface{}{
-				"data": []interface{}{},
-			},
+			name:    "Empty data array",
+			input:   []permit.ResourceInstance{},
 			wantErr: false,
 		},
 	}
@@ -63,10 +51,7 @@ func TestMapAccountsResponseToStruct(t *testing.T) {
 				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
-				if tt.input != nil && len(tt.input["data"].([]interface{})) > 0 {
-					assert.NotNil(t, result)
-					assert.Len(t, result, len(tt.input["data"].([]interface{})))
-				}
+				assert.Len(t, result, len(tt.input))
 			}
 		})
 	}
@@ -79,7 +64,7 @@ func TestMapAccountResponseToStruct(t *testing.T) {
 
 	tests := []struct {
 		name    string
-		input   map[string]interface{}
+		input   *permit.ResourceInstance
 		wantErr bool
 	}{
 		{
@@ -93,17 +78,13 @@ func TestMapAccountResponseToStruct(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "Invalid key field",
-			input: map[string]interface{}{
-				"key": "not-a-uuid",
-			},
+			name:    "Invalid key field",
+			input:   &permit.ResourceInstance{Entity: permit.Entity{Key: "not-a-uuid"}},
 			wantErr: true,
 		},
 		{
-			name: "Missing attributes",
-			input: map[string]interface{}{
-				"key": validID.String(),
-			},
+			name:    "Missing attributes",
+			input:   &permit.ResourceInstance{Entity: permit.Entity{Key: validID.String()}},
 			wantErr: true,
 		},
 	}
@@ -129,7 +110,7 @@ func TestMapAccountData(t *testing.T) {
 
 	tests := []struct {
 		name    string
-		input   map[string]interface{}
+		input   *permit.ResourceInstance
 		wantErr bool
 	}{
 		{
@@ -143,17 +124,13 @@ func TestMapAccountData(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "Invalid key field",
-			input: map[string]interface{}{
-				"key": "not-a-uuid",
-			},
+			name:    "Invalid key field",
+			input:   &permit.ResourceInstance{Entity: permit.Entity{Key: "not-a-uuid"}},
 			wantErr: true,
 		},
 		{
-			name: "Missing attributes",
-			input: map[string]interface{}{
-				"key": validID.String(),
-			},
+			name:    "Missing attributes",
+			input:   &permit.ResourceInstance{Entity: permit.Entity{Key: validID.String()}},
 			wantErr: true,
 		},
 	}
@@ -302,13 +279,12 @@ func TestMapBillingAddress(t *testing.T) {
 }
 
 // Helper function to build test account data
-func buildTestAccountData(id uuid.UUID) map[string]interface{} {
-	return map[string]interface{}{
-		"key":        id.String(),
-		"name":       "Test Account",
-		"created_at": time.Now().String(),
-		"updated_at": time.Now().String(),
-		"attributes": map[string]interface{}{
+func buildTestAccountData(id uuid.UUID) *permit.ResourceInstance {
+	return &permit.ResourceInstance{Entity: permit.Entity{
+		Key:       id.String(),
+		CreatedAt: time.Now().String(),
+		UpdatedAt: time.Now().String(),
+		Attributes: map[string]interface{}{
 			"id":          id.String(),
 			"name":        "Test Account",
 			"description": "Test Account Description",
@@ -330,5 +306,5 @@ func buildTestAccountData(id uuid.UUID) map[string]interface{} {
 				},
 			},
 		},
-	}
+	}}
 }
@@ -6,6 +6,8 @@ import (
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/events"
 	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	"iam_services_main_v1/pkg/logger"
@@ -19,6 +21,8 @@ import (
 // AccountMutationResolver handles GraphQL mutations for account-related operations using GORM DB and Permit client
 type AccountMutationResolver struct {
 	PC permit.PermitService
+	// Events receives the changes made, for subscriptions. It may be nil.
+	Events *events.Bus
 }
 
 // CreateAccount creates a new account based on the provided input.
@@ -35,36 +39,38 @@ func (r *AccountMutationResolver) CreateAccount(ctx context.Context, input model
 	// Get the user ID and tenant ID from the context, which are required for account creation
 	tenantID, err := helpers.GetTenantID(ctx)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get tenant ID", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get tenant ID", err), nil
 	}
 
 	//Map and validate the account creation input data
 	_, err = ValidateCreateAccountInput(input)
 	if err != nil {
 		logger.LogError("error occurred during input mapping and validation", "error", err)
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to do input mapping and validation", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to do input mapping and validation", err), nil
 	}
 
 	// Prepare metadata for the account from the input data
 	metadata, err := r.prepareMetadata(ctx, input)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to prepare metadata in create account", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to prepare metadata in create account", err), nil
 	}
 
 	// Create the resource instances in the permit system with provided metadata
 	err = r.createResourceInstances(ctx, input, tenantID, metadata)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to create the resource instances", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to create the resource instances", err), nil
 	}
 
 	// Create relationship tuples in the permit system to establish parent-child relationships
 	err = r.createRelationshipTuples(ctx, input, tenantID)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to create the relationship tuples", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to create the relationship tuples", err), nil
 	}
 
 	// format the success response
-	return r.formatSuccessResponse(ctx, input.ID)
+	res, err := r.formatSuccessResponse(ctx, input.ID)
+	r.publish(ctx, models.ChangeTypeEnumCreated, input.ID, res)
+	return res, err
 }
 
 // UpdateAccount processes an account update operation by:
@@ -90,22 +96,24 @@ func (r *AccountMutationResolver) UpdateAccount(ctx context.Context, input model
 	// Fetch existing account data
 	existingAccount, err := r.getExistingAccount(ctx, input.ID)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get existing account data", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get existing account data", err), nil
 	}
 
 	// Merge existing data with updates
 	updatedMetadata, err := r.mergeAccountData(ctx, existingAccount, input)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to prepare metadata in update account", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to prepare metadata in update account", err), nil
 	}
 
 	// Update in Permit system
 	if err := r.updatePermitResource(ctx, input.ID, updatedMetadata); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to update account in Permit", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to update account in Permit", err), nil
 	}
 
 	// format the success response
-	return r.formatSuccessResponse(ctx, input.ID)
+	res, err := r.formatSuccessResponse(ctx, input.ID)
+	r.publish(ctx, models.ChangeTypeEnumUpdated, input.ID, res)
+	return res, err
 }
 
 // DeleteAccount performs a deletion operation for an account.
@@ -128,24 +136,22 @@ func (r *AccountMutationResolver) UpdateAccount(ctx context.Context, input model
 func (r *AccountMutationResolver) DeleteAccount(ctx context.Context, input models.DeleteInput) (models.OperationResult, error) {
 	logger.LogInfo("Started the delete account operation")
 
-	resourceURL := fmt.Sprintf("resource_instances/%s", input.ID)
-	accountResource, err := r.PC.SendRequest(ctx, "GET", resourceURL, nil)
+	accountResource, err := r.PC.GetResourceInstance(ctx, input.ID.String())
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get existing account data", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get existing account data", err), nil
 	}
 	if accountResource == nil {
-		return utils.FormatErrorResponse(http.StatusNotFound, "Account not found", "The account with the provided ID does not exist"), nil
+		return errorcatalog.Account.New(http.StatusNotFound, "Account not found", "The account with the provided ID does not exist"), nil
 	}
-	resourceType := helpers.GetString(accountResource, "resource")
-	if resourceType != config.AccountResourceTypeID {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "The provided account ID does not match the expected resource type for deletion", "The provided account ID does not match the expected resource type for deletion"), nil
+	if accountResource.Resource != config.AccountResourceTypeID {
+		return errorcatalog.Account.New(http.StatusBadRequest, "The provided account ID does not match the expected resource type for deletion", "The provided account ID does not match the expected resource type for deletion"), nil
 	}
 
 	// Delete the resource instance from Permit
-	_, err = r.PC.SendRequest(ctx, "DELETE", fmt.Sprintf("resource_instances/%s", input.ID), map[string]interface{}{})
-	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to delete account in Permit", err.Error()), nil
+	if err := r.PC.DeleteResourceInstance(ctx, input.ID.String()); err != nil {
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to delete account in Permit", err), nil
 	}
+	r.publish(ctx, models.ChangeTypeEnumDeleted, input.ID, nil)
 
 	// Format success response
 	response, _ := utils.FormatSuccessResponse([]models.Data{})
@@ -217,11 +223,11 @@ func (r *AccountMutationResolver) prepareMetadata(ctx context.Context, account m
 // createResourceInstances creates resource instances for a given account by making a POST request to the resource_instances endpoint
 func (r *AccountMutationResolver) createResourceInstances(ctx context.Context, input models.CreateAccountInput, tenantID *uuid.UUID, metadata map[string]interface{}) error {
 	AccountResourceTypeID, _ := uuid.Parse(config.AccountResourceTypeID)
-	_, err := r.PC.SendRequest(ctx, "POST", "resource_instances", map[string]interface{}{
-		"key":        input.ID,
-		"resource":   AccountResourceTypeID.String(),
-		"tenant":     tenantID.String(),
-		"attributes": metadata,
+	_, err := r.PC.CreateResourceInstance(ctx, permit.ResourceInstanceCreate{
+		Key:        input.ID.String(),
+		Resource:   AccountResourceTypeID.String(),
+		Tenant:     tenantID.String(),
+		Attributes: metadata,
 	})
 
 	if err != nil {
@@ -239,8 +245,7 @@ func (r *AccountMutationResolver) createRelationshipTuples(ctx context.Context,
 	if strings.ToLower(string(input.RelationType)) == "parent" {
 		subjectType = config.ClientOrgUnitResourceTypeID + ":" + input.ParentID.String()
 		objectType = config.AccountResourceTypeID + ":" + input.ID.String()
-		resourceURL := fmt.Sprintf("resource_instances/%s", input.ParentID)
-		resourceResponse, err := r.PC.SendRequest(ctx, "GET", resourceURL, nil)
+		resourceResponse, err := r.PC.GetResourceInstance(ctx, input.ParentID.String())
 		if err != nil {
 			return err
 		}
@@ -248,15 +253,14 @@ func (r *AccountMutationResolver) createRelationshipTuples(ctx context.Context,
 			return fmt.Errorf("parent resource not found for ID: %s", input.ParentID)
 		}
 
-		if helpers.GetString(resourceResponse, "resource") != config.ClientOrgUnitResourceTypeID {
-			return fmt.Errorf("parent resource type mismatch: expected %s, got %s", config.ClientOrgUnitResourceTypeID, helpers.GetString(resourceResponse, "resource"))
+		if resourceResponse.Resource != config.ClientOrgUnitResourceTypeID {
+			return fmt.Errorf("parent resource type mismatch: expected %s, got %s", config.ClientOrgUnitResourceTypeID, resourceResponse.Resource)
 		}
 
 	} else {
 		subjectType = config.AccountResourceTypeID + ":" + input.ID.String()
 		objectType = config.TenantResourceTypeID + ":" + input.ParentID.String()
-		resourceURL := fmt.Sprintf("resource_instances/%s", input.ParentID)
-		resourceResponse, err := r.PC.SendRequest(ctx, "GET", resourceURL, nil)
+		resourceResponse, err := r.PC.GetResourceInstance(ctx, input.ParentID.String())
 		if err != nil {
 			return err
 		}
@@ -264,15 +268,15 @@ func (r *AccountMutationResolver) createRelationshipTuples(ctx context.Context,
 			return fmt.Errorf("parent resource not found for ID: %s", input.ParentID)
 		}
 
-		if helpers.GetString(resourceResponse, "resource") != config.TenantResourceTypeID {
-			return fmt.Errorf("parent resource type mismatch: expected %s, got %s", config.TenantResourceTypeID, helpers.GetString(resourceResponse, "resource"))
+		if resourceResponse.Resource != config.TenantResourceTypeID {
+			return fmt.Errorf("parent resource type mismatch: expected %s, got %s", config.TenantResourceTypeID, resourceResponse.Resource)
 		}
 	}
-	_, err := r.PC.SendRequest(ctx, "POST", "relationship_tuples", map[string]interface{}{
-		"object":   objectType,
-		"relation": strings.ToLower(string(input.RelationType)),
-		"subject":  subjectType,
-		"tenant":   tenantID.String(),
+	_, err := r.PC.CreateRelationshipTuple(ctx, permit.RelationshipTupleCreate{
+		Object:   objectType,
+		Relation: strings.ToLower(string(input.RelationType)),
+		Subject:  subjectType,
+		Tenant:   tenantID.String(),
 	})
 
 	if err != nil {
@@ -287,26 +291,33 @@ func (r *AccountMutationResolver) createRelationshipTuples(ctx context.Context,
 func (r *AccountMutationResolver) formatSuccessResponse(ctx context.Context, id uuid.UUID) (models.OperationResult, error) {
 	account, err := r.getAccountById(ctx, id)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get the account details by id", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get the account details by id", err), nil
 	}
 	return account, nil
 }
 
+// publish reports a change of the account with the given ID in the caller's tenant to subscribers
+func (r *AccountMutationResolver) publish(ctx context.Context, change models.ChangeTypeEnum, id uuid.UUID, res models.OperationResult) {
+	tenantID, err := helpers.GetTenantID(ctx)
+	if err != nil {
+		return
+	}
+	r.Events.OrganizationChanged(change, config.Account, *tenantID, id, res)
+}
+
 // getExistingAccount fetches an existing account's attributes from Permit using the provided ID and returns them as a map
 func (r *AccountMutationResolver) getExistingAccount(ctx context.Context, id uuid.UUID) (map[string]interface{}, error) {
-	resourceURL := fmt.Sprintf("resource_instances/%s", id)
-	accountResource, err := r.PC.SendRequest(ctx, "GET", resourceURL, nil)
+	accountResource, err := r.PC.GetResourceInstance(permit.WithFreshReads(ctx), id.String())
 	if err != nil {
 		logger.LogError("Failed to fetch account from Permit", "error", err)
 		return nil, fmt.Errorf("failed to fetch account: %w", err)
 	}
 
-	attributes, err := helpers.GetMap(accountResource, "attributes")
-	if err != nil {
-		logger.LogError("Invalid account data structure", "error", err)
-		return nil, fmt.Errorf("invalid account data structure: %s", err)
+	if accountResource.Attributes == nil {
+		logger.LogError("Invalid account data structure", "error", "missing attributes")
+		return nil, fmt.Errorf("invalid account data structure: missing or invalid map for key: attributes")
 	}
-	return attributes, nil
+	return accountResource.Attributes, nil
 }
 
 // mergeAccountData combines existing account data with new input data, updating only provided fields
@@ -414,9 +425,8 @@ func (r *AccountMutationResolver) mergeBillingAddress(existing map[string]interf
 
 // updatePermitResource updates metadata attributes for a resource instance in Permit with the given ID
 func (r *AccountMutationResolver) updatePermitResource(ctx context.Context, id uuid.UUID, metadata map[string]interface{}) error {
-	resourceURL := fmt.Sprintf("resource_instances/%s", id)
-	_, err := r.PC.SendRequest(ctx, "PATCH", resourceURL, map[string]interface{}{
-		"attributes": metadata,
+	_, err := r.PC.UpdateResourceInstance(ctx, id.String(), permit.ResourceInstanceUpdate{
+		Attributes: metadata,
 	})
 	if err != nil {
 		logger.LogError("Failed to update resource in Permit", "error", err)
@@ -431,7 +441,7 @@ func (r *AccountMutationResolver) getAccountById(ctx context.Context, accountID
 	accountResolver := &AccountQueryResolver{PC: r.PC}
 	data, err := accountResolver.Account(ctx, accountID)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get the account details by id from permit", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get the account details by id from permit", err), nil
 	}
 	return data, nil
 }
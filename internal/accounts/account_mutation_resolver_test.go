@@ -5,6 +5,8 @@ import (
 	"errors"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	mocks "iam_services_main_v1/mocks"
 	"testing"
@@ -67,7 +69,7 @@ func TestCreateAccount(t *testing.T) {
 			ctx:       validCtx,
 			input:     invalidInput,
 			mockSetup: func() {},
-			output:    buildErrorResponse(400, "Failed to do input mapping and validation", "Invalid input data"),
+			output:    errorcatalog.Account.New(400, "Failed to do input mapping and validation", "Invalid input data"),
 		},
 		{
 			name:  "Missing tenant ID in context",
@@ -76,7 +78,7 @@ func TestCreateAccount(t *testing.T) {
 			mockSetup: func() {
 				// No mock calls expected
 			},
-			output: buildErrorResponse(400, "Failed to get tenant ID", "error getting tenant ID from context"),
+			output: errorcatalog.Account.New(400, "Failed to get tenant ID", "error getting tenant ID from context"),
 		},
 		{
 			name:  "Missing user ID in context",
@@ -85,7 +87,7 @@ func TestCreateAccount(t *testing.T) {
 			mockSetup: func() {
 				// No mock calls expected
 			},
-			output: buildErrorResponse(400, "Failed to prepare metadata in create account", "error getting user ID from context"),
+			output: errorcatalog.Account.New(400, "Failed to prepare metadata in create account", "error getting user ID from context"),
 		},
 		{
 			name:  "Error creating resource instance",
@@ -93,10 +95,10 @@ func TestCreateAccount(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", "resource_instances", mock.Any()).
+					CreateResourceInstance(mock.Any(), mock.Any()).
 					Return(nil, errors.New("resource instance error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Failed to create the resource instances", "resource instance error"),
+			output: errorcatalog.Account.New(400, "Failed to create the resource instances", "resource instance error"),
 		},
 		{
 			name:  "Error creating relationship tuples",
@@ -104,14 +106,14 @@ func TestCreateAccount(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", "resource_instances", mock.Any()).
-					Return(map[string]interface{}{"key": validID.String()}, nil).MaxTimes(1)
+					CreateResourceInstance(mock.Any(), mock.Any()).
+					Return(&permit.ResourceInstance{Entity: permit.Entity{Key: validID.String()}}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", "relationship_tuples", mock.Any()).
+					CreateRelationshipTuple(mock.Any(), mock.Any()).
 					Return(nil, errors.New("relationship tuples error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Failed to create the relationship tuples", "relationship tuples error"),
+			output: errorcatalog.Account.New(400, "Failed to create the relationship tuples", "relationship tuples error"),
 		},
 		{
 			name:  "Error getting created account",
@@ -119,18 +121,18 @@ func TestCreateAccount(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", "resource_instances", mock.Any()).
-					Return(map[string]interface{}{"key": validID.String()}, nil).MaxTimes(1)
+					CreateResourceInstance(mock.Any(), mock.Any()).
+					Return(&permit.ResourceInstance{Entity: permit.Entity{Key: validID.String()}}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", "relationship_tuples", mock.Any()).
-					Return(map[string]interface{}{"created": true}, nil).MaxTimes(1)
+					CreateRelationshipTuple(mock.Any(), mock.Any()).
+					Return(&permit.RelationshipTuple{}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetResourceInstance(mock.Any(), mock.Any()).
 					Return(nil, errors.New("get account error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Failed to get the account details by id", "get account error"),
+			output: errorcatalog.Account.New(400, "Failed to get the account details by id", "get account error"),
 		},
 		{
 			name:  "Success",
@@ -138,15 +140,15 @@ func TestCreateAccount(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", "resource_instances", mock.Any()).
-					Return(map[string]interface{}{"key": validID.String()}, nil).MaxTimes(1)
+					CreateResourceInstance(mock.Any(), mock.Any()).
+					Return(&permit.ResourceInstance{Entity: permit.Entity{Key: validID.String()}}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", "relationship_tuples", mock.Any()).
-					Return(map[string]interface{}{"created": true}, nil).MaxTimes(1)
+					CreateRelationshipTuple(mock.Any(), mock.Any()).
+					Return(&permit.RelationshipTuple{}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetResourceInstance(mock.Any(), mock.Any()).
 					Return(account, nil).MaxTimes(1)
 			},
 			output: buildSuccessResponse(mappedAccount),
@@ -221,10 +223,10 @@ func TestUpdateAccount(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetResourceInstance(mock.Any(), mock.Any()).
 					Return(nil, errors.New("get account error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Failed to get existing account data", "get account error"),
+			output: errorcatalog.Account.New(400, "Failed to get existing account data", "get account error"),
 		},
 		{
 			name:  "Error updating account in permit",
@@ -232,14 +234,14 @@ func TestUpdateAccount(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetResourceInstance(mock.Any(), mock.Any()).
 					Return(accountData, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "PATCH", mock.Any(), mock.Any()).
+					UpdateResourceInstance(mock.Any(), validID.String(), mock.Any()).
 					Return(nil, errors.New("update error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Failed to update account in Permit", "update error"),
+			output: errorcatalog.Account.New(400, "Failed to update account in Permit", "update error"),
 		},
 		{
 			name:  "Error getting updated account",
@@ -248,20 +250,20 @@ func TestUpdateAccount(t *testing.T) {
 			mockSetup: func() {
 				// First call for getExistingAccount
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetResourceInstance(mock.Any(), mock.Any()).
 					Return(accountData, nil).MaxTimes(1)
 
 				// Call for updating account - use PATCH instead of GET
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "PATCH", mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"updated": true}, nil).MaxTimes(1)
+					UpdateResourceInstance(mock.Any(), validID.String(), mock.Any()).
+					Return(accountData, nil).MaxTimes(1)
 
 				// Second call for getAccountById
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetResourceInstance(mock.Any(), mock.Any()).
 					Return(nil, errors.New("get updated account error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Failed to get the account details by id", "get updated account error"),
+			output: errorcatalog.Account.New(400, "Failed to get the account details by id", "get updated account error"),
 		},
 		{
 			name:  "Success",
@@ -270,17 +272,17 @@ func TestUpdateAccount(t *testing.T) {
 			mockSetup: func() {
 				// First call for getExistingAccount
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetResourceInstance(mock.Any(), mock.Any()).
 					Return(accountData, nil).MaxTimes(1)
 
 				// Call for updating account - use PATCH instead of GET
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "PATCH", mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"updated": true}, nil).MaxTimes(1)
+					UpdateResourceInstance(mock.Any(), validID.String(), mock.Any()).
+					Return(accountData, nil).MaxTimes(1)
 
 				// Second call for getAccountById
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetResourceInstance(mock.Any(), mock.Any()).
 					Return(accountData, nil).MaxTimes(1)
 			},
 			output: buildSuccessResponse(mappedAccount),
@@ -316,6 +318,9 @@ func TestDeleteAccount(t *testing.T) {
 	validID := uuid.New()
 	validInput := models.DeleteInput{ID: validID}
 
+	accountData := buildTestAccountsData()
+	accountData.Resource = config.AccountResourceTypeID
+
 	testCases := []struct {
 		name      string
 		ctx       context.Context
@@ -329,10 +334,14 @@ func TestDeleteAccount(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(nil, errors.New("delete error"))
+					GetResourceInstance(mock.Any(), validID.String()).
+					Return(accountData, nil)
+
+				mockService.EXPECT().
+					DeleteResourceInstance(mock.Any(), validID.String()).
+					Return(errors.New("delete error"))
 			},
-			output: buildErrorResponse(400, "Failed to delete account in Permit", "delete error"),
+			output: errorcatalog.Account.New(400, "Failed to delete account in Permit", "delete error"),
 		},
 		{
 			name:  "Success",
@@ -340,8 +349,12 @@ func TestDeleteAccount(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"deleted": true}, nil)
+					GetResourceInstance(mock.Any(), validID.String()).
+					Return(accountData, nil)
+
+				mockService.EXPECT().
+					DeleteResourceInstance(mock.Any(), validID.String()).
+					Return(nil)
 			},
 			output: buildSuccessResponse([]models.Data{}),
 		},
@@ -535,10 +548,6 @@ func buildSuccessResponse(data []models.Data) models.OperationResult {
 	return response
 }
 
-func buildErrorResponse(statusCode int, message string, details string) models.OperationResult {
-	return utils.FormatErrorResponse(statusCode, message, details)
-}
-
 func prepareValidAccountInput() models.CreateAccountInput {
 	description := "Test Description"
 	parentID := uuid.New()
@@ -575,14 +584,13 @@ func prepareValidAccountInput() models.CreateAccountInput {
 	}
 }
 
-func buildTestAccountsData() map[string]interface{} {
+func buildTestAccountsData() *permit.ResourceInstance {
 	id := uuid.New()
-	return map[string]interface{}{
-		"key":        id.String(),
-		"name":       "Test Account",
-		"created_at": "2023-01-01T00:00:00Z",
-		"updated_at": "2023-01-01T00:00:00Z",
-		"attributes": map[string]interface{}{
+	return &permit.ResourceInstance{Entity: permit.Entity{
+		Key:       id.String(),
+		CreatedAt: "2023-01-01T00:00:00Z",
+		UpdatedAt: "2023-01-01T00:00:00Z",
+		Attributes: map[string]interface{}{
 			"id":          id.String(),
 			"name":        "Test Account",
 			"description": "Test Account Description",
@@ -604,5 +612,5 @@ func buildTestAccountsData() map[string]interface{} {
 				},
 			},
 		},
-	}
+	}}
 }
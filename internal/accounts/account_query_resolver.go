@@ -2,10 +2,11 @@ package accounts
 
 import (
 	"context"
-	"fmt"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/filters"
 	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	"iam_services_main_v1/pkg/logger"
@@ -19,40 +20,86 @@ type AccountQueryResolver struct {
 	PC permit.PermitService
 }
 
-// Accounts retrieves all account resources for a given tenant.
+// Accounts retrieves the account resources of a given tenant that match filter,
+// ordered by orderBy.
 // It performs the following operations:
-// 1. Extracts the tenant ID from the context
+// 1. Extracts the tenant ID from the context and validates the filter
 // 2. Queries the permit service for account resources associated with the tenant
-// 3. Maps the response data to account structures
+// 3. Maps the response data to account structures and applies the filter and ordering
 // 4. Returns formatted operation result containing the accounts
 //
 // Parameters:
 //   - ctx: The context.Context for the request
+//   - filter: Optional filter the accounts must match
+//   - orderBy: Optional ordering of the accounts
 //
 // Returns:
 //   - models.OperationResult: Contains either the accounts data or error details
 //   - error: Any error encountered during processing
-func (r *AccountQueryResolver) Accounts(ctx context.Context) (models.OperationResult, error) {
+func (r *AccountQueryResolver) Accounts(ctx context.Context, filter *models.OrganizationFilter, orderBy *models.OrganizationOrder) (models.OperationResult, error) {
 	logger.LogInfo("Fetching all accounts")
 
 	// Get tenant ID from context
 	tenantID, err := helpers.GetTenantID(ctx)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get tenant ID", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get tenant ID", err), nil
+	}
+	if err := filters.Validate(filter); err != nil {
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Invalid account filter", err), nil
 	}
 
 	// Fetch account resources from permit
-	accounts, err := r.fetchAccounts(ctx, tenantID)
+	accounts, totalCount, err := r.fetchAccounts(ctx, tenantID, filter, orderBy)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get all accounts from permit", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get all accounts from permit", err), nil
 	}
 
 	// Format success response
-	response, _ := utils.FormatSuccessResponse(accounts)
+	response, _ := utils.FormatSuccessWithTotal(accounts, totalCount)
 	return response, nil
 
 }
 
+// AccountsConnection retrieves one page of the accounts of the tenant in the
+// context that match filter, ordered by orderBy. Without a filter or ordering
+// the page is read from Permit's own paging, otherwise it is cut from the
+// filtered and ordered list.
+func (r *AccountQueryResolver) AccountsConnection(ctx context.Context, after *string, filter *models.OrganizationFilter, first *int, orderBy *models.OrganizationOrder) (models.OperationResult, error) {
+	logger.LogInfo("Fetching a page of accounts")
+
+	page, err := utils.ParsePage(first, after)
+	if err != nil {
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Invalid pagination arguments", err), nil
+	}
+
+	tenantID, err := helpers.GetTenantID(ctx)
+	if err != nil {
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get tenant ID", err), nil
+	}
+
+	if filter != nil || orderBy != nil {
+		if err := filters.Validate(filter); err != nil {
+			return errorcatalog.Account.FromError(http.StatusBadRequest, "Invalid account filter", err), nil
+		}
+		accounts, totalCount, err := r.fetchAccounts(ctx, tenantID, filter, orderBy)
+		if err != nil {
+			return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get all accounts from permit", err), nil
+		}
+		return utils.FormatConnection(utils.PageOf(accounts, page), page, totalCount), nil
+	}
+
+	accountResources, totalCount, err := r.PC.ListResourceInstancesPage(ctx, tenantID.String(), config.AccountResourceTypeID, page)
+	if err != nil {
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get all accounts from permit", err), nil
+	}
+
+	accounts, err := MapAccountsResponseToStruct(accountResources)
+	if err != nil {
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to map account resources to struct", err), nil
+	}
+	return utils.FormatConnection(accounts, page, totalCount), nil
+}
+
 // Account retrieves account details by UUID from the resource instance.
 //
 // Parameters:
@@ -75,13 +122,13 @@ func (r *AccountQueryResolver) Account(ctx context.Context, id uuid.UUID) (model
 	// Get tenant ID from context
 	_, err := helpers.GetTenantID(ctx)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get tenant ID", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get tenant ID", err), nil
 	}
 
 	// Fetch account resources from permit
 	account, err := r.fetchAccount(ctx, id)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get account resources from permit", err.Error()), nil
+		return errorcatalog.Account.FromError(http.StatusBadRequest, "Failed to get account resources from permit", err), nil
 	}
 
 	// Format success response
@@ -89,30 +136,38 @@ func (r *AccountQueryResolver) Account(ctx context.Context, id uuid.UUID) (model
 	return response, nil
 }
 
-// fetchAccounts retrieves all account resources for a given tenant from the resource instance.
-func (r *AccountQueryResolver) fetchAccounts(ctx context.Context, tenantID *uuid.UUID) ([]models.Data, error) {
+// fetchAccounts retrieves the account resources for a given tenant from the resource instance
+// that match filter, ordered by orderBy, together with their total number. Without a filter
+// the total is the one reported by Permit.
+func (r *AccountQueryResolver) fetchAccounts(ctx context.Context, tenantID *uuid.UUID, filter *models.OrganizationFilter, orderBy *models.OrganizationOrder) ([]models.Data, int, error) {
 	// Fetch all account resources from permit
-	resourceURL := fmt.Sprintf("resource_instances/detailed?tenant=%s&resource=%s",
-		tenantID.String(), config.AccountResourceTypeID)
-	accountResources, err := r.PC.SendRequest(ctx, "GET", resourceURL, nil)
+	accountResources, totalCount, err := r.PC.ListResourceInstances(ctx, tenantID.String(), config.AccountResourceTypeID)
 	if err != nil {
 		logger.LogError("Failed to get account resources from permit", "error", err)
-		return nil, err
+		return nil, 0, err
 	}
 
 	// Map account resources to struct
 	accounts, err := MapAccountsResponseToStruct(accountResources)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	// Filter and order accounts
+	accounts, err = filters.Apply(accounts, filter, orderBy)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter != nil {
+		totalCount = len(accounts)
 	}
-	return accounts, nil
+	return accounts, totalCount, nil
 }
 
 // fetchAccount retrieves account details by UUID from the resource instance.
 func (r *AccountQueryResolver) fetchAccount(ctx context.Context, id uuid.UUID) ([]models.Data, error) {
 	// Fetch account resources from permit
-	resourceURL := fmt.Sprintf("resource_instances/%s", id)
-	accountResource, err := r.PC.SendRequest(ctx, "GET", resourceURL, nil)
+	accountResource, err := r.PC.GetResourceInstance(ctx, id.String())
 	if err != nil {
 		return nil, err
 	}
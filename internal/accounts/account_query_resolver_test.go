@@ -3,9 +3,10 @@ package accounts
 import (
 	"context"
 	"errors"
-	"fmt"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	mocks "iam_services_main_v1/mocks"
 	"testing"
@@ -34,9 +35,7 @@ func TestAccounts(t *testing.T) {
 
 	// Mock account data
 	accountData := buildTestAccountData(uuid.New())
-	accountsResponse := map[string]interface{}{
-		"data": []interface{}{accountData},
-	}
+	accountsResponse := []permit.ResourceInstance{*accountData}
 	mappedAccounts, _ := MapAccountsResponseToStruct(accountsResponse)
 	successResponse, _ := utils.FormatSuccessResponse(mappedAccounts)
 
@@ -50,11 +49,9 @@ func TestAccounts(t *testing.T) {
 			name: "Success with accounts",
 			ctx:  validCtx,
 			mockSetup: func() {
-				expectedURL := fmt.Sprintf("resource_instances/detailed?tenant=%s&resource=%s",
-					fixedTenantID, config.AccountResourceTypeID)
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", expectedURL, nil).
-					Return(accountsResponse, nil)
+					ListResourceInstances(mock.Any(), fixedTenantID, config.AccountResourceTypeID).
+					Return(accountsResponse, len(accountsResponse), nil)
 			},
 			expected: successResponse,
 		},
@@ -62,34 +59,30 @@ func TestAccounts(t *testing.T) {
 			name: "Error from permit service",
 			ctx:  validCtx,
 			mockSetup: func() {
-				expectedURL := fmt.Sprintf("resource_instances/detailed?tenant=%s&resource=%s",
-					fixedTenantID, config.AccountResourceTypeID)
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", expectedURL, nil).
-					Return(nil, errors.New("permit service error"))
+					ListResourceInstances(mock.Any(), fixedTenantID, config.AccountResourceTypeID).
+					Return(nil, 0, errors.New("permit service error"))
 			},
-			expected: utils.FormatErrorResponse(400, "Failed to get all accounts from permit", "permit service error"),
+			expected: errorcatalog.Account.New(400, "Failed to get all accounts from permit", "permit service error"),
 		},
 		{
 			name: "Invalid response format",
 			ctx:  validCtx,
 			mockSetup: func() {
-				expectedURL := fmt.Sprintf("resource_instances/detailed?tenant=%s&resource=%s",
-					fixedTenantID, config.AccountResourceTypeID)
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", expectedURL, nil).
-					Return(map[string]interface{}{
-						"data": "not an array",
-					}, nil)
+					ListResourceInstances(mock.Any(), fixedTenantID, config.AccountResourceTypeID).
+					Return([]permit.ResourceInstance{
+						{Entity: permit.Entity{Key: "not a valid UUID"}},
+					}, 1, nil)
 			},
-			expected: utils.FormatErrorResponse(400, "Failed to get all accounts from permit", "missing or invalid data field"),
+			expected: errorcatalog.Account.New(400, "Failed to get all accounts from permit", "invalid or missing UUID for key: key"),
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockSetup()
-			result, _ := resolver.Accounts(tc.ctx)
+			result, _ := resolver.Accounts(tc.ctx, nil, nil)
 
 			assert.NotNil(t, result)
 			// Additional assertions could be added here
@@ -97,6 +90,40 @@ func TestAccounts(t *testing.T) {
 	}
 }
 
+func TestAccountsConnection(t *testing.T) {
+	ctrl := mock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPermitService(ctrl)
+	resolver := AccountQueryResolver{
+		PC: mockService,
+	}
+
+	fixedTenantID := "03a60027-ceec-088a-1ebd-10c657320f0f"
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", fixedTenantID)
+	validCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+
+	accountsResponse := []permit.ResourceInstance{*buildTestAccountData(uuid.New())}
+	mockService.EXPECT().
+		ListResourceInstancesPage(mock.Any(), fixedTenantID, config.AccountResourceTypeID, permit.Page{Limit: utils.DefaultPageSize}).
+		Return(accountsResponse, 1, nil)
+
+	result, err := resolver.AccountsConnection(validCtx, nil, nil, nil, nil)
+
+	assert.NoError(t, err)
+	conn, ok := result.(*models.Connection)
+	assert.True(t, ok)
+	assert.Len(t, conn.Edges, 1)
+	assert.Equal(t, 1, conn.TotalCount)
+	assert.False(t, conn.PageInfo.HasNextPage)
+
+	result, err = resolver.AccountsConnection(context.Background(), nil, nil, nil, nil)
+	assert.NoError(t, err)
+	_, ok = result.(*models.ResponseError)
+	assert.True(t, ok, "the tenant is required")
+}
+
 func TestAccount(t *testing.T) {
 	ctrl := mock.NewController(t)
 	defer ctrl.Finish()
@@ -133,7 +160,7 @@ func TestAccount(t *testing.T) {
 			id:   validID,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetResourceInstance(mock.Any(), fixedAccountID).
 					Return(accountData, nil).MaxTimes(1)
 			},
 			expected: successResponse,
@@ -143,26 +170,22 @@ func TestAccount(t *testing.T) {
 			ctx:  validCtx,
 			id:   validID,
 			mockSetup: func() {
-				expectedURL := fmt.Sprintf("resource_instances/%s", fixedAccountID)
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", expectedURL, nil).
+					GetResourceInstance(mock.Any(), fixedAccountID).
 					Return(nil, errors.New("permit service error")).MaxTimes(1)
 			},
-			expected: utils.FormatErrorResponse(400, "Failed to get account resources from permit", "permit service error"),
+			expected: errorcatalog.Account.New(400, "Failed to get account resources from permit", "permit service error"),
 		},
 		{
 			name: "Invalid response format",
 			ctx:  validCtx,
 			id:   validID,
 			mockSetup: func() {
-				expectedURL := fmt.Sprintf("resource_instances/%s", fixedAccountID)
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", expectedURL, nil).
-					Return(map[string]interface{}{
-						"key": "not a valid UUID",
-					}, nil).MaxTimes(1)
+					GetResourceInstance(mock.Any(), fixedAccountID).
+					Return(&permit.ResourceInstance{Entity: permit.Entity{Key: "not a valid UUID"}}, nil).MaxTimes(1)
 			},
-			expected: utils.FormatErrorResponse(400, "Failed to get account resources from permit", "failed to get UUID from account data: invalid UUID format"),
+			expected: errorcatalog.Account.New(400, "Failed to get account resources from permit", "failed to get UUID from account data: invalid UUID format"),
 		},
 	}
 
@@ -189,44 +212,38 @@ func TestFetchAccounts(t *testing.T) {
 	tenantID := uuid.New()
 	ctx := context.Background()
 
-	accountsResponse := map[string]interface{}{
-		"data": []interface{}{
-			buildTestAccountData(uuid.New()),
-		},
-	}
+	accountsResponse := []permit.ResourceInstance{*buildTestAccountData(uuid.New())}
 
 	t.Run("Success", func(t *testing.T) {
-		expectedURL := fmt.Sprintf("resource_instances/detailed?tenant=%s&resource=%s",
-			tenantID.String(), config.AccountResourceTypeID)
-
 		mockService.EXPECT().
-			SendRequest(mock.Any(), "GET", expectedURL, nil).
-			Return(accountsResponse, nil)
+			ListResourceInstances(mock.Any(), tenantID.String(), config.AccountResourceTypeID).
+			Return(accountsResponse, len(accountsResponse), nil)
 
-		result, err := resolver.fetchAccounts(ctx, &tenantID)
+		result, totalCount, err := resolver.fetchAccounts(ctx, &tenantID, nil, nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Len(t, result, 1)
+		assert.Equal(t, 1, totalCount)
 	})
 
 	t.Run("Error from permit service", func(t *testing.T) {
 		mockService.EXPECT().
-			SendRequest(mock.Any(), "GET", mock.Any(), nil).
-			Return(nil, errors.New("permit service error"))
+			ListResourceInstances(mock.Any(), mock.Any(), mock.Any()).
+			Return(nil, 0, errors.New("permit service error"))
 
-		result, err := resolver.fetchAccounts(ctx, &tenantID)
+		result, _, err := resolver.fetchAccounts(ctx, &tenantID, nil, nil)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
 
 	t.Run("Invalid response format", func(t *testing.T) {
 		mockService.EXPECT().
-			SendRequest(mock.Any(), "GET", mock.Any(), nil).
-			Return(map[string]interface{}{
-				"data": "not an array",
-			}, nil)
+			ListResourceInstances(mock.Any(), mock.Any(), mock.Any()).
+			Return([]permit.ResourceInstance{
+				{Entity: permit.Entity{Key: "not a valid UUID"}},
+			}, 1, nil)
 
-		result, err := resolver.fetchAccounts(ctx, &tenantID)
+		result, _, err := resolver.fetchAccounts(ctx, &tenantID, nil, nil)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -247,10 +264,8 @@ func TestFetchAccount(t *testing.T) {
 	accountData := buildTestAccountData(uuid.New())
 
 	t.Run("Success", func(t *testing.T) {
-		expectedURL := fmt.Sprintf("resource_instances/%s", validID)
-
 		mockService.EXPECT().
-			SendRequest(mock.Any(), "GET", expectedURL, nil).
+			GetResourceInstance(mock.Any(), validID.String()).
 			Return(accountData, nil)
 
 		result, err := resolver.fetchAccount(ctx, validID)
@@ -261,7 +276,7 @@ func TestFetchAccount(t *testing.T) {
 
 	t.Run("Error from permit service", func(t *testing.T) {
 		mockService.EXPECT().
-			SendRequest(mock.Any(), "GET", mock.Any(), nil).
+			GetResourceInstance(mock.Any(), mock.Any()).
 			Return(nil, errors.New("permit service error"))
 
 		result, err := resolver.fetchAccount(ctx, validID)
@@ -271,10 +286,8 @@ func TestFetchAccount(t *testing.T) {
 
 	t.Run("Invalid response format", func(t *testing.T) {
 		mockService.EXPECT().
-			SendRequest(mock.Any(), "GET", mock.Any(), nil).
-			Return(map[string]interface{}{
-				"key": "not a valid UUID",
-			}, nil)
+			GetResourceInstance(mock.Any(), mock.Any()).
+			Return(&permit.ResourceInstance{Entity: permit.Entity{Key: "not a valid UUID"}}, nil)
 
 		result, err := resolver.fetchAccount(ctx, validID)
 		assert.Error(t, err)
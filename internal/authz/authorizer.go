@@ -0,0 +1,71 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Authorizer decides whether a user may perform actions on resources. The
+// Permit SDK is one implementation; AllowAll, DenyAll and Policy are meant for
+// tests and local development.
+type Authorizer interface {
+	// Check reports whether userID may perform action on the resource in
+	// tenant. A denial is returned as false together with ErrPermissionDenied.
+	// An empty resourceID or "*" checks the resource type as a whole.
+	Check(ctx context.Context, userID, action, resourceType, resourceID, tenant string) (bool, error)
+	// BulkCheck decides every check of userID in tenant and returns the
+	// decisions in the order of checks. A denial is reported as false, not as
+	// an error. Batches larger than MaxBulkChecks fail with ErrTooManyChecks.
+	BulkCheck(ctx context.Context, userID, tenant string, checks []PermissionCheck) ([]bool, error)
+	// ListAllowedActions returns the actions userID may perform on the
+	// resource in tenant, sorted. AllActions in the result stands for every
+	// action.
+	ListAllowedActions(ctx context.Context, userID, resourceType, resourceID, tenant string) ([]string, error)
+}
+
+// PermissionCheck is one item of a BulkCheck.
+type PermissionCheck struct {
+	Action       string
+	ResourceType string
+	ResourceID   string
+}
+
+// MaxBulkChecks is the largest number of checks accepted by one BulkCheck call.
+const MaxBulkChecks = 100
+
+// AllActions is returned by ListAllowedActions when every action is allowed.
+const AllActions = "*"
+
+var (
+	// ErrPermissionDenied is returned by Check when the request is denied.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrTooManyChecks is returned by BulkCheck when a batch exceeds MaxBulkChecks.
+	ErrTooManyChecks = fmt.Errorf("too many permission checks in one batch, the limit is %d", MaxBulkChecks)
+	// ErrUnavailable is wrapped by errors of authorizers that cannot reach
+	// their decision point and have no cached decision to fall back on.
+	ErrUnavailable = errors.New("authorizer unavailable")
+)
+
+// DecisionInvalidator is implemented by authorizers that cache decisions and
+// must be told when role assignments or role permissions change.
+type DecisionInvalidator interface {
+	InvalidateUserDecisions(userID string)
+	InvalidateAllDecisions()
+}
+
+// InvalidateUserDecisions drops the cached decisions of userID when a caches
+// them. It is safe to call with any authorizer, including nil.
+func InvalidateUserDecisions(a Authorizer, userID string) {
+	if invalidator, ok := a.(DecisionInvalidator); ok {
+		invalidator.InvalidateUserDecisions(userID)
+	}
+}
+
+// InvalidateAllDecisions drops every decision cached by a. It is safe to call
+// with any authorizer, including nil.
+func InvalidateAllDecisions(a Authorizer) {
+	if invalidator, ok := a.(DecisionInvalidator); ok {
+		invalidator.InvalidateAllDecisions()
+	}
+}
@@ -0,0 +1,71 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingAuthorizer records the invalidations it receives.
+type recordingAuthorizer struct {
+	Authorizer
+	users []string
+	all   int
+}
+
+func (r *recordingAuthorizer) InvalidateUserDecisions(userID string) {
+	r.users = append(r.users, userID)
+}
+func (r *recordingAuthorizer) InvalidateAllDecisions() { r.all++ }
+
+func TestConstant(t *testing.T) {
+	ctx := context.Background()
+	checks := []PermissionCheck{{Action: "read", ResourceType: "account"}, {Action: "delete", ResourceType: "tenant"}}
+
+	t.Run("Allow all", func(t *testing.T) {
+		allowed, err := AllowAll().Check(ctx, "u1", "delete", "tenant", "t1", "t1")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+
+		results, err := AllowAll().BulkCheck(ctx, "u1", "t1", checks)
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, true}, results)
+
+		actions, err := AllowAll().ListAllowedActions(ctx, "u1", "account", "", "t1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{AllActions}, actions)
+	})
+
+	t.Run("Deny all", func(t *testing.T) {
+		allowed, err := DenyAll().Check(ctx, "u1", "read", "account", "", "t1")
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+		assert.False(t, allowed)
+
+		results, err := DenyAll().BulkCheck(ctx, "u1", "t1", checks)
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false, false}, results)
+
+		actions, err := DenyAll().ListAllowedActions(ctx, "u1", "account", "", "t1")
+		assert.NoError(t, err)
+		assert.Empty(t, actions)
+	})
+
+	t.Run("Oversized batch is rejected", func(t *testing.T) {
+		_, err := AllowAll().BulkCheck(ctx, "u1", "t1", make([]PermissionCheck, MaxBulkChecks+1))
+		assert.ErrorIs(t, err, ErrTooManyChecks)
+	})
+}
+
+func TestInvalidateDecisions(t *testing.T) {
+	recorder := &recordingAuthorizer{}
+	InvalidateUserDecisions(recorder, "u1")
+	InvalidateAllDecisions(recorder)
+	assert.Equal(t, []string{"u1"}, recorder.users)
+	assert.Equal(t, 1, recorder.all)
+
+	assert.NotPanics(t, func() {
+		InvalidateUserDecisions(AllowAll(), "u1")
+		InvalidateAllDecisions(nil)
+	})
+}
@@ -0,0 +1,48 @@
+package authz
+
+import "context"
+
+// constant is an Authorizer that gives the same decision to every request.
+type constant struct {
+	allow bool
+}
+
+// AllowAll returns an Authorizer that allows every request. It must only be
+// used in tests and local development.
+func AllowAll() Authorizer {
+	return constant{allow: true}
+}
+
+// DenyAll returns an Authorizer that denies every request.
+func DenyAll() Authorizer {
+	return constant{allow: false}
+}
+
+// Check allows or denies every request.
+func (c constant) Check(ctx context.Context, userID, action, resourceType, resourceID, tenant string) (bool, error) {
+	if !c.allow {
+		return false, ErrPermissionDenied
+	}
+	return true, nil
+}
+
+// BulkCheck gives every check the same decision.
+func (c constant) BulkCheck(ctx context.Context, userID, tenant string, checks []PermissionCheck) ([]bool, error) {
+	if len(checks) > MaxBulkChecks {
+		return nil, ErrTooManyChecks
+	}
+	results := make([]bool, len(checks))
+	for i := range results {
+		results[i] = c.allow
+	}
+	return results, nil
+}
+
+// ListAllowedActions returns AllActions when every request is allowed and no
+// actions otherwise.
+func (c constant) ListAllowedActions(ctx context.Context, userID, resourceType, resourceID, tenant string) ([]string, error) {
+	if !c.allow {
+		return []string{}, nil
+	}
+	return []string{AllActions}, nil
+}
@@ -0,0 +1,123 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Rule grants actions on resources to users. Every field other than Actions
+// may be "*" or left empty to match any value.
+type Rule struct {
+	User         string   `json:"user"`
+	Tenant       string   `json:"tenant"`
+	ResourceType string   `json:"resourceType"`
+	ResourceID   string   `json:"resourceId"`
+	Actions      []string `json:"actions"`
+}
+
+// Policy is an Authorizer that allows a request when any of its rules grants
+// it. It is loaded from a JSON file for tests and local development, e.g.
+//
+//	{"rules": [
+//	  {"user": "*", "tenant": "*", "resourceType": "*", "actions": ["read"]},
+//	  {"user": "3f6c...", "resourceType": "account", "actions": ["*"]}
+//	]}
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadPolicy reads a Policy from the JSON file at path.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parse policy file %s: %w", path, err)
+	}
+	for i, rule := range policy.Rules {
+		if len(rule.Actions) == 0 {
+			return nil, fmt.Errorf("parse policy file %s: rule %d has no actions", path, i)
+		}
+	}
+	return &policy, nil
+}
+
+// Check allows the request when a rule grants action on the resource.
+func (p *Policy) Check(ctx context.Context, userID, action, resourceType, resourceID, tenant string) (bool, error) {
+	for _, rule := range p.matching(userID, resourceType, resourceID, tenant) {
+		if matches(rule.Actions, action) {
+			return true, nil
+		}
+	}
+	return false, ErrPermissionDenied
+}
+
+// BulkCheck decides every check with Check.
+func (p *Policy) BulkCheck(ctx context.Context, userID, tenant string, checks []PermissionCheck) ([]bool, error) {
+	if len(checks) > MaxBulkChecks {
+		return nil, ErrTooManyChecks
+	}
+	results := make([]bool, len(checks))
+	for i, check := range checks {
+		results[i], _ = p.Check(ctx, userID, check.Action, check.ResourceType, check.ResourceID, tenant)
+	}
+	return results, nil
+}
+
+// ListAllowedActions returns the actions granted on the resource by any rule.
+func (p *Policy) ListAllowedActions(ctx context.Context, userID, resourceType, resourceID, tenant string) ([]string, error) {
+	seen := map[string]bool{}
+	for _, rule := range p.matching(userID, resourceType, resourceID, tenant) {
+		for _, action := range rule.Actions {
+			seen[action] = true
+		}
+	}
+	if seen[AllActions] {
+		return []string{AllActions}, nil
+	}
+	actions := make([]string, 0, len(seen))
+	for action := range seen {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions, nil
+}
+
+// matching returns the rules that apply to the user and resource.
+func (p *Policy) matching(userID, resourceType, resourceID, tenant string) []Rule {
+	if resourceID == "*" {
+		resourceID = ""
+	}
+	var rules []Rule
+	for _, rule := range p.Rules {
+		if !matchesOne(rule.User, userID) || !matchesOne(rule.Tenant, tenant) || !matchesOne(rule.ResourceType, resourceType) {
+			continue
+		}
+		// A rule scoped to one resource does not grant access to the whole type.
+		if rule.ResourceID != "" && rule.ResourceID != "*" && rule.ResourceID != resourceID {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// matchesOne reports whether a rule field matches value.
+func matchesOne(field, value string) bool {
+	return field == "" || field == "*" || field == value
+}
+
+// matches reports whether any of the rule values matches value.
+func matches(fields []string, value string) bool {
+	for _, field := range fields {
+		if field == "*" || field == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package authz
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Run("Valid file", func(t *testing.T) {
+		policy, err := LoadPolicy(writePolicy(t, `{"rules":[{"user":"u1","resourceType":"account","actions":["read"]}]}`))
+		assert.NoError(t, err)
+		assert.Equal(t, []Rule{{User: "u1", ResourceType: "account", Actions: []string{"read"}}}, policy.Rules)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := LoadPolicy(filepath.Join(t.TempDir(), "missing.json"))
+		assert.ErrorContains(t, err, "read policy file")
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := LoadPolicy(writePolicy(t, `{"rules":`))
+		assert.ErrorContains(t, err, "parse policy file")
+	})
+
+	t.Run("Rule without actions", func(t *testing.T) {
+		_, err := LoadPolicy(writePolicy(t, `{"rules":[{"user":"u1"}]}`))
+		assert.ErrorContains(t, err, "rule 0 has no actions")
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy := &Policy{Rules: []Rule{
+		{User: "*", Tenant: "*", ResourceType: "*", Actions: []string{"read"}},
+		{User: "alice", Tenant: "t1", ResourceType: "account", Actions: []string{"update", "delete"}},
+		{User: "bob", Tenant: "t1", ResourceType: "account", ResourceID: "a1", Actions: []string{"*"}},
+	}}
+
+	tests := []struct {
+		name       string
+		user       string
+		action     string
+		resourceID string
+		tenant     string
+		allowed    bool
+	}{
+		{name: "Wildcard rule", user: "carol", action: "read", resourceID: "a1", tenant: "t9", allowed: true},
+		{name: "User rule", user: "alice", action: "delete", resourceID: "a1", tenant: "t1", allowed: true},
+		{name: "User rule in other tenant", user: "alice", action: "delete", resourceID: "a1", tenant: "t2", allowed: false},
+		{name: "Instance rule on its instance", user: "bob", action: "delete", resourceID: "a1", tenant: "t1", allowed: true},
+		{name: "Instance rule on another instance", user: "bob", action: "delete", resourceID: "a2", tenant: "t1", allowed: false},
+		{name: "Instance rule on the whole type", user: "bob", action: "delete", resourceID: "*", tenant: "t1", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := policy.Check(ctx, tt.user, tt.action, "account", tt.resourceID, tt.tenant)
+			assert.Equal(t, tt.allowed, allowed)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrPermissionDenied)
+			}
+		})
+	}
+
+	t.Run("Bulk check", func(t *testing.T) {
+		results, err := policy.BulkCheck(ctx, "alice", "t1", []PermissionCheck{
+			{Action: "read", ResourceType: "tenant"},
+			{Action: "update", ResourceType: "account", ResourceID: "a1"},
+			{Action: "update", ResourceType: "tenant"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, true, false}, results)
+	})
+
+	t.Run("Allowed actions", func(t *testing.T) {
+		actions, err := policy.ListAllowedActions(ctx, "alice", "account", "a1", "t1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"delete", "read", "update"}, actions)
+
+		actions, err = policy.ListAllowedActions(ctx, "bob", "account", "a1", "t1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{AllActions}, actions)
+	})
+}
@@ -0,0 +1,45 @@
+package bindings
+
+import (
+	"context"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/federation"
+	"iam_services_main_v1/internal/permit"
+
+	"github.com/google/uuid"
+)
+
+// BindingsEntityResolver resolves the bindings other subgraphs reference.
+type BindingsEntityResolver struct {
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
+}
+
+// FindManyBindingByIDs resolves a batch of binding references, in order, from
+// one listing of the role assignments of the caller's tenant. It needs the
+// binding action, like the binding query. References to bindings of other
+// tenants resolve to null.
+func (r *BindingsEntityResolver) FindManyBindingByIDs(ctx context.Context, reps []*models.BindingByIDsInput) ([]*models.Binding, error) {
+	tenantID, err := federation.Authorize(ctx, r.Authorizer, "binding", config.BindingResourceTypeID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, _, err := r.PC.ListRoleAssignments(ctx, permit.RoleAssignmentFilter{Tenant: tenantID.String()})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*models.Binding, len(assignments))
+	for _, assignment := range assignments {
+		binding, err := mapBinding(assignment)
+		if err != nil {
+			return nil, err
+		}
+		byID[binding.ID] = binding
+	}
+	return federation.Resolve(ctx, reps, func(ctx context.Context, rep *models.BindingByIDsInput) (*models.Binding, error) {
+		return byID[rep.ID], nil
+	})
+}
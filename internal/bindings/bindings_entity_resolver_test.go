@@ -0,0 +1,56 @@
+package bindings
+
+import (
+	"context"
+	"errors"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/permit"
+	mocks "iam_services_main_v1/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindManyBindingByIDs(t *testing.T) {
+	tenantID := uuid.New()
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantID.String())
+	ginCtx.Set("userID", uuid.New().String())
+	ctx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+	first, second, missing := uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("Resolves a batch from one listing", func(t *testing.T) {
+		ctrl := mock.NewController(t)
+		defer ctrl.Finish()
+		mockPC := mocks.NewMockPermitService(ctrl)
+		mockPC.EXPECT().ListRoleAssignments(mock.Any(), permit.RoleAssignmentFilter{Tenant: tenantID.String()}).Return([]permit.RoleAssignment{
+			{ID: first.String(), Role: uuid.NewString(), User: uuid.NewString(), Tenant: tenantID.String()},
+			{ID: second.String(), Role: uuid.NewString(), User: uuid.NewString(), Tenant: tenantID.String()},
+		}, 2, nil).Times(1)
+
+		resolver := BindingsEntityResolver{PC: mockPC, Authorizer: authz.AllowAll()}
+		bindings, err := resolver.FindManyBindingByIDs(ctx, []*models.BindingByIDsInput{{ID: second}, {ID: missing}, {ID: first}})
+		assert.NoError(t, err)
+		if assert.Len(t, bindings, 3) {
+			assert.Equal(t, second, bindings[0].ID)
+			assert.Nil(t, bindings[1])
+			assert.Equal(t, first, bindings[2].ID)
+		}
+	})
+
+	t.Run("Listing fails", func(t *testing.T) {
+		ctrl := mock.NewController(t)
+		defer ctrl.Finish()
+		mockPC := mocks.NewMockPermitService(ctrl)
+		mockPC.EXPECT().ListRoleAssignments(mock.Any(), mock.Any()).Return(nil, 0, errors.New("permit down"))
+
+		resolver := BindingsEntityResolver{PC: mockPC, Authorizer: authz.AllowAll()}
+		_, err := resolver.FindManyBindingByIDs(ctx, []*models.BindingByIDsInput{{ID: first}})
+		assert.EqualError(t, err, "permit down")
+	})
+}
@@ -13,6 +13,7 @@ import (
 
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
 	mocks "iam_services_main_v1/mocks"
 	"iam_services_main_v1/pkg/logger"
 
@@ -64,7 +65,7 @@ func TestFieldResolverBindings(t *testing.T) {
 			input: binding,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to fetch"))
+				mockService.EXPECT().GetUser(mock.Any(), mock.Any()).Return(nil, errors.New("failed to fetch"))
 			},
 			output: nil,
 		},
@@ -73,7 +74,7 @@ func TestFieldResolverBindings(t *testing.T) {
 			input: binding,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(principal, nil)
+				mockService.EXPECT().GetUser(mock.Any(), mock.Any()).Return(principal, nil)
 			},
 			output: user,
 		},
@@ -133,7 +134,7 @@ func TestFieldResolverBindingsRole(t *testing.T) {
 			input: binding,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to fetch"))
+				mockService.EXPECT().ListResources(mock.Any()).Return(nil, 0, errors.New("failed to fetch"))
 			},
 			output: nil,
 		},
@@ -142,7 +143,7 @@ func TestFieldResolverBindingsRole(t *testing.T) {
 			input: binding,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(bindingData, nil)
+				mockService.EXPECT().ListResources(mock.Any()).Return(bindingData, len(bindingData), nil)
 			},
 			output: user,
 		},
@@ -171,22 +172,22 @@ func buildBinding() *models.Binding {
 	}
 }
 
-func buildPrincipal() map[string]interface{} {
-	principal := make(map[string]interface{}, 0)
-	principal["key"] = uuid.New().String()
-	principal["first_name"] = "first"
-	principal["last_name"] = "last"
-	principal["created_at"] = time.Now().String()
-	principal["updated_at"] = time.Now().String()
-	associated_tenants := make([]interface{}, 0)
-	associated_tenant := make(map[string]interface{}, 0)
-	associated_tenant["tenant"] = uuid.New().String()
-	associated_tenants = append(associated_tenants, associated_tenant)
-	principal["associated_tenants"] = associated_tenants
-	return principal
+func buildPrincipal() *permit.User {
+	return &permit.User{
+		Entity: permit.Entity{
+			Key:       uuid.New().String(),
+			CreatedAt: time.Now().String(),
+			UpdatedAt: time.Now().String(),
+		},
+		FirstName: "first",
+		LastName:  "last",
+		AssociatedTenants: []permit.UserTenant{
+			{Tenant: uuid.New().String()},
+		},
+	}
 }
 
-func fetchBindingData() map[string]interface{} {
+func fetchBindingData() []permit.Resource {
 	file, err := os.Open("test_files/bindings.json")
 	if err != nil {
 		fmt.Println("error")
@@ -204,13 +205,15 @@ func fetchBindingData() map[string]interface{} {
 		log.Fatal(err)
 	}
 
-	// Declare a variable to hold the unmarshalled map
-	var result map[string]interface{}
+	// Declare a variable to hold the unmarshalled resources
+	var result struct {
+		Data []permit.Resource `json:"data"`
+	}
 
-	// Unmarshal the JSON into the map
+	// Unmarshal the JSON into the resources
 	err = json.Unmarshal(fileContents, &result)
 	if err != nil {
 		log.Fatal(err)
 	}
-	return result
+	return result.Data
 }
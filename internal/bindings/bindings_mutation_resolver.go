@@ -2,11 +2,11 @@ package bindings
 
 import (
 	"context"
-	"fmt"
-	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
-	"iam_services_main_v1/internal/constants"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/events"
 	"iam_services_main_v1/internal/permit"
 	"net/http"
 	"time"
@@ -21,7 +21,10 @@ const (
 )
 
 type BindingsMutationResolver struct {
-	PC permit.PermitService
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
+	// Events receives the changes made, for subscriptions. It may be nil.
+	Events *events.Bus
 }
 
 func (r *BindingsMutationResolver) CreateBinding(ctx context.Context, input models.CreateBindingInput) (models.OperationResult, error) {
@@ -35,23 +38,23 @@ func (r *BindingsMutationResolver) CreateBinding(ctx context.Context, input mode
 	tenantId, err := helpers.GetTenantID(ctx)
 	if err != nil {
 		logger.Info("unable to find tenant id in context")
-		return buildErrorResponse(http.StatusBadRequest, "unable to find tenant id in context", err.Error()), nil
+		return errorcatalog.Binding.New(http.StatusBadRequest, "unable to find tenant id in context", err.Error()), nil
 	}
 
 	userId, err := helpers.GetUserID(ctx)
 	if err != nil {
 		logger.Info("unable to find user id in context")
-		return buildErrorResponse(http.StatusBadRequest, "unable to find user id in context", err.Error()), nil
+		return errorcatalog.Binding.New(http.StatusBadRequest, "unable to find user id in context", err.Error()), nil
 	}
 
 	if input.PrincipalID == uuid.Nil {
 		logger.Info("unable to find principal id in request")
-		return buildErrorResponse(http.StatusBadRequest, "unable to find principal id in input", "principal id is required"), nil
+		return errorcatalog.Binding.New(http.StatusBadRequest, "unable to find principal id in input", "principal id is required"), nil
 	}
 
 	if input.RoleID == uuid.Nil {
 		logger.Info("unable to find role id in request")
-		return buildErrorResponse(http.StatusBadRequest, "unable to find role id in input", "role id is required"), nil
+		return errorcatalog.Binding.New(http.StatusBadRequest, "unable to find role id in input", "role id is required"), nil
 	}
 
 	roleID := input.RoleID
@@ -59,17 +62,17 @@ func (r *BindingsMutationResolver) CreateBinding(ctx context.Context, input mode
 	currentDate := time.Now().String()
 
 	// Create binding in Permit
-	reqBody := map[string]interface{}{
-		constants.ROLE:              input.RoleID.String(),
-		constants.TENANT:            tenantId.String(),
-		constants.USER:              input.PrincipalID.String(),
-		constants.RESOURCE_INSTANCE: input.ScopeRefID.String() + ":" + tenantId.String(),
-	}
-	_, err = r.PC.APIExecute(ctx, constants.POST, constants.PERMIT_ROLE_ASSIGNMENTS, reqBody)
+	_, err = r.PC.AssignRole(ctx, permit.RoleAssignmentCreate{
+		Role:             input.RoleID.String(),
+		Tenant:           tenantId.String(),
+		User:             input.PrincipalID.String(),
+		ResourceInstance: input.ScopeRefID.String() + ":" + tenantId.String(),
+	})
 
 	if err != nil {
-		return buildErrorResponse(http.StatusBadRequest, err.Error(), "unable to create binding in permit"), nil
+		return errorcatalog.Binding.FromError(http.StatusBadRequest, "unable to create binding in permit", err), nil
 	}
+	authz.InvalidateUserDecisions(r.Authorizer, input.PrincipalID.String())
 
 	logger.Info("Binding created successfully")
 
@@ -95,121 +98,13 @@ func (r *BindingsMutationResolver) CreateBinding(ctx context.Context, input mode
 		IsSuccess: true,
 		Message:   "Binding created successfully",
 	}
+	r.Events.BindingChanged(models.ChangeTypeEnumCreated, *tenantId, resourceId, input.PrincipalID, roleID, operationResult)
 	return operationResult, nil
 
 }
 
 func (r *BindingsMutationResolver) UpdateBinding(ctx context.Context, input models.UpdateBindingInput) (models.OperationResult, error) {
 	return nil, nil
-	// logger := log.WithContext(ctx).WithFields(log.Fields{
-	// 	"class":     "bindings_mutation_resolver",
-	// 	"method":    "UpdateBinding",
-	// 	"bindingID": input.ID,
-	// })
-	// logger.Info("update binding request received")
-	//return nil, nil
-	// logger := log.WithContext(ctx).WithFields(log.Fields{
-	// 	"class":     "bindings_mutation_resolver",
-	// 	"method":    "UpdateBinding",
-	// 	"bindingID": input.ID,
-	// })
-	// logger.Info("update binding request received")
-
-	// if input.ID == uuid.Nil {
-	// 	return buildErrorResponse(http.StatusBadRequest, "invalid id provided", "invalid binding id provided"), nil
-	// }
-	// if input.Name == "" {
-	// 	return buildErrorResponse(http.StatusBadRequest, "name is invalid", "name should not be empty"), nil
-	// }
-	// tenantId, err := helpers.GetTenantID(ctx)
-	// if err != nil {
-	// 	return buildErrorResponse(http.StatusBadRequest, "tenant id is missing", "tenant id is missing"), nil
-	// }
-	// if input.ID == uuid.Nil {
-	// 	return buildErrorResponse(http.StatusBadRequest, "invalid id provided", "invalid binding id provided"), nil
-	// }
-	// if input.Name == "" {
-	// 	return buildErrorResponse(http.StatusBadRequest, "name is invalid", "name should not be empty"), nil
-	// }
-	// tenantId, err := helpers.GetTenantID(ctx)
-	// if err != nil {
-	// 	return buildErrorResponse(http.StatusBadRequest, "tenant id is missing", "tenant id is missing"), nil
-	// }
-
-	// userId, err := helpers.GetUserID(ctx)
-	// if err != nil {
-	// 	return buildErrorResponse(http.StatusBadRequest, "user id is missing", "user id is missing"), nil
-	// }
-	// userId, err := helpers.GetUserID(ctx)
-	// if err != nil {
-	// 	return buildErrorResponse(http.StatusBadRequest, "user id is missing", "user id is missing"), nil
-	// }
-
-	// _, err = r.PC.APIExecute(ctx, constants.POST, constants.PERMIT_ROLE_ASSIGNMENTS, map[string]interface{}{
-	// 	constants.USER:              input.PrincipalID,
-	// 	constants.ROLE:              input.RoleID,
-	// 	constants.TENANT:            tenantId,
-	// 	constants.RESOURCE_INSTANCE: TENANT_RESOURCE_TYPE_ID + ":" + tenantId.String(),
-	// })
-	// _, err = r.PC.APIExecute(ctx, constants.POST, constants.PERMIT_ROLE_ASSIGNMENTS, map[string]interface{}{
-	// 	constants.USER:              input.PrincipalID,
-	// 	constants.ROLE:              input.RoleID,
-	// 	constants.TENANT:            tenantId,
-	// 	constants.RESOURCE_INSTANCE: TENANT_RESOURCE_TYPE_ID + ":" + tenantId.String(),
-	// })
-
-	// if err != nil {
-	// 	return buildErrorResponse(http.StatusBadRequest, err.Error(), "unable to create binding in permit"), nil
-	// }
-	// if err != nil {
-	// 	return buildErrorResponse(http.StatusBadRequest, err.Error(), "unable to create binding in permit"), nil
-	// }
-
-	// createdAt := time.Now().String()
-	// assignment := &models.Binding{
-	// 	ID:        input.ID,
-	// 	Name:      input.Name,
-	// 	CreatedAt: createdAt,
-	// 	UpdatedAt: createdAt,
-	// 	Role:      &models.Role{ID: input.RoleID},
-	// 	Version:   "V1",
-	// 	UpdatedBy: *userId,
-	// }
-	// createdAt := time.Now().String()
-	// assignment := &models.Binding{
-	// 	ID:        input.ID,
-	// 	Name:      input.Name,
-	// 	CreatedAt: createdAt,
-	// 	UpdatedAt: createdAt,
-	// 	Role:      &models.Role{ID: input.RoleID},
-	// 	Version:   "V1",
-	// 	UpdatedBy: *userId,
-	// }
-
-	// if err != nil {
-	// 	return buildErrorResponse(http.StatusBadRequest, err.Error(), "cannot delete binding"), nil
-	// }
-	// if err != nil {
-	// 	return buildErrorResponse(http.StatusBadRequest, err.Error(), "cannot delete binding"), nil
-	// }
-
-	// dataArray := make([]models.Data, 0)
-	// dataArray = append(dataArray, assignment)
-	// dataArray := make([]models.Data, 0)
-	// dataArray = append(dataArray, assignment)
-
-	// operationResult := models.SuccessResponse{
-	// 	Data:      dataArray,
-	// 	IsSuccess: true,
-	// 	Message:   "Binding updated successfully",
-	// }
-	// return operationResult, nil
-	// operationResult := models.SuccessResponse{
-	// 	Data:      dataArray,
-	// 	IsSuccess: true,
-	// 	Message:   "Binding updated successfully",
-	// }
-	// return operationResult, nil
 }
 
 // DeleteBinding is the resolver for the deleteBinding field.
@@ -223,34 +118,35 @@ func (r *BindingsMutationResolver) DeleteBinding(ctx context.Context, input mode
 	logger.Info("delete binding request received")
 	if input.ID == uuid.Nil {
 		logger.Error("invalid id provided. Please provide valid binding id")
-		return buildErrorResponse(http.StatusBadRequest, "id is not present in request", "id is mandatory"), nil
+		return errorcatalog.Binding.New(http.StatusBadRequest, "id is not present in request", "id is mandatory"), nil
 	}
 	tenantId, err := helpers.GetTenantID(ctx)
 	if err != nil {
-		return buildErrorResponse(http.StatusBadRequest, "unable to find tenantId in context", err.Error()), nil
+		return errorcatalog.Binding.New(http.StatusBadRequest, "unable to find tenantId in context", err.Error()), nil
 	}
 
 	if input.PrincipalID == uuid.Nil {
 		logger.Info("unable to find principal id in request")
-		return buildErrorResponse(http.StatusBadRequest, "unable to find principal id in input", "principal id is required"), nil
+		return errorcatalog.Binding.New(http.StatusBadRequest, "unable to find principal id in input", "principal id is required"), nil
 	}
 
 	if input.RoleID == uuid.Nil {
 		logger.Info("unable to find role id in request")
-		return buildErrorResponse(http.StatusBadRequest, "unable to find role id in input", "role id is required"), nil
+		return errorcatalog.Binding.New(http.StatusBadRequest, "unable to find role id in input", "role id is required"), nil
 	}
 
-	deleteRequestBody := map[string]interface{}{
-		constants.ROLE:              input.RoleID,
-		constants.TENANT:            tenantId,
-		constants.USER:              input.PrincipalID,
-		constants.RESOURCE_INSTANCE: input.ScopeRefID.String() + ":" + tenantId.String(),
-	}
-	_, err = r.PC.APIExecute(ctx, constants.DELETE, constants.PERMIT_ROLE_ASSIGNMENTS, deleteRequestBody)
+	err = r.PC.UnassignRole(ctx, permit.RoleAssignmentCreate{
+		Role:             input.RoleID.String(),
+		Tenant:           tenantId.String(),
+		User:             input.PrincipalID.String(),
+		ResourceInstance: input.ScopeRefID.String() + ":" + tenantId.String(),
+	})
 
 	if err != nil {
-		return buildErrorResponse(http.StatusNotFound, err.Error(), "unable to delete binding in permit"), nil
+		return errorcatalog.Binding.FromError(http.StatusNotFound, "unable to delete binding in permit", err), nil
 	}
+	authz.InvalidateUserDecisions(r.Authorizer, input.PrincipalID.String())
+	r.Events.BindingChanged(models.ChangeTypeEnumDeleted, *tenantId, input.ID, input.PrincipalID, input.RoleID, nil)
 
 	logger.Info("binding deleted successfully")
 	result := &models.SuccessResponse{
@@ -260,13 +156,3 @@ func (r *BindingsMutationResolver) DeleteBinding(ctx context.Context, input mode
 	}
 	return result, nil
 }
-
-func buildErrorResponse(statusCode int, errorDetail, message string) models.ResponseError {
-	return models.ResponseError{
-		ErrorCode:     fmt.Sprint(statusCode),
-		ErrorDetails:  &errorDetail,
-		IsSuccess:     false,
-		Message:       config.GenericErrorMessage,
-		SystemMessage: message,
-	}
-}
@@ -7,6 +7,8 @@ import (
 
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/permit"
 	mocks "iam_services_main_v1/mocks"
 
 	"github.com/gin-gonic/gin"
@@ -66,44 +68,44 @@ func TestCreateBinding(t *testing.T) {
 			input:     successRequest,
 			ctx:       context.WithValue(context.Background(), config.GinContextKey, &gin.Context{}),
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "unable to fetch gin context", "tenant id not found in context"),
+			output:    errorcatalog.Binding.New(400, "unable to fetch gin context", "tenant id not found in context"),
 		},
 		{
 			name:      "CreateBinding when user id is not present in context",
 			input:     successRequest,
 			ctx:       testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "failed", "error parsing user id: invalid UUID length: 0"),
+			output:    errorcatalog.Binding.New(400, "failed", "error parsing user id: invalid UUID length: 0"),
 		},
 		{
 			name:      "CreateBinding when Principal is not present",
 			input:     requestWithEmptyPrincipal,
 			ctx:       validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "failed", "principal id is required"),
+			output:    errorcatalog.Binding.New(400, "failed", "principal id is required"),
 		},
 		{
 			name:      "CreateBinding when Role is not present",
 			input:     requestWithEmptyRoleId,
 			ctx:       validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "failed", "role id is required"),
+			output:    errorcatalog.Binding.New(400, "failed", "role id is required"),
 		},
 		{
 			name:  "CreateBinding failed in permit",
 			input: successRequest,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().APIExecute(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to create"))
+				mockService.EXPECT().AssignRole(mock.Any(), mock.Any()).Return(nil, errors.New("failed to create"))
 			},
-			output: buildErrorResponse(400, "unable to create organization in permit", "unable to create binding in permit"),
+			output: errorcatalog.Binding.New(400, "unable to create organization in permit", "unable to create binding in permit"),
 		},
 		{
 			name:  "CreateBinding success",
 			input: successRequest,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().APIExecute(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, nil)
+				mockService.EXPECT().AssignRole(mock.Any(), mock.Any()).Return(&permit.RoleAssignment{}, nil)
 			},
 			output: nil,
 		},
@@ -179,44 +181,44 @@ func TestDeleteBinding(t *testing.T) {
 			input:     requestWithEmptyId,
 			ctx:       context.WithValue(context.Background(), config.GinContextKey, &gin.Context{}),
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "unable to fetch gin context", "tenant id not found in context"),
+			output:    errorcatalog.Binding.New(400, "unable to fetch gin context", "tenant id not found in context"),
 		},
 		{
 			name:      "DeleteBinding when tenantId is not present in context",
 			input:     successRequest,
 			ctx:       context.WithValue(context.Background(), config.GinContextKey, &gin.Context{}),
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "unable to fetch gin context", "tenant id not found in context"),
+			output:    errorcatalog.Binding.New(400, "unable to fetch gin context", "tenant id not found in context"),
 		},
 		{
 			name:      "DeleteBinding when Principal is not present",
 			input:     requestWithEmptyPrincipal,
 			ctx:       validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "failed", "principal id is required"),
+			output:    errorcatalog.Binding.New(400, "failed", "principal id is required"),
 		},
 		{
 			name:      "DeleteBinding when Role is not present",
 			input:     requestWithEmptyRoleId,
 			ctx:       validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "failed", "role id is required"),
+			output:    errorcatalog.Binding.New(400, "failed", "role id is required"),
 		},
 		{
 			name:  "DeleteBinding failed in permit",
 			input: successRequest,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().APIExecute(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to create"))
+				mockService.EXPECT().UnassignRole(mock.Any(), mock.Any()).Return(errors.New("failed to create"))
 			},
-			output: buildErrorResponse(400, "unable to create organization in permit", "unable to create binding in permit"),
+			output: errorcatalog.Binding.New(400, "unable to create organization in permit", "unable to create binding in permit"),
 		},
 		{
 			name:  "DeleteBinding success",
 			input: successRequest,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().APIExecute(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, nil)
+				mockService.EXPECT().UnassignRole(mock.Any(), mock.Any()).Return(nil)
 			},
 			output: nil,
 		},
@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
-	"iam_services_main_v1/internal/constants"
+	"iam_services_main_v1/internal/errorcatalog"
 	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/utils"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -27,30 +28,26 @@ func (r *BindingsQueryResolver) Binding(ctx context.Context, id uuid.UUID) (mode
 	logger.Infof("getbinding request received with Id %v", id)
 	if id == uuid.Nil {
 		logger.Error("invalid binding id provided")
-		return buildErrorResponse(http.StatusBadRequest, "invalid id provided", "user id is invalid"), nil
+		return errorcatalog.Binding.New(http.StatusBadRequest, "invalid id provided", "user id is invalid"), nil
 	}
 
-	url := fmt.Sprintf(constants.PERMIT_ROLE_ASSIGNMENTS+"?user=%s", id)
-	bindingsFromPermit, err := r.PC.ExecuteGetAPI(ctx, constants.GET, url)
+	bindingsFromPermit, _, err := r.PC.ListRoleAssignments(ctx, permit.RoleAssignmentFilter{User: id.String()})
 
 	if err != nil {
 		logger.Error("unable to fetch bindings from permit ")
-		return buildErrorResponse(http.StatusInternalServerError, "unable to fetch assignments from permit", err.Error()), nil
+		return errorcatalog.Binding.FromError(http.StatusInternalServerError, "unable to fetch assignments from permit", err), nil
 	}
-	fmt.Println(bindingsFromPermit)
 
 	assignments := make([]models.Data, 0)
 
 	for _, binding := range bindingsFromPermit {
-		assignment := models.Binding{
-			Role:      &models.Role{ID: uuid.MustParse(binding["role"].(string))},
-			ID:        uuid.MustParse(binding["id"].(string)),
-			Principal: &models.User{ID: uuid.MustParse(binding["user"].(string))},
-			CreatedAt: binding["created_at"].(string),
+		assignment, err := mapBinding(binding)
+		if err != nil {
+			logger.Error("unable to map binding from permit")
+			return errorcatalog.Binding.New(http.StatusInternalServerError, "unable to map assignments from permit", err.Error()), nil
 		}
-		assignments = append(assignments, assignment)
+		assignments = append(assignments, *assignment)
 	}
-	fmt.Println(bindingsFromPermit)
 
 	result := &models.SuccessResponse{
 		Data:      assignments,
@@ -71,44 +68,94 @@ func (r *BindingsQueryResolver) Bindings(ctx context.Context) (models.OperationR
 	tenantId, err := helpers.GetTenantID(ctx)
 	if err != nil {
 		logger.Error("unable to find tenant id from context")
-		return buildErrorResponse(http.StatusBadRequest, "failed to fetch tenant id", "tenant id is missing in headers"), nil
+		return errorcatalog.Binding.New(http.StatusBadRequest, "failed to fetch tenant id", "tenant id is missing in headers"), nil
 	}
 
-	// resourceInstance := fmt.Sprintf("?tenant="+"%s", tenantId)
-	url := fmt.Sprintf(constants.PERMIT_ROLE_ASSIGNMENTS+"?tenant=%s", tenantId)
-	res, err := r.PC.ExecuteGetAPI(ctx, constants.GET, url)
+	res, totalCount, err := r.PC.ListRoleAssignments(ctx, permit.RoleAssignmentFilter{Tenant: tenantId.String()})
 
 	if err != nil {
 		logger.Error("unable to fetch resources from permit")
-		return buildErrorResponse(http.StatusInternalServerError, "failed to fetch resources from permit", "failed to fetch bindings from permit"), nil
+		return errorcatalog.Binding.FromError(http.StatusInternalServerError, "failed to fetch bindings from permit", err), nil
 	}
 
 	logger.Infof("fetch allBindings request received")
 	var bindings []models.Data
 
 	for _, binding := range res {
-		createdAt := binding["created_at"].(string)
-		updatedAt := binding["created_at"].(string)
-		// principalType := r.FetchPrincipalBasedOnPrincipalId(ctx, uuid.MustParse(binding.UserId))
-
-		bindingData := &models.Binding{
-			ID:        uuid.MustParse(binding["id"].(string)),
-			CreatedAt: createdAt,
-			UpdatedAt: updatedAt,
-			Role:      &models.Role{ID: uuid.MustParse(binding["role"].(string))},
-			Principal: &models.User{
-				ID: uuid.MustParse(binding["user"].(string)),
-			},
+		bindingData, err := mapBinding(binding)
+		if err != nil {
+			logger.Error("unable to map binding from permit")
+			return errorcatalog.Binding.New(http.StatusInternalServerError, "failed to map bindings from permit", err.Error()), nil
 		}
-
 		bindings = append(bindings, bindingData)
 	}
 
 	result := &models.SuccessResponse{
-		Data:      bindings,
-		IsSuccess: true,
-		Message:   "Bindings retrieved successfully",
+		Data:       bindings,
+		IsSuccess:  true,
+		Message:    "Bindings retrieved successfully",
+		TotalCount: &totalCount,
 	}
 
 	return result, nil
 }
+
+// BindingsConnection is the resolver for the bindingsConnection field. It reads
+// one page of the role assignments of the tenant in the context.
+func (r *BindingsQueryResolver) BindingsConnection(ctx context.Context, after *string, first *int) (models.OperationResult, error) {
+	logger := log.WithContext(ctx).WithFields(log.Fields{
+		"className": "binding_query_resolver",
+		"method":    "BindingsConnection",
+	})
+
+	page, err := utils.ParsePage(first, after)
+	if err != nil {
+		return errorcatalog.Binding.New(http.StatusBadRequest, "invalid pagination arguments", err.Error()), nil
+	}
+
+	tenantId, err := helpers.GetTenantID(ctx)
+	if err != nil {
+		logger.Error("unable to find tenant id from context")
+		return errorcatalog.Binding.New(http.StatusBadRequest, "failed to fetch tenant id", "tenant id is missing in headers"), nil
+	}
+
+	res, totalCount, err := r.PC.ListRoleAssignmentsPage(ctx, permit.RoleAssignmentFilter{Tenant: tenantId.String()}, page)
+	if err != nil {
+		logger.Error("unable to fetch resources from permit")
+		return errorcatalog.Binding.FromError(http.StatusInternalServerError, "failed to fetch bindings from permit", err), nil
+	}
+
+	bindings := make([]models.Data, 0, len(res))
+	for _, binding := range res {
+		bindingData, err := mapBinding(binding)
+		if err != nil {
+			logger.Error("unable to map binding from permit")
+			return errorcatalog.Binding.New(http.StatusInternalServerError, "failed to map bindings from permit", err.Error()), nil
+		}
+		bindings = append(bindings, bindingData)
+	}
+	return utils.FormatConnection(bindings, page, totalCount), nil
+}
+
+// mapBinding maps a Permit role assignment to a Binding model
+func mapBinding(assignment permit.RoleAssignment) (*models.Binding, error) {
+	id, err := uuid.Parse(assignment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid binding id %q: %w", assignment.ID, err)
+	}
+	roleID, err := uuid.Parse(assignment.Role)
+	if err != nil {
+		return nil, fmt.Errorf("invalid role id %q: %w", assignment.Role, err)
+	}
+	userID, err := uuid.Parse(assignment.User)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id %q: %w", assignment.User, err)
+	}
+	return &models.Binding{
+		ID:        id,
+		CreatedAt: assignment.CreatedAt,
+		UpdatedAt: assignment.CreatedAt,
+		Role:      &models.Role{ID: roleID},
+		Principal: &models.User{ID: userID},
+	}, nil
+}
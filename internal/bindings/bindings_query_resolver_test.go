@@ -8,6 +8,8 @@ import (
 
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/permit"
 	mocks "iam_services_main_v1/mocks"
 
 	"github.com/gin-gonic/gin"
@@ -33,20 +35,20 @@ func TestBinding(t *testing.T) {
 
 	testCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
 
-	assignment1 := make(map[string]interface{}, 0)
-	assignment1["role"] = uuid.New().String()
-	assignment1["user"] = uuid.New().String()
-	assignment1["id"] = uuid.New().String()
-	assignment1["created_at"] = time.Now().String()
-
-	assignment2 := make(map[string]interface{}, 0)
-	assignment2["role"] = uuid.New().String()
-	assignment2["user"] = uuid.New().String()
-	assignment2["id"] = uuid.New().String()
-	assignment2["created_at"] = time.Now().String()
-
-	assignments := make([]map[string]interface{}, 0)
-	assignments = append(assignments, assignment1, assignment2)
+	assignments := []permit.RoleAssignment{
+		{
+			ID:        uuid.New().String(),
+			Role:      uuid.New().String(),
+			User:      uuid.New().String(),
+			CreatedAt: time.Now().String(),
+		},
+		{
+			ID:        uuid.New().String(),
+			Role:      uuid.New().String(),
+			User:      uuid.New().String(),
+			CreatedAt: time.Now().String(),
+		},
+	}
 
 	testcases := []struct {
 		name      string
@@ -60,23 +62,23 @@ func TestBinding(t *testing.T) {
 			input:     uuid.Nil,
 			ctx:       context.WithValue(context.Background(), config.GinContextKey, &gin.Context{}),
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "unable to fetch gin context", "tenant id not found in context"),
+			output:    errorcatalog.Binding.New(400, "unable to fetch gin context", "tenant id not found in context"),
 		},
 		{
 			name:  "GetBinding when fetch fails from permit",
 			input: uuid.New(),
 			ctx:   testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().ExecuteGetAPI(mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to fetch from permit"))
+				mockService.EXPECT().ListRoleAssignments(mock.Any(), mock.Any()).Return(nil, 0, errors.New("failed to fetch from permit"))
 			},
-			output: buildErrorResponse(400, "failed", "error parsing user id: invalid UUID length: 0"),
+			output: errorcatalog.Binding.New(400, "failed", "error parsing user id: invalid UUID length: 0"),
 		},
 		{
 			name:  "GetBinding success",
 			input: uuid.New(),
 			ctx:   testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockSvc.EXPECT().ExecuteGetAPI(mock.Any(), mock.Any(), mock.Any()).Return(assignments, nil)
+				mockSvc.EXPECT().ListRoleAssignments(mock.Any(), mock.Any()).Return(assignments, len(assignments), nil)
 			},
 			output: nil,
 		},
@@ -111,20 +113,20 @@ func TestBindings(t *testing.T) {
 
 	testCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
 
-	assignment1 := make(map[string]interface{}, 0)
-	assignment1["role"] = uuid.New().String()
-	assignment1["user"] = uuid.New().String()
-	assignment1["id"] = uuid.New().String()
-	assignment1["created_at"] = time.Now().String()
-
-	assignment2 := make(map[string]interface{}, 0)
-	assignment2["role"] = uuid.New().String()
-	assignment2["user"] = uuid.New().String()
-	assignment2["id"] = uuid.New().String()
-	assignment2["created_at"] = time.Now().String()
-
-	assignments := make([]map[string]interface{}, 0)
-	assignments = append(assignments, assignment1, assignment2)
+	assignments := []permit.RoleAssignment{
+		{
+			ID:        uuid.New().String(),
+			Role:      uuid.New().String(),
+			User:      uuid.New().String(),
+			CreatedAt: time.Now().String(),
+		},
+		{
+			ID:        uuid.New().String(),
+			Role:      uuid.New().String(),
+			User:      uuid.New().String(),
+			CreatedAt: time.Now().String(),
+		},
+	}
 
 	testcases := []struct {
 		name      string
@@ -138,23 +140,23 @@ func TestBindings(t *testing.T) {
 			input:     uuid.Nil,
 			ctx:       context.WithValue(context.Background(), config.GinContextKey, &gin.Context{}),
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "failed to fetch tenant id", "tenant id is missing in headers"),
+			output:    errorcatalog.Binding.New(400, "failed to fetch tenant id", "tenant id is missing in headers"),
 		},
 		{
 			name:  "GetBindings when fetch fails from permit",
 			input: uuid.New(),
 			ctx:   testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().ExecuteGetAPI(mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to fetch from permit"))
+				mockService.EXPECT().ListRoleAssignments(mock.Any(), mock.Any()).Return(nil, 0, errors.New("failed to fetch from permit"))
 			},
-			output: buildErrorResponse(400, "failed", "error parsing user id: invalid UUID length: 0"),
+			output: errorcatalog.Binding.New(400, "failed", "error parsing user id: invalid UUID length: 0"),
 		},
 		{
 			name:  "GetBindings success",
 			input: uuid.New(),
 			ctx:   testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockSvc.EXPECT().ExecuteGetAPI(mock.Any(), mock.Any(), mock.Any()).Return(assignments, nil)
+				mockSvc.EXPECT().ListRoleAssignments(mock.Any(), mock.Any()).Return(assignments, len(assignments), nil)
 			},
 			output: nil,
 		},
@@ -171,3 +173,54 @@ func TestBindings(t *testing.T) {
 	}
 
 }
+
+func TestBindingsConnection(t *testing.T) {
+	ctrl := mock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPermitService(ctrl)
+	objUnderTest := BindingsQueryResolver{
+		PC: mockService,
+	}
+
+	tenantId := "7ed6cfa6-fd7e-4a2a-bbce-773ef8ea4c12"
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantId)
+	testCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+
+	assignments := []permit.RoleAssignment{
+		{
+			ID:        uuid.New().String(),
+			Role:      uuid.New().String(),
+			User:      uuid.New().String(),
+			CreatedAt: time.Now().String(),
+		},
+	}
+
+	t.Run("Reads the page of the tenant from permit", func(t *testing.T) {
+		first := 1
+		mockService.EXPECT().
+			ListRoleAssignmentsPage(mock.Any(), permit.RoleAssignmentFilter{Tenant: tenantId}, permit.Page{Limit: 1}).
+			Return(assignments, 3, nil)
+
+		result, err := objUnderTest.BindingsConnection(testCtx, nil, &first)
+
+		assert.NoError(t, err)
+		conn, ok := result.(*models.Connection)
+		assert.True(t, ok)
+		assert.Len(t, conn.Edges, 1)
+		assert.Equal(t, 3, conn.TotalCount)
+		assert.True(t, conn.PageInfo.HasNextPage)
+		assert.False(t, conn.PageInfo.HasPreviousPage)
+	})
+
+	t.Run("Malformed cursor", func(t *testing.T) {
+		after := "bogus"
+		result, err := objUnderTest.BindingsConnection(testCtx, &after, nil)
+
+		assert.NoError(t, err)
+		errResp, ok := result.(*models.ResponseError)
+		assert.True(t, ok)
+		assert.Equal(t, "IAM-BINDING-400", errResp.ErrorCode)
+	})
+}
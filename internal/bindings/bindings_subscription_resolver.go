@@ -0,0 +1,40 @@
+package bindings
+
+import (
+	"context"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/events"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/google/uuid"
+)
+
+type BindingsSubscriptionResolver struct {
+	Events     *events.Bus
+	Authorizer authz.Authorizer
+}
+
+// BindingChanged returns the changes made to the bindings of the caller's
+// tenant from now on, only those of principalID when it is given.
+func (r *BindingsSubscriptionResolver) BindingChanged(ctx context.Context, principalID *uuid.UUID) (<-chan *models.BindingChangeEvent, error) {
+	logger := log.WithContext(ctx).WithFields(log.Fields{
+		"class":  "bindings_subscription_resolver",
+		"method": "BindingChanged",
+	})
+
+	tenantId, _, err := events.Authorize(ctx, r.Events, r.Authorizer, authz.PermissionCheck{
+		Action:       "bindings",
+		ResourceType: config.BindingResourceTypeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("subscribed to binding changes")
+	return events.Stream(ctx, r.Events, events.TopicBinding, tenantId.String(), func(event *models.BindingChangeEvent) bool {
+		return principalID == nil || event.PrincipalID == *principalID
+	}), nil
+}
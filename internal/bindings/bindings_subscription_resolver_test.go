@@ -0,0 +1,79 @@
+package bindings
+
+import (
+	"context"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/events"
+	mocks "iam_services_main_v1/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindingChanged(t *testing.T) {
+	ctrl := mock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPermitService(ctrl)
+	bus := events.NewBus(events.DefaultBuffer)
+	mutations := BindingsMutationResolver{PC: mockService, Events: bus}
+	objUnderTest := BindingsSubscriptionResolver{Events: bus, Authorizer: authz.AllowAll()}
+
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", "7ed6cfa6-fd7e-4a2a-bbce-773ef8ea4c12")
+	ginCtx.Set("userID", "b5b44e90-906e-458a-8bb1-e9e4ee180696")
+	validCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+	ctx, cancel := context.WithCancel(validCtx)
+	defer cancel()
+
+	principalID := uuid.New()
+	ch, err := objUnderTest.BindingChanged(ctx, &principalID)
+	assert.NoError(t, err)
+
+	mockService.EXPECT().AssignRole(mock.Any(), mock.Any()).Return(nil, nil).Times(2)
+	mockService.EXPECT().UnassignRole(mock.Any(), mock.Any()).Return(nil)
+
+	_, err = mutations.CreateBinding(validCtx, models.CreateBindingInput{Name: "other", PrincipalID: uuid.New(), RoleID: uuid.New(), ScopeRefID: uuid.New()})
+	assert.NoError(t, err)
+	created := models.CreateBindingInput{Name: "admins", PrincipalID: principalID, RoleID: uuid.New(), ScopeRefID: uuid.New()}
+	_, err = mutations.CreateBinding(validCtx, created)
+	assert.NoError(t, err)
+	deleted := models.DeleteBindingInput{ID: uuid.New(), PrincipalID: principalID, RoleID: uuid.New(), ScopeRefID: uuid.New()}
+	_, err = mutations.DeleteBinding(validCtx, deleted)
+	assert.NoError(t, err)
+
+	for _, want := range []struct {
+		change models.ChangeTypeEnum
+		roleID uuid.UUID
+	}{
+		{models.ChangeTypeEnumCreated, created.RoleID},
+		{models.ChangeTypeEnumDeleted, deleted.RoleID},
+	} {
+		select {
+		case event := <-ch:
+			assert.Equal(t, want.change, event.Change)
+			assert.Equal(t, principalID, event.PrincipalID)
+			assert.Equal(t, want.roleID, event.RoleID)
+			assert.Equal(t, want.change == models.ChangeTypeEnumCreated, event.Binding != nil)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the binding change")
+		}
+	}
+}
+
+func TestBindingChangedDenied(t *testing.T) {
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", "7ed6cfa6-fd7e-4a2a-bbce-773ef8ea4c12")
+	ginCtx.Set("userID", "b5b44e90-906e-458a-8bb1-e9e4ee180696")
+	validCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+
+	objUnderTest := BindingsSubscriptionResolver{Events: events.NewBus(events.DefaultBuffer), Authorizer: authz.DenyAll()}
+	_, err := objUnderTest.BindingChanged(validCtx, nil)
+	assert.ErrorIs(t, err, events.ErrForbidden)
+}
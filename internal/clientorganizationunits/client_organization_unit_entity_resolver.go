@@ -0,0 +1,41 @@
+package clientorganizationunits
+
+import (
+	"context"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/internal/federation"
+	"iam_services_main_v1/internal/permit"
+)
+
+// ClientOrganizationUnitEntityResolver resolves the client organization units
+// other subgraphs reference.
+type ClientOrganizationUnitEntityResolver struct {
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
+}
+
+// FindManyClientOrganizationUnitByIDs resolves a batch of client organization
+// unit references, in order. It needs the clientOrganizationUnit action, like
+// the clientOrganizationUnit query. References to units of other tenants
+// resolve to null.
+func (r *ClientOrganizationUnitEntityResolver) FindManyClientOrganizationUnitByIDs(ctx context.Context, reps []*models.ClientOrganizationUnitByIDsInput) ([]*models.ClientOrganizationUnit, error) {
+	tenantID, err := federation.Authorize(ctx, r.Authorizer, "clientOrganizationUnit", config.ClientOrgUnitResourceTypeID)
+	if err != nil {
+		return nil, err
+	}
+	loaders := dataloaders.For(ctx, r.PC)
+	return federation.Resolve(ctx, reps, func(ctx context.Context, rep *models.ClientOrganizationUnitByIDsInput) (*models.ClientOrganizationUnit, error) {
+		instance, err := loaders.ResourceInstances.Load(ctx, rep.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if instance.Resource != config.ClientOrgUnitResourceTypeID || instance.Tenant != tenantID.String() {
+			return nil, nil
+		}
+		return BuildOrgUnit(instance)
+	})
+}
@@ -0,0 +1,48 @@
+package clientorganizationunits
+
+import (
+	"context"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/permit"
+	mocks "iam_services_main_v1/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindManyClientOrganizationUnitByIDs(t *testing.T) {
+	tenantID := uuid.New()
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantID.String())
+	ginCtx.Set("userID", uuid.New().String())
+	ctx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+
+	ctrl := mock.NewController(t)
+	defer ctrl.Finish()
+	own, foreign := uuid.New(), uuid.New()
+	mockPC := mocks.NewMockPermitService(ctrl)
+	mockPC.EXPECT().GetResourceInstance(mock.Any(), own.String()).Return(&permit.ResourceInstance{
+		Entity:   permit.Entity{Key: own.String(), Attributes: map[string]interface{}{"key": own.String(), "name": "Retail", "tenantId": tenantID.String()}},
+		Tenant:   tenantID.String(),
+		Resource: config.ClientOrgUnitResourceTypeID,
+	}, nil)
+	mockPC.EXPECT().GetResourceInstance(mock.Any(), foreign.String()).Return(&permit.ResourceInstance{
+		Entity:   permit.Entity{Key: foreign.String(), Attributes: map[string]interface{}{"name": "Other"}},
+		Tenant:   uuid.NewString(),
+		Resource: config.ClientOrgUnitResourceTypeID,
+	}, nil)
+
+	resolver := ClientOrganizationUnitEntityResolver{PC: mockPC, Authorizer: authz.AllowAll()}
+	units, err := resolver.FindManyClientOrganizationUnitByIDs(ctx, []*models.ClientOrganizationUnitByIDsInput{{ID: foreign}, {ID: own}})
+	assert.NoError(t, err)
+	if assert.Len(t, units, 2) {
+		assert.Nil(t, units[0])
+		assert.Equal(t, own, units[1].ID)
+		assert.Equal(t, "Retail", units[1].Name)
+	}
+}
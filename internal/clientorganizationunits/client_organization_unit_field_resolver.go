@@ -2,13 +2,12 @@ package clientorganizationunits
 
 import (
 	"context"
-	"fmt"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/dataloaders"
 	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/tenants"
 	"iam_services_main_v1/internal/users"
 	"iam_services_main_v1/pkg/logger"
-	"log"
 )
 
 // AccountFieldResolver provides database operations for resolving Account fields
@@ -18,25 +17,21 @@ type ClientOrganizationUnitResolver struct {
 
 // ParentOrg resolves the ParentOrg field on the Account type
 func (r *ClientOrganizationUnitResolver) ParentOrg(ctx context.Context, corg *models.ClientOrganizationUnit) (models.Organization, error) {
-	url := fmt.Sprintf("resource_instances/%s", corg.ParentOrg.GetID())
-	resourceResponse, err := r.PC.GetSingleResource(ctx, "GET", url)
+	resourceResponse, err := dataloaders.For(ctx, r.PC).ResourceInstances.Load(ctx, corg.ParentOrg.GetID().String())
 	if err != nil {
 		logger.LogError("error fetching parent org", "error", err)
 		return nil, err
 	}
-	log.Printf("ParentOrg: %v", resourceResponse)
-	clientOrg := BuildOrgUnit(resourceResponse)
-	return clientOrg, nil
+	return BuildOrgUnit(resourceResponse)
 }
 
 // ParentOrg resolves the ParentOrg field on the Account type
 func (r *ClientOrganizationUnitResolver) Tenant(ctx context.Context, corg *models.ClientOrganizationUnit) (*models.Tenant, error) {
-	tenantResolver := &tenants.TenantQueryResolver{PC: r.PC}
-	resourceResponse, err := tenantResolver.FetchTenant(ctx, corg.Tenant.ID)
+	resourceResponse, err := dataloaders.For(ctx, r.PC).Tenants.Load(ctx, corg.Tenant.ID.String())
 	if err != nil {
 		return nil, err
 	}
-	tenant, err := tenants.MapTenantData(resourceResponse)
+	tenant, err := tenants.MapTenantData(&resourceResponse.Entity)
 	if err != nil {
 		logger.LogError("error mapping Tenant data in MapTenantResponseToStruct", "error", err)
 		return nil, err
@@ -8,6 +8,7 @@ import (
 
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
 	mocks "iam_services_main_v1/mocks"
 
 	"github.com/gin-gonic/gin"
@@ -58,7 +59,7 @@ func TestFieldResolverTenant(t *testing.T) {
 			input: corg,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to fetch"))
+				mockService.EXPECT().GetTenant(mock.Any(), mock.Any()).Return(nil, errors.New("failed to fetch"))
 			},
 			output: nil,
 		},
@@ -67,7 +68,7 @@ func TestFieldResolverTenant(t *testing.T) {
 			input: corg,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(tenantMap, nil)
+				mockService.EXPECT().GetTenant(mock.Any(), mock.Any()).Return(tenantMap, nil)
 			},
 			output: tenant,
 		},
@@ -84,14 +85,10 @@ func TestFieldResolverTenant(t *testing.T) {
 
 }
 
-func buildTenant() map[string]interface{} {
-	tenantMap := make(map[string]interface{}, 0)
+func buildTenant() *permit.Tenant {
 	attributes := make(map[string]interface{}, 0)
 	contactInfo := make(map[string]interface{}, 0)
 	address := make(map[string]interface{}, 0)
-	tenantMap["key"] = uuid.New().String()
-	tenantMap["created_at"] = time.Now().String()
-	tenantMap["updated_at"] = time.Now().String()
 
 	contactInfo["email"] = "test@tmobile.com"
 	contactInfo["phoneNumber"] = "123-45-6789"
@@ -107,7 +104,11 @@ func buildTenant() map[string]interface{} {
 	attributes["description"] = "description"
 	attributes["name"] = "tenant"
 	attributes["contactInfo"] = contactInfo
-	tenantMap["attributes"] = attributes
-	return tenantMap
 
+	return &permit.Tenant{Entity: permit.Entity{
+		Key:        uuid.New().String(),
+		CreatedAt:  time.Now().String(),
+		UpdatedAt:  time.Now().String(),
+		Attributes: attributes,
+	}}
 }
@@ -3,13 +3,13 @@ package clientorganizationunits
 import (
 	"context"
 	"errors"
-	"fmt"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
 	constants "iam_services_main_v1/internal/constants"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/events"
 	"iam_services_main_v1/internal/permit"
-	"iam_services_main_v1/internal/utils"
 	"net/http"
 	"time"
 
@@ -20,6 +20,8 @@ import (
 
 type ClientOrganizationUnitMutationResolver struct {
 	PC permit.PermitService
+	// Events receives the changes made, for subscriptions. It may be nil.
+	Events *events.Bus
 }
 
 func (r *ClientOrganizationUnitMutationResolver) CreateClientOrganizationUnit(ctx context.Context, input models.CreateClientOrganizationUnitInput) (models.OperationResult, error) {
@@ -30,23 +32,23 @@ func (r *ClientOrganizationUnitMutationResolver) CreateClientOrganizationUnit(ct
 	logger.Info("create clientOrganizationUnit")
 
 	if input.ID == uuid.Nil {
-		return buildErrorResponse(http.StatusBadRequest, "ID is mandatory", "ID is mandatory"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "ID is mandatory", "ID is mandatory"), nil
 	}
 	if input.Name == "" {
-		return buildErrorResponse(http.StatusBadRequest, "Name is mandatory", "Name is mandatory"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "Name is mandatory", "Name is mandatory"), nil
 	}
 
 	tenantId, err := helpers.GetTenantID(ctx)
 	if err != nil {
-		return buildErrorResponse(http.StatusBadRequest, "Tenant is mandatory", "Tenant id is not present"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "Tenant is mandatory", "Tenant id is not present"), nil
 	}
 	userId, err := helpers.GetUserID(ctx)
 	if err != nil {
-		return buildErrorResponse(http.StatusBadRequest, "user id is not present", "user id not present in header"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "user id is not present", "user id not present in header"), nil
 	}
 
 	if input.AccountOwnerID == uuid.Nil {
-		return buildErrorResponse(http.StatusBadRequest, "Account owner id is mandatory", "Account owner id is mandatory"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "Account owner id is mandatory", "Account owner id is mandatory"), nil
 	}
 
 	resourceTypeId := uuid.MustParse(constants.CORG_RESOURCE_TYPE_ID)
@@ -71,18 +73,20 @@ func (r *ClientOrganizationUnitMutationResolver) CreateClientOrganizationUnit(ct
 	attributes[constants.TYPE] = config.ClientOrganizationUnit
 	attributes[constants.CORG_TAGS] = input.Tags
 
-	_, err = r.PC.APIExecute(ctx, constants.POST, constants.PERMIT_RESOURCE_INSTANCES, map[string]interface{}{
-		constants.KEY:        resourceId,
-		constants.TENANT:     tenantId,
-		constants.RESOURCE:   resourceTypeId,
-		constants.ATTRIBUTES: attributes,
+	_, err = r.PC.CreateResourceInstance(ctx, permit.ResourceInstanceCreate{
+		Key:        resourceId.String(),
+		Tenant:     tenantId.String(),
+		Resource:   resourceTypeId.String(),
+		Attributes: attributes,
 	})
 
 	if err != nil {
-		return buildErrorResponse(http.StatusBadRequest, err.Error(), "unable to create organization in permit"), nil
+		return errorcatalog.ClientOrganizationUnit.FromError(http.StatusBadRequest, "unable to create organization in permit", err), nil
 	}
 
-	return r.FormatSuccessResponse(ctx, resourceId)
+	res, err := r.FormatSuccessResponse(ctx, resourceId)
+	r.Events.OrganizationChanged(models.ChangeTypeEnumCreated, config.ClientOrganizationUnit, *tenantId, resourceId, res)
+	return res, err
 }
 
 func (r *ClientOrganizationUnitMutationResolver) UpdateClientOrganizationUnit(ctx context.Context, input models.UpdateClientOrganizationUnitInput) (models.OperationResult, error) {
@@ -92,36 +96,35 @@ func (r *ClientOrganizationUnitMutationResolver) UpdateClientOrganizationUnit(ct
 	})
 	var zeroUUID uuid.UUID
 	if input.ID == zeroUUID {
-		return buildErrorResponse(http.StatusBadRequest, "invalid id provided in the request", "unable to update organization in permit"), errors.New("id is mandatory for update")
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "invalid id provided in the request", "unable to update organization in permit"), errors.New("id is mandatory for update")
 	}
 
 	tenantId, err := helpers.GetTenantID(ctx)
 	if err != nil {
 		logger.Error("unable to find tenant id")
-		return buildErrorResponse(http.StatusBadRequest, "tenant id not present", "tenant id not present"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "tenant id not present", "tenant id not present"), nil
 	}
 
 	userId, err := helpers.GetUserID(ctx)
 	if err != nil {
 		logger.Error("unable to find user id")
-		return buildErrorResponse(http.StatusBadRequest, "user id not present", "user id not present"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "user id not present", "user id not present"), nil
 	}
 
 	if input.ParentID != nil && input.RelationType == nil {
-		return buildErrorResponse(http.StatusBadRequest, "relation should not be empty", "relation type should not be empty"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "relation should not be empty", "relation type should not be empty"), nil
 	}
 
 	if !input.RelationType.IsValid() {
-		return buildErrorResponse(http.StatusBadRequest, "relation type is invalid", "relation type is invalid"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "relation type is invalid", "relation type is invalid"), nil
 	}
 
-	url := fmt.Sprintf(constants.PERMIT_RESOURCE_INSTANCES+"/%s", input.ID)
-	clientOrg, err := r.PC.GetSingleResource(ctx, "GET", url)
+	clientOrg, err := r.PC.GetResourceInstance(permit.WithFreshReads(ctx), input.ID.String())
 	if err != nil {
-		return buildErrorResponse(http.StatusBadRequest, err.Error(), "unable to update organization in permit"), nil
+		return errorcatalog.ClientOrganizationUnit.FromError(http.StatusBadRequest, "unable to update organization in permit", err), nil
 	}
 
-	attributes := clientOrg[constants.ATTRIBUTES].(map[string]interface{})
+	attributes := clientOrg.Attributes
 
 	updatedAt := time.Now()
 	if attributes != nil {
@@ -137,17 +140,18 @@ func (r *ClientOrganizationUnitMutationResolver) UpdateClientOrganizationUnit(ct
 		attributes[constants.CORG_TAGS] = input.Tags
 
 		logger.Info("Client org in permit is ", clientOrg)
-		reqBody := map[string]interface{}{
-			constants.ATTRIBUTES: attributes,
-		}
-		_, err = r.PC.APIExecute(ctx, constants.PATCH, url, reqBody)
+		_, err = r.PC.UpdateResourceInstance(ctx, input.ID.String(), permit.ResourceInstanceUpdate{
+			Attributes: attributes,
+		})
 
 		if err != nil {
-			return buildErrorResponse(http.StatusBadRequest, err.Error(), "unable to update organization in permit"), nil
+			return errorcatalog.ClientOrganizationUnit.FromError(http.StatusBadRequest, "unable to update organization in permit", err), nil
 		}
 	}
 
-	return r.FormatSuccessResponse(ctx, input.ID)
+	res, err := r.FormatSuccessResponse(ctx, input.ID)
+	r.Events.OrganizationChanged(models.ChangeTypeEnumUpdated, config.ClientOrganizationUnit, *tenantId, input.ID, res)
+	return res, err
 }
 
 func (r *ClientOrganizationUnitMutationResolver) DeleteClientOrganizationUnit(ctx context.Context, input models.DeleteInput) (models.OperationResult, error) {
@@ -158,52 +162,44 @@ func (r *ClientOrganizationUnitMutationResolver) DeleteClientOrganizationUnit(ct
 
 	var zeroUUID uuid.UUID
 	if input.ID == zeroUUID {
-		return buildErrorResponse(http.StatusBadRequest, "invalid input id", "id is mandatory"), errors.New("id is mandatory for delete")
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "invalid input id", "id is mandatory"), errors.New("id is mandatory for delete")
 	}
 
 	logger.Info("delete client organization request received")
 
-	resourceURL := fmt.Sprintf("resource_instances/%s", input.ID)
-	clientOrgResource, err := r.PC.SendRequest(ctx, "GET", resourceURL, nil)
+	clientOrgResource, err := r.PC.GetResourceInstance(ctx, input.ID.String())
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get existing client org data", err.Error()), nil
+		return errorcatalog.ClientOrganizationUnit.FromError(http.StatusBadRequest, "Failed to get existing client org data", err), nil
 	}
 	if clientOrgResource == nil {
-		return utils.FormatErrorResponse(http.StatusNotFound, "Client org not found", "The client org with the provided ID does not exist"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusNotFound, "Client org not found", "The client org with the provided ID does not exist"), nil
 	}
-	resourceType := helpers.GetString(clientOrgResource, "resource")
-	if resourceType != config.ClientOrgUnitResourceTypeID {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "The provided client org ID does not match the expected resource type for deletion", "The provided client org ID does not match the expected resource type for deletion"), nil
+	if clientOrgResource.Resource != config.ClientOrgUnitResourceTypeID {
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "The provided client org ID does not match the expected resource type for deletion", "The provided client org ID does not match the expected resource type for deletion"), nil
 	}
 
 	tenantID, err := helpers.GetTenantID(ctx)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get tenant ID", err.Error()), nil
+		return errorcatalog.ClientOrganizationUnit.FromError(http.StatusBadRequest, "Failed to get tenant ID", err), nil
 	}
 	subjectType := config.ClientOrgUnitResourceTypeID + ":" + input.ID.String()
-	url := fmt.Sprintf("relationship_tuples/detailed?tenant=%s&subject=%s",
-		tenantID.String(),
-		subjectType,
-	)
-	clientOrgUnitResponse, err := r.PC.SendRequest(ctx, constants.GET, url, nil)
+	tuples, _, err := r.PC.ListRelationshipTuples(ctx, permit.RelationshipTupleFilter{
+		Tenant:  tenantID.String(),
+		Subject: subjectType,
+	})
 	if err != nil {
-		return buildErrorResponse(http.StatusNotFound, err.Error(), "unable to fetch resource from permit"), nil
-	}
-	rawData, ok := clientOrgUnitResponse["data"].([]interface{})
-	if !ok {
-		return buildErrorResponse(http.StatusNotFound, "missing or invalid data field", "missing or invalid data field"), nil
+		return errorcatalog.ClientOrganizationUnit.FromError(http.StatusNotFound, "unable to fetch resource from permit", err), nil
 	}
-	if len(rawData) > 0 {
-		return buildErrorResponse(http.StatusBadRequest, "unable to delete organization due to associated accounts", "organization has accounts associated with it"), nil
+	if len(tuples) > 0 {
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "unable to delete organization due to associated accounts", "organization has accounts associated with it"), nil
 	}
 
-	url = fmt.Sprintf(constants.PERMIT_RESOURCE_INSTANCES+"/%s", input.ID)
-
-	_, err = r.PC.APIExecute(ctx, constants.DELETE, url, nil)
+	err = r.PC.DeleteResourceInstance(ctx, input.ID.String())
 	if err != nil {
 		// do we need to rever in our database too?
-		return buildErrorResponse(http.StatusBadRequest, err.Error(), "unable to delete organization in permit"), nil
+		return errorcatalog.ClientOrganizationUnit.FromError(http.StatusBadRequest, "unable to delete organization in permit", err), nil
 	}
+	r.Events.OrganizationChanged(models.ChangeTypeEnumDeleted, config.ClientOrganizationUnit, *tenantID, input.ID, nil)
 
 	result := models.SuccessResponse{
 		Data:      make([]models.Data, 0),
@@ -214,11 +210,14 @@ func (r *ClientOrganizationUnitMutationResolver) DeleteClientOrganizationUnit(ct
 }
 
 func (r *ClientOrganizationUnitMutationResolver) FormatSuccessResponse(ctx context.Context, resourceId uuid.UUID) (models.OperationResult, error) {
-	url := fmt.Sprintf(constants.PERMIT_RESOURCE_INSTANCES+"/%s", resourceId)
-	res, err := r.PC.GetSingleResource(ctx, constants.GET, url)
+	res, err := r.PC.GetResourceInstance(ctx, resourceId.String())
+	if err != nil {
+		return errorcatalog.ClientOrganizationUnit.FromError(http.StatusInternalServerError, "unable to fetch corg details", err), nil
+	}
+	unit, err := BuildOrgUnit(res)
 	if err != nil {
-		return buildErrorResponse(http.StatusInternalServerError, "unable to fetch corg details", err.Error()), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusInternalServerError, "unable to map corg details", err.Error()), nil
 	}
-	return buildSuccessResponse(BuildOrgUnit(res)), nil
+	return buildSuccessResponse(unit), nil
 
 }
@@ -7,6 +7,7 @@ import (
 
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
 	mocks "iam_services_main_v1/mocks"
 
 	"github.com/gin-gonic/gin"
@@ -57,6 +58,7 @@ func TestCreateClientOrganizationUnit(t *testing.T) {
 	}
 
 	corg := buildClientOrganization()
+	unit, _ := BuildOrgUnit(corg)
 	testcases := []struct {
 		name      string
 		input     models.CreateClientOrganizationUnitInput
@@ -69,33 +71,33 @@ func TestCreateClientOrganizationUnit(t *testing.T) {
 			input:     nilIdInput,
 			ctx:       context.WithValue(context.Background(), config.GinContextKey, &gin.Context{}),
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "unable to fetch gin context", "error while fetching gin context"),
+			output:    errorcatalog.ClientOrganizationUnit.New(400, "unable to fetch gin context", "error while fetching gin context"),
 		},
 		{
 			name:      "CreateClientOrganizationUnit Name not present",
 			input:     nameNotPresent,
 			ctx:       testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "failed", "unable to fetch resource from permit"),
+			output:    errorcatalog.ClientOrganizationUnit.New(400, "failed", "unable to fetch resource from permit"),
 		},
 		{
 			name:  "CreateClientOrganizationUnit Tenant failed error from permit",
 			input: successRequest,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().APIExecute(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to create"))
+				mockService.EXPECT().CreateResourceInstance(mock.Any(), mock.Any()).Return(nil, errors.New("failed to create"))
 			},
-			output: buildErrorResponse(400, "unable to create organization in permit", "failed to create"),
+			output: errorcatalog.ClientOrganizationUnit.New(400, "unable to create organization in permit", "failed to create"),
 		},
 		{
 			name:  "CreateClientOrganizationUnit success",
 			input: successRequest,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().APIExecute(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, nil)
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(corg, nil).MaxTimes(1)
+				mockService.EXPECT().CreateResourceInstance(mock.Any(), mock.Any()).Return(corg, nil)
+				mockService.EXPECT().GetResourceInstance(mock.Any(), mock.Any()).Return(corg, nil).MaxTimes(1)
 			},
-			output: buildSuccessResponse(BuildOrgUnit(corg)),
+			output: buildSuccessResponse(unit),
 		},
 	}
 
@@ -136,6 +138,7 @@ func TestUpdateClientOrganizationUnit(t *testing.T) {
 
 	corg := buildClientOrganization()
 	corgUpdated := buildClientOrganization()
+	unit, _ := BuildOrgUnit(corg)
 	validCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtxWithUserId)
 	nilIdInput := models.UpdateClientOrganizationUnitInput{
 		ID: uuid.Nil,
@@ -166,50 +169,50 @@ func TestUpdateClientOrganizationUnit(t *testing.T) {
 			input:     nilIdInput,
 			ctx:       context.WithValue(context.Background(), config.GinContextKey, &gin.Context{}),
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "unable to fetch gin context", "error while fetching gin context"),
+			output:    errorcatalog.ClientOrganizationUnit.New(400, "unable to fetch gin context", "error while fetching gin context"),
 		},
 		{
 			name:  "UpdateClientOrganizationUnit fetch resource from permit failed",
 			input: successRequest,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to get"))
+				mockService.EXPECT().GetResourceInstance(mock.Any(), mock.Any()).Return(nil, errors.New("failed to get"))
 			},
-			output: buildErrorResponse(400, "unable to create organization in permit", "failed to create"),
+			output: errorcatalog.ClientOrganizationUnit.New(400, "unable to create organization in permit", "failed to create"),
 		},
 		{
 			name:  "UpdateClientOrganizationUnit update request failed",
 			input: successRequest,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(corg, nil)
-				mockService.EXPECT().APIExecute(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to update"))
+				mockService.EXPECT().GetResourceInstance(mock.Any(), mock.Any()).Return(corg, nil)
+				mockService.EXPECT().UpdateResourceInstance(mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed to update"))
 			},
-			output: buildErrorResponse(400, "unable to create organization in permit", "failed to create"),
+			output: errorcatalog.ClientOrganizationUnit.New(400, "unable to create organization in permit", "failed to create"),
 		},
 		{
 			name:  "UpdateClientOrganizationUnit update successful",
 			input: successRequest,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(corg, nil)
-				mockService.EXPECT().APIExecute(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, nil)
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed"))
+				mockService.EXPECT().GetResourceInstance(mock.Any(), mock.Any()).Return(corg, nil)
+				mockService.EXPECT().UpdateResourceInstance(mock.Any(), mock.Any(), mock.Any()).Return(corg, nil)
+				mockService.EXPECT().GetResourceInstance(mock.Any(), mock.Any()).Return(nil, errors.New("failed"))
 
 			},
-			output: buildErrorResponse(400, "unable to create organization in permit", "failed to create"),
+			output: errorcatalog.ClientOrganizationUnit.New(400, "unable to create organization in permit", "failed to create"),
 		},
 		{
 			name:  "UpdateClientOrganizationUnit update successful",
 			input: successRequest,
 			ctx:   validCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(corg, nil)
-				mockService.EXPECT().APIExecute(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil, nil)
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(corgUpdated, nil)
+				mockService.EXPECT().GetResourceInstance(mock.Any(), mock.Any()).Return(corg, nil)
+				mockService.EXPECT().UpdateResourceInstance(mock.Any(), mock.Any(), mock.Any()).Return(corg, nil)
+				mockService.EXPECT().GetResourceInstance(mock.Any(), mock.Any()).Return(corgUpdated, nil)
 
 			},
-			output: buildSuccessResponse(BuildOrgUnit(corg)),
+			output: buildSuccessResponse(unit),
 		},
 	}
 
@@ -263,7 +266,7 @@ func TestDeleteClientOrganizationUnit(t *testing.T) {
 			input:     inputNullId,
 			ctx:       context.WithValue(context.Background(), config.GinContextKey, &gin.Context{}),
 			mockStubs: func(mockSvc mocks.MockPermitService) {},
-			output:    buildErrorResponse(400, "unable to fetch gin context", "error while fetching gin context"),
+			output:    errorcatalog.ClientOrganizationUnit.New(400, "unable to fetch gin context", "error while fetching gin context"),
 		},
 	}
 
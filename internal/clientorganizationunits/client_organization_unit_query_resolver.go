@@ -3,12 +3,15 @@ package clientorganizationunits
 import (
 	"context"
 	"fmt"
-	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/authz"
 	constants "iam_services_main_v1/internal/constants"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/filters"
 	"iam_services_main_v1/internal/permit"
 	tag_helper "iam_services_main_v1/internal/tags"
+	"iam_services_main_v1/internal/utils"
 	"net/http"
 
 	log "github.com/sirupsen/logrus"
@@ -17,8 +20,8 @@ import (
 )
 
 type ClientOrganizationUnitQueryResolver struct {
-	PC  permit.PermitService
-	PSC *permit.PermitSdkService
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
 }
 
 func (r *ClientOrganizationUnitQueryResolver) ClientOrganizationUnit(ctx context.Context, id uuid.UUID) (models.OperationResult, error) {
@@ -30,95 +33,176 @@ func (r *ClientOrganizationUnitQueryResolver) ClientOrganizationUnit(ctx context
 	_, err := helpers.GetTenantID(ctx)
 	if err != nil {
 		logger.Error("unable to fetch tenantID")
-		return buildErrorResponse(400, "unable to find tenantid", "tenantId is not present in header"), nil
+		return errorcatalog.ClientOrganizationUnit.New(400, "unable to find tenantid", "tenantId is not present in header"), nil
 	}
 
-	url := fmt.Sprintf(constants.PERMIT_RESOURCE_INSTANCES+"/%s", id)
-	res, err := r.PC.GetSingleResource(ctx, constants.GET, url)
+	res, err := r.PC.GetResourceInstance(ctx, id.String())
 	if err != nil {
-		return buildErrorResponse(http.StatusNotFound, err.Error(), "unable to fetch resource from permit"), nil
+		return errorcatalog.ClientOrganizationUnit.FromError(http.StatusNotFound, "unable to fetch resource from permit", err), nil
 	}
 
-	return buildSuccessResponse(BuildOrgUnit(res)), nil
+	unit, err := BuildOrgUnit(res)
+	if err != nil {
+		logger.Error("unable to map client org from permit")
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusInternalServerError, "unable to map client organization unit", err.Error()), nil
+	}
+	return buildSuccessResponse(unit), nil
 }
 
-func (r *ClientOrganizationUnitQueryResolver) ClientOrganizationUnits(ctx context.Context) (models.OperationResult, error) {
+func (r *ClientOrganizationUnitQueryResolver) ClientOrganizationUnits(ctx context.Context, filter *models.OrganizationFilter, orderBy *models.OrganizationOrder) (models.OperationResult, error) {
 	logger := log.WithContext(ctx).WithFields(log.Fields{
 		"className":  "organization_query_resolver",
 		"methodName": "ClientOrganizationUnits",
 	})
 	tenantId, err := helpers.GetTenantID(ctx)
 	if err != nil {
-		return buildErrorResponse(http.StatusBadRequest, "unable to find tenant id", "unable to find tenant id in headers"), nil
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "unable to find tenant id", "unable to find tenant id in headers"), nil
+	}
+	if err := filters.Validate(filter); err != nil {
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "invalid client organization unit filter", err.Error()), nil
+	}
+
+	orgs, totalCount, errResponse := r.fetchOrgUnits(ctx, logger, tenantId.String(), filter, orderBy)
+	if errResponse != nil {
+		return errResponse, nil
+	}
+	result := models.SuccessResponse{
+		Data:       orgs,
+		IsSuccess:  true,
+		Message:    "All Client Organizations retrieved successfully",
+		TotalCount: &totalCount,
 	}
 
-	endpoint := fmt.Sprintf(constants.PERMIT_RESOURCE_INSTANCES+"/detailed?tenant=%s&resource=%s", tenantId, constants.CORG_RESOURCE_TYPE_ID)
-	permitResult, err := r.PC.SendRequest(ctx, constants.GET, endpoint, nil)
+	return result, nil
+}
+
+// ClientOrganizationUnitsConnection retrieves one page of the client
+// organization units of the tenant in the context that match filter, ordered
+// by orderBy. Without a filter or ordering the page is read from Permit's own
+// paging, otherwise it is cut from the filtered and ordered list.
+func (r *ClientOrganizationUnitQueryResolver) ClientOrganizationUnitsConnection(ctx context.Context, after *string, filter *models.OrganizationFilter, first *int, orderBy *models.OrganizationOrder) (models.OperationResult, error) {
+	logger := log.WithContext(ctx).WithFields(log.Fields{
+		"className":  "organization_query_resolver",
+		"methodName": "ClientOrganizationUnitsConnection",
+	})
+	page, err := utils.ParsePage(first, after)
+	if err != nil {
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "invalid pagination arguments", err.Error()), nil
+	}
+	tenantId, err := helpers.GetTenantID(ctx)
+	if err != nil {
+		return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "unable to find tenant id", "unable to find tenant id in headers"), nil
+	}
+
+	if filter != nil || orderBy != nil {
+		if err := filters.Validate(filter); err != nil {
+			return errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "invalid client organization unit filter", err.Error()), nil
+		}
+		orgs, totalCount, errResponse := r.fetchOrgUnits(ctx, logger, tenantId.String(), filter, orderBy)
+		if errResponse != nil {
+			return errResponse, nil
+		}
+		return utils.FormatConnection(utils.PageOf(orgs, page), page, totalCount), nil
+	}
+
+	clientOrgs, totalCount, err := r.PC.ListResourceInstancesPage(ctx, tenantId.String(), constants.CORG_RESOURCE_TYPE_ID, page)
 	if err != nil {
 		logger.Error("unable to fetch client orgs from permit")
 		message := "unable to fetch client organization units from permit"
-		return buildErrorResponse(http.StatusNotFound, err.Error(), message), nil
+		return errorcatalog.ClientOrganizationUnit.FromError(http.StatusNotFound, message, err), nil
 	}
 
-	clientOrgs := permitResult["data"].([]interface{})
-	var orgs []models.Data
-	for _, corg := range clientOrgs {
-		clientOrgUnit := corg.(map[string]interface{})
-
-		if clientOrgUnit != nil {
-			unit := BuildOrgUnit(corg.(map[string]interface{}))
-			orgs = append(orgs, unit)
+	orgs := make([]models.Data, 0, len(clientOrgs))
+	for i := range clientOrgs {
+		unit, err := BuildOrgUnit(&clientOrgs[i])
+		if err != nil {
+			logger.Error("unable to map client org from permit")
+			return errorcatalog.ClientOrganizationUnit.New(http.StatusInternalServerError, "unable to map client organization units", err.Error()), nil
 		}
+		orgs = append(orgs, unit)
 	}
-	result := models.SuccessResponse{
-		Data:      orgs,
-		IsSuccess: true,
-		Message:   "All Client Organizations retrieved successfully",
+	return utils.FormatConnection(orgs, page, totalCount), nil
+}
+
+// fetchOrgUnits reads the client organization units of a tenant from Permit
+// that match filter, ordered by orderBy, together with their total number.
+// Without a filter the total is the one reported by Permit. On failure it
+// returns the error response to send.
+func (r *ClientOrganizationUnitQueryResolver) fetchOrgUnits(ctx context.Context, logger *log.Entry, tenantId string, filter *models.OrganizationFilter, orderBy *models.OrganizationOrder) ([]models.Data, int, *models.ResponseError) {
+	clientOrgs, totalCount, err := r.PC.ListResourceInstances(ctx, tenantId, constants.CORG_RESOURCE_TYPE_ID)
+	if err != nil {
+		logger.Error("unable to fetch client orgs from permit")
+		message := "unable to fetch client organization units from permit"
+		return nil, 0, errorcatalog.ClientOrganizationUnit.FromError(http.StatusNotFound, message, err)
 	}
 
-	return result, nil
+	var orgs []models.Data
+	for i := range clientOrgs {
+		unit, err := BuildOrgUnit(&clientOrgs[i])
+		if err != nil {
+			logger.Error("unable to map client org from permit")
+			return nil, 0, errorcatalog.ClientOrganizationUnit.New(http.StatusInternalServerError, "unable to map client organization units", err.Error())
+		}
+		orgs = append(orgs, unit)
+	}
+
+	orgs, err = filters.Apply(orgs, filter, orderBy)
+	if err != nil {
+		return nil, 0, errorcatalog.ClientOrganizationUnit.New(http.StatusBadRequest, "invalid client organization unit filter", err.Error())
+	}
+	if filter != nil {
+		totalCount = len(orgs)
+	}
+	return orgs, totalCount, nil
 }
 
-func BuildOrgUnit(result map[string]interface{}) *models.ClientOrganizationUnit {
-	attributes := result[constants.ATTRIBUTES].(map[string]interface{})
-	description := attributes[constants.DESCRIPTION].(string)
-	tenantId := uuid.MustParse(attributes[constants.TENANT_ID].(string))
-	key := uuid.MustParse(attributes[constants.KEY].(string))
-	createdBy := uuid.MustParse(attributes[constants.CREATED_BY].(string))
-	updatedBy := uuid.MustParse(attributes[constants.UPDATED_BY].(string))
-	relationType := models.RelationTypeEnum(attributes[constants.CORG_RELATION_TYPE].(string))
-	status := models.StatusTypeEnum(attributes[constants.CORG_STATUS].(string))
-	accountOwner := &models.User{ID: uuid.MustParse(attributes[constants.CORG_ACCOUNT_OWNER_ID].(string))}
-	createdAt, _ := helpers.ConvertToZFormat(attributes[constants.CREATED_AT].(string))
-	updatedAt, _ := helpers.ConvertToZFormat(attributes[constants.UPDATED_AT].(string))
-
-	// if len(tags) == 0 {
-	// 	tagList = make([]*models.Tags, 0)
-	// } else {
-	// 	for _, v := range tags {
-	// 		tag := &models.Tags{}
-	// 		if tagMap, ok := v.(map[string]interface{}); ok {
-	// 			tag.Key = helpers.GetString(tagMap, "key")
-	// 			tag.Value = helpers.GetString(tagMap, "value")
-	// 			tagList = append(tagList, tag)
-	// 		}
-	// 	}
-	// }
+// BuildOrgUnit maps a Permit resource instance to a ClientOrganizationUnit model.
+// It returns an error when the instance is missing its attributes, key or tenant.
+func BuildOrgUnit(result *permit.ResourceInstance) (*models.ClientOrganizationUnit, error) {
+	if result == nil || result.Attributes == nil {
+		return nil, fmt.Errorf("missing or invalid map for key: %s", constants.ATTRIBUTES)
+	}
+	attributes := result.Attributes
+	key, err := helpers.GetUUID(attributes, constants.KEY)
+	if err != nil {
+		return nil, err
+	}
+	tenantId, err := helpers.GetUUID(attributes, constants.TENANT_ID)
+	if err != nil {
+		return nil, err
+	}
+	description := helpers.GetString(attributes, constants.DESCRIPTION)
+	createdBy, _ := helpers.GetUUID(attributes, constants.CREATED_BY)
+	updatedBy, _ := helpers.GetUUID(attributes, constants.UPDATED_BY)
+	accountOwnerId, _ := helpers.GetUUID(attributes, constants.CORG_ACCOUNT_OWNER_ID)
+	relationType := models.RelationTypeEnum(helpers.GetString(attributes, constants.CORG_RELATION_TYPE))
+	status := models.StatusTypeEnum(helpers.GetString(attributes, constants.CORG_STATUS))
+	parentId, _ := helpers.GetUUID(attributes, constants.PARENT_RESOURCE_ID)
+	var parentOrg models.Organization
+	if relationType == models.RelationTypeEnumChild {
+		parentOrg = &models.Tenant{ID: parentId}
+	} else {
+		parentOrg = &models.ClientOrganizationUnit{ID: parentId}
+	}
+	createdAt, _ := helpers.ConvertToZFormat(helpers.GetString(attributes, constants.CREATED_AT))
+	updatedAt, _ := helpers.ConvertToZFormat(helpers.GetString(attributes, constants.UPDATED_AT))
+
 	unit := &models.ClientOrganizationUnit{
 		ID:           key,
-		Name:         attributes[constants.NAME].(string),
+		Name:         helpers.GetString(attributes, constants.NAME),
 		Description:  &description,
 		Tenant:       &models.Tenant{ID: tenantId},
+		ParentOrg:    parentOrg,
 		CreatedAt:    createdAt,
 		UpdatedAt:    updatedAt,
 		CreatedBy:    createdBy,
 		UpdatedBy:    updatedBy,
 		RelationType: relationType,
-		AccountOwner: accountOwner,
+		AccountOwner: &models.User{ID: accountOwnerId},
 		Status:       status,
 		Tags:         tag_helper.GetResourceTags(attributes, constants.CORG_TAGS),
 	}
-	return unit
+	return unit, nil
 }
 
 func buildSuccessResponse(unit *models.ClientOrganizationUnit) models.OperationResult {
@@ -131,12 +215,3 @@ func buildSuccessResponse(unit *models.ClientOrganizationUnit) models.OperationR
 	}
 	return result
 }
-func buildErrorResponse(statusCode int, errorDetail, message string) models.ResponseError {
-	return models.ResponseError{
-		ErrorCode:     fmt.Sprint(statusCode),
-		ErrorDetails:  &errorDetail,
-		IsSuccess:     false,
-		Message:       config.GenericErrorMessage,
-		SystemMessage: message,
-	}
-}
@@ -8,6 +8,9 @@ import (
 
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	constants "iam_services_main_v1/internal/constants"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/permit"
 	mocks "iam_services_main_v1/mocks"
 
 	"github.com/gin-gonic/gin"
@@ -30,6 +33,7 @@ func TestClientOrganizationUnitQueryResolver(t *testing.T) {
 	testCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
 
 	corg := buildClientOrganization()
+	unit, _ := BuildOrgUnit(corg)
 	testcases := []struct {
 		name      string
 		input     uuid.UUID
@@ -42,9 +46,9 @@ func TestClientOrganizationUnitQueryResolver(t *testing.T) {
 			input: uuid.New(),
 			ctx:   testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(nil, errors.New("failed"))
+				mockService.EXPECT().GetResourceInstance(mock.Any(), mock.Any()).Return(nil, errors.New("failed"))
 			},
-			output: buildErrorResponse(400, "failed", "unable to fetch resource from permit"),
+			output: errorcatalog.ClientOrganizationUnit.New(400, "failed", "unable to fetch resource from permit"),
 		},
 
 		{
@@ -52,9 +56,9 @@ func TestClientOrganizationUnitQueryResolver(t *testing.T) {
 			input: uuid.New(),
 			ctx:   testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().GetSingleResource(mock.Any(), mock.Any(), mock.Any()).Return(corg, nil)
+				mockService.EXPECT().GetResourceInstance(mock.Any(), mock.Any()).Return(corg, nil)
 			},
-			output: buildSuccessResponse(BuildOrgUnit(corg)),
+			output: buildSuccessResponse(unit),
 		},
 	}
 
@@ -83,10 +87,8 @@ func TestClientOrganizationUnitsQueryResolver(t *testing.T) {
 	testCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
 
 	corg := buildClientOrganization()
-	corgs := make([]interface{}, 0)
-	corgs = append(corgs, corg)
-	corgResult := make(map[string]interface{}, 0)
-	corgResult["data"] = corgs
+	unit, _ := BuildOrgUnit(corg)
+	corgResult := []permit.ResourceInstance{*corg}
 	testcases := []struct {
 		name      string
 		ctx       context.Context
@@ -97,25 +99,25 @@ func TestClientOrganizationUnitsQueryResolver(t *testing.T) {
 			name: "Test GetClientOrganizationUnitByID when client returns error",
 			ctx:  testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().SendRequest(mock.Any(), mock.Any(), mock.Any(), nil).Return(nil, errors.New("failed"))
+				mockService.EXPECT().ListResourceInstances(mock.Any(), mock.Any(), mock.Any()).Return(nil, 0, errors.New("failed"))
 			},
-			output: buildErrorResponse(400, "failed", "unable to fetch resource from permit"),
+			output: errorcatalog.ClientOrganizationUnit.New(400, "failed", "unable to fetch resource from permit"),
 		},
 
 		{
 			name: "Test GetClientOrganizationUnitByID success",
 			ctx:  testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
-				mockService.EXPECT().SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(corgResult, nil)
+				mockService.EXPECT().ListResourceInstances(mock.Any(), mock.Any(), mock.Any()).Return(corgResult, len(corgResult), nil)
 			},
-			output: buildSuccessResponse(BuildOrgUnit(corg)),
+			output: buildSuccessResponse(unit),
 		},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockStubs(*mockService)
-			result, _ := objUnderTest.ClientOrganizationUnits(tc.ctx)
+			result, _ := objUnderTest.ClientOrganizationUnits(tc.ctx, nil, nil)
 			assert.NotNil(t, result)
 		})
 
@@ -123,6 +125,38 @@ func TestClientOrganizationUnitsQueryResolver(t *testing.T) {
 
 }
 
+func TestClientOrganizationUnitsFilter(t *testing.T) {
+	ctrl := mock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPermitService(ctrl)
+	objUnderTest := ClientOrganizationUnitQueryResolver{
+		PC: mockService,
+	}
+
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", "7ed6cfa6-fd7e-4a2a-bbce-773ef8ea4c12")
+	testCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+
+	parentId := uuid.New()
+	child, other := buildClientOrganization(), buildClientOrganization()
+	child.Attributes[constants.PARENT_RESOURCE_ID] = parentId.String()
+	other.Attributes[constants.PARENT_RESOURCE_ID] = uuid.New().String()
+	mockService.EXPECT().
+		ListResourceInstances(mock.Any(), "7ed6cfa6-fd7e-4a2a-bbce-773ef8ea4c12", constants.CORG_RESOURCE_TYPE_ID).
+		Return([]permit.ResourceInstance{*other, *child}, 2, nil)
+
+	result, err := objUnderTest.ClientOrganizationUnits(testCtx, &models.OrganizationFilter{ParentID: &parentId}, nil)
+
+	assert.NoError(t, err)
+	success, ok := result.(models.SuccessResponse)
+	assert.True(t, ok)
+	assert.Equal(t, 1, *success.TotalCount)
+	unit := success.Data[0].(*models.ClientOrganizationUnit)
+	assert.Equal(t, child.Key, unit.ID.String())
+	assert.Equal(t, &models.ClientOrganizationUnit{ID: parentId}, unit.ParentOrg)
+}
+
 func buildClientOrganizationData() *models.ClientOrganizationUnit {
 	return &models.ClientOrganizationUnit{
 		ParentOrg: nil,
@@ -132,9 +166,8 @@ func buildClientOrganizationData() *models.ClientOrganizationUnit {
 	}
 }
 
-func buildClientOrganization() map[string]interface{} {
+func buildClientOrganization() *permit.ResourceInstance {
 	id := uuid.New()
-	result := make(map[string]interface{}, 0)
 	attributes := make(map[string]interface{}, 0)
 	attributes["tenantId"] = id.String()
 	attributes["parentOrgId"] = "parentOrgId"
@@ -148,9 +181,9 @@ func buildClientOrganization() map[string]interface{} {
 	attributes["relation_type"] = "PARENT"
 	attributes["status"] = "ACTIVE"
 	attributes["account_owner_id"] = uuid.New().String()
-	result["key"] = id.String()
-	result["name"] = "clientorg"
-	result["attributes"] = attributes
 
-	return result
+	return &permit.ResourceInstance{Entity: permit.Entity{
+		Key:        id.String(),
+		Attributes: attributes,
+	}}
 }
@@ -0,0 +1,164 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"iam_services_main_v1/internal/permit"
+)
+
+// FetchFunc fetches the values of keys. It returns one value and one error per
+// key, in the order of keys.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) ([]V, []error)
+
+// fetchTimeout bounds the fetch of a batch, which the end of the contexts of
+// its Loads does not stop.
+var fetchTimeout = time.Minute
+
+// Loader collects the keys loaded within a short window into one call to its
+// FetchFunc and remembers the values and the keys that do not exist, so each
+// such key is fetched at most once for as long as the loader lives. Any other
+// failure, e.g. an unavailable upstream, is forgotten, so the next Load of the
+// key fetches it again. It is meant to live for one request.
+type Loader[K comparable, V any] struct {
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	results map[K]*result[V]
+	pending *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	// stale reports whether the batch of the value read stale cached data.
+	stale bool
+}
+
+type batch[K comparable, V any] struct {
+	ctx     context.Context
+	keys    []K
+	results []*result[V]
+}
+
+// NewLoader returns a loader that waits up to wait for more keys before
+// fetching a batch, and fetches at once when maxBatch keys are pending. A wait
+// of zero fetches every key on its own, with the context of its Load, and
+// remembers nothing.
+func NewLoader[K comparable, V any](fetch FetchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		results:  map[K]*result[V]{},
+	}
+}
+
+// Load returns the value of key. A batch is fetched with the values of the
+// context of the first Load that joined it, but neither its cancellation nor
+// its deadline: the batch is bounded by fetchTimeout instead. Every Load of a
+// value whose batch read stale cached data records it in its own context.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	if l.wait <= 0 {
+		return l.loadOne(ctx, key)
+	}
+
+	l.mu.Lock()
+	r, ok := l.results[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.results[key] = r
+		l.enqueue(ctx, key, r)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		if r.stale {
+			permit.MarkStale(ctx)
+		}
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// loadOne fetches key on its own with ctx, as if the caller read it itself.
+func (l *Loader[K, V]) loadOne(ctx context.Context, key K) (V, error) {
+	var value V
+	var err error
+	values, errs := l.fetch(ctx, []K{key})
+	if len(values) > 0 {
+		value = values[0]
+	}
+	if len(errs) > 0 {
+		err = errs[0]
+	}
+	return value, err
+}
+
+// enqueue adds key to the pending batch. The caller must hold l.mu.
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, r *result[V]) {
+	if l.pending == nil {
+		l.pending = &batch[K, V]{ctx: ctx}
+		pending := l.pending
+		time.AfterFunc(l.wait, func() { l.dispatch(pending) })
+	}
+	l.pending.keys = append(l.pending.keys, key)
+	l.pending.results = append(l.pending.results, r)
+	if l.maxBatch > 0 && len(l.pending.keys) >= l.maxBatch {
+		go l.dispatch(l.pending)
+		l.pending = nil
+	}
+}
+
+// dispatch fetches b unless it has already been fetched.
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	l.mu.Lock()
+	if l.pending == b {
+		l.pending = nil
+	} else if b.keys == nil {
+		l.mu.Unlock()
+		return
+	}
+	keys, results := b.keys, b.results
+	// A batch dispatched early is marked so its timer does nothing.
+	b.keys = nil
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(b.ctx), fetchTimeout)
+	defer cancel()
+	ctx, stale := permit.TrackStaleReads(ctx)
+	values, errs := l.fetch(ctx, keys)
+	l.mu.Lock()
+	for i, r := range results {
+		r.stale = stale()
+		if i < len(values) {
+			r.value = values[i]
+		}
+		if i < len(errs) {
+			r.err = errs[i]
+		}
+		if r.err != nil && !isNotFound(r.err) && l.results[keys[i]] == r {
+			delete(l.results, keys[i])
+		}
+	}
+	l.mu.Unlock()
+	for _, r := range results {
+		close(r.done)
+	}
+}
+
+// isNotFound reports whether err tells that a key does not exist, which is
+// the only failure a loader remembers.
+func isNotFound(err error) bool {
+	var apiErr *permit.PermitAPIError
+	return errors.Is(err, ErrRoleNotFound) || (errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound)
+}
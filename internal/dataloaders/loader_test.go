@@ -0,0 +1,209 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"iam_services_main_v1/internal/permit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingFetch returns a fetch function that echoes keys and records batches.
+func recordingFetch() (FetchFunc[int, string], func() [][]int) {
+	var mu sync.Mutex
+	var batches [][]int
+	fetch := func(ctx context.Context, keys []int) ([]string, []error) {
+		mu.Lock()
+		batches = append(batches, append([]int(nil), keys...))
+		mu.Unlock()
+		values := make([]string, len(keys))
+		errs := make([]error, len(keys))
+		for i, key := range keys {
+			if key < 0 {
+				errs[i] = errors.New("negative key")
+				continue
+			}
+			values[i] = fmt.Sprint(key)
+		}
+		return values, errs
+	}
+	return fetch, func() [][]int {
+		mu.Lock()
+		defer mu.Unlock()
+		return batches
+	}
+}
+
+// loadConcurrently loads every key at once and returns the values by key.
+func loadConcurrently(t *testing.T, loader *Loader[int, string], keys ...int) map[int]string {
+	t.Helper()
+	var mu sync.Mutex
+	values := map[int]string{}
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := loader.Load(context.Background(), key)
+			assert.NoError(t, err)
+			mu.Lock()
+			values[key] = value
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return values
+}
+
+func TestLoader(t *testing.T) {
+	t.Run("Batches and deduplicates concurrent loads", func(t *testing.T) {
+		fetch, batches := recordingFetch()
+		loader := NewLoader(fetch, 20*time.Millisecond, 100)
+
+		values := loadConcurrently(t, loader, 1, 2, 2, 3, 1)
+		assert.Equal(t, map[int]string{1: "1", 2: "2", 3: "3"}, values)
+		assert.Len(t, batches(), 1)
+		assert.ElementsMatch(t, []int{1, 2, 3}, batches()[0])
+
+		value, err := loader.Load(context.Background(), 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "2", value)
+		assert.Len(t, batches(), 1, "loaded keys are remembered")
+	})
+
+	t.Run("Full batches are fetched without waiting", func(t *testing.T) {
+		fetch, batches := recordingFetch()
+		loader := NewLoader(fetch, time.Hour, 2)
+
+		loadConcurrently(t, loader, 1, 2, 3, 4)
+		assert.Len(t, batches(), 2)
+	})
+
+	t.Run("Zero wait fetches every key on its own", func(t *testing.T) {
+		fetch, batches := recordingFetch()
+		loader := NewLoader(fetch, 0, 100)
+
+		loadConcurrently(t, loader, 1, 2)
+		assert.Len(t, batches(), 2)
+	})
+
+	t.Run("Errors are per key", func(t *testing.T) {
+		fetch, _ := recordingFetch()
+		loader := NewLoader(fetch, time.Millisecond, 100)
+
+		_, err := loader.Load(context.Background(), -1)
+		assert.EqualError(t, err, "negative key")
+		value, err := loader.Load(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", value)
+	})
+
+	t.Run("Failures are fetched again", func(t *testing.T) {
+		var calls int
+		loader := NewLoader(func(ctx context.Context, keys []int) ([]string, []error) {
+			calls++
+			if calls == 1 {
+				return make([]string, len(keys)), []error{&permit.PermitAPIError{StatusCode: http.StatusServiceUnavailable}}
+			}
+			return []string{fmt.Sprint(keys[0])}, make([]error, len(keys))
+		}, time.Millisecond, 100)
+
+		_, err := loader.Load(context.Background(), 1)
+		assert.Error(t, err)
+		value, err := loader.Load(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", value)
+		_, _ = loader.Load(context.Background(), 1)
+		assert.Equal(t, 2, calls, "values are remembered")
+	})
+
+	t.Run("Missing keys are remembered", func(t *testing.T) {
+		for _, notFound := range []error{ErrRoleNotFound, &permit.PermitAPIError{StatusCode: http.StatusNotFound}} {
+			var calls int
+			loader := NewLoader(func(ctx context.Context, keys []int) ([]string, []error) {
+				calls++
+				return make([]string, len(keys)), []error{fmt.Errorf("key %d: %w", keys[0], notFound)}
+			}, time.Millisecond, 100)
+
+			_, err := loader.Load(context.Background(), 1)
+			assert.ErrorIs(t, err, notFound)
+			_, err = loader.Load(context.Background(), 1)
+			assert.ErrorIs(t, err, notFound)
+			assert.Equal(t, 1, calls)
+		}
+	})
+
+	t.Run("Batches are not stopped by the end of the context of their first Load", func(t *testing.T) {
+		fetch, batches := recordingFetch()
+		var deadline time.Time
+		loader := NewLoader(func(ctx context.Context, keys []int) ([]string, []error) {
+			time.Sleep(20 * time.Millisecond)
+			if err := ctx.Err(); err != nil {
+				return make([]string, len(keys)), []error{err}
+			}
+			deadline, _ = ctx.Deadline()
+			return fetch(ctx, keys)
+		}, time.Millisecond, 100)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, err := loader.Load(ctx, 1)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		value, err := loader.Load(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", value)
+		assert.Len(t, batches(), 1)
+		assert.WithinDuration(t, time.Now().Add(fetchTimeout), deadline, time.Second)
+	})
+
+	t.Run("Stale reads are recorded by every Load of their batch", func(t *testing.T) {
+		loader := NewLoader(func(ctx context.Context, keys []int) ([]string, []error) {
+			if keys[0] < 10 {
+				permit.MarkStale(ctx)
+			}
+			values := make([]string, len(keys))
+			for i, key := range keys {
+				values[i] = fmt.Sprint(key)
+			}
+			return values, make([]error, len(keys))
+		}, 20*time.Millisecond, 100)
+
+		load := func(key int) bool {
+			ctx, stale := permit.TrackStaleReads(context.Background())
+			_, err := loader.Load(ctx, key)
+			assert.NoError(t, err)
+			return stale()
+		}
+		var wg sync.WaitGroup
+		stale := make([]bool, 2)
+		for i := range stale {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				stale[i] = load(i + 1)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, []bool{true, true}, stale)
+		assert.True(t, load(1), "remembered values stay stale")
+		assert.False(t, load(10))
+	})
+
+	t.Run("Caller stops waiting when its context ends", func(t *testing.T) {
+		fetch, _ := recordingFetch()
+		loader := NewLoader(fetch, time.Hour, 100)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := loader.Load(ctx, 1)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
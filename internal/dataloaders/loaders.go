@@ -0,0 +1,140 @@
+// Package dataloaders batches and deduplicates the Permit lookups made by
+// GraphQL field resolvers within one request. Resolving a field on every item
+// of a list, e.g. the tenant of 200 accounts, then costs one Permit call per
+// distinct tenant instead of one per account, and every role is resolved from
+// a single resource listing.
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"iam_services_main_v1/internal/permit"
+)
+
+const (
+	// batchWait is how long a loader waits for more keys before fetching.
+	batchWait = 2 * time.Millisecond
+	// maxBatch is the number of keys that are fetched without waiting.
+	maxBatch = 100
+	// maxParallel bounds the concurrent Permit calls of one batch for lookups
+	// Permit has no batch endpoint for.
+	maxParallel = 8
+)
+
+// ErrRoleNotFound is returned by the Roles loader for keys no resource type
+// defines a role for.
+var ErrRoleNotFound = errors.New("not found")
+
+// ResourceRole is a role together with the resource type it is defined on.
+type ResourceRole struct {
+	Resource permit.Resource
+	Role     permit.Role
+}
+
+// Loaders holds the loaders of one request.
+type Loaders struct {
+	Users             *Loader[string, *permit.User]
+	Tenants           *Loader[string, *permit.Tenant]
+	ResourceInstances *Loader[string, *permit.ResourceInstance]
+	// Roles loads roles by key from every resource type.
+	Roles *Loader[string, *ResourceRole]
+}
+
+// NewLoaders returns loaders that read from pc. A wait of zero disables batching.
+func NewLoaders(pc permit.PermitService, wait time.Duration) *Loaders {
+	return &Loaders{
+		Users:             NewLoader(fetchEach(pc.GetUser), wait, maxBatch),
+		Tenants:           NewLoader(fetchEach(pc.GetTenant), wait, maxBatch),
+		ResourceInstances: NewLoader(fetchEach(pc.GetResourceInstance), wait, maxBatch),
+		Roles:             NewLoader(fetchRoles(pc), wait, maxBatch),
+	}
+}
+
+// fetchEach fetches every key with get, at most maxParallel at a time.
+func fetchEach[V any](get func(ctx context.Context, key string) (V, error)) FetchFunc[string, V] {
+	return func(ctx context.Context, keys []string) ([]V, []error) {
+		values := make([]V, len(keys))
+		errs := make([]error, len(keys))
+		sem := make(chan struct{}, maxParallel)
+		var wg sync.WaitGroup
+		for i, key := range keys {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				values[i], errs[i] = get(ctx, key)
+			}()
+		}
+		wg.Wait()
+		return values, errs
+	}
+}
+
+// fetchRoles resolves a batch of role keys from one resource listing.
+func fetchRoles(pc permit.PermitService) FetchFunc[string, *ResourceRole] {
+	return func(ctx context.Context, keys []string) ([]*ResourceRole, []error) {
+		values := make([]*ResourceRole, len(keys))
+		errs := make([]error, len(keys))
+		resources, _, err := pc.ListResources(ctx)
+		if err != nil {
+			for i := range errs {
+				errs[i] = err
+			}
+			return values, errs
+		}
+
+		for i, key := range keys {
+			values[i], errs[i] = findRole(resources, key)
+		}
+		return values, errs
+	}
+}
+
+func findRole(resources []permit.Resource, key string) (*ResourceRole, error) {
+	for _, resource := range resources {
+		for _, role := range resource.Roles {
+			if strings.EqualFold(role.Key, key) {
+				return &ResourceRole{Resource: resource, Role: role}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("role %s %w", key, ErrRoleNotFound)
+}
+
+type loadersCtxKey struct{}
+
+// registry holds the loaders of one request, created on first use for each
+// PermitService.
+type registry struct {
+	mu      sync.Mutex
+	loaders map[permit.PermitService]*Loaders
+}
+
+// WithLoaders returns a context that carries the loaders of one request.
+func WithLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersCtxKey{}, &registry{loaders: map[permit.PermitService]*Loaders{}})
+}
+
+// For returns the request's loaders reading from pc. Without loaders in ctx it
+// returns loaders that fetch every key on its own, so callers outside a
+// request behave as if they called pc directly.
+func For(ctx context.Context, pc permit.PermitService) *Loaders {
+	reg, ok := ctx.Value(loadersCtxKey{}).(*registry)
+	if !ok {
+		return NewLoaders(pc, 0)
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	loaders, ok := reg.loaders[pc]
+	if !ok {
+		loaders = NewLoaders(pc, batchWait)
+		reg.loaders[pc] = loaders
+	}
+	return loaders
+}
@@ -0,0 +1,96 @@
+package dataloaders
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permit/permittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countRequests returns how many requests the fake received for paths ending in suffix.
+func countRequests(fake *permittest.Server, method, suffix string) int {
+	count := 0
+	for _, req := range fake.Requests() {
+		if req.Method == method && strings.HasSuffix(req.Path, suffix) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestLoaders(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	pc := permit.NewPermitServiceImpl(fake.Client())
+	for i := 0; i < 3; i++ {
+		fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: fmt.Sprintf("t%d", i)}, Name: fmt.Sprintf("Tenant %d", i)})
+	}
+	fake.AddResource(permit.Resource{Key: "account", Roles: map[string]permit.Role{"r1": {Key: "r1", Name: "Viewer"}}})
+	fake.AddResource(permit.Resource{Key: "tenant", Roles: map[string]permit.Role{"r2": {Key: "r2", Name: "Admin"}}})
+
+	t.Run("Lookups within a request are batched and deduplicated", func(t *testing.T) {
+		fake.ResetRequests()
+		ctx := WithLoaders(context.Background())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 60; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				loaders := For(ctx, pc)
+				tenant, err := loaders.Tenants.Load(ctx, fmt.Sprintf("t%d", i%3))
+				assert.NoError(t, err)
+				assert.Equal(t, fmt.Sprintf("Tenant %d", i%3), tenant.Name)
+				role, err := loaders.Roles.Load(ctx, []string{"r1", "r2"}[i%2])
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"account", "tenant"}[i%2], role.Resource.Key)
+			}()
+		}
+		wg.Wait()
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, 1, countRequests(fake, http.MethodGet, fmt.Sprintf("/tenants/t%d", i)))
+		}
+		assert.Equal(t, 1, countRequests(fake, http.MethodGet, "/resources"))
+	})
+
+	t.Run("Missing entries fail only their own lookup", func(t *testing.T) {
+		ctx := WithLoaders(context.Background())
+		loaders := For(ctx, pc)
+
+		_, err := loaders.Tenants.Load(ctx, "missing")
+		assert.Error(t, err)
+		_, err = loaders.Roles.Load(ctx, "missing")
+		assert.EqualError(t, err, "role missing not found")
+		assert.ErrorIs(t, err, ErrRoleNotFound)
+		_, err = loaders.Tenants.Load(ctx, "t0")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Requests do not share results", func(t *testing.T) {
+		fake.ResetRequests()
+		for i := 0; i < 2; i++ {
+			ctx := WithLoaders(context.Background())
+			_, err := For(ctx, pc).Tenants.Load(ctx, "t1")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, countRequests(fake, http.MethodGet, "/tenants/t1"))
+	})
+
+	t.Run("Without request loaders every lookup is made", func(t *testing.T) {
+		fake.ResetRequests()
+		ctx := context.Background()
+		for i := 0; i < 2; i++ {
+			_, err := For(ctx, pc).Tenants.Load(ctx, "t1")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, countRequests(fake, http.MethodGet, "/tenants/t1"))
+	})
+}
@@ -0,0 +1,290 @@
+// Package errorcatalog is the catalog of the errors the service reports. Every
+// error has a stable code, IAM-<DOMAIN>-<STATUS> (e.g. IAM-TENANT-404), made of
+// the domain it comes from and the HTTP status of its category, together with
+// a user-facing message, the code extension of GraphQL errors, and whether the
+// request may succeed when retried.
+//
+// The catalog does not encode the rows of MCP_IAM_Errors.xlsx, which list the
+// messages of each API without a code or status. Codes are made of the domain
+// and the status alone, and what failed is told by the free-text system
+// message each call site passes; the user-facing messages only tell the
+// category.
+package errorcatalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/pkg/logger"
+)
+
+// Domain is the part of the service an error comes from.
+type Domain string
+
+const (
+	Tenant                 Domain = "TENANT"
+	ClientOrganizationUnit Domain = "ORGUNIT"
+	Account                Domain = "ACCOUNT"
+	Role                   Domain = "ROLE"
+	Permission             Domain = "PERMISSION"
+	Binding                Domain = "BINDING"
+	ResourceType           Domain = "RESOURCETYPE"
+	Tag                    Domain = "TAG"
+	// Authorization is the domain of the decisions on the caller's permissions.
+	Authorization Domain = "AUTHZ"
+	// Common is the domain of errors shared by every part of the service.
+	Common Domain = "COMMON"
+)
+
+// resources names the resource of each domain in the messages.
+var resources = map[Domain]string{
+	Tenant:                 "tenant",
+	ClientOrganizationUnit: "client organization unit",
+	Account:                "account",
+	Role:                   "role",
+	Permission:             "permission",
+	Binding:                "binding",
+	ResourceType:           "resource type",
+	Tag:                    "tag search",
+	Authorization:          "resource",
+	Common:                 "request",
+}
+
+// Code is the stable code of an error, e.g. IAM-TENANT-404.
+type Code string
+
+// category describes the errors of an HTTP status.
+type category struct {
+	graphQLCode string
+	// message is the user-facing message, {resource} standing for the
+	// resource of the error's domain.
+	message   string
+	retryable bool
+}
+
+// categories are the error categories, by HTTP status.
+var categories = map[int]category{
+	http.StatusBadRequest: {
+		graphQLCode: "BAD_REQUEST",
+		message:     "The {resource} request could not be processed. Please check the input and try again.",
+	},
+	http.StatusForbidden: {
+		graphQLCode: "FORBIDDEN",
+		message:     "User does not have permission to perform this action",
+	},
+	http.StatusNotFound: {
+		graphQLCode: "NOT_FOUND",
+		message:     "The requested {resource} was not found.",
+	},
+	http.StatusConflict: {
+		graphQLCode: "CONFLICT",
+		message:     "The request conflicts with the current state of the {resource}.",
+	},
+	http.StatusUnprocessableEntity: {
+		graphQLCode: "VALIDATION_FAILED",
+		message:     "The {resource} failed validation. Please check the input and try again.",
+	},
+	http.StatusInternalServerError: {
+		graphQLCode: "INTERNAL_SERVER_ERROR",
+		message:     config.GenericErrorMessage,
+	},
+	http.StatusServiceUnavailable: {
+		graphQLCode: "UPSTREAM_UNAVAILABLE",
+		message:     config.UpstreamUnavailableErrorMessage,
+		retryable:   true,
+	},
+}
+
+// Entry is an error of the catalog.
+type Entry struct {
+	Code   Code
+	Domain Domain
+	// HTTPStatus is the HTTP status of the error's category.
+	HTTPStatus int
+	// GraphQLCode is the code extension of GraphQL errors, e.g. NOT_FOUND.
+	GraphQLCode string
+	// Message is the user-facing message.
+	Message string
+	// Retryable reports whether the same request may succeed later.
+	Retryable bool
+}
+
+var catalog = map[Code]Entry{}
+
+func init() {
+	for domain, resource := range resources {
+		for status, c := range categories {
+			entry := Entry{
+				Code:        Code(fmt.Sprintf("IAM-%s-%d", domain, status)),
+				Domain:      domain,
+				HTTPStatus:  status,
+				GraphQLCode: c.graphQLCode,
+				Message:     strings.ReplaceAll(c.message, "{resource}", resource),
+				Retryable:   c.retryable,
+			}
+			catalog[entry.Code] = entry
+		}
+	}
+}
+
+// Lookup returns the entry of code.
+func Lookup(code Code) (Entry, bool) {
+	entry, ok := catalog[code]
+	return entry, ok
+}
+
+// Entries returns every entry of the catalog, sorted by code.
+func Entries() []Entry {
+	entries := make([]Entry, 0, len(catalog))
+	for _, entry := range catalog {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries
+}
+
+// Entry returns the entry of the domain for an HTTP status. Statuses of
+// unavailable upstreams are reported as 503, and statuses without a category
+// as 500.
+func (d Domain) Entry(status int) Entry {
+	switch status {
+	case http.StatusBadGateway, http.StatusGatewayTimeout, http.StatusTooManyRequests:
+		status = http.StatusServiceUnavailable
+	}
+	if _, ok := categories[status]; !ok {
+		status = http.StatusInternalServerError
+	}
+	if _, ok := resources[d]; !ok {
+		d = Common
+	}
+	return catalog[Code(fmt.Sprintf("IAM-%s-%d", d, status))]
+}
+
+// New returns the error response of the domain for status. systemMessage
+// tells what failed, and details, when not empty, why.
+func (d Domain) New(status int, systemMessage, details string) *models.ResponseError {
+	return d.Entry(status).Response(systemMessage, details)
+}
+
+// FromError returns the error response of the domain for err. Permit errors
+// are reported with the status of their category; any other error is
+// reported with fallback.
+func (d Domain) FromError(fallback int, systemMessage string, err error) *models.ResponseError {
+	return d.New(StatusFromError(err, fallback), systemMessage, err.Error())
+}
+
+// Response returns the error response of the entry. Errors of the service
+// (5xx) are logged at ERROR, errors of the request (4xx) at WARN.
+func (e Entry) Response(systemMessage, details string) *models.ResponseError {
+	var errorDetails *string
+	if details != "" {
+		errorDetails = &details
+	}
+	if e.HTTPStatus >= http.StatusInternalServerError {
+		logger.LogError(systemMessage, "code", string(e.Code), "error", details)
+	} else {
+		logger.LogWarn(systemMessage, "code", string(e.Code), "error", details)
+	}
+	return &models.ResponseError{
+		ErrorCode:     string(e.Code),
+		ErrorDetails:  errorDetails,
+		HTTPStatus:    e.HTTPStatus,
+		IsSuccess:     false,
+		Message:       e.Message,
+		Retryable:     e.Retryable,
+		SystemMessage: systemMessage,
+	}
+}
+
+// Extensions returns the extensions of GraphQL errors of the entry.
+func (e Entry) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":       e.GraphQLCode,
+		"errorCode":  string(e.Code),
+		"httpStatus": e.HTTPStatus,
+		"retryable":  e.Retryable,
+	}
+}
+
+// Error is an error of the catalog returned by a resolver. PresentError
+// reports it as a GraphQL error with the user-facing message of its entry and
+// the entry's extensions.
+type Error struct {
+	Entry
+	// Err is the cause of the error, kept for logging.
+	Err error
+}
+
+// Err returns the error of the domain for status, caused by err, which may be
+// nil.
+func (d Domain) Err(status int, err error) *Error {
+	return &Error{Entry: d.Entry(status), Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// PresentError is the error presenter of the GraphQL handler. It adds the
+// extensions of their entry to the errors of the catalog, keeping those the
+// error already has, and presents every other error as gqlgen does.
+func PresentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	var catalogErr *Error
+	if !errors.As(err, &catalogErr) {
+		return gqlErr
+	}
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]interface{}{}
+	}
+	for key, value := range catalogErr.Extensions() {
+		if _, ok := gqlErr.Extensions[key]; !ok {
+			gqlErr.Extensions[key] = value
+		}
+	}
+	return gqlErr
+}
+
+// StatusFromError returns the status to report for err. Permit 404, 409 and
+// 422 responses keep their status, throttling (by Permit or the client-side
+// rate limiter), 5xx responses and calls stopped by an open circuit breaker
+// become 503 so clients can tell an unavailable upstream apart from a bad
+// request, errors of the catalog keep the status of their entry, and
+// everything else yields fallback.
+func StatusFromError(err error, fallback int) int {
+	if errors.Is(err, permit.ErrRateLimited) || errors.Is(err, permit.ErrCircuitOpen) {
+		return http.StatusServiceUnavailable
+	}
+	var catalogErr *Error
+	if errors.As(err, &catalogErr) {
+		return catalogErr.HTTPStatus
+	}
+	var apiErr *permit.PermitAPIError
+	if !errors.As(err, &apiErr) {
+		return fallback
+	}
+	switch {
+	case apiErr.StatusCode == http.StatusNotFound,
+		apiErr.StatusCode == http.StatusConflict,
+		apiErr.StatusCode == http.StatusUnprocessableEntity:
+		return apiErr.StatusCode
+	case apiErr.StatusCode == http.StatusTooManyRequests, apiErr.StatusCode >= 500:
+		return http.StatusServiceUnavailable
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,203 @@
+package errorcatalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/internal/permit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestCatalog(t *testing.T) {
+	t.Run("Every entry has a stable code, a message and a GraphQL code", func(t *testing.T) {
+		code := regexp.MustCompile(`^IAM-[A-Z]+-\d{3}$`)
+		entries := Entries()
+		assert.Len(t, entries, len(resources)*len(categories))
+		for _, entry := range entries {
+			assert.Regexp(t, code, string(entry.Code))
+			assert.Equal(t, Code(fmt.Sprintf("IAM-%s-%d", entry.Domain, entry.HTTPStatus)), entry.Code)
+			assert.NotEmpty(t, entry.Message)
+			assert.NotContains(t, entry.Message, "{resource}")
+			assert.NotEmpty(t, entry.GraphQLCode)
+		}
+	})
+
+	t.Run("Lookup finds entries by code", func(t *testing.T) {
+		entry, ok := Lookup("IAM-TENANT-404")
+		require.True(t, ok)
+		assert.Equal(t, Tenant, entry.Domain)
+		assert.Equal(t, 404, entry.HTTPStatus)
+		assert.Equal(t, "NOT_FOUND", entry.GraphQLCode)
+		assert.Equal(t, "The requested tenant was not found.", entry.Message)
+		assert.False(t, entry.Retryable)
+
+		_, ok = Lookup("IAM-TENANT-418")
+		assert.False(t, ok)
+	})
+
+	t.Run("Only unavailable upstreams are retryable", func(t *testing.T) {
+		for _, entry := range Entries() {
+			assert.Equal(t, entry.HTTPStatus == 503, entry.Retryable, entry.Code)
+		}
+	})
+}
+
+func TestDomainEntry(t *testing.T) {
+	testcases := []struct {
+		name   string
+		domain Domain
+		status int
+		want   Code
+	}{
+		{name: "Category status", domain: Account, status: 409, want: "IAM-ACCOUNT-409"},
+		{name: "Bad gateway", domain: Role, status: 502, want: "IAM-ROLE-503"},
+		{name: "Gateway timeout", domain: Role, status: 504, want: "IAM-ROLE-503"},
+		{name: "Status without a category", domain: Binding, status: 418, want: "IAM-BINDING-500"},
+		{name: "Unknown domain", domain: "UNKNOWN", status: 400, want: "IAM-COMMON-400"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.domain.Entry(tc.status).Code)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	testcases := []struct {
+		name        string
+		domain      Domain
+		status      int
+		msg         string
+		details     string
+		wantCode    string
+		wantMsg     string
+		wantDetails *string
+	}{
+		{
+			name:        "Basic error",
+			domain:      Tenant,
+			status:      400,
+			msg:         "Test error",
+			details:     "Error details",
+			wantCode:    "IAM-TENANT-400",
+			wantMsg:     "The tenant request could not be processed. Please check the input and try again.",
+			wantDetails: ptr("Error details"),
+		},
+		{
+			name:     "Empty details",
+			domain:   Account,
+			status:   500,
+			msg:      "Server error",
+			wantCode: "IAM-ACCOUNT-500",
+			wantMsg:  config.GenericErrorMessage,
+		},
+		{
+			name:     "Not found",
+			domain:   ClientOrganizationUnit,
+			status:   404,
+			msg:      "Client org missing",
+			wantCode: "IAM-ORGUNIT-404",
+			wantMsg:  "The requested client organization unit was not found.",
+		},
+		{
+			name:     "Upstream unavailable",
+			domain:   Role,
+			status:   503,
+			msg:      "Permit down",
+			wantCode: "IAM-ROLE-503",
+			wantMsg:  config.UpstreamUnavailableErrorMessage,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			errorResp := tc.domain.New(tc.status, tc.msg, tc.details)
+
+			assert.Equal(t, tc.wantCode, errorResp.ErrorCode)
+			assert.Equal(t, tc.wantMsg, errorResp.Message)
+			assert.Equal(t, tc.msg, errorResp.SystemMessage)
+			assert.Equal(t, tc.wantDetails, errorResp.ErrorDetails)
+			assert.Equal(t, tc.status, errorResp.HTTPStatus)
+			assert.Equal(t, tc.status == 503, errorResp.Retryable)
+			assert.False(t, errorResp.IsSuccess)
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	testcases := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{name: "Permit not found", err: &permit.PermitAPIError{StatusCode: 404, Code: "NOT_FOUND"}, wantCode: "IAM-TENANT-404"},
+		{name: "Permit conflict", err: &permit.PermitAPIError{StatusCode: 409}, wantCode: "IAM-TENANT-409"},
+		{name: "Permit validation", err: &permit.PermitAPIError{StatusCode: 422}, wantCode: "IAM-TENANT-422"},
+		{name: "Permit server error", err: fmt.Errorf("wrapped: %w", &permit.PermitAPIError{StatusCode: 502}), wantCode: "IAM-TENANT-503"},
+		{name: "Permit throttling", err: &permit.PermitAPIError{StatusCode: 429}, wantCode: "IAM-TENANT-503"},
+		{name: "Client-side rate limit", err: permit.ErrRateLimited, wantCode: "IAM-TENANT-503"},
+		{name: "Open circuit breaker", err: permit.ErrCircuitOpen, wantCode: "IAM-TENANT-503"},
+		{name: "Catalog error keeps its status", err: Authorization.Err(403, nil), wantCode: "IAM-TENANT-403"},
+		{name: "Permit bad request keeps fallback", err: &permit.PermitAPIError{StatusCode: 400}, wantCode: "IAM-TENANT-400"},
+		{name: "Non Permit error keeps fallback", err: errors.New("mapping failed"), wantCode: "IAM-TENANT-400"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			errorResp := Tenant.FromError(400, "operation failed", tc.err)
+
+			assert.Equal(t, tc.wantCode, errorResp.ErrorCode)
+			entry, ok := Lookup(Code(tc.wantCode))
+			require.True(t, ok)
+			assert.Equal(t, entry.Message, errorResp.Message)
+			assert.Equal(t, "operation failed", errorResp.SystemMessage)
+			assert.Equal(t, tc.err.Error(), *errorResp.ErrorDetails)
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("pdp down")
+	err := Authorization.Err(503, cause)
+
+	assert.EqualError(t, err, config.UpstreamUnavailableErrorMessage)
+	assert.ErrorIs(t, err, cause)
+
+	gqlErr := PresentError(context.Background(), gqlerror.WrapIfUnwrapped(err))
+	assert.Equal(t, config.UpstreamUnavailableErrorMessage, gqlErr.Message)
+	assert.Equal(t, map[string]interface{}{
+		"code":       "UPSTREAM_UNAVAILABLE",
+		"errorCode":  "IAM-AUTHZ-503",
+		"httpStatus": 503,
+		"retryable":  true,
+	}, gqlErr.Extensions)
+
+	t.Run("Extensions already set are kept", func(t *testing.T) {
+		gqlErr := gqlerror.WrapIfUnwrapped(Authorization.Err(403, nil))
+		gqlErr.Extensions = map[string]interface{}{"code": "CUSTOM"}
+
+		presented := PresentError(context.Background(), gqlErr)
+
+		assert.Equal(t, "CUSTOM", presented.Extensions["code"])
+		assert.Equal(t, "IAM-AUTHZ-403", presented.Extensions["errorCode"])
+	})
+
+	t.Run("Other errors are presented as by gqlgen", func(t *testing.T) {
+		presented := PresentError(context.Background(), errors.New("boom"))
+
+		assert.Equal(t, "boom", presented.Message)
+		assert.Nil(t, presented.Extensions)
+	})
+}
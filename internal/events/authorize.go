@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrUnavailable is returned for subscriptions made without a bus.
+	ErrUnavailable = errors.New("subscriptions are not available")
+	// ErrForbidden is returned when the caller may not receive any of the
+	// events of a subscription.
+	ErrForbidden = errors.New("User does not have permission to perform this action")
+)
+
+// Authorize checks, when a subscription is made, which of checks the caller
+// may perform in their tenant, so that a subscription resolver only reports
+// the events the caller may receive. It returns the caller's tenant and one
+// decision per check, and fails unless at least one check is allowed.
+func Authorize(ctx context.Context, b *Bus, authorizer authz.Authorizer, checks ...authz.PermissionCheck) (*uuid.UUID, []bool, error) {
+	if b == nil || authorizer == nil {
+		return nil, nil, ErrUnavailable
+	}
+	userID, tenantID, err := helpers.GetUserAndTenantID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range checks {
+		checks[i].Action = strings.ToLower(checks[i].Action)
+		if checks[i].ResourceID == "" {
+			checks[i].ResourceID = "*"
+		}
+	}
+	allowed, err := authorizer.BulkCheck(ctx, userID.String(), tenantID.String(), checks)
+	if errors.Is(err, authz.ErrUnavailable) {
+		return nil, nil, errorcatalog.Authorization.Err(http.StatusServiceUnavailable, err)
+	}
+	if err != nil {
+		logger.LogError("Subscription authorization failed", "error", err)
+		return nil, nil, ErrForbidden
+	}
+	for _, ok := range allowed {
+		if ok {
+			return tenantID, allowed, nil
+		}
+	}
+	return nil, nil, ErrForbidden
+}
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/internal/authz"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func callerContext(userID, tenantID uuid.UUID) context.Context {
+	ginCtx := &gin.Context{}
+	ginCtx.Set("userID", userID.String())
+	ginCtx.Set("tenantID", tenantID.String())
+	return context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+}
+
+func TestAuthorize(t *testing.T) {
+	b := NewBus(DefaultBuffer)
+	userID, tenantID := uuid.New(), uuid.New()
+	ctx := callerContext(userID, tenantID)
+	accounts := authz.PermissionCheck{Action: "accounts", ResourceType: config.AccountResourceTypeID}
+	units := authz.PermissionCheck{Action: "clientOrganizationUnits", ResourceType: config.ClientOrgUnitResourceTypeID}
+
+	t.Run("Returns the caller's tenant and decisions", func(t *testing.T) {
+		policy := &authz.Policy{Rules: []authz.Rule{{User: userID.String(), Tenant: tenantID.String(), ResourceType: config.ClientOrgUnitResourceTypeID, Actions: []string{"clientorganizationunits"}}}}
+		tenant, allowed, err := Authorize(ctx, b, policy, accounts, units)
+		assert.NoError(t, err)
+		assert.Equal(t, tenantID, *tenant)
+		assert.Equal(t, []bool{false, true}, allowed)
+	})
+
+	t.Run("Fails when nothing is allowed", func(t *testing.T) {
+		_, _, err := Authorize(ctx, b, authz.DenyAll(), accounts, units)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Fails without a bus or authorizer", func(t *testing.T) {
+		_, _, err := Authorize(ctx, nil, authz.AllowAll(), accounts)
+		assert.ErrorIs(t, err, ErrUnavailable)
+		_, _, err = Authorize(ctx, b, nil, accounts)
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+
+	t.Run("Fails without a caller", func(t *testing.T) {
+		_, _, err := Authorize(context.Background(), b, authz.AllowAll(), accounts)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,131 @@
+// Package events carries IAM change events from the mutation resolvers to the
+// GraphQL subscriptions of the same process.
+//
+// Events are delivered on a best-effort basis: a subscriber that falls more
+// than its buffer behind misses events rather than slowing down mutations, and
+// events published on one replica are not seen by subscribers of another.
+package events
+
+import (
+	"context"
+	"sync"
+
+	"iam_services_main_v1/pkg/logger"
+)
+
+// Topic groups the events a subscription can ask for.
+type Topic string
+
+const (
+	TopicTenant       Topic = "tenant"
+	TopicOrganization Topic = "organization"
+	TopicBinding      Topic = "binding"
+)
+
+// DefaultBuffer is the number of events held for a subscriber that has not
+// received them yet.
+const DefaultBuffer = 64
+
+// Event is a change published on the bus.
+type Event struct {
+	Topic Topic
+	// Tenant is the tenant the change was made in. Subscribers only receive
+	// the events of their own tenant.
+	Tenant string
+	// Payload is the GraphQL change event, e.g. *models.TenantChangeEvent.
+	Payload interface{}
+}
+
+type subscriber struct {
+	topic  Topic
+	tenant string
+	ch     chan Event
+}
+
+// Bus fans events out to the subscribers of their topic and tenant. A nil
+// *Bus drops every event, so resolvers built without one keep working.
+type Bus struct {
+	buffer int
+
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]*subscriber
+}
+
+// NewBus returns a bus holding up to buffer undelivered events per subscriber.
+func NewBus(buffer int) *Bus {
+	if buffer < 1 {
+		buffer = DefaultBuffer
+	}
+	return &Bus{buffer: buffer, subs: map[int]*subscriber{}}
+}
+
+// Publish delivers e to every subscriber of its topic and tenant without
+// waiting for them.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.subs {
+		if sub.topic != e.Topic || sub.tenant != e.Tenant {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			logger.LogError("Dropping event for a subscriber that is falling behind", "topic", e.Topic, "tenant", e.Tenant)
+		}
+	}
+}
+
+// Subscribe returns the events of topic published in tenant from now on. The
+// channel is closed once ctx is done.
+func (b *Bus) Subscribe(ctx context.Context, topic Topic, tenant string) <-chan Event {
+	sub := &subscriber{topic: topic, tenant: tenant, ch: make(chan Event, b.buffer)}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = sub
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, id)
+		close(sub.ch)
+		b.mu.Unlock()
+	}()
+	return sub.ch
+}
+
+// Subscribers returns the number of open subscriptions.
+func (b *Bus) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Stream returns the payloads of type T of the events of topic published in
+// tenant for which keep returns true. The channel is closed once ctx is done.
+func Stream[T any](ctx context.Context, b *Bus, topic Topic, tenant string, keep func(T) bool) <-chan T {
+	in := b.Subscribe(ctx, topic, tenant)
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for e := range in {
+			payload, ok := e.Payload.(T)
+			if !ok || (keep != nil && !keep(payload)) {
+				continue
+			}
+			select {
+			case out <- payload:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
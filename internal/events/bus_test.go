@@ -0,0 +1,140 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"iam_services_main_v1/gql/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func receive[T any](t *testing.T, ch <-chan T) (T, bool) {
+	t.Helper()
+	select {
+	case v, ok := <-ch:
+		return v, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an event")
+		var zero T
+		return zero, false
+	}
+}
+
+func assertNothing[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+	select {
+	case v, ok := <-ch:
+		if ok {
+			t.Fatalf("unexpected event %#v", v)
+		}
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestBus(t *testing.T) {
+	t.Run("Delivers events of the topic and tenant", func(t *testing.T) {
+		b := NewBus(DefaultBuffer)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch := b.Subscribe(ctx, TopicTenant, "t1")
+
+		b.Publish(Event{Topic: TopicBinding, Tenant: "t1", Payload: "binding"})
+		b.Publish(Event{Topic: TopicTenant, Tenant: "t2", Payload: "other tenant"})
+		b.Publish(Event{Topic: TopicTenant, Tenant: "t1", Payload: "tenant"})
+
+		e, ok := receive(t, ch)
+		assert.True(t, ok)
+		assert.Equal(t, "tenant", e.Payload)
+		assertNothing(t, ch)
+	})
+
+	t.Run("Drops events for subscribers falling behind", func(t *testing.T) {
+		b := NewBus(1)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch := b.Subscribe(ctx, TopicTenant, "t1")
+
+		b.Publish(Event{Topic: TopicTenant, Tenant: "t1", Payload: 1})
+		b.Publish(Event{Topic: TopicTenant, Tenant: "t1", Payload: 2})
+
+		e, _ := receive(t, ch)
+		assert.Equal(t, 1, e.Payload)
+		assertNothing(t, ch)
+	})
+
+	t.Run("Closes subscriptions once their context is done", func(t *testing.T) {
+		b := NewBus(DefaultBuffer)
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := b.Subscribe(ctx, TopicTenant, "t1")
+		assert.Equal(t, 1, b.Subscribers())
+
+		cancel()
+		_, ok := receive(t, ch)
+		assert.False(t, ok)
+		assert.Equal(t, 0, b.Subscribers())
+		b.Publish(Event{Topic: TopicTenant, Tenant: "t1"})
+	})
+
+	t.Run("A nil bus drops events", func(t *testing.T) {
+		var b *Bus
+		b.Publish(Event{Topic: TopicTenant, Tenant: "t1"})
+		b.TenantChanged(models.ChangeTypeEnumDeleted, uuid.New(), nil)
+	})
+}
+
+func TestStream(t *testing.T) {
+	b := NewBus(DefaultBuffer)
+	ctx, cancel := context.WithCancel(context.Background())
+	tenantID, principalID := uuid.New(), uuid.New()
+	ch := Stream(ctx, b, TopicBinding, tenantID.String(), func(e *models.BindingChangeEvent) bool {
+		return e.PrincipalID == principalID
+	})
+
+	b.Publish(Event{Topic: TopicBinding, Tenant: tenantID.String(), Payload: "not a binding event"})
+	b.BindingChanged(models.ChangeTypeEnumCreated, tenantID, uuid.New(), uuid.New(), uuid.New(), nil)
+	binding := &models.Binding{ID: uuid.New(), Name: "admins"}
+	b.BindingChanged(models.ChangeTypeEnumCreated, tenantID, binding.ID, principalID, uuid.New(), &models.SuccessResponse{Data: []models.Data{binding}})
+
+	e, ok := receive(t, ch)
+	assert.True(t, ok)
+	assert.Equal(t, models.ChangeTypeEnumCreated, e.Change)
+	assert.Equal(t, binding.ID, e.ID)
+	assert.Equal(t, tenantID, e.TenantID)
+	assert.Same(t, binding, e.Binding)
+	_, err := time.Parse(time.RFC3339, e.OccurredAt)
+	assert.NoError(t, err)
+	assertNothing(t, ch)
+
+	cancel()
+	_, ok = receive(t, ch)
+	assert.False(t, ok)
+}
+
+func TestChanges(t *testing.T) {
+	b := NewBus(DefaultBuffer)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tenantID := uuid.New()
+	tenants := Stream[*models.TenantChangeEvent](ctx, b, TopicTenant, tenantID.String(), nil)
+	orgs := Stream[*models.OrganizationChangeEvent](ctx, b, TopicOrganization, tenantID.String(), nil)
+
+	tenant := &models.Tenant{ID: tenantID, Name: "Acme"}
+	b.TenantChanged(models.ChangeTypeEnumUpdated, tenantID, &models.SuccessResponse{Data: []models.Data{tenant}})
+	e, _ := receive(t, tenants)
+	assert.Equal(t, models.ChangeTypeEnumUpdated, e.Change)
+	assert.Same(t, tenant, e.Tenant)
+
+	b.TenantChanged(models.ChangeTypeEnumUpdated, tenantID, &models.ResponseError{ErrorCode: "400"})
+	e, _ = receive(t, tenants)
+	assert.Nil(t, e.Tenant)
+
+	unit := &models.ClientOrganizationUnit{ID: uuid.New(), Name: "Platform"}
+	b.OrganizationChanged(models.ChangeTypeEnumCreated, "ClientOrganizationUnit", tenantID, unit.ID, models.SuccessResponse{Data: []models.Data{unit}})
+	org, _ := receive(t, orgs)
+	assert.Equal(t, unit.ID, org.ID)
+	assert.Equal(t, "ClientOrganizationUnit", org.OrganizationType)
+	assert.Same(t, unit, org.Organization)
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"time"
+
+	"iam_services_main_v1/gql/models"
+
+	"github.com/google/uuid"
+)
+
+// TenantChanged publishes a change of the tenant with the given ID. res is the
+// result returned by the mutation; its tenant, if any, is sent with the event.
+func (b *Bus) TenantChanged(change models.ChangeTypeEnum, tenantID uuid.UUID, res models.OperationResult) {
+	event := &models.TenantChangeEvent{
+		Change:     change,
+		OccurredAt: now(),
+		TenantID:   tenantID,
+	}
+	if tenant, ok := first(res).(*models.Tenant); ok {
+		event.Tenant = tenant
+	}
+	b.Publish(Event{Topic: TopicTenant, Tenant: tenantID.String(), Payload: event})
+}
+
+// OrganizationChanged publishes a change of the organization of type
+// orgType, config.Account or config.ClientOrganizationUnit, with the given ID
+// in tenant. res is the result returned by the mutation; its organization, if
+// any, is sent with the event.
+func (b *Bus) OrganizationChanged(change models.ChangeTypeEnum, orgType string, tenantID, id uuid.UUID, res models.OperationResult) {
+	event := &models.OrganizationChangeEvent{
+		Change:           change,
+		ID:               id,
+		OccurredAt:       now(),
+		OrganizationType: orgType,
+		TenantID:         tenantID,
+	}
+	if org, ok := first(res).(models.Organization); ok {
+		event.Organization = org
+	}
+	b.Publish(Event{Topic: TopicOrganization, Tenant: tenantID.String(), Payload: event})
+}
+
+// BindingChanged publishes a change of a binding in tenant. res is the result
+// returned by the mutation; its binding, if any, is sent with the event.
+func (b *Bus) BindingChanged(change models.ChangeTypeEnum, tenantID, id, principalID, roleID uuid.UUID, res models.OperationResult) {
+	event := &models.BindingChangeEvent{
+		Change:      change,
+		ID:          id,
+		OccurredAt:  now(),
+		PrincipalID: principalID,
+		RoleID:      roleID,
+		TenantID:    tenantID,
+	}
+	if binding, ok := first(res).(*models.Binding); ok {
+		event.Binding = binding
+	}
+	b.Publish(Event{Topic: TopicBinding, Tenant: tenantID.String(), Payload: event})
+}
+
+// first returns the first item of a successful result, or nil.
+func first(res models.OperationResult) models.Data {
+	var data []models.Data
+	switch success := res.(type) {
+	case *models.SuccessResponse:
+		if success != nil {
+			data = success.Data
+		}
+	case models.SuccessResponse:
+		data = success.Data
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return data[0]
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
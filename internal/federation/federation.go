@@ -0,0 +1,123 @@
+// Package federation holds what the entity resolvers of the service share. The
+// service is an Apollo Federation subgraph: other subgraphs reference its
+// tenants, organizations, roles, users and bindings by ID, and the router
+// resolves those references in batches through the _entities query.
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// ErrForbidden is returned when the caller may not read the entities of a type.
+var ErrForbidden = errors.New("User does not have permission to perform this action")
+
+// Authorize checks that the caller may perform action on resourceType in
+// their tenant, and returns the tenant. The _entities field has no @authorize
+// directive, as it resolves every entity type, so every entity resolver must
+// call it.
+func Authorize(ctx context.Context, authorizer authz.Authorizer, action, resourceType string) (*uuid.UUID, error) {
+	userID, tenantID, err := helpers.GetUserAndTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if authorizer == nil {
+		return nil, ErrForbidden
+	}
+	allowed, err := authorizer.Check(ctx, userID.String(), strings.ToLower(action), resourceType, "*", tenantID.String())
+	if err == nil && !allowed {
+		err = authz.ErrPermissionDenied
+	}
+	if errors.Is(err, authz.ErrUnavailable) {
+		return nil, errorcatalog.Authorization.Err(http.StatusServiceUnavailable, err)
+	}
+	if err != nil {
+		logger.LogInfo("Entity lookup denied", "action", action, "resourceType", resourceType, "error", err)
+		return nil, ErrForbidden
+	}
+	return tenantID, nil
+}
+
+// AuthorizeEach checks, in batches of authz.MaxBulkChecks, whether the caller
+// may perform action on each of resourceIDs of resourceType in their tenant,
+// and returns the decisions in the order of resourceIDs. Entity resolvers
+// whose queries check the instance call it instead of Authorize, and resolve
+// the denied references to nil.
+func AuthorizeEach(ctx context.Context, authorizer authz.Authorizer, action, resourceType string, resourceIDs []string) ([]bool, error) {
+	userID, tenantID, err := helpers.GetUserAndTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if authorizer == nil {
+		return nil, ErrForbidden
+	}
+	allowed := make([]bool, 0, len(resourceIDs))
+	for start := 0; start < len(resourceIDs); start += authz.MaxBulkChecks {
+		batch := resourceIDs[start:min(start+authz.MaxBulkChecks, len(resourceIDs))]
+		checks := make([]authz.PermissionCheck, len(batch))
+		for i, id := range batch {
+			checks[i] = authz.PermissionCheck{Action: strings.ToLower(action), ResourceType: resourceType, ResourceID: id}
+		}
+		decisions, err := authorizer.BulkCheck(ctx, userID.String(), tenantID.String(), checks)
+		if errors.Is(err, authz.ErrUnavailable) {
+			return nil, errorcatalog.Authorization.Err(http.StatusServiceUnavailable, err)
+		}
+		if err == nil && len(decisions) != len(batch) {
+			err = fmt.Errorf("authorizer returned %d decisions for %d checks", len(decisions), len(batch))
+		}
+		if err != nil {
+			logger.LogInfo("Entity lookup denied", "action", action, "resourceType", resourceType, "error", err)
+			decisions = make([]bool, len(batch))
+		}
+		allowed = append(allowed, decisions...)
+	}
+	return allowed, nil
+}
+
+// Resolve resolves every representation of reps with resolve and returns the
+// entities in the order of reps. The representations are resolved
+// concurrently, so that resolvers going through the request's dataloaders
+// fetch them in batches. An entity that does not exist resolves to nil; any
+// other error fails the whole batch.
+func Resolve[R, E any](ctx context.Context, reps []R, resolve func(ctx context.Context, rep R) (E, error)) ([]E, error) {
+	entities := make([]E, len(reps))
+	errs := make([]error, len(reps))
+	var wg sync.WaitGroup
+	for i, rep := range reps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entity, err := resolve(ctx, rep)
+			if IsNotFound(err) {
+				return
+			}
+			entities[i], errs[i] = entity, err
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// IsNotFound reports whether err tells that an entity does not exist.
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, dataloaders.ErrRoleNotFound) || errorcatalog.StatusFromError(err, 0) == http.StatusNotFound
+}
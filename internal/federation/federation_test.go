@@ -0,0 +1,180 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/internal/permit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func callerContext(tenantID uuid.UUID) context.Context {
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantID.String())
+	ginCtx.Set("userID", uuid.New().String())
+	return context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+}
+
+type unavailable struct{ authz.Authorizer }
+
+func (unavailable) Check(context.Context, string, string, string, string, string) (bool, error) {
+	return false, fmt.Errorf("pdp: %w", authz.ErrUnavailable)
+}
+
+// denyingWithoutError denies every check without returning an error.
+type denyingWithoutError struct{ authz.Authorizer }
+
+func (denyingWithoutError) Check(context.Context, string, string, string, string, string) (bool, error) {
+	return false, nil
+}
+
+func (denyingWithoutError) BulkCheck(context.Context, string, string, []authz.PermissionCheck) ([]bool, error) {
+	return nil, nil
+}
+
+func TestAuthorize(t *testing.T) {
+	tenantID := uuid.New()
+	ctx := callerContext(tenantID)
+
+	t.Run("Returns the caller's tenant", func(t *testing.T) {
+		got, err := Authorize(ctx, authz.AllowAll(), "tenant", config.TenantResourceTypeID)
+		assert.NoError(t, err)
+		assert.Equal(t, tenantID, *got)
+	})
+
+	t.Run("Checks the action on the resource type", func(t *testing.T) {
+		policy := &authz.Policy{Rules: []authz.Rule{{Actions: []string{"account"}, ResourceType: config.AccountResourceTypeID}}}
+		_, err := Authorize(ctx, policy, "account", config.AccountResourceTypeID)
+		assert.NoError(t, err)
+		_, err = Authorize(ctx, policy, "tenant", config.TenantResourceTypeID)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		_, err := Authorize(ctx, authz.DenyAll(), "tenant", config.TenantResourceTypeID)
+		assert.ErrorIs(t, err, ErrForbidden)
+		_, err = Authorize(ctx, nil, "tenant", config.TenantResourceTypeID)
+		assert.ErrorIs(t, err, ErrForbidden)
+		_, err = Authorize(ctx, denyingWithoutError{}, "tenant", config.TenantResourceTypeID)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Authorizer unavailable", func(t *testing.T) {
+		_, err := Authorize(ctx, unavailable{}, "tenant", config.TenantResourceTypeID)
+		assert.EqualError(t, err, config.UpstreamUnavailableErrorMessage)
+	})
+
+	t.Run("Without a caller", func(t *testing.T) {
+		_, err := Authorize(context.Background(), authz.AllowAll(), "tenant", config.TenantResourceTypeID)
+		assert.Error(t, err)
+	})
+}
+
+func (unavailable) BulkCheck(context.Context, string, string, []authz.PermissionCheck) ([]bool, error) {
+	return nil, fmt.Errorf("pdp: %w", authz.ErrUnavailable)
+}
+
+// countingAuthorizer counts the BulkCheck calls it passes on.
+type countingAuthorizer struct {
+	authz.Authorizer
+	calls int
+}
+
+func (c *countingAuthorizer) BulkCheck(ctx context.Context, userID, tenant string, checks []authz.PermissionCheck) ([]bool, error) {
+	c.calls++
+	return c.Authorizer.BulkCheck(ctx, userID, tenant, checks)
+}
+
+func TestAuthorizeEach(t *testing.T) {
+	tenantID := uuid.New()
+	ctx := callerContext(tenantID)
+	own, foreign := tenantID.String(), uuid.New().String()
+
+	t.Run("Checks each instance", func(t *testing.T) {
+		policy := &authz.Policy{Rules: []authz.Rule{{Actions: []string{"tenant"}, ResourceType: config.TenantResourceTypeID, ResourceID: own}}}
+		allowed, err := AuthorizeEach(ctx, policy, "tenant", config.TenantResourceTypeID, []string{foreign, own})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false, true}, allowed)
+	})
+
+	t.Run("Checks in batches", func(t *testing.T) {
+		ids := make([]string, authz.MaxBulkChecks+1)
+		for i := range ids {
+			ids[i] = uuid.New().String()
+		}
+		authorizer := &countingAuthorizer{Authorizer: authz.AllowAll()}
+		allowed, err := AuthorizeEach(ctx, authorizer, "tenant", config.TenantResourceTypeID, ids)
+		assert.NoError(t, err)
+		assert.Len(t, allowed, len(ids))
+		assert.Equal(t, 2, authorizer.calls)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		allowed, err := AuthorizeEach(ctx, authz.DenyAll(), "tenant", config.TenantResourceTypeID, []string{own})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false}, allowed)
+		_, err = AuthorizeEach(ctx, nil, "tenant", config.TenantResourceTypeID, []string{own})
+		assert.ErrorIs(t, err, ErrForbidden)
+		allowed, err = AuthorizeEach(ctx, denyingWithoutError{}, "tenant", config.TenantResourceTypeID, []string{own, foreign})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false, false}, allowed)
+	})
+
+	t.Run("Authorizer unavailable", func(t *testing.T) {
+		_, err := AuthorizeEach(ctx, unavailable{}, "tenant", config.TenantResourceTypeID, []string{own})
+		assert.EqualError(t, err, config.UpstreamUnavailableErrorMessage)
+	})
+}
+
+func TestResolve(t *testing.T) {
+	notFound := &permit.PermitAPIError{StatusCode: http.StatusNotFound}
+
+	t.Run("Keeps the order of the representations", func(t *testing.T) {
+		got, err := Resolve(context.Background(), []int{3, 1, 2}, func(_ context.Context, rep int) (string, error) {
+			return fmt.Sprint(rep), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"3", "1", "2"}, got)
+	})
+
+	t.Run("Missing entities resolve to nil", func(t *testing.T) {
+		got, err := Resolve(context.Background(), []int{1, 2}, func(_ context.Context, rep int) (*int, error) {
+			if rep == 2 {
+				return nil, fmt.Errorf("get: %w", notFound)
+			}
+			return &rep, nil
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, got, 2) {
+			assert.Equal(t, 1, *got[0])
+			assert.Nil(t, got[1])
+		}
+	})
+
+	t.Run("Other errors fail the batch", func(t *testing.T) {
+		boom := errors.New("boom")
+		_, err := Resolve(context.Background(), []int{1, 2}, func(_ context.Context, rep int) (int, error) {
+			if rep == 2 {
+				return 0, boom
+			}
+			return rep, nil
+		})
+		assert.ErrorIs(t, err, boom)
+	})
+}
+
+func TestIsNotFound(t *testing.T) {
+	assert.True(t, IsNotFound(&permit.PermitAPIError{StatusCode: http.StatusNotFound}))
+	assert.True(t, IsNotFound(fmt.Errorf("role r1 %w", dataloaders.ErrRoleNotFound)))
+	assert.False(t, IsNotFound(&permit.PermitAPIError{StatusCode: http.StatusInternalServerError}))
+	assert.False(t, IsNotFound(nil))
+}
@@ -0,0 +1,278 @@
+// Package filters applies the filter and ordering arguments of the organization
+// list queries to mapped tenants, client organization units and accounts.
+//
+// Permit can only narrow a tenant listing by name, see Search. Every other
+// filter, and all ordering, is applied in memory after the list is read.
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"iam_services_main_v1/gql/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidFilter is returned for a time range that is not made of RFC 3339
+// timestamps.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// organization holds the fields of a tenant, client organization unit or
+// account that can be filtered and ordered by.
+type organization struct {
+	name           string
+	status         models.StatusTypeEnum
+	tags           []*models.Tags
+	parentID       *uuid.UUID
+	relationType   models.RelationTypeEnum
+	accountOwnerID *uuid.UUID
+	createdAt      time.Time
+	updatedAt      time.Time
+}
+
+// ForTenants returns filter as the OrganizationFilter it is a subset of.
+func ForTenants(filter *models.TenantFilter) *models.OrganizationFilter {
+	if filter == nil {
+		return nil
+	}
+	return &models.OrganizationFilter{
+		AccountOwnerID: filter.AccountOwnerID,
+		CreatedAt:      filter.CreatedAt,
+		NameContains:   filter.NameContains,
+		NamePrefix:     filter.NamePrefix,
+		Status:         filter.Status,
+		Tag:            filter.Tag,
+		UpdatedAt:      filter.UpdatedAt,
+	}
+}
+
+// Search returns the text Permit can search tenants by to narrow the listing
+// for filter, or "" when filter has none. Permit matches the text anywhere in
+// the name or key of a tenant, so the listing must still be passed to Apply.
+func Search(filter *models.OrganizationFilter) string {
+	switch {
+	case filter == nil:
+		return ""
+	case filter.NamePrefix != nil:
+		return *filter.NamePrefix
+	case filter.NameContains != nil:
+		return *filter.NameContains
+	default:
+		return ""
+	}
+}
+
+// Validate reports whether filter can be applied.
+func Validate(filter *models.OrganizationFilter) error {
+	_, err := newMatcher(filter)
+	return err
+}
+
+// Apply returns the organizations of data that match filter, ordered by order.
+// Without an order data keeps its order. Items that are not organizations are
+// dropped when a filter is given.
+func Apply(data []models.Data, filter *models.OrganizationFilter, order *models.OrganizationOrder) ([]models.Data, error) {
+	if filter == nil && order == nil {
+		return data, nil
+	}
+	m, err := newMatcher(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		data models.Data
+		org  organization
+	}
+	entries := make([]entry, 0, len(data))
+	for _, item := range data {
+		org, ok := fieldsOf(item)
+		if filter != nil && (!ok || !m.match(org)) {
+			continue
+		}
+		entries = append(entries, entry{data: item, org: org})
+	}
+
+	if order != nil {
+		less := lessFunc(order.Field)
+		sort.SliceStable(entries, func(i, j int) bool {
+			if order.Direction == models.OrderDirectionEnumDesc {
+				return less(entries[j].org, entries[i].org)
+			}
+			return less(entries[i].org, entries[j].org)
+		})
+	}
+
+	result := make([]models.Data, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.data)
+	}
+	return result, nil
+}
+
+func fieldsOf(item models.Data) (organization, bool) {
+	switch org := item.(type) {
+	case *models.Tenant:
+		return organization{
+			name:           org.Name,
+			status:         org.Status,
+			tags:           org.Tags,
+			accountOwnerID: userID(org.AccountOwner),
+			createdAt:      parseTime(org.CreatedAt),
+			updatedAt:      parseTime(org.UpdatedAt),
+		}, true
+	case *models.ClientOrganizationUnit:
+		return organization{
+			name:           org.Name,
+			status:         org.Status,
+			tags:           org.Tags,
+			parentID:       organizationID(org.ParentOrg),
+			relationType:   org.RelationType,
+			accountOwnerID: userID(org.AccountOwner),
+			createdAt:      parseTime(org.CreatedAt),
+			updatedAt:      parseTime(org.UpdatedAt),
+		}, true
+	case *models.Account:
+		return organization{
+			name:           org.Name,
+			status:         org.Status,
+			tags:           org.Tags,
+			parentID:       organizationID(org.ParentOrg),
+			relationType:   org.RelationType,
+			accountOwnerID: userID(org.AccountOwner),
+			createdAt:      parseTime(org.CreatedAt),
+			updatedAt:      parseTime(org.UpdatedAt),
+		}, true
+	default:
+		return organization{}, false
+	}
+}
+
+func userID(user *models.User) *uuid.UUID {
+	if user == nil {
+		return nil
+	}
+	return &user.ID
+}
+
+func organizationID(org models.Organization) *uuid.UUID {
+	if org == nil {
+		return nil
+	}
+	id := org.GetID()
+	return &id
+}
+
+// parseTime returns the zero time for a missing or malformed timestamp.
+func parseTime(value string) time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return t
+}
+
+// timeRange is a parsed TimeRangeInput. A nil end is open.
+type timeRange struct {
+	from, to *time.Time
+}
+
+func parseRange(name string, input *models.TimeRangeInput) (timeRange, error) {
+	if input == nil {
+		return timeRange{}, nil
+	}
+	from, err := parseEnd(name+".from", input.From)
+	if err != nil {
+		return timeRange{}, err
+	}
+	to, err := parseEnd(name+".to", input.To)
+	if err != nil {
+		return timeRange{}, err
+	}
+	return timeRange{from: from, to: to}, nil
+}
+
+func parseEnd(field string, value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s %q is not an RFC 3339 timestamp", ErrInvalidFilter, field, *value)
+	}
+	return &t, nil
+}
+
+// contains reports whether t lies in the range. Organizations without a
+// timestamp are outside of every range with an end.
+func (r timeRange) contains(t time.Time) bool {
+	if r.from == nil && r.to == nil {
+		return true
+	}
+	if t.IsZero() {
+		return false
+	}
+	return (r.from == nil || !t.Before(*r.from)) && (r.to == nil || t.Before(*r.to))
+}
+
+type matcher struct {
+	filter    models.OrganizationFilter
+	createdAt timeRange
+	updatedAt timeRange
+}
+
+func newMatcher(filter *models.OrganizationFilter) (*matcher, error) {
+	m := &matcher{}
+	if filter == nil {
+		return m, nil
+	}
+	m.filter = *filter
+	var err error
+	if m.createdAt, err = parseRange("createdAt", filter.CreatedAt); err != nil {
+		return nil, err
+	}
+	if m.updatedAt, err = parseRange("updatedAt", filter.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *matcher) match(org organization) bool {
+	f := m.filter
+	name := strings.ToLower(org.name)
+	switch {
+	case f.Status != nil && org.status != *f.Status:
+	case f.NameContains != nil && !strings.Contains(name, strings.ToLower(*f.NameContains)):
+	case f.NamePrefix != nil && !strings.HasPrefix(name, strings.ToLower(*f.NamePrefix)):
+	case f.Tag != nil && !hasTag(org.tags, *f.Tag):
+	case f.ParentID != nil && (org.parentID == nil || *org.parentID != *f.ParentID):
+	case f.RelationType != nil && org.relationType != *f.RelationType:
+	case f.AccountOwnerID != nil && (org.accountOwnerID == nil || *org.accountOwnerID != *f.AccountOwnerID):
+	case !m.createdAt.contains(org.createdAt):
+	case !m.updatedAt.contains(org.updatedAt):
+	default:
+		return true
+	}
+	return false
+}
+
+func hasTag(tags []*models.Tags, want models.TagFilterInput) bool {
+	for _, tag := range tags {
+		if tag != nil && tag.Key == want.Key && (want.Value == nil || tag.Value == *want.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+func lessFunc(field models.OrganizationOrderFieldEnum) func(a, b organization) bool {
+	switch field {
+	case models.OrganizationOrderFieldEnumCreatedAt:
+		return func(a, b organization) bool { return a.createdAt.Before(b.createdAt) }
+	case models.OrganizationOrderFieldEnumUpdatedAt:
+		return func(a, b organization) bool { return a.updatedAt.Before(b.updatedAt) }
+	default:
+		return func(a, b organization) bool { return strings.ToLower(a.name) < strings.ToLower(b.name) }
+	}
+}
@@ -0,0 +1,140 @@
+package filters
+
+import (
+	"testing"
+
+	"iam_services_main_v1/gql/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func names(data []models.Data) []string {
+	result := []string{}
+	for _, item := range data {
+		org, _ := fieldsOf(item)
+		result = append(result, org.name)
+	}
+	return result
+}
+
+func TestApply(t *testing.T) {
+	owner, parent := uuid.New(), uuid.New()
+	data := []models.Data{
+		&models.Account{
+			Name:         "Beta Account",
+			Status:       models.StatusTypeEnumActive,
+			Tags:         []*models.Tags{{Key: "env", Value: "prod"}},
+			ParentOrg:    &models.ClientOrganizationUnit{ID: parent},
+			RelationType: models.RelationTypeEnumParent,
+			AccountOwner: &models.User{ID: owner},
+			CreatedAt:    "2024-03-01T00:00:00Z",
+			UpdatedAt:    "2024-06-01T00:00:00Z",
+		},
+		&models.ClientOrganizationUnit{
+			Name:         "alpha unit",
+			Status:       models.StatusTypeEnumSuspanded,
+			Tags:         []*models.Tags{{Key: "env", Value: "dev"}},
+			ParentOrg:    &models.Tenant{ID: uuid.New()},
+			RelationType: models.RelationTypeEnumChild,
+			AccountOwner: &models.User{ID: uuid.New()},
+			CreatedAt:    "2024-01-01T00:00:00Z",
+			UpdatedAt:    "2024-07-01T00:00:00Z",
+		},
+		&models.Tenant{
+			Name:         "Gamma Tenant",
+			Status:       models.StatusTypeEnumActive,
+			AccountOwner: &models.User{ID: owner},
+			CreatedAt:    "2024-02-01T00:00:00Z",
+			UpdatedAt:    "2024-05-01T00:00:00Z",
+		},
+	}
+
+	testcases := []struct {
+		name   string
+		filter *models.OrganizationFilter
+		order  *models.OrganizationOrder
+		want   []string
+	}{
+		{name: "No filter or order", want: []string{"Beta Account", "alpha unit", "Gamma Tenant"}},
+		{name: "Status", filter: &models.OrganizationFilter{Status: ptr(models.StatusTypeEnumActive)}, want: []string{"Beta Account", "Gamma Tenant"}},
+		{name: "Name contains ignores case", filter: &models.OrganizationFilter{NameContains: ptr("UNIT")}, want: []string{"alpha unit"}},
+		{name: "Name prefix", filter: &models.OrganizationFilter{NamePrefix: ptr("gam")}, want: []string{"Gamma Tenant"}},
+		{name: "Name prefix is not contains", filter: &models.OrganizationFilter{NamePrefix: ptr("unit")}, want: []string{}},
+		{name: "Tag key", filter: &models.OrganizationFilter{Tag: &models.TagFilterInput{Key: "env"}}, want: []string{"Beta Account", "alpha unit"}},
+		{name: "Tag key and value", filter: &models.OrganizationFilter{Tag: &models.TagFilterInput{Key: "env", Value: ptr("dev")}}, want: []string{"alpha unit"}},
+		{name: "Parent", filter: &models.OrganizationFilter{ParentID: &parent}, want: []string{"Beta Account"}},
+		{name: "Relation type", filter: &models.OrganizationFilter{RelationType: ptr(models.RelationTypeEnumChild)}, want: []string{"alpha unit"}},
+		{name: "Account owner", filter: &models.OrganizationFilter{AccountOwnerID: &owner}, want: []string{"Beta Account", "Gamma Tenant"}},
+		{
+			name:   "Created range includes from and excludes to",
+			filter: &models.OrganizationFilter{CreatedAt: &models.TimeRangeInput{From: ptr("2024-02-01T00:00:00Z"), To: ptr("2024-03-01T00:00:00Z")}},
+			want:   []string{"Gamma Tenant"},
+		},
+		{
+			name:   "Open updated range",
+			filter: &models.OrganizationFilter{UpdatedAt: &models.TimeRangeInput{From: ptr("2024-06-01T00:00:00Z")}},
+			want:   []string{"Beta Account", "alpha unit"},
+		},
+		{
+			name:   "Every field must match",
+			filter: &models.OrganizationFilter{Status: ptr(models.StatusTypeEnumActive), AccountOwnerID: &owner, NameContains: ptr("account")},
+			want:   []string{"Beta Account"},
+		},
+		{name: "Order by name", order: &models.OrganizationOrder{Field: models.OrganizationOrderFieldEnumName, Direction: models.OrderDirectionEnumAsc}, want: []string{"alpha unit", "Beta Account", "Gamma Tenant"}},
+		{name: "Order by creation descending", order: &models.OrganizationOrder{Field: models.OrganizationOrderFieldEnumCreatedAt, Direction: models.OrderDirectionEnumDesc}, want: []string{"Beta Account", "Gamma Tenant", "alpha unit"}},
+		{
+			name:   "Filter and order by update",
+			filter: &models.OrganizationFilter{Status: ptr(models.StatusTypeEnumActive)},
+			order:  &models.OrganizationOrder{Field: models.OrganizationOrderFieldEnumUpdatedAt, Direction: models.OrderDirectionEnumAsc},
+			want:   []string{"Gamma Tenant", "Beta Account"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Apply(data, tc.filter, tc.order)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, names(result))
+		})
+	}
+
+	t.Run("Input is left unchanged", func(t *testing.T) {
+		_, err := Apply(data, nil, &models.OrganizationOrder{Field: models.OrganizationOrderFieldEnumName})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Beta Account", "alpha unit", "Gamma Tenant"}, names(data))
+	})
+
+	t.Run("Missing timestamps are outside every range", func(t *testing.T) {
+		result, err := Apply([]models.Data{&models.Tenant{Name: "No Dates"}}, &models.OrganizationFilter{CreatedAt: &models.TimeRangeInput{To: ptr("2030-01-01T00:00:00Z")}}, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(nil))
+	assert.NoError(t, Validate(&models.OrganizationFilter{CreatedAt: &models.TimeRangeInput{From: ptr("2024-01-01T00:00:00+02:00")}}))
+
+	err := Validate(&models.OrganizationFilter{UpdatedAt: &models.TimeRangeInput{To: ptr("yesterday")}})
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+	assert.Contains(t, err.Error(), "updatedAt.to")
+
+	_, err = Apply(nil, &models.OrganizationFilter{CreatedAt: &models.TimeRangeInput{From: ptr("2024-01-01")}}, nil)
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestForTenantsAndSearch(t *testing.T) {
+	assert.Nil(t, ForTenants(nil))
+	assert.Equal(t, "", Search(nil))
+
+	filter := ForTenants(&models.TenantFilter{NameContains: ptr("acme"), Status: ptr(models.StatusTypeEnumActive)})
+	assert.Equal(t, ptr(models.StatusTypeEnumActive), filter.Status)
+	assert.Equal(t, "acme", Search(filter))
+
+	filter.NamePrefix = ptr("ac")
+	assert.Equal(t, "ac", Search(filter))
+	assert.Equal(t, "", Search(&models.OrganizationFilter{Status: ptr(models.StatusTypeEnumActive)}))
+}
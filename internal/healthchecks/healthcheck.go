@@ -5,13 +5,32 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"iam_services_main_v1/internal/permit"
 )
 
-type HealthHandler struct{}
+type HealthHandler struct {
+	// Breakers are the circuit breakers in front of Permit reported by ReadinessCheck.
+	Breakers []*permit.CircuitBreaker
+	// Retries, if set, is the Permit API client whose retry counters are
+	// reported by ReadinessCheck.
+	Retries interface{ RetryStats() permit.RetryStats }
+	// Decisions, if set, is the PDP client whose decision cache counters are
+	// reported by ReadinessCheck.
+	Decisions interface {
+		DecisionCacheStats() permit.DecisionCacheStats
+	}
+}
 
 type HealthResponse struct {
 	Status    string `json:"status"`
 	Timestamp string `json:"timestamp"`
+	// Breakers maps the name of every circuit breaker to its state.
+	Breakers map[string]string `json:"breakers,omitempty"`
+	// Retries are the retry counters of the Permit API client.
+	Retries *permit.RetryStats `json:"retries,omitempty"`
+	// DecisionCache are the hit and miss counters of the PDP decision cache.
+	DecisionCache *permit.DecisionCacheStats `json:"decisionCache,omitempty"`
 }
 
 type StatusResponse struct {
@@ -33,10 +52,32 @@ func (h *HealthHandler) LivenessCheck(c *gin.Context) {
 	})
 }
 
-// ReadinessCheck checks if service is ready
+// ReadinessCheck checks if service is ready and reports the state of the circuit
+// breakers, the retry counters of the Permit API client and the counters of the
+// PDP decision cache. The service is "degraded" while a breaker is not closed;
+// it stays ready since it still serves reads from cache.
 func (h *HealthHandler) ReadinessCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, HealthResponse{
+	response := HealthResponse{
 		Status:    "up",
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	})
+	}
+	for _, breaker := range h.Breakers {
+		if response.Breakers == nil {
+			response.Breakers = map[string]string{}
+		}
+		state := breaker.State()
+		response.Breakers[breaker.Name()] = state.String()
+		if state != permit.BreakerClosed {
+			response.Status = "degraded"
+		}
+	}
+	if h.Retries != nil {
+		stats := h.Retries.RetryStats()
+		response.Retries = &stats
+	}
+	if h.Decisions != nil {
+		stats := h.Decisions.DecisionCacheStats()
+		response.DecisionCache = &stats
+	}
+	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,111 @@
+package healthchecks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permit/permittest"
+)
+
+func TestReadinessCheckReportsBreakers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := permittest.NewServer()
+	defer fake.Close()
+
+	client := fake.Client()
+	client.Breaker = permit.NewCircuitBreaker("permit-api", permit.BreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Minute})
+	pdp := permit.NewCircuitBreaker("permit-pdp", permit.DefaultBreakerPolicy())
+
+	router := gin.New()
+	handler := &HealthHandler{Breakers: []*permit.CircuitBreaker{client.Breaker, pdp}}
+	router.GET("/health/ready", handler.ReadinessCheck)
+
+	ready := func() HealthResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/health/ready", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response HealthResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	response := ready()
+	assert.Equal(t, "up", response.Status)
+	assert.Equal(t, map[string]string{"permit-api": "closed", "permit-pdp": "closed"}, response.Breakers)
+
+	fake.FailNext(1, http.StatusBadGateway)
+	_, err := permit.NewPermitServiceImpl(client).GetTenant(context.Background(), "t1")
+	assert.Error(t, err)
+
+	response = ready()
+	assert.Equal(t, "degraded", response.Status)
+	assert.Equal(t, map[string]string{"permit-api": "open", "permit-pdp": "closed"}, response.Breakers)
+}
+
+func TestReadinessCheckReportsRetries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := permittest.NewServer()
+	defer fake.Close()
+
+	client := fake.Client()
+	client.Retry = &permit.RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, MaxElapsedTime: time.Second}
+	service := permit.NewPermitServiceImpl(client)
+
+	router := gin.New()
+	handler := &HealthHandler{}
+	router.GET("/health/ready", handler.ReadinessCheck)
+	ready := func() HealthResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/health/ready", nil)
+		router.ServeHTTP(w, req)
+		var response HealthResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	assert.Nil(t, ready().Retries, "no counters without a Permit client")
+
+	handler.Retries = service.(*permit.PermitServiceImpl)
+	fake.FailNext(1, http.StatusServiceUnavailable)
+	_, _ = service.GetTenant(context.Background(), "t1")
+
+	response := ready()
+	if assert.NotNil(t, response.Retries) {
+		assert.Equal(t, uint64(1), response.Retries.Requests)
+		assert.Equal(t, uint64(1), response.Retries.Retries)
+	}
+}
+
+// decisionCache reports fixed decision cache counters.
+type decisionCache permit.DecisionCacheStats
+
+func (d decisionCache) DecisionCacheStats() permit.DecisionCacheStats {
+	return permit.DecisionCacheStats(d)
+}
+
+func TestReadinessCheckReportsDecisionCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := &HealthHandler{}
+	router.GET("/health/ready", handler.ReadinessCheck)
+	ready := func() string {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/health/ready", nil)
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	assert.NotContains(t, ready(), "decisionCache")
+
+	handler.Decisions = decisionCache{Hits: 7, Misses: 3}
+	assert.Contains(t, ready(), `"decisionCache":{"hits":7,"misses":3}`)
+}
@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"errors"
 	"fmt"
 	"iam_services_main_v1/pkg/auth/jwt"
 	"iam_services_main_v1/pkg/logger"
@@ -13,31 +14,17 @@ import (
 
 // AuthMiddleware creates a Gin middleware for JWT authentication with the default OpenID endpoint
 func AuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		tenantID := os.Getenv("AZURE_TENANT_ID")
-		userClaimField := os.Getenv("JWT_USER_CLAIM_FIELD")
-		cacheDurationStr := os.Getenv("JWKS_CACHE_DURATION")
-		cacheDuration, err := time.ParseDuration(cacheDurationStr)
-		if err != nil {
-			logger.LogError("Invalid JWKS_CACHE_DURATION format", "error", err)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "Invalid JWKS_CACHE_DURATION format"})
-			return
-		}
-		if tenantID == "" {
-			logger.LogError("OpenID config URL not set")
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "OpenID config URL not set"})
-			return
-		}
+	return AuthMiddlewareWithHTTPClient(nil)
+}
 
-		// Create authenticator with default options but custom OpenID config URL
-		options := jwt.DefaultOptions()
-		options.OpenIDConfigURL = fmt.Sprintf("https://login.microsoftonline.com/%s/v2.0/.well-known/openid-configuration", tenantID)
-		options.UserClaimField = userClaimField
-		options.CacheDuration = cacheDuration
-		authenticator, err := jwt.NewAuthenticator(options)
+// AuthMiddlewareWithHTTPClient is AuthMiddleware fetching the OpenID
+// configuration and signing keys with httpClient. A nil client uses the
+// authenticator's default.
+func AuthMiddlewareWithHTTPClient(httpClient *http.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticator, err := AuthenticatorFromEnv(httpClient)
 		if err != nil {
-			logger.LogError("Failed to initialize JWT authenticator", "error", err)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "Failed to initialize authenticator"})
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
 		}
 
@@ -45,3 +32,37 @@ func AuthMiddleware() gin.HandlerFunc {
 		authenticator.GinMiddleware()(c)
 	}
 }
+
+// AuthenticatorFromEnv returns the JWT authenticator of the Azure tenant
+// AZURE_TENANT_ID, reading the user from the JWT_USER_CLAIM_FIELD claim and
+// caching the signing keys for JWKS_CACHE_DURATION. The error is the message
+// reported to the client.
+func AuthenticatorFromEnv(httpClient *http.Client) (*jwt.Authenticator, error) {
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	userClaimField := os.Getenv("JWT_USER_CLAIM_FIELD")
+	cacheDurationStr := os.Getenv("JWKS_CACHE_DURATION")
+	cacheDuration, err := time.ParseDuration(cacheDurationStr)
+	if err != nil {
+		logger.LogError("Invalid JWKS_CACHE_DURATION format", "error", err)
+		return nil, errors.New("Invalid JWKS_CACHE_DURATION format")
+	}
+	if tenantID == "" {
+		logger.LogError("OpenID config URL not set")
+		return nil, errors.New("OpenID config URL not set")
+	}
+
+	// Create authenticator with default options but custom OpenID config URL
+	options := jwt.DefaultOptions()
+	options.OpenIDConfigURL = fmt.Sprintf("https://login.microsoftonline.com/%s/v2.0/.well-known/openid-configuration", tenantID)
+	options.UserClaimField = userClaimField
+	options.CacheDuration = cacheDuration
+	if httpClient != nil {
+		options.HTTPClient = httpClient
+	}
+	authenticator, err := jwt.NewAuthenticator(options)
+	if err != nil {
+		logger.LogError("Failed to initialize JWT authenticator", "error", err)
+		return nil, errors.New("Failed to initialize authenticator")
+	}
+	return authenticator, nil
+}
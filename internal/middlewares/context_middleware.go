@@ -3,14 +3,17 @@ package middlewares
 import (
 	"context"
 	"iam_services_main_v1/config"
+	"iam_services_main_v1/internal/dataloaders"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GinContextToContextMiddleware attaches the Gin context to the request context.
+// GinContextToContextMiddleware attaches the Gin context and the request's
+// dataloaders to the request context.
 func GinContextToContextMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := context.WithValue(c.Request.Context(), config.GinContextKey, c)
+		ctx = dataloaders.WithLoaders(ctx)
 		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
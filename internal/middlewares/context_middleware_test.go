@@ -2,11 +2,14 @@ package middlewares
 
 import (
 	"iam_services_main_v1/config"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/mocks"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -186,3 +189,24 @@ func TestGinContextToContextMiddlewareWithNextCall(t *testing.T) {
 	assert.True(t, middlewareCalled, "Middleware should have been called")
 	assert.True(t, nextHandlerCalled, "Next handler should have been called")
 }
+
+func TestGinContextToContextMiddlewareDataloaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GinContextToContextMiddleware())
+
+	pc := mocks.NewMockPermitService(gomock.NewController(t))
+	var seen []*dataloaders.Loaders
+	router.GET("/test", func(c *gin.Context) {
+		loaders := dataloaders.For(c.Request.Context(), pc)
+		assert.Same(t, loaders, dataloaders.For(c.Request.Context(), pc), "one set of loaders per request")
+		seen = append(seen, loaders)
+	})
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	assert.Len(t, seen, 2)
+	assert.NotSame(t, seen[0], seen[1], "requests do not share loaders")
+}
@@ -0,0 +1,369 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/pkg/logger"
+)
+
+const authorizeDirective = "authorize"
+
+// resourceTypes maps the resource types named by @authorize to their IDs in
+// the authorization schema.
+var resourceTypes = map[string]string{
+	config.Tenant:                 config.TenantResourceTypeID,
+	config.Account:                config.AccountResourceTypeID,
+	config.ClientOrganizationUnit: config.ClientOrgUnitResourceTypeID,
+	config.Role:                   config.RoleResourceTypeID,
+	"Permission":                  config.PermissionResourceTypeID,
+	"Binding":                     config.BindingResourceTypeID,
+	"Root":                        config.RootResourceTypeID,
+}
+
+// rootObjects are the operation types whose fields must be authorized.
+var rootObjects = map[string]bool{
+	"Query":        true,
+	"Mutation":     true,
+	"Subscription": true,
+}
+
+// unauthorizedRootFields are the root fields resolved without an @authorize
+// directive: the federation fields, whose entity resolvers authorize every
+// entity type themselves, and introspection.
+var unauthorizedRootFields = map[string]bool{
+	"_entities": true,
+	"_service":  true,
+}
+
+// FieldAuthorization enforces the @authorize directive of the schema.
+//
+// As a handler extension it authorizes every root field of an operation before
+// any of them resolves: the fields are collected through fragments, aliases
+// and @skip/@include the way the executor collects them, their permissions are
+// checked at once, and the whole operation is rejected with a report of the
+// denied fields when any field is denied. Root fields without an @authorize
+// directive are always denied.
+//
+// Its Directive method is the directive's handler, which checks a root field
+// the operation check did not cover before it resolves.
+type FieldAuthorization struct {
+	Authorizer authz.Authorizer
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+	graphql.FieldInterceptor
+} = FieldAuthorization{}
+
+// operationTypes maps operations to the type of their root fields.
+var operationTypes = map[ast.Operation]string{
+	ast.Query:        "Query",
+	ast.Mutation:     "Mutation",
+	ast.Subscription: "Subscription",
+}
+
+func (FieldAuthorization) ExtensionName() string {
+	return "FieldAuthorization"
+}
+
+// Validate rejects schemas with an @authorize directive naming an unknown
+// resource type, which could never be allowed.
+func (FieldAuthorization) Validate(schema graphql.ExecutableSchema) error {
+	for _, def := range schema.Schema().Types {
+		for _, field := range def.Fields {
+			for _, rule := range fieldRules(field) {
+				if _, ok := resourceTypes[rule.ResourceType]; !ok {
+					return fmt.Errorf("%s.%s: @authorize names unknown resource type %q", def.Name, field.Name, rule.ResourceType)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// DeniedField reports a root field an operation was rejected for.
+type DeniedField struct {
+	// Field is the key of the field in the response, its alias if it has one.
+	Field string `json:"field"`
+	// Name is the name of the field in the schema.
+	Name string `json:"name"`
+	// Reason tells why the field was denied.
+	Reason string `json:"reason"`
+	// Checks are the permissions checked for the field, any of which would
+	// have allowed it.
+	Checks []DeniedCheck `json:"checks,omitempty"`
+}
+
+// DeniedCheck is a permission the caller lacks.
+type DeniedCheck struct {
+	Action string `json:"action"`
+	// ResourceType is the resource type as named by @authorize.
+	ResourceType string `json:"resourceType"`
+	// ResourceID is the checked instance, or "*" for every instance.
+	ResourceID string `json:"resourceId"`
+}
+
+// authorizedFields are the root fields of an operation allowed before it ran.
+type authorizedFields map[*ast.Field]bool
+
+// MutateOperationContext authorizes every root field of the operation, and
+// rejects the operation when any of them is denied. The error's deniedFields
+// extension reports each denied field.
+func (a FieldAuthorization) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if opCtx.Operation == nil {
+		return nil
+	}
+	fields := graphql.CollectFields(opCtx, opCtx.Operation.SelectionSet, []string{operationTypes[opCtx.Operation.Operation]})
+
+	type pending struct {
+		field  graphql.CollectedField
+		rules  []authorizeRule
+		checks []authz.PermissionCheck
+	}
+	var denied []DeniedField
+	var toCheck []pending
+	for _, field := range fields {
+		if !needsAuthorization(field.Name) {
+			continue
+		}
+		rules := knownRules(fieldRules(field.Definition))
+		if len(rules) == 0 {
+			denied = append(denied, DeniedField{Field: field.Alias, Name: field.Name, Reason: "the field has no @authorize directive"})
+			continue
+		}
+		toCheck = append(toCheck, pending{field: field, rules: rules, checks: fieldChecks(rules, field.ArgumentMap(opCtx.Variables))})
+	}
+
+	var checks []authz.PermissionCheck
+	for _, p := range toCheck {
+		checks = append(checks, p.checks...)
+	}
+	var allowed []bool
+	if len(checks) > 0 {
+		var err error
+		if allowed, err = a.bulkCheck(ctx, checks); err != nil {
+			return gqlerror.WrapIfUnwrapped(err)
+		}
+	}
+
+	authorized := authorizedFields{}
+	for _, p := range toCheck {
+		decisions := allowed[:len(p.checks)]
+		allowed = allowed[len(p.checks):]
+		if anyAllowed(decisions) {
+			authorized[p.field.Field] = true
+			continue
+		}
+		report := DeniedField{Field: p.field.Alias, Name: p.field.Name, Reason: "permission denied"}
+		for i, check := range p.checks {
+			report.Checks = append(report.Checks, DeniedCheck{Action: check.Action, ResourceType: p.rules[i].ResourceType, ResourceID: check.ResourceID})
+		}
+		denied = append(denied, report)
+	}
+
+	if len(denied) > 0 {
+		logger.LogInfo("Operation denied", "operation", opCtx.OperationName, "deniedFields", len(denied), "rootFields", len(fields))
+		err := forbiddenError()
+		err.Extensions["deniedFields"] = denied
+		return err
+	}
+	opCtx.Stats.SetExtension(FieldAuthorization{}.ExtensionName(), authorized)
+	return nil
+}
+
+// InterceptField denies the root fields without an @authorize directive.
+func (FieldAuthorization) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !rootObjects[fc.Object] || !needsAuthorization(fc.Field.Name) {
+		return next(ctx)
+	}
+	if len(fieldRules(fc.Field.Definition)) == 0 {
+		logger.LogError("Denied a root field without an @authorize directive", "field", fc.Field.Name)
+		return nil, forbidden(fc.Field.Name)
+	}
+	return next(ctx)
+}
+
+type authorizedFieldKey struct{}
+
+// Directive is the handler of the @authorize directive. The field is resolved
+// when any of its @authorize directives is allowed, so all of them are checked
+// at once by the handler of the first, and the others let the field through.
+// Fields already allowed by the operation check are not checked again.
+func (a FieldAuthorization) Directive(ctx context.Context, _ interface{}, next graphql.Resolver, _ string, _ string, _ *string) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil {
+		return nil, errors.New("@authorize used outside of a field")
+	}
+	if authorized, _ := ctx.Value(authorizedFieldKey{}).(*graphql.FieldContext); authorized == fc {
+		return next(ctx)
+	}
+
+	var args map[string]interface{}
+	if graphql.HasOperationContext(ctx) {
+		opCtx := graphql.GetOperationContext(ctx)
+		if authorized, _ := opCtx.Stats.GetExtension(a.ExtensionName()).(authorizedFields); authorized[fc.Field.Field] {
+			return next(context.WithValue(ctx, authorizedFieldKey{}, fc))
+		}
+		args = fc.Field.ArgumentMap(opCtx.Variables)
+	}
+	checks := fieldChecks(knownRules(fieldRules(fc.Field.Definition)), args)
+	if len(checks) == 0 {
+		return nil, forbidden(fc.Field.Name)
+	}
+	allowed, err := a.bulkCheck(ctx, checks)
+	if err != nil {
+		return nil, err
+	}
+	if !anyAllowed(allowed) {
+		logger.LogInfo("Field authorization denied", "field", fc.Field.Name)
+		return nil, forbidden(fc.Field.Name)
+	}
+	return next(context.WithValue(ctx, authorizedFieldKey{}, fc))
+}
+
+// needsAuthorization reports whether the root field name must be authorized.
+func needsAuthorization(name string) bool {
+	return !unauthorizedRootFields[name] && !strings.HasPrefix(name, "__")
+}
+
+// knownRules returns the rules naming a known resource type.
+func knownRules(rules []authorizeRule) []authorizeRule {
+	known := make([]authorizeRule, 0, len(rules))
+	for _, rule := range rules {
+		if _, ok := resourceTypes[rule.ResourceType]; ok {
+			known = append(known, rule)
+		}
+	}
+	return known
+}
+
+// fieldChecks returns one permission check per rule, with the resource IDs
+// taken from args.
+func fieldChecks(rules []authorizeRule, args map[string]interface{}) []authz.PermissionCheck {
+	checks := make([]authz.PermissionCheck, 0, len(rules))
+	for _, rule := range rules {
+		checks = append(checks, authz.PermissionCheck{
+			Action:       strings.ToLower(rule.Action),
+			ResourceType: resourceTypes[rule.ResourceType],
+			ResourceID:   rule.resourceID(args),
+		})
+	}
+	return checks
+}
+
+// bulkCheck decides checks for the caller in their tenant, in batches of at
+// most authz.MaxBulkChecks. A failed check is a denial.
+func (a FieldAuthorization) bulkCheck(ctx context.Context, checks []authz.PermissionCheck) ([]bool, error) {
+	userID, tenantID, err := helpers.GetUserAndTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]bool, 0, len(checks))
+	for start := 0; start < len(checks); start += authz.MaxBulkChecks {
+		batch := checks[start:min(start+authz.MaxBulkChecks, len(checks))]
+		if a.Authorizer == nil {
+			allowed = append(allowed, make([]bool, len(batch))...)
+			continue
+		}
+		decisions, err := a.Authorizer.BulkCheck(ctx, userID.String(), tenantID.String(), batch)
+		if errors.Is(err, authz.ErrUnavailable) {
+			return nil, errorcatalog.Authorization.Err(http.StatusServiceUnavailable, err)
+		}
+		if err != nil {
+			logger.LogError("Field authorization failed", "error", err)
+			decisions = make([]bool, len(batch))
+		}
+		allowed = append(allowed, decisions...)
+	}
+	return allowed, nil
+}
+
+func anyAllowed(decisions []bool) bool {
+	for _, ok := range decisions {
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func forbidden(field string) *gqlerror.Error {
+	err := forbiddenError()
+	err.Extensions["field"] = field
+	return err
+}
+
+// forbiddenError returns the error of a denied authorization, with the
+// extensions of its catalog entry.
+func forbiddenError() *gqlerror.Error {
+	entry := errorcatalog.Authorization.Entry(http.StatusForbidden)
+	return &gqlerror.Error{Message: entry.Message, Extensions: entry.Extensions()}
+}
+
+// authorizeRule is one @authorize directive of a field.
+type authorizeRule struct {
+	Action       string
+	ResourceType string
+	// IDArg is the dotted path of the argument holding the resource's ID,
+	// empty for checks on every instance of the type.
+	IDArg string
+}
+
+// fieldRules returns the @authorize directives of def.
+func fieldRules(def *ast.FieldDefinition) []authorizeRule {
+	if def == nil {
+		return nil
+	}
+	var rules []authorizeRule
+	for _, directive := range def.Directives.ForNames(authorizeDirective) {
+		rules = append(rules, authorizeRule{
+			Action:       directiveArgument(directive, "action"),
+			ResourceType: directiveArgument(directive, "resourceType"),
+			IDArg:        directiveArgument(directive, "idArg"),
+		})
+	}
+	return rules
+}
+
+func directiveArgument(directive *ast.Directive, name string) string {
+	arg := directive.Arguments.ForName(name)
+	if arg == nil || arg.Value == nil {
+		return ""
+	}
+	return arg.Value.Raw
+}
+
+// resourceID returns the ID found at the rule's IDArg in args, or "*", which
+// checks the action on every instance of the type, when the rule names no
+// argument or it is not set.
+func (r authorizeRule) resourceID(args map[string]interface{}) string {
+	if r.IDArg == "" {
+		return "*"
+	}
+	var value interface{} = args
+	for _, name := range strings.Split(r.IDArg, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "*"
+		}
+		value = object[name]
+	}
+	if value == nil || fmt.Sprint(value) == "" {
+		return "*"
+	}
+	return fmt.Sprint(value)
+}
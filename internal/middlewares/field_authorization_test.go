@@ -0,0 +1,308 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/internal/authz"
+)
+
+var authorizationSchema = gqlparser.MustLoadSchema(&ast.Source{Input: `
+	directive @authorize(action: String!, resourceType: String!, idArg: String) repeatable on FIELD_DEFINITION
+	input DeleteInput { id: ID! }
+	type Query {
+		tenant(id: ID!): String @authorize(action: "tenant", resourceType: "Tenant", idArg: "id")
+		tenants: [String!]! @authorize(action: "tenants", resourceType: "Tenant")
+		organizations: [String!]! @authorize(action: "accounts", resourceType: "Account") @authorize(action: "clientOrganizationUnits", resourceType: "ClientOrganizationUnit")
+		root: String
+		_service: String
+	}
+	type Mutation {
+		deleteTenant(input: DeleteInput!): String @authorize(action: "deleteTenant", resourceType: "Tenant", idArg: "input.id")
+	}
+`})
+
+type fakeExecutableSchema struct {
+	graphql.ExecutableSchema
+	schema *ast.Schema
+}
+
+func (s fakeExecutableSchema) Schema() *ast.Schema {
+	return s.schema
+}
+
+// authorizationOperation returns the first operation of query, made by a
+// caller in tenantID, and its context.
+func authorizationOperation(t *testing.T, tenantID, query string, variables map[string]interface{}) (context.Context, *graphql.OperationContext) {
+	t.Helper()
+	doc, errs := gqlparser.LoadQuery(authorizationSchema, query)
+	if !assert.Empty(t, errs) {
+		t.FailNow()
+	}
+	opCtx := &graphql.OperationContext{Doc: doc, Operation: doc.Operations[0], Variables: variables}
+
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantID)
+	ginCtx.Set("userID", uuid.New().String())
+	ctx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+	return graphql.WithOperationContext(ctx, opCtx), opCtx
+}
+
+// rootFieldContext returns the context of the first root field of query.
+func rootFieldContext(t *testing.T, tenantID, query string, variables map[string]interface{}) context.Context {
+	t.Helper()
+	ctx, opCtx := authorizationOperation(t, tenantID, query, variables)
+	field := opCtx.Operation.SelectionSet[0].(*ast.Field)
+	return graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object: field.ObjectDefinition.Name,
+		Field:  graphql.CollectedField{Field: field},
+	})
+}
+
+// resolveField runs the field of ctx through the extension and its
+// @authorize directives, the way the generated code does.
+func resolveField(ctx context.Context, a FieldAuthorization) (bool, error) {
+	resolved := false
+	resolver := func(context.Context) (interface{}, error) {
+		resolved = true
+		return "ok", nil
+	}
+	for range graphql.GetFieldContext(ctx).Field.Definition.Directives.ForNames(authorizeDirective) {
+		next := resolver
+		resolver = func(ctx context.Context) (interface{}, error) {
+			return a.Directive(ctx, nil, next, "", "", nil)
+		}
+	}
+	_, err := a.InterceptField(ctx, resolver)
+	return resolved, err
+}
+
+type unavailableAuthorizer struct{ authz.Authorizer }
+
+func (unavailableAuthorizer) BulkCheck(context.Context, string, string, []authz.PermissionCheck) ([]bool, error) {
+	return nil, fmt.Errorf("pdp: %w", authz.ErrUnavailable)
+}
+
+// countingAuthorizer counts the BulkCheck calls of its Authorizer.
+type countingAuthorizer struct {
+	authz.Authorizer
+	calls int
+}
+
+func (c *countingAuthorizer) BulkCheck(ctx context.Context, userID, tenant string, checks []authz.PermissionCheck) ([]bool, error) {
+	c.calls++
+	return c.Authorizer.BulkCheck(ctx, userID, tenant, checks)
+}
+
+func TestFieldAuthorization(t *testing.T) {
+	tenantID := uuid.New().String()
+	allow := func(action, resourceType, resourceID string) FieldAuthorization {
+		return FieldAuthorization{Authorizer: &authz.Policy{Rules: []authz.Rule{{
+			Actions:      []string{action},
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			Tenant:       tenantID,
+		}}}}
+	}
+	assertForbidden := func(t *testing.T, err error) {
+		var gqlErr *gqlerror.Error
+		if assert.ErrorAs(t, err, &gqlErr) {
+			assert.Equal(t, "FORBIDDEN", gqlErr.Extensions["code"])
+		}
+	}
+
+	t.Run("Checks the action on the resource type", func(t *testing.T) {
+		ctx := rootFieldContext(t, tenantID, `{ tenants }`, nil)
+		resolved, err := resolveField(ctx, allow("tenants", config.TenantResourceTypeID, ""))
+		assert.NoError(t, err)
+		assert.True(t, resolved)
+
+		resolved, err = resolveField(ctx, allow("tenants", config.AccountResourceTypeID, ""))
+		assertForbidden(t, err)
+		assert.False(t, resolved)
+	})
+
+	t.Run("Checks the instance named by the ID argument", func(t *testing.T) {
+		a := allow("tenant", config.TenantResourceTypeID, "t1")
+		resolved, err := resolveField(rootFieldContext(t, tenantID, `{ tenant(id: "t1") }`, nil), a)
+		assert.NoError(t, err)
+		assert.True(t, resolved)
+
+		resolved, err = resolveField(rootFieldContext(t, tenantID, `{ tenant(id: "t2") }`, nil), a)
+		assertForbidden(t, err)
+		assert.False(t, resolved)
+	})
+
+	t.Run("Reads the ID from input objects and variables", func(t *testing.T) {
+		a := allow("deletetenant", config.TenantResourceTypeID, "t1")
+		query := `mutation($input: DeleteInput!) { deleteTenant(input: $input) }`
+		resolved, err := resolveField(rootFieldContext(t, tenantID, query, map[string]interface{}{"input": map[string]interface{}{"id": "t1"}}), a)
+		assert.NoError(t, err)
+		assert.True(t, resolved)
+
+		resolved, err = resolveField(rootFieldContext(t, tenantID, query, map[string]interface{}{"input": map[string]interface{}{"id": "t2"}}), a)
+		assertForbidden(t, err)
+		assert.False(t, resolved)
+	})
+
+	t.Run("Any of several directives allows the field", func(t *testing.T) {
+		ctx := rootFieldContext(t, tenantID, `{ organizations }`, nil)
+		resolved, err := resolveField(ctx, allow("clientorganizationunits", config.ClientOrgUnitResourceTypeID, ""))
+		assert.NoError(t, err)
+		assert.True(t, resolved)
+
+		resolved, err = resolveField(ctx, allow("tenants", config.TenantResourceTypeID, ""))
+		assertForbidden(t, err)
+		assert.False(t, resolved)
+	})
+
+	t.Run("Only checks the caller's tenant", func(t *testing.T) {
+		ctx := rootFieldContext(t, uuid.New().String(), `{ tenants }`, nil)
+		_, err := resolveField(ctx, allow("tenants", config.TenantResourceTypeID, ""))
+		assertForbidden(t, err)
+	})
+
+	t.Run("Root fields without a directive are denied", func(t *testing.T) {
+		resolved, err := resolveField(rootFieldContext(t, tenantID, `{ root }`, nil), FieldAuthorization{Authorizer: authz.AllowAll()})
+		assertForbidden(t, err)
+		assert.False(t, resolved)
+	})
+
+	t.Run("Federation fields are authorized by their resolvers", func(t *testing.T) {
+		resolved, err := resolveField(rootFieldContext(t, tenantID, `{ _service }`, nil), FieldAuthorization{Authorizer: authz.DenyAll()})
+		assert.NoError(t, err)
+		assert.True(t, resolved)
+	})
+
+	t.Run("Authorizer unavailable", func(t *testing.T) {
+		_, err := resolveField(rootFieldContext(t, tenantID, `{ tenants }`, nil), FieldAuthorization{Authorizer: unavailableAuthorizer{}})
+		assert.EqualError(t, err, config.UpstreamUnavailableErrorMessage)
+	})
+
+	t.Run("Without an authorizer", func(t *testing.T) {
+		_, err := resolveField(rootFieldContext(t, tenantID, `{ tenants }`, nil), FieldAuthorization{})
+		assertForbidden(t, err)
+	})
+}
+
+func TestFieldAuthorizationValidate(t *testing.T) {
+	assert.NoError(t, FieldAuthorization{}.Validate(fakeExecutableSchema{schema: authorizationSchema}))
+
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		directive @authorize(action: String!, resourceType: String!, idArg: String) repeatable on FIELD_DEFINITION
+		type Query { tenants: [String!]! @authorize(action: "tenants", resourceType: "tenant") }
+	`})
+	err := FieldAuthorization{}.Validate(fakeExecutableSchema{schema: schema})
+	assert.ErrorContains(t, err, `Query.tenants: @authorize names unknown resource type "tenant"`)
+}
+
+func TestFieldAuthorizationOperation(t *testing.T) {
+	tenantID := uuid.New().String()
+	tenants := FieldAuthorization{Authorizer: &authz.Policy{Rules: []authz.Rule{{
+		Actions:      []string{"tenants"},
+		ResourceType: config.TenantResourceTypeID,
+	}}}}
+	deniedFields := func(t *testing.T, err *gqlerror.Error) []DeniedField {
+		if !assert.NotNil(t, err) {
+			return nil
+		}
+		assert.Equal(t, "FORBIDDEN", err.Extensions["code"])
+		denied, _ := err.Extensions["deniedFields"].([]DeniedField)
+		return denied
+	}
+
+	t.Run("Allows operations whose every root field is allowed", func(t *testing.T) {
+		ctx, opCtx := authorizationOperation(t, tenantID, `{ tenants again: tenants __typename _service }`, nil)
+		assert.Nil(t, tenants.MutateOperationContext(ctx, opCtx))
+	})
+
+	t.Run("Rejects the operation when any root field is denied", func(t *testing.T) {
+		ctx, opCtx := authorizationOperation(t, tenantID, `{ tenants organizations }`, nil)
+		assert.Equal(t, []DeniedField{{
+			Field:  "organizations",
+			Name:   "organizations",
+			Reason: "permission denied",
+			Checks: []DeniedCheck{
+				{Action: "accounts", ResourceType: "Account", ResourceID: "*"},
+				{Action: "clientorganizationunits", ResourceType: "ClientOrganizationUnit", ResourceID: "*"},
+			},
+		}}, deniedFields(t, tenants.MutateOperationContext(ctx, opCtx)))
+	})
+
+	t.Run("Follows fragments and aliases", func(t *testing.T) {
+		ctx, opCtx := authorizationOperation(t, tenantID, `
+			query { list: tenants ...Fields ... on Query { one: tenant(id: "t1") } }
+			fragment Fields on Query { r: root }
+		`, nil)
+		assert.Equal(t, []DeniedField{
+			{Field: "r", Name: "root", Reason: "the field has no @authorize directive"},
+			{Field: "one", Name: "tenant", Reason: "permission denied", Checks: []DeniedCheck{{Action: "tenant", ResourceType: "Tenant", ResourceID: "t1"}}},
+		}, deniedFields(t, tenants.MutateOperationContext(ctx, opCtx)))
+	})
+
+	t.Run("Skipped fields are not authorized", func(t *testing.T) {
+		ctx, opCtx := authorizationOperation(t, tenantID, `query($skip: Boolean!) { tenants root @skip(if: $skip) }`, map[string]interface{}{"skip": true})
+		assert.Nil(t, tenants.MutateOperationContext(ctx, opCtx))
+	})
+
+	t.Run("Operation names do not select the action", func(t *testing.T) {
+		ctx, opCtx := authorizationOperation(t, tenantID, `mutation tenants($input: DeleteInput!) { deleteTenant(input: $input) }`, map[string]interface{}{"input": map[string]interface{}{"id": "t1"}})
+		denied := deniedFields(t, tenants.MutateOperationContext(ctx, opCtx))
+		if assert.Len(t, denied, 1) {
+			assert.Equal(t, "deleteTenant", denied[0].Name)
+		}
+	})
+
+	t.Run("Checks every field at once and not again when it resolves", func(t *testing.T) {
+		counting := &countingAuthorizer{Authorizer: authz.AllowAll()}
+		a := FieldAuthorization{Authorizer: counting}
+		ctx, opCtx := authorizationOperation(t, tenantID, `{ tenants organizations }`, nil)
+		assert.Nil(t, a.MutateOperationContext(ctx, opCtx))
+		assert.Equal(t, 1, counting.calls)
+
+		for _, selection := range opCtx.Operation.SelectionSet {
+			field := selection.(*ast.Field)
+			fieldCtx := graphql.WithFieldContext(ctx, &graphql.FieldContext{Object: "Query", Field: graphql.CollectedField{Field: field}})
+			resolved, err := resolveField(fieldCtx, a)
+			assert.NoError(t, err)
+			assert.True(t, resolved)
+		}
+		assert.Equal(t, 1, counting.calls)
+	})
+
+	t.Run("Splits large operations into batches", func(t *testing.T) {
+		query := "{"
+		for i := 0; i <= authz.MaxBulkChecks; i++ {
+			query += fmt.Sprintf(" t%d: tenants", i)
+		}
+		counting := &countingAuthorizer{Authorizer: authz.AllowAll()}
+		ctx, opCtx := authorizationOperation(t, tenantID, query+" }", nil)
+		assert.Nil(t, FieldAuthorization{Authorizer: counting}.MutateOperationContext(ctx, opCtx))
+		assert.Equal(t, 2, counting.calls)
+	})
+
+	t.Run("Authorizer unavailable", func(t *testing.T) {
+		ctx, opCtx := authorizationOperation(t, tenantID, `{ tenants }`, nil)
+		err := FieldAuthorization{Authorizer: unavailableAuthorizer{}}.MutateOperationContext(ctx, opCtx)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, config.UpstreamUnavailableErrorMessage, err.Message)
+		}
+	})
+
+	t.Run("Federation operations need no caller", func(t *testing.T) {
+		doc, errs := gqlparser.LoadQuery(authorizationSchema, `{ _service }`)
+		assert.Empty(t, errs)
+		opCtx := &graphql.OperationContext{Doc: doc, Operation: doc.Operations[0]}
+		assert.Nil(t, tenants.MutateOperationContext(context.Background(), opCtx))
+	})
+}
@@ -0,0 +1,174 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// DefaultPersistedQueryCacheSize is the number of automatic persisted queries
+// remembered when no size is configured.
+const DefaultPersistedQueryCacheSize = 1000
+
+const (
+	errPersistedQueryNotFound     = "PersistedQueryNotFound"
+	errPersistedQueryNotFoundCode = "PERSISTED_QUERY_NOT_FOUND"
+	errPersistedQueryNotAllowed   = "PERSISTED_QUERY_NOT_ALLOWED"
+)
+
+// PersistedQueries is a GraphQL handler extension serving persisted queries,
+// which clients send as the SHA-256 hash of their text in the
+// extensions.persistedQuery.sha256Hash parameter.
+//
+// By default it implements automatic persisted queries: a hash the server
+// has not seen is answered with a PERSISTED_QUERY_NOT_FOUND error, and the
+// client retries with the hash and the full query, which is remembered in an
+// LRU cache. Queries sent without a hash run as usual.
+//
+// With an allowlist it runs in strict mode instead: only the queries of the
+// allowlist run, whether they are sent in full or as a hash, and every other
+// operation is rejected with a PERSISTED_QUERY_NOT_ALLOWED error.
+type PersistedQueries struct {
+	cache     *lru.LRU[string]
+	allowlist map[string]string
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = (*PersistedQueries)(nil)
+
+// NewPersistedQueries returns automatic persisted queries remembering up to
+// cacheSize queries or, when allowlist is not nil, strict persisted queries
+// only running the queries of allowlist, keyed by their hash.
+func NewPersistedQueries(cacheSize int, allowlist map[string]string) *PersistedQueries {
+	if allowlist != nil {
+		return &PersistedQueries{allowlist: allowlist}
+	}
+	if cacheSize < 1 {
+		cacheSize = DefaultPersistedQueryCacheSize
+	}
+	return &PersistedQueries{cache: lru.New[string](cacheSize)}
+}
+
+// LoadAllowlist reads an allowlist from the JSON file at path. The file maps
+// the SHA-256 hash of every allowed query, in hex, to the query, e.g.
+//
+//	{"5b4c...": "query Tenants { tenants { __typename } }"}
+func LoadAllowlist(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read allowlist file: %w", err)
+	}
+	allowlist := map[string]string{}
+	if err := json.Unmarshal(data, &allowlist); err != nil {
+		return nil, fmt.Errorf("parse allowlist file %s: %w", path, err)
+	}
+	for hash, query := range allowlist {
+		if queryHash(query) != hash {
+			return nil, fmt.Errorf("parse allowlist file %s: %s is not the SHA-256 hash of its query", path, hash)
+		}
+	}
+	return allowlist, nil
+}
+
+// Strict reports whether only the queries of an allowlist run.
+func (p *PersistedQueries) Strict() bool {
+	return p.allowlist != nil
+}
+
+func (*PersistedQueries) ExtensionName() string {
+	return "PersistedQueries"
+}
+
+func (*PersistedQueries) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationParameters replaces a persisted query's hash with its text.
+func (p *PersistedQueries) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	query, err := p.resolve(ctx, rawParams.Query, rawParams.Extensions)
+	if err != nil {
+		return err
+	}
+	rawParams.Query = query
+	return nil
+}
+
+// resolve returns the query to run for a request sending query and
+// extensions.
+func (p *PersistedQueries) resolve(ctx context.Context, query string, extensions map[string]interface{}) (string, *gqlerror.Error) {
+	hash, err := persistedQueryHash(extensions)
+	if err != nil {
+		return "", err
+	}
+	if hash != "" && query != "" && queryHash(query) != hash {
+		return "", gqlerror.Errorf("provided persisted query hash does not match query")
+	}
+
+	if p.Strict() {
+		if hash == "" && query == "" {
+			return "", nil
+		}
+		if hash == "" {
+			hash = queryHash(query)
+		}
+		allowed, ok := p.allowlist[hash]
+		if !ok {
+			err := gqlerror.Errorf("operation is not in the allowlist")
+			errcode.Set(err, errPersistedQueryNotAllowed)
+			return "", err
+		}
+		return allowed, nil
+	}
+
+	switch {
+	case hash == "":
+		return query, nil
+	case query == "":
+		cached, ok := p.cache.Get(ctx, hash)
+		if !ok {
+			err := gqlerror.Errorf(errPersistedQueryNotFound)
+			errcode.Set(err, errPersistedQueryNotFoundCode)
+			return "", err
+		}
+		return cached, nil
+	default:
+		p.cache.Add(ctx, hash, query)
+		return query, nil
+	}
+}
+
+// persistedQueryHash returns the hash of the persistedQuery extension, if any.
+func persistedQueryHash(extensions map[string]interface{}) (string, *gqlerror.Error) {
+	raw, ok := extensions["persistedQuery"]
+	if !ok || raw == nil {
+		return "", nil
+	}
+	ext, ok := raw.(map[string]interface{})
+	if !ok {
+		return "", gqlerror.Errorf("invalid persisted query extension data")
+	}
+	// The version is a json.Number or a float64, depending on the decoder
+	if fmt.Sprint(ext["version"]) != "1" {
+		return "", gqlerror.Errorf("unsupported persisted query version")
+	}
+	hash, ok := ext["sha256Hash"].(string)
+	if !ok || hash == "" {
+		return "", gqlerror.Errorf("invalid persisted query extension data")
+	}
+	return hash, nil
+}
+
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,118 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func persistedQuery(hash string) map[string]interface{} {
+	return map[string]interface{}{
+		"persistedQuery": map[string]interface{}{"version": 1.0, "sha256Hash": hash},
+	}
+}
+
+func TestPersistedQueries(t *testing.T) {
+	ctx := context.Background()
+	query := `{ tenants { __typename } }`
+	hash := queryHash(query)
+
+	t.Run("Queries without a hash run as usual", func(t *testing.T) {
+		p := NewPersistedQueries(0, nil)
+		resolved, err := p.resolve(ctx, query, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, query, resolved)
+	})
+
+	t.Run("Unknown hashes are not found until sent with their query", func(t *testing.T) {
+		p := NewPersistedQueries(0, nil)
+		_, err := p.resolve(ctx, "", persistedQuery(hash))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "PersistedQueryNotFound", err.Message)
+			assert.Equal(t, "PERSISTED_QUERY_NOT_FOUND", err.Extensions["code"])
+		}
+
+		resolved, err := p.resolve(ctx, query, persistedQuery(hash))
+		assert.Nil(t, err)
+		assert.Equal(t, query, resolved)
+
+		resolved, err = p.resolve(ctx, "", persistedQuery(hash))
+		assert.Nil(t, err)
+		assert.Equal(t, query, resolved)
+	})
+
+	t.Run("Rejects a hash not matching the query", func(t *testing.T) {
+		p := NewPersistedQueries(0, nil)
+		_, err := p.resolve(ctx, `{ accounts { __typename } }`, persistedQuery(hash))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "provided persisted query hash does not match query", err.Message)
+		}
+	})
+
+	t.Run("Rejects unsupported versions", func(t *testing.T) {
+		p := NewPersistedQueries(0, nil)
+		_, err := p.resolve(ctx, "", map[string]interface{}{
+			"persistedQuery": map[string]interface{}{"version": 2.0, "sha256Hash": hash},
+		})
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "unsupported persisted query version", err.Message)
+		}
+	})
+
+	t.Run("Strict mode only runs allowlisted queries", func(t *testing.T) {
+		p := NewPersistedQueries(0, map[string]string{hash: query})
+		assert.True(t, p.Strict())
+
+		resolved, err := p.resolve(ctx, "", persistedQuery(hash))
+		assert.Nil(t, err)
+		assert.Equal(t, query, resolved)
+
+		resolved, err = p.resolve(ctx, query, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, query, resolved)
+
+		_, err = p.resolve(ctx, `{ accounts { __typename } }`, nil)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "PERSISTED_QUERY_NOT_ALLOWED", err.Extensions["code"])
+		}
+
+		other := `{ accounts { __typename } }`
+		_, err = p.resolve(ctx, other, persistedQuery(queryHash(other)))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "PERSISTED_QUERY_NOT_ALLOWED", err.Extensions["code"])
+		}
+	})
+}
+
+func TestLoadAllowlist(t *testing.T) {
+	query := `{ tenants { __typename } }`
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "allowlist.json")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("Loads queries by hash", func(t *testing.T) {
+		data, _ := json.Marshal(map[string]string{queryHash(query): query})
+		allowlist, err := LoadAllowlist(write(t, string(data)))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{queryHash(query): query}, allowlist)
+	})
+
+	t.Run("Rejects wrong hashes", func(t *testing.T) {
+		data, _ := json.Marshal(map[string]string{queryHash("{ accounts { __typename } }"): query})
+		_, err := LoadAllowlist(write(t, string(data)))
+		assert.ErrorContains(t, err, "is not the SHA-256 hash of its query")
+	})
+
+	t.Run("Rejects invalid files", func(t *testing.T) {
+		_, err := LoadAllowlist(write(t, "[]"))
+		assert.Error(t, err)
+		_, err = LoadAllowlist(filepath.Join(t.TempDir(), "missing.json"))
+		assert.Error(t, err)
+	})
+}
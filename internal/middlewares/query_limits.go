@@ -0,0 +1,180 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// DefaultMaxDepth is the deepest nesting of fields an operation may select.
+	DefaultMaxDepth = 10
+	// DefaultMaxComplexity is the highest complexity an operation may have.
+	DefaultMaxComplexity = 200
+
+	// measureFactor bounds the work spent measuring an operation: measuring
+	// stops once the complexity is this many times over the limit.
+	measureFactor = 10
+
+	errDepthLimit      = "DEPTH_LIMIT_EXCEEDED"
+	errComplexityLimit = "COMPLEXITY_LIMIT_EXCEEDED"
+)
+
+// QueryLimits is a GraphQL handler extension that rejects operations nesting
+// fields deeper than MaxDepth or with a complexity above MaxComplexity, before
+// any of their resolvers run. A limit of 0 or less is not enforced.
+//
+// The complexity of an operation is the sum of the weights of the fields it
+// selects: the weight given by the field's @cost directive, or 1. Introspection
+// fields are free, so clients can always load the schema.
+type QueryLimits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = QueryLimits{}
+
+// DefaultQueryLimits returns the limits used when none are configured.
+func DefaultQueryLimits() QueryLimits {
+	return QueryLimits{MaxDepth: DefaultMaxDepth, MaxComplexity: DefaultMaxComplexity}
+}
+
+func (QueryLimits) ExtensionName() string {
+	return "QueryLimits"
+}
+
+func (QueryLimits) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationContext measures the operation and rejects it when it is over
+// a limit. The error's extensions report the measured depth and complexity,
+// the limits, and the complexity added by each field.
+func (l QueryLimits) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if opCtx.Operation == nil {
+		return nil
+	}
+	cost := MeasureOperation(opCtx.Operation, l.MaxComplexity*measureFactor)
+
+	var err *gqlerror.Error
+	switch {
+	case l.MaxDepth > 0 && cost.Depth > l.MaxDepth:
+		err = gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", cost.Depth, l.MaxDepth)
+		err.Extensions = map[string]interface{}{"code": errDepthLimit}
+	case l.MaxComplexity > 0 && cost.Complexity > l.MaxComplexity:
+		atLeast := ""
+		if cost.Truncated {
+			atLeast = "at least "
+		}
+		err = gqlerror.Errorf("operation has complexity %s%d, which exceeds the limit of %d", atLeast, cost.Complexity, l.MaxComplexity)
+		err.Extensions = map[string]interface{}{"code": errComplexityLimit}
+	default:
+		return nil
+	}
+	err.Extensions["depth"] = cost.Depth
+	err.Extensions["maxDepth"] = l.MaxDepth
+	err.Extensions["complexity"] = cost.Complexity
+	err.Extensions["maxComplexity"] = l.MaxComplexity
+	err.Extensions["fieldCosts"] = cost.Fields
+	err.Extensions["truncated"] = cost.Truncated
+	return err
+}
+
+// OperationCost is the measured size of an operation.
+type OperationCost struct {
+	// Depth is the deepest nesting of fields, 1 for an operation selecting
+	// only root fields.
+	Depth int
+	// Complexity is the sum of the weights of the selected fields.
+	Complexity int
+	// Fields is the complexity added by each field, keyed by its schema
+	// coordinate, e.g. "Account.parentOrg".
+	Fields map[string]int
+	// Truncated reports that measuring stopped early, so the operation is
+	// more complex, and may be deeper, than measured.
+	Truncated bool
+}
+
+// MeasureOperation returns the depth and complexity of op, which must have
+// been validated against the schema. Measuring stops once the complexity
+// exceeds stopAbove, if it is positive, so a query built to be expensive to
+// measure is not walked in full.
+func MeasureOperation(op *ast.OperationDefinition, stopAbove int) OperationCost {
+	m := &measurer{stopAbove: stopAbove, cost: OperationCost{Fields: map[string]int{}}}
+	m.selectionSet(op.SelectionSet, 1)
+	return m.cost
+}
+
+type measurer struct {
+	stopAbove int
+	cost      OperationCost
+}
+
+func (m *measurer) done() bool {
+	if m.stopAbove > 0 && m.cost.Complexity > m.stopAbove {
+		m.cost.Truncated = true
+	}
+	return m.cost.Truncated
+}
+
+func (m *measurer) selectionSet(set ast.SelectionSet, depth int) {
+	for _, selection := range set {
+		if m.done() {
+			return
+		}
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name, "__") {
+				continue
+			}
+			if depth > m.cost.Depth {
+				m.cost.Depth = depth
+			}
+			weight := fieldWeight(selection.Definition)
+			m.cost.Complexity += weight
+			m.cost.Fields[coordinate(selection)] += weight
+			m.selectionSet(selection.SelectionSet, depth+1)
+		case *ast.InlineFragment:
+			m.selectionSet(selection.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			if selection.Definition != nil {
+				m.selectionSet(selection.Definition.SelectionSet, depth)
+			}
+		}
+	}
+}
+
+// fieldWeight returns the weight of the @cost directive of def, or 1.
+func fieldWeight(def *ast.FieldDefinition) int {
+	if def == nil {
+		return 1
+	}
+	directive := def.Directives.ForName("cost")
+	if directive == nil {
+		return 1
+	}
+	arg := directive.Arguments.ForName("weight")
+	if arg == nil || arg.Value == nil {
+		return 1
+	}
+	weight, err := strconv.Atoi(arg.Value.Raw)
+	if err != nil || weight < 0 {
+		return 1
+	}
+	return weight
+}
+
+func coordinate(field *ast.Field) string {
+	if field.ObjectDefinition == nil {
+		return field.Name
+	}
+	return fmt.Sprintf("%s.%s", field.ObjectDefinition.Name, field.Name)
+}
@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+var limitsSchema = gqlparser.MustLoadSchema(&ast.Source{Input: `
+	directive @cost(weight: Int!) on FIELD_DEFINITION
+	type Query {
+		accounts: [Account!]! @cost(weight: 10)
+		version: String!
+	}
+	type Account {
+		id: ID!
+		parentOrg: Account @cost(weight: 5)
+	}
+`})
+
+func operationContext(t *testing.T, query string) *graphql.OperationContext {
+	t.Helper()
+	doc, errs := gqlparser.LoadQuery(limitsSchema, query)
+	if !assert.Empty(t, errs) {
+		t.FailNow()
+	}
+	return &graphql.OperationContext{Doc: doc, Operation: doc.Operations[0]}
+}
+
+func TestMeasureOperation(t *testing.T) {
+	t.Run("Sums field weights", func(t *testing.T) {
+		cost := MeasureOperation(operationContext(t, `{ version accounts { id parentOrg { id parentOrg { id } } } }`).Operation, 0)
+		assert.Equal(t, 4, cost.Depth)
+		assert.Equal(t, 1+10+1+5+1+5+1, cost.Complexity)
+		assert.Equal(t, map[string]int{"Query.version": 1, "Query.accounts": 10, "Account.id": 3, "Account.parentOrg": 10}, cost.Fields)
+	})
+
+	t.Run("Follows fragments", func(t *testing.T) {
+		cost := MeasureOperation(operationContext(t, `
+			{ accounts { ...Parent ... on Account { id } } }
+			fragment Parent on Account { parentOrg { id } }
+		`).Operation, 0)
+		assert.Equal(t, 3, cost.Depth)
+		assert.Equal(t, 10+5+1+1, cost.Complexity)
+	})
+
+	t.Run("Introspection is free", func(t *testing.T) {
+		cost := MeasureOperation(operationContext(t, `{ __schema { types { fields { type { ofType { ofType { name } } } } } } __typename }`).Operation, 0)
+		assert.Equal(t, 0, cost.Depth)
+		assert.Equal(t, 0, cost.Complexity)
+	})
+
+	t.Run("Stops once over the limit", func(t *testing.T) {
+		cost := MeasureOperation(operationContext(t, `{ accounts { id } a: accounts { id } b: accounts { id } }`).Operation, 10)
+		assert.Equal(t, 11, cost.Complexity)
+		assert.True(t, cost.Truncated)
+	})
+}
+
+func TestQueryLimits(t *testing.T) {
+	deep := `{ accounts { parentOrg { parentOrg { parentOrg { id } } } } }`
+
+	t.Run("Allows operations within the limits", func(t *testing.T) {
+		assert.Nil(t, DefaultQueryLimits().MutateOperationContext(context.Background(), operationContext(t, deep)))
+	})
+
+	t.Run("Rejects operations too deep", func(t *testing.T) {
+		err := QueryLimits{MaxDepth: 4, MaxComplexity: 100}.MutateOperationContext(context.Background(), operationContext(t, deep))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "operation has depth 5, which exceeds the limit of 4", err.Message)
+			assert.Equal(t, "DEPTH_LIMIT_EXCEEDED", err.Extensions["code"])
+			assert.Equal(t, 5, err.Extensions["depth"])
+			assert.Equal(t, 4, err.Extensions["maxDepth"])
+			assert.Equal(t, 26, err.Extensions["complexity"])
+		}
+	})
+
+	t.Run("Rejects operations too complex", func(t *testing.T) {
+		err := QueryLimits{MaxComplexity: 20}.MutateOperationContext(context.Background(), operationContext(t, deep))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "operation has complexity 26, which exceeds the limit of 20", err.Message)
+			assert.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", err.Extensions["code"])
+			assert.Equal(t, 20, err.Extensions["maxComplexity"])
+			assert.Equal(t, map[string]int{"Query.accounts": 10, "Account.parentOrg": 15, "Account.id": 1}, err.Extensions["fieldCosts"])
+			assert.Equal(t, false, err.Extensions["truncated"])
+		}
+
+		err = QueryLimits{MaxComplexity: 2}.MutateOperationContext(context.Background(), operationContext(t, deep))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "operation has complexity at least 25, which exceeds the limit of 2", err.Message)
+			assert.Equal(t, true, err.Extensions["truncated"])
+		}
+	})
+
+	t.Run("A limit of 0 is not enforced", func(t *testing.T) {
+		assert.Nil(t, QueryLimits{}.MutateOperationContext(context.Background(), operationContext(t, deep)))
+	})
+}
@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
+)
+
+// StaleReads is a GraphQL handler extension that tells clients when a query
+// was answered from cached Permit data because Permit is unavailable. The
+// message of such a SuccessResponse or Connection is replaced with config.StaleDataMessage.
+type StaleReads struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.FieldInterceptor
+} = StaleReads{}
+
+func (StaleReads) ExtensionName() string {
+	return "StaleReads"
+}
+
+func (StaleReads) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptField tracks the Permit lookups of every root query field.
+func (StaleReads) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Object != "Query" {
+		return next(ctx)
+	}
+
+	ctx, stale := permit.TrackStaleReads(ctx)
+	res, err := next(ctx)
+	if stale() {
+		switch success := res.(type) {
+		case *models.SuccessResponse:
+			success.Message = config.StaleDataMessage
+		case *models.Connection:
+			success.Message = config.StaleDataMessage
+		}
+	}
+	return res, err
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permit/permittest"
+)
+
+func TestStaleReads(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: "t1"}, Name: "Tenant"})
+
+	client := fake.Client()
+	client.Breaker = permit.NewCircuitBreaker("permit-api", permit.BreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Minute})
+	svc := permit.NewCachedPermitService(permit.NewPermitServiceImpl(client), permit.CachePolicy{TTL: time.Millisecond, MaxEntries: 10})
+
+	_, err := svc.GetTenant(context.Background(), "t1")
+	assert.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	fake.FailNext(1, http.StatusServiceUnavailable)
+	_, err = svc.GetTenant(context.Background(), "t1")
+	assert.Error(t, err)
+
+	resolve := func(ctx context.Context) (interface{}, error) {
+		_, err := svc.GetTenant(ctx, "t1")
+		assert.NoError(t, err)
+		return &models.SuccessResponse{IsSuccess: true, Message: "Operation successful"}, nil
+	}
+	intercept := func(object string) string {
+		ctx := graphql.WithFieldContext(context.Background(), &graphql.FieldContext{Object: object})
+		res, err := StaleReads{}.InterceptField(ctx, resolve)
+		assert.NoError(t, err)
+		return res.(*models.SuccessResponse).Message
+	}
+
+	assert.Equal(t, config.StaleDataMessage, intercept("Query"))
+	assert.Equal(t, "Operation successful", intercept("Tenant"), "only root query fields are tracked")
+
+	ctx := graphql.WithFieldContext(context.Background(), &graphql.FieldContext{Object: "Query"})
+	res, err := StaleReads{}.InterceptField(ctx, func(ctx context.Context) (interface{}, error) {
+		_, err := svc.GetTenant(ctx, "t1")
+		assert.NoError(t, err)
+		return &models.Connection{IsSuccess: true, Message: "Operation successful"}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, config.StaleDataMessage, res.(*models.Connection).Message, "connections are marked too")
+}
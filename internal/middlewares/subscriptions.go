@@ -0,0 +1,155 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/pkg/auth/jwt"
+	"iam_services_main_v1/pkg/logger"
+)
+
+type webSocketKey struct{}
+
+// WebSocketUpgrade only lets WebSocket upgrade requests through, marking
+// their context so Subscriptions can tell them apart. It guards the GET
+// GraphQL route, which is not behind AuthMiddleware: WebSocketAuth
+// authenticates its connections.
+func WebSocketUpgrade() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !websocket.IsWebSocketUpgrade(c.Request) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Only WebSocket upgrade requests are accepted"})
+			return
+		}
+		ctx := context.WithValue(c.Request.Context(), webSocketKey{}, true)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func isWebSocket(ctx context.Context) bool {
+	ws, _ := ctx.Value(webSocketKey{}).(bool)
+	return ws
+}
+
+// WebSocketAuth authenticates the WebSocket connections subscriptions are
+// served over. Browsers cannot set headers on WebSocket upgrade requests, so
+// clients send their token in the connection_init payload, as an
+// Authorization entry such as "Bearer <token>", together with their tenant
+// as X-Tenant-ID. Clients that can set headers may send them on the upgrade
+// request instead.
+type WebSocketAuth struct {
+	// Authenticator returns the authenticator validating tokens, e.g.
+	// AuthenticatorFromEnv.
+	Authenticator func() (*jwt.Authenticator, error)
+	// AllowedOrigins are the origins, e.g. https://portal.example.com, of the
+	// pages allowed to open connections. Requests without an Origin header
+	// do not come from a browser and are always allowed.
+	AllowedOrigins []string
+}
+
+// CheckOrigin is the CheckOrigin of the WebSocket upgrader. It rejects
+// upgrades from pages of origins not allowed, so that another site cannot
+// open a connection with the credentials of the browser.
+func (a *WebSocketAuth) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range a.AllowedOrigins {
+		if strings.EqualFold(origin, allowed) {
+			return true
+		}
+	}
+	logger.LogInfo("WebSocket upgrade from an origin not allowed", "origin", origin)
+	return false
+}
+
+// Init is the InitFunc of the WebSocket transport. It validates the token of
+// the connection_init payload as AuthMiddleware validates the Authorization
+// header, and sets the user and tenant in the Gin context of the upgrade
+// request. The connection is closed when the token is missing or invalid.
+func (a *WebSocketAuth) Init(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	c, ok := ctx.Value(config.GinContextKey).(*gin.Context)
+	if !ok {
+		return nil, nil, errors.New("gin context not found in the WebSocket connection")
+	}
+	authorization := payload.Authorization()
+	if authorization == "" {
+		authorization = c.GetHeader("Authorization")
+	}
+	tenantID := payload.GetString("X-Tenant-ID")
+	if tenantID == "" {
+		tenantID = c.GetHeader("X-Tenant-ID")
+	}
+
+	authenticator, err := a.Authenticator()
+	if err != nil {
+		return nil, nil, err
+	}
+	claims, err := authenticator.Authenticate(authorization)
+	if err != nil {
+		logger.LogInfo("WebSocket connection not authenticated", "error", err)
+		return nil, nil, errors.New("Token verification failed: " + err.Error())
+	}
+	authenticator.SetIdentity(c, claims, tenantID)
+	return ctx, nil, nil
+}
+
+// Subscriptions is a GraphQL handler extension that keeps subscriptions and
+// the other operations on their own routes: subscriptions are only served
+// over WebSocket, and queries and mutations never are.
+// Every event of a subscription is resolved with fresh dataloaders, so
+// long-lived subscriptions do not report cached data.
+type Subscriptions struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+	graphql.ResponseInterceptor
+} = Subscriptions{}
+
+func (Subscriptions) ExtensionName() string {
+	return "Subscriptions"
+}
+
+func (Subscriptions) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation rejects operations sent over the wrong transport.
+func (Subscriptions) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	subscription := isSubscription(ctx)
+	switch {
+	case subscription && !isWebSocket(ctx):
+		return graphql.OneShot(graphql.ErrorResponse(ctx, "subscriptions are only served over WebSocket"))
+	case !subscription && isWebSocket(ctx):
+		return graphql.OneShot(graphql.ErrorResponse(ctx, "only subscriptions are served over WebSocket"))
+	}
+	return next(ctx)
+}
+
+// InterceptResponse gives every event of a subscription its own dataloaders.
+func (Subscriptions) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !isSubscription(ctx) {
+		return next(ctx)
+	}
+	return next(dataloaders.WithLoaders(ctx))
+}
+
+func isSubscription(ctx context.Context) bool {
+	if !graphql.HasOperationContext(ctx) {
+		return false
+	}
+	op := graphql.GetOperationContext(ctx).Operation
+	return op != nil && op.Operation == ast.Subscription
+}
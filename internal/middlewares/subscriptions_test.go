@@ -0,0 +1,172 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permit/permittest"
+	"iam_services_main_v1/pkg/auth/jwt"
+	"iam_services_main_v1/pkg/auth/jwt/jwttest"
+)
+
+func TestWebSocketUpgrade(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var upgraded bool
+	router := gin.New()
+	router.GET("/graphql", WebSocketUpgrade(), func(c *gin.Context) {
+		upgraded = isWebSocket(c.Request.Context())
+		c.Status(http.StatusSwitchingProtocols)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?query={tenants{__typename}}", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, upgraded)
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusSwitchingProtocols, w.Code)
+	assert.True(t, upgraded)
+}
+
+func TestWebSocketAuth(t *testing.T) {
+	provider := jwttest.NewServer()
+	defer provider.Close()
+	auth := &WebSocketAuth{
+		Authenticator:  func() (*jwt.Authenticator, error) { return provider.Authenticator(), nil },
+		AllowedOrigins: []string{"https://portal.example.com"},
+	}
+	userID, tenantID := "6f1c1a4e-8f0e-4c57-9d0a-3f7c2b1d5e90", "0b9d7c5e-3a1f-4e2d-8c6b-9a8f7e6d5c4b"
+
+	upgrade := func(header http.Header) (context.Context, *gin.Context) {
+		c := &gin.Context{Request: httptest.NewRequest(http.MethodGet, "/graphql", nil)}
+		for key, values := range header {
+			c.Request.Header[key] = values
+		}
+		return context.WithValue(context.Background(), config.GinContextKey, c), c
+	}
+
+	t.Run("Origins", func(t *testing.T) {
+		for origin, allowed := range map[string]bool{
+			"":                           true,
+			"https://portal.example.com": true,
+			"https://PORTAL.example.com": true,
+			"https://evil.example.com":   false,
+			"http://portal.example.com":  false,
+		} {
+			req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			if origin != "" {
+				req.Header.Set("Origin", origin)
+			}
+			assert.Equal(t, allowed, auth.CheckOrigin(req), origin)
+		}
+	})
+
+	t.Run("Token of the connection_init payload", func(t *testing.T) {
+		ctx, _ := upgrade(nil)
+		ctx, _, err := auth.Init(ctx, transport.InitPayload{"Authorization": "Bearer " + provider.Token(userID), "X-Tenant-ID": tenantID})
+		assert.NoError(t, err)
+
+		user, tenant, err := helpers.GetUserAndTenantID(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, user.String())
+		assert.Equal(t, tenantID, tenant.String())
+	})
+
+	t.Run("Headers of the upgrade request", func(t *testing.T) {
+		ctx, _ := upgrade(http.Header{"Authorization": {"Bearer " + provider.Token(userID)}, "X-Tenant-Id": {tenantID}})
+		ctx, _, err := auth.Init(ctx, nil)
+		assert.NoError(t, err)
+
+		user, tenant, err := helpers.GetUserAndTenantID(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, user.String())
+		assert.Equal(t, tenantID, tenant.String())
+	})
+
+	t.Run("Missing or invalid token", func(t *testing.T) {
+		for _, payload := range []transport.InitPayload{nil, {"Authorization": "Bearer invalid"}, {"Authorization": provider.Token(userID)}} {
+			ctx, c := upgrade(nil)
+			_, _, err := auth.Init(ctx, payload)
+			assert.Error(t, err)
+			_, authenticated := c.Get("userID")
+			assert.False(t, authenticated)
+		}
+	})
+
+	t.Run("Authenticator not configured", func(t *testing.T) {
+		failing := &WebSocketAuth{Authenticator: func() (*jwt.Authenticator, error) { return nil, assert.AnError }}
+		ctx, _ := upgrade(nil)
+		_, _, err := failing.Init(ctx, transport.InitPayload{"Authorization": "Bearer " + provider.Token(userID)})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestSubscriptions(t *testing.T) {
+	operation := func(ctx context.Context, op ast.Operation) context.Context {
+		return graphql.WithOperationContext(ctx, &graphql.OperationContext{Operation: &ast.OperationDefinition{Operation: op}})
+	}
+	webSocket := context.WithValue(context.Background(), webSocketKey{}, true)
+	run := func(ctx context.Context) *graphql.Response {
+		var ran bool
+		res := Subscriptions{}.InterceptOperation(ctx, func(ctx context.Context) graphql.ResponseHandler {
+			ran = true
+			return graphql.OneShot(&graphql.Response{Data: []byte(`{}`)})
+		})(ctx)
+		if ran {
+			assert.Empty(t, res.Errors)
+		}
+		return res
+	}
+
+	t.Run("Operations run on their own transport", func(t *testing.T) {
+		assert.Empty(t, run(operation(context.Background(), ast.Query)).Errors)
+		assert.Empty(t, run(operation(context.Background(), ast.Mutation)).Errors)
+		assert.Empty(t, run(operation(webSocket, ast.Subscription)).Errors)
+	})
+
+	t.Run("Operations on the wrong transport are rejected", func(t *testing.T) {
+		res := run(operation(context.Background(), ast.Subscription))
+		if assert.Len(t, res.Errors, 1) {
+			assert.Equal(t, "subscriptions are only served over WebSocket", res.Errors[0].Message)
+		}
+		res = run(operation(webSocket, ast.Query))
+		if assert.Len(t, res.Errors, 1) {
+			assert.Equal(t, "only subscriptions are served over WebSocket", res.Errors[0].Message)
+		}
+	})
+
+	t.Run("Every event of a subscription gets fresh dataloaders", func(t *testing.T) {
+		fake := permittest.NewServer()
+		defer fake.Close()
+		pc := permit.NewPermitServiceImpl(fake.Client())
+		loaders := func(ctx context.Context) *dataloaders.Loaders {
+			var l *dataloaders.Loaders
+			Subscriptions{}.InterceptResponse(ctx, func(ctx context.Context) *graphql.Response {
+				l = dataloaders.For(ctx, pc)
+				return &graphql.Response{}
+			})
+			return l
+		}
+
+		query := operation(dataloaders.WithLoaders(context.Background()), ast.Query)
+		assert.Same(t, loaders(query), loaders(query))
+		subscription := operation(dataloaders.WithLoaders(webSocket), ast.Subscription)
+		assert.NotSame(t, loaders(subscription), loaders(subscription))
+	})
+}
@@ -0,0 +1,45 @@
+package organizations
+
+import (
+	"context"
+	"errors"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/events"
+	"iam_services_main_v1/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationSubscriptionResolver streams changes to the accounts and client
+// organization units of the caller's tenant
+type OrganizationSubscriptionResolver struct {
+	Events     *events.Bus
+	Authorizer authz.Authorizer
+}
+
+// OrganizationChanged returns the changes made to the organizations of the
+// caller's tenant from now on. Only the organization types the caller may list
+// are reported, and tenantID, when given, must be the caller's tenant.
+func (r *OrganizationSubscriptionResolver) OrganizationChanged(ctx context.Context, tenantID *uuid.UUID) (<-chan *models.OrganizationChangeEvent, error) {
+	callerTenantID, allowed, err := events.Authorize(ctx, r.Events, r.Authorizer,
+		authz.PermissionCheck{Action: "accounts", ResourceType: config.AccountResourceTypeID},
+		authz.PermissionCheck{Action: "clientOrganizationUnits", ResourceType: config.ClientOrgUnitResourceTypeID},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tenantID != nil && *tenantID != *callerTenantID {
+		return nil, errors.New("only changes in the caller's tenant can be subscribed to")
+	}
+
+	types := map[string]bool{
+		config.Account:                allowed[0],
+		config.ClientOrganizationUnit: allowed[1],
+	}
+	logger.LogInfo("Subscribed to organization changes", "tenantId", callerTenantID, "types", types)
+	return events.Stream(ctx, r.Events, events.TopicOrganization, callerTenantID.String(), func(event *models.OrganizationChangeEvent) bool {
+		return types[event.OrganizationType]
+	}), nil
+}
@@ -0,0 +1,59 @@
+package organizations
+
+import (
+	"context"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/events"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizationChanged(t *testing.T) {
+	userID, tenantID := uuid.New(), uuid.New()
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantID.String())
+	ginCtx.Set("userID", userID.String())
+	validCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+
+	t.Run("Streams the organization types the caller may list", func(t *testing.T) {
+		bus := events.NewBus(events.DefaultBuffer)
+		ctx, cancel := context.WithCancel(validCtx)
+		defer cancel()
+		policy := &authz.Policy{Rules: []authz.Rule{{User: userID.String(), ResourceType: config.AccountResourceTypeID, Actions: []string{"accounts"}}}}
+		resolver := OrganizationSubscriptionResolver{Events: bus, Authorizer: policy}
+
+		ch, err := resolver.OrganizationChanged(ctx, &tenantID)
+		assert.NoError(t, err)
+
+		unitID, accountID := uuid.New(), uuid.New()
+		bus.OrganizationChanged(models.ChangeTypeEnumDeleted, config.ClientOrganizationUnit, tenantID, unitID, nil)
+		bus.OrganizationChanged(models.ChangeTypeEnumDeleted, config.Account, uuid.New(), uuid.New(), nil)
+		bus.OrganizationChanged(models.ChangeTypeEnumDeleted, config.Account, tenantID, accountID, nil)
+		select {
+		case event := <-ch:
+			assert.Equal(t, accountID, event.ID)
+			assert.Equal(t, config.Account, event.OrganizationType)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the organization change")
+		}
+	})
+
+	t.Run("Other tenants", func(t *testing.T) {
+		resolver := OrganizationSubscriptionResolver{Events: events.NewBus(events.DefaultBuffer), Authorizer: authz.AllowAll()}
+		otherTenantID := uuid.New()
+		_, err := resolver.OrganizationChanged(validCtx, &otherTenantID)
+		assert.Error(t, err)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		resolver := OrganizationSubscriptionResolver{Events: events.NewBus(events.DefaultBuffer), Authorizer: authz.DenyAll()}
+		_, err := resolver.OrganizationChanged(validCtx, nil)
+		assert.ErrorIs(t, err, events.ErrForbidden)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/errorcatalog"
 	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	"iam_services_main_v1/internal/validations"
@@ -25,18 +26,18 @@ func (r *PermissionMutationResolver) CreatePermission(ctx context.Context, input
 	// Get user and tenant context
 	userID, tenantID, err := helpers.GetUserAndTenantID(ctx)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "failed to fetch user and tenant IDs", err.Error()), nil
+		return errorcatalog.Permission.FromError(http.StatusBadRequest, "failed to fetch user and tenant IDs", err), nil
 	}
 
 	// Validate input
 	if err := validateCreatePermissionInput(input); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "input validation failed", err.Error()), nil
+		return errorcatalog.Permission.FromError(http.StatusBadRequest, "input validation failed", err), nil
 
 	}
 
 	// Create role in permit system
 	if err := r.createPermissionInPermit(ctx, input, userID, tenantID); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "permit role creation failed", err.Error()), nil
+		return errorcatalog.Permission.FromError(http.StatusBadRequest, "permit role creation failed", err), nil
 
 	}
 
@@ -74,13 +75,11 @@ func (r *PermissionMutationResolver) createPermissionInPermit(ctx context.Contex
 	name := strings.ToLower(input.Name)
 	namekey := strings.ReplaceAll(name, " ", "_")
 
-	permitMap := map[string]interface{}{
-		"description": input.Description,
-		"key":         namekey,
-		"name":        input.Name,
-	}
-
-	_, err := r.PC.SendRequest(ctx, "POST", fmt.Sprintf("resources/%s/actions", input.AssignableScopeRef), permitMap)
+	_, err := r.PC.CreateResourceAction(ctx, input.AssignableScopeRef.String(), permit.ActionCreate{
+		Key:         namekey,
+		Name:        input.Name,
+		Description: input.Description,
+	})
 	return err
 }
 
@@ -88,57 +87,54 @@ func (r *PermissionMutationResolver) getPermissionDetailsById(ctx context.Contex
 	logger.LogInfo("Fetching all roles")
 
 	// Fetch roles from permit system
-	resourceURL := fmt.Sprintf("resources/%s", resourceID)
-	resourceResources, err := r.PC.SendRequest(ctx, "GET", resourceURL, nil)
+	resourceResources, err := r.PC.GetResource(ctx, resourceID.String())
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Error retrieving roles from permit system", err.Error()), nil
+		return errorcatalog.Permission.FromError(http.StatusBadRequest, "Error retrieving roles from permit system", err), nil
 	}
 
 	// Map resource data to resource struct
 	resources, err := MapToPermissionData(resourceResources)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to map tenant resources to struct", err.Error()), nil
+		return errorcatalog.Permission.FromError(http.StatusBadRequest, "Failed to map tenant resources to struct", err), nil
 
 	}
 
 	// Format and return success response
 	successResponse, err := utils.FormatSuccess([]models.Data{resources})
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to format success response", err.Error()), nil
+		return errorcatalog.Permission.FromError(http.StatusBadRequest, "Failed to format success response", err), nil
 	}
 	return successResponse, nil
 }
 
 // MapToPermissionData processes resources types for a role
-func MapToPermissionData(resourceData map[string]interface{}) (*models.ResourceType, error) {
-	id, err := helpers.GetUUID(resourceData, "key")
+func MapToPermissionData(resourceData *permit.Resource) (*models.ResourceType, error) {
+	if resourceData == nil {
+		return nil, errors.New("missing resource data")
+	}
+	id, err := uuid.Parse(resourceData.Key)
 	if err != nil {
 		return nil, fmt.Errorf("invalid resource type ID: %w", err)
 	}
-	actionsData, err := helpers.GetMap(resourceData, "actions")
-	if err != nil {
-		logger.LogError("failed to get actions map", "error", err)
-		return nil, fmt.Errorf("failed to extract actions: %w", err)
+	if resourceData.Actions == nil {
+		logger.LogError("failed to get actions map", "error", "missing actions")
+		return nil, fmt.Errorf("failed to extract actions: missing or invalid map for key: actions")
 	}
-	actions := MapToPermissionActions(id, actionsData)
+	actions := MapToPermissionActions(id, resourceData.Actions)
 	log.Printf("actions: %v", actions)
 	return &models.ResourceType{
 		ID:          id,
-		Name:        helpers.GetString(resourceData, "name"),
-		CreatedAt:   helpers.GetString(resourceData, "createdAt"),
-		UpdatedAt:   helpers.GetString(resourceData, "updatedAt"),
+		Name:        resourceData.Name,
+		CreatedAt:   resourceData.CreatedAt,
+		UpdatedAt:   resourceData.UpdatedAt,
 		Permissions: actions,
 	}, nil
 }
 
-func MapToPermissionActions(resourceID uuid.UUID, actionsData map[string]interface{}) []*models.Permission {
+func MapToPermissionActions(resourceID uuid.UUID, actionsData map[string]permit.Action) []*models.Permission {
 	var actions []*models.Permission
 	for key, action := range actionsData {
-		actionData, ok := action.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		id, err := helpers.GetUUID(actionData, "id")
+		id, err := uuid.Parse(action.ID)
 		if err != nil {
 			logger.LogError("failed to get action ID", "error", err)
 			continue
@@ -146,9 +142,9 @@ func MapToPermissionActions(resourceID uuid.UUID, actionsData map[string]interfa
 		actions = append(actions, &models.Permission{
 			ID:          id,
 			Name:        key,
-			Description: helpers.StringPtr(helpers.GetString(actionData, "description")),
-			CreatedAt:   helpers.GetString(actionData, "createdAt"),
-			UpdatedAt:   helpers.GetString(actionData, "updatedAt"),
+			Description: helpers.StringPtr(action.Description),
+			CreatedAt:   action.CreatedAt,
+			UpdatedAt:   action.UpdatedAt,
 		})
 	}
 	return actions
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
 	mocks "iam_services_main_v1/mocks"
 	"testing"
 
@@ -91,9 +92,8 @@ func TestCreatePermission(t *testing.T) {
 			ctx:   validCtx,
 			input: validInput,
 			mockSetup: func() {
-				expectedEndpoint := "resources/" + validInput.AssignableScopeRef.String() + "/actions"
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", expectedEndpoint, mock.Any()).
+					CreateResourceAction(mock.Any(), validInput.AssignableScopeRef.String(), mock.Any()).
 					Return(nil, errors.New("permit service error")).Times(1)
 			},
 			wantErr: true,
@@ -103,14 +103,12 @@ func TestCreatePermission(t *testing.T) {
 			ctx:   validCtx,
 			input: validInput,
 			mockSetup: func() {
-				expectedEndpoint := "resources/" + validInput.AssignableScopeRef.String() + "/actions"
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", expectedEndpoint, mock.Any()).
-					Return(map[string]interface{}{"key": validInput.AssignableScopeRef.String()}, nil).Times(1)
+					CreateResourceAction(mock.Any(), validInput.AssignableScopeRef.String(), mock.Any()).
+					Return(&permit.Action{Key: "test_permission"}, nil).Times(1)
 
-				getEndpoint := "resources/" + validInput.AssignableScopeRef.String()
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", getEndpoint, nil).
+					GetResource(mock.Any(), validInput.AssignableScopeRef.String()).
 					Return(nil, errors.New("get permission error")).Times(1)
 			},
 			wantErr: true,
@@ -120,14 +118,12 @@ func TestCreatePermission(t *testing.T) {
 			ctx:   validCtx,
 			input: validInput,
 			mockSetup: func() {
-				expectedEndpoint := "resources/" + validInput.AssignableScopeRef.String() + "/actions"
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", expectedEndpoint, mock.Any()).
-					Return(map[string]interface{}{"key": validInput.AssignableScopeRef.String()}, nil).Times(1)
+					CreateResourceAction(mock.Any(), validInput.AssignableScopeRef.String(), mock.Any()).
+					Return(&permit.Action{Key: "test_permission"}, nil).Times(1)
 
-				getEndpoint := "resources/" + validInput.AssignableScopeRef.String()
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", getEndpoint, nil).
+					GetResource(mock.Any(), validInput.AssignableScopeRef.String()).
 					Return(permissionData, nil).Times(1)
 			},
 			wantErr: false,
@@ -249,24 +245,22 @@ func TestCreatePermissionInPermit(t *testing.T) {
 		{
 			name: "Successful creation",
 			mockSetup: func() {
-				expectedEndpoint := "resources/" + input.AssignableScopeRef.String() + "/actions"
-				expectedPayload := map[string]interface{}{
-					"description": input.Description,
-					"key":         "test_permission",
-					"name":        input.Name,
+				expectedPayload := permit.ActionCreate{
+					Key:         "test_permission",
+					Name:        input.Name,
+					Description: input.Description,
 				}
 				mockService.EXPECT().
-					SendRequest(ctx, "POST", expectedEndpoint, expectedPayload).
-					Return(map[string]interface{}{"key": "test_permission"}, nil).Times(1)
+					CreateResourceAction(ctx, input.AssignableScopeRef.String(), expectedPayload).
+					Return(&permit.Action{Key: "test_permission"}, nil).Times(1)
 			},
 			wantErr: false,
 		},
 		{
 			name: "Permit service error",
 			mockSetup: func() {
-				expectedEndpoint := "resources/" + input.AssignableScopeRef.String() + "/actions"
 				mockService.EXPECT().
-					SendRequest(ctx, "POST", expectedEndpoint, mock.Any()).
+					CreateResourceAction(ctx, input.AssignableScopeRef.String(), mock.Any()).
 					Return(nil, errors.New("permit service error")).Times(1)
 			},
 			wantErr: true,
@@ -306,10 +300,9 @@ func TestGetPermissionDetailsById(t *testing.T) {
 		{
 			name: "Successful retrieval",
 			mockSetup: func() {
-				expectedEndpoint := "resources/" + resourceID.String()
 				permissionData := buildTestPermissionData()
 				mockService.EXPECT().
-					SendRequest(ctx, "GET", expectedEndpoint, nil).
+					GetResource(ctx, resourceID.String()).
 					Return(permissionData, nil).Times(1)
 			},
 			wantErr: false,
@@ -317,9 +310,8 @@ func TestGetPermissionDetailsById(t *testing.T) {
 		{
 			name: "Permit service error",
 			mockSetup: func() {
-				expectedEndpoint := "resources/" + resourceID.String()
 				mockService.EXPECT().
-					SendRequest(ctx, "GET", expectedEndpoint, nil).
+					GetResource(ctx, resourceID.String()).
 					Return(nil, errors.New("permit service error")).Times(1)
 			},
 			wantErr: true,
@@ -327,12 +319,11 @@ func TestGetPermissionDetailsById(t *testing.T) {
 		{
 			name: "Invalid permission data format",
 			mockSetup: func() {
-				expectedEndpoint := "resources/" + resourceID.String()
-				invalidData := map[string]interface{}{
-					"invalid": "data",
+				invalidData := &permit.Resource{
+					Key: "invalid-uuid",
 				}
 				mockService.EXPECT().
-					SendRequest(ctx, "GET", expectedEndpoint, nil).
+					GetResource(ctx, resourceID.String()).
 					Return(invalidData, nil).Times(1)
 			},
 			wantErr: true,
@@ -355,23 +346,23 @@ func TestMapToPermissionData(t *testing.T) {
 
 	testCases := []struct {
 		name     string
-		input    map[string]interface{}
+		input    *permit.Resource
 		wantErr  bool
 		expected *models.ResourceType
 	}{
 		{
 			name: "Valid permission data",
-			input: map[string]interface{}{
-				"key":       permissionID.String(),
-				"name":      "Test Resource Type",
-				"createdAt": "2025-07-14T10:00:00Z",
-				"updatedAt": "2025-07-14T10:00:00Z",
-				"actions": map[string]interface{}{
-					"read": map[string]interface{}{
-						"id":          actionID.String(),
-						"description": "Read permission",
-						"createdAt":   "2025-07-14T10:00:00Z",
-						"updatedAt":   "2025-07-14T10:00:00Z",
+			input: &permit.Resource{
+				Key:       permissionID.String(),
+				Name:      "Test Resource Type",
+				CreatedAt: "2025-07-14T10:00:00Z",
+				UpdatedAt: "2025-07-14T10:00:00Z",
+				Actions: map[string]permit.Action{
+					"read": {
+						ID:          actionID.String(),
+						Description: "Read permission",
+						CreatedAt:   "2025-07-14T10:00:00Z",
+						UpdatedAt:   "2025-07-14T10:00:00Z",
 					},
 				},
 			},
@@ -385,32 +376,26 @@ func TestMapToPermissionData(t *testing.T) {
 		},
 		{
 			name: "Invalid key format",
-			input: map[string]interface{}{
-				"key":  "invalid-uuid",
-				"name": "Test Resource Type",
-				"actions": map[string]interface{}{
-					"read": map[string]interface{}{
-						"id": actionID.String(),
-					},
+			input: &permit.Resource{
+				Key:  "invalid-uuid",
+				Name: "Test Resource Type",
+				Actions: map[string]permit.Action{
+					"read": {ID: actionID.String()},
 				},
 			},
 			wantErr: true,
 		},
 		{
 			name: "Missing actions",
-			input: map[string]interface{}{
-				"key":  permissionID.String(),
-				"name": "Test Resource Type",
+			input: &permit.Resource{
+				Key:  permissionID.String(),
+				Name: "Test Resource Type",
 			},
 			wantErr: true,
 		},
 		{
-			name: "Invalid actions format",
-			input: map[string]interface{}{
-				"key":     permissionID.String(),
-				"name":    "Test Resource Type",
-				"actions": "invalid-actions-format",
-			},
+			name:    "Nil resource",
+			input:   nil,
 			wantErr: true,
 		},
 	}
@@ -443,51 +428,49 @@ func TestMapToPermissionActions(t *testing.T) {
 	testCases := []struct {
 		name        string
 		resourceID  uuid.UUID
-		actionsData map[string]interface{}
+		actionsData map[string]permit.Action
 		expected    int // expected number of permissions
 	}{
 		{
 			name:       "Valid actions data",
 			resourceID: resourceID,
-			actionsData: map[string]interface{}{
-				"read": map[string]interface{}{
-					"id":          actionID1.String(),
-					"description": "Read permission",
-					"createdAt":   "2025-07-14T10:00:00Z",
-					"updatedAt":   "2025-07-14T10:00:00Z",
+			actionsData: map[string]permit.Action{
+				"read": {
+					ID:          actionID1.String(),
+					Description: "Read permission",
+					CreatedAt:   "2025-07-14T10:00:00Z",
+					UpdatedAt:   "2025-07-14T10:00:00Z",
 				},
-				"write": map[string]interface{}{
-					"id":          actionID2.String(),
-					"description": "Write permission",
-					"createdAt":   "2025-07-14T10:00:00Z",
-					"updatedAt":   "2025-07-14T10:00:00Z",
+				"write": {
+					ID:          actionID2.String(),
+					Description: "Write permission",
+					CreatedAt:   "2025-07-14T10:00:00Z",
+					UpdatedAt:   "2025-07-14T10:00:00Z",
 				},
 			},
 			expected: 2,
 		},
 		{
-			name:       "Invalid action data format",
+			name:       "Action with missing ID",
 			resourceID: resourceID,
-			actionsData: map[string]interface{}{
-				"read":  "invalid-format",
-				"write": map[string]interface{}{"id": actionID2.String()},
+			actionsData: map[string]permit.Action{
+				"read":  {},
+				"write": {ID: actionID2.String()},
 			},
 			expected: 1,
 		},
 		{
 			name:       "Invalid action ID",
 			resourceID: resourceID,
-			actionsData: map[string]interface{}{
-				"read": map[string]interface{}{
-					"id": "invalid-uuid",
-				},
+			actionsData: map[string]permit.Action{
+				"read": {ID: "invalid-uuid"},
 			},
 			expected: 0,
 		},
 		{
 			name:        "Empty actions data",
 			resourceID:  resourceID,
-			actionsData: map[string]interface{}{},
+			actionsData: map[string]permit.Action{},
 			expected:    0,
 		},
 	}
@@ -506,21 +489,21 @@ func TestMapToPermissionActions(t *testing.T) {
 }
 
 // Helper function to build test permission data
-func buildTestPermissionData() map[string]interface{} {
+func buildTestPermissionData() *permit.Resource {
 	permissionID := uuid.New()
 	actionID := uuid.New()
 
-	return map[string]interface{}{
-		"key":       permissionID.String(),
-		"name":      "Test Resource Type",
-		"createdAt": "2025-07-14T10:00:00Z",
-		"updatedAt": "2025-07-14T10:00:00Z",
-		"actions": map[string]interface{}{
-			"read": map[string]interface{}{
-				"id":          actionID.String(),
-				"description": "Read permission",
-				"createdAt":   "2025-07-14T10:00:00Z",
-				"updatedAt":   "2025-07-14T10:00:00Z",
+	return &permit.Resource{
+		Key:       permissionID.String(),
+		Name:      "Test Resource Type",
+		CreatedAt: "2025-07-14T10:00:00Z",
+		UpdatedAt: "2025-07-14T10:00:00Z",
+		Actions: map[string]permit.Action{
+			"read": {
+				ID:          actionID.String(),
+				Description: "Read permission",
+				CreatedAt:   "2025-07-14T10:00:00Z",
+				UpdatedAt:   "2025-07-14T10:00:00Z",
 			},
 		},
 	}
@@ -0,0 +1,268 @@
+package permit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ListTenants returns all tenants in the environment together with their total count.
+func (pc *PermitServiceImpl) ListTenants(ctx context.Context) ([]Tenant, int, error) {
+	return collectAll(ctx, newPager[Tenant](pc, factsAPI, "tenants", nil))
+}
+
+// ListTenantsPage returns one page of tenants together with the total count.
+func (pc *PermitServiceImpl) ListTenantsPage(ctx context.Context, page Page) ([]Tenant, int, error) {
+	return collectPage(ctx, newPager[Tenant](pc, factsAPI, "tenants", nil), page)
+}
+
+// SearchTenants returns the tenants whose name or key contains search, ignoring
+// case, together with their total count.
+func (pc *PermitServiceImpl) SearchTenants(ctx context.Context, search string) ([]Tenant, int, error) {
+	query := url.Values{}
+	query.Set("search", search)
+
+	return collectAll(ctx, newPager[Tenant](pc, factsAPI, "tenants", query))
+}
+
+// GetTenant returns the tenant with the given key.
+func (pc *PermitServiceImpl) GetTenant(ctx context.Context, key string) (*Tenant, error) {
+	var tenant Tenant
+	if err := pc.do(ctx, factsAPI, http.MethodGet, "tenants/"+url.PathEscape(key), nil, &tenant); err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// CreateTenant creates a tenant.
+func (pc *PermitServiceImpl) CreateTenant(ctx context.Context, input TenantCreate) (*Tenant, error) {
+	var tenant Tenant
+	if err := pc.do(ctx, factsAPI, http.MethodPost, "tenants", input, &tenant); err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// UpdateTenant patches the tenant with the given key.
+func (pc *PermitServiceImpl) UpdateTenant(ctx context.Context, key string, input TenantUpdate) (*Tenant, error) {
+	var tenant Tenant
+	if err := pc.do(ctx, factsAPI, http.MethodPatch, "tenants/"+url.PathEscape(key), input, &tenant); err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// DeleteTenant deletes the tenant with the given key.
+func (pc *PermitServiceImpl) DeleteTenant(ctx context.Context, key string) error {
+	return pc.do(ctx, factsAPI, http.MethodDelete, "tenants/"+url.PathEscape(key), nil, nil)
+}
+
+// GetUser returns the user with the given key.
+func (pc *PermitServiceImpl) GetUser(ctx context.Context, key string) (*User, error) {
+	var user User
+	if err := pc.do(ctx, factsAPI, http.MethodGet, "users/"+url.PathEscape(key), nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListResourceInstances returns the instances of a resource type within a tenant
+// together with their total count.
+func (pc *PermitServiceImpl) ListResourceInstances(ctx context.Context, tenant, resource string) ([]ResourceInstance, int, error) {
+	query := url.Values{}
+	query.Set("tenant", tenant)
+	query.Set("resource", resource)
+
+	return collectAll(ctx, newPager[ResourceInstance](pc, factsAPI, "resource_instances/detailed", query))
+}
+
+// ListResourceInstancesPage returns one page of the instances of a resource
+// type within a tenant together with the total count.
+func (pc *PermitServiceImpl) ListResourceInstancesPage(ctx context.Context, tenant, resource string, page Page) ([]ResourceInstance, int, error) {
+	query := url.Values{}
+	query.Set("tenant", tenant)
+	query.Set("resource", resource)
+
+	return collectPage(ctx, newPager[ResourceInstance](pc, factsAPI, "resource_instances/detailed", query), page)
+}
+
+// GetResourceInstance returns the resource instance with the given key.
+func (pc *PermitServiceImpl) GetResourceInstance(ctx context.Context, key string) (*ResourceInstance, error) {
+	var instance ResourceInstance
+	if err := pc.do(ctx, factsAPI, http.MethodGet, "resource_instances/"+url.PathEscape(key), nil, &instance); err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
+// CreateResourceInstance creates a resource instance.
+func (pc *PermitServiceImpl) CreateResourceInstance(ctx context.Context, input ResourceInstanceCreate) (*ResourceInstance, error) {
+	var instance ResourceInstance
+	if err := pc.do(ctx, factsAPI, http.MethodPost, "resource_instances", input, &instance); err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
+// UpdateResourceInstance patches the resource instance with the given key.
+func (pc *PermitServiceImpl) UpdateResourceInstance(ctx context.Context, key string, input ResourceInstanceUpdate) (*ResourceInstance, error) {
+	var instance ResourceInstance
+	if err := pc.do(ctx, factsAPI, http.MethodPatch, "resource_instances/"+url.PathEscape(key), input, &instance); err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
+// DeleteResourceInstance deletes the resource instance with the given key.
+func (pc *PermitServiceImpl) DeleteResourceInstance(ctx context.Context, key string) error {
+	return pc.do(ctx, factsAPI, http.MethodDelete, "resource_instances/"+url.PathEscape(key), nil, nil)
+}
+
+// ListResources returns all resource types together with their actions and roles,
+// and the total number of resource types.
+func (pc *PermitServiceImpl) ListResources(ctx context.Context) ([]Resource, int, error) {
+	return collectAll(ctx, newPager[Resource](pc, schemaAPI, "resources", nil))
+}
+
+// GetResource returns the resource type with the given key.
+func (pc *PermitServiceImpl) GetResource(ctx context.Context, key string) (*Resource, error) {
+	var resource Resource
+	if err := pc.do(ctx, schemaAPI, http.MethodGet, "resources/"+url.PathEscape(key), nil, &resource); err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// CreateResource creates a resource type.
+func (pc *PermitServiceImpl) CreateResource(ctx context.Context, input ResourceCreate) (*Resource, error) {
+	var resource Resource
+	if err := pc.do(ctx, schemaAPI, http.MethodPost, "resources", input, &resource); err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// UpdateResource patches the resource type with the given key.
+func (pc *PermitServiceImpl) UpdateResource(ctx context.Context, key string, input ResourceUpdate) (*Resource, error) {
+	var resource Resource
+	if err := pc.do(ctx, schemaAPI, http.MethodPatch, "resources/"+url.PathEscape(key), input, &resource); err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// CreateResourceAction adds an action to the resource type with the given key.
+func (pc *PermitServiceImpl) CreateResourceAction(ctx context.Context, resourceKey string, input ActionCreate) (*Action, error) {
+	var action Action
+	if err := pc.do(ctx, schemaAPI, http.MethodPost, fmt.Sprintf("resources/%s/actions", url.PathEscape(resourceKey)), input, &action); err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+// ListResourceRelations returns the relations of the resource type with the given key
+// together with their total count.
+func (pc *PermitServiceImpl) ListResourceRelations(ctx context.Context, resourceKey string) ([]Relation, int, error) {
+	return collectAll(ctx, newPager[Relation](pc, schemaAPI, fmt.Sprintf("resources/%s/relations", url.PathEscape(resourceKey)), nil))
+}
+
+// CreateResourceRelation adds a relation to the resource type with the given key.
+func (pc *PermitServiceImpl) CreateResourceRelation(ctx context.Context, resourceKey string, input RelationCreate) (*Relation, error) {
+	var relation Relation
+	if err := pc.do(ctx, schemaAPI, http.MethodPost, fmt.Sprintf("resources/%s/relations", url.PathEscape(resourceKey)), input, &relation); err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// CreateResourceRole adds a role to the resource type with the given key.
+func (pc *PermitServiceImpl) CreateResourceRole(ctx context.Context, resourceKey string, input RoleCreate) (*Role, error) {
+	var role Role
+	if err := pc.do(ctx, schemaAPI, http.MethodPost, fmt.Sprintf("resources/%s/roles", url.PathEscape(resourceKey)), input, &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// UpdateResourceRole patches a role of the resource type with the given key.
+func (pc *PermitServiceImpl) UpdateResourceRole(ctx context.Context, resourceKey, roleKey string, input RoleUpdate) (*Role, error) {
+	var role Role
+	endpoint := fmt.Sprintf("resources/%s/roles/%s", url.PathEscape(resourceKey), url.PathEscape(roleKey))
+	if err := pc.do(ctx, schemaAPI, http.MethodPatch, endpoint, input, &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// DeleteResourceRole removes a role from the resource type with the given key.
+func (pc *PermitServiceImpl) DeleteResourceRole(ctx context.Context, resourceKey, roleKey string) error {
+	endpoint := fmt.Sprintf("resources/%s/roles/%s", url.PathEscape(resourceKey), url.PathEscape(roleKey))
+	return pc.do(ctx, schemaAPI, http.MethodDelete, endpoint, nil, nil)
+}
+
+// ListRoleAssignments returns the role assignments matching the filter together with their total count.
+func (pc *PermitServiceImpl) ListRoleAssignments(ctx context.Context, filter RoleAssignmentFilter) ([]RoleAssignment, int, error) {
+	query := url.Values{}
+	if filter.User != "" {
+		query.Set("user", filter.User)
+	}
+	if filter.Tenant != "" {
+		query.Set("tenant", filter.Tenant)
+	}
+
+	return collectAll(ctx, newPager[RoleAssignment](pc, factsAPI, "role_assignments", query))
+}
+
+// ListRoleAssignmentsPage returns one page of the role assignments matching
+// filter together with the total count.
+func (pc *PermitServiceImpl) ListRoleAssignmentsPage(ctx context.Context, filter RoleAssignmentFilter, page Page) ([]RoleAssignment, int, error) {
+	query := url.Values{}
+	if filter.User != "" {
+		query.Set("user", filter.User)
+	}
+	if filter.Tenant != "" {
+		query.Set("tenant", filter.Tenant)
+	}
+
+	return collectPage(ctx, newPager[RoleAssignment](pc, factsAPI, "role_assignments", query), page)
+}
+
+// AssignRole grants a role to a user.
+func (pc *PermitServiceImpl) AssignRole(ctx context.Context, input RoleAssignmentCreate) (*RoleAssignment, error) {
+	var assignment RoleAssignment
+	if err := pc.do(ctx, factsAPI, http.MethodPost, "role_assignments", input, &assignment); err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// UnassignRole revokes a role from a user.
+func (pc *PermitServiceImpl) UnassignRole(ctx context.Context, input RoleAssignmentCreate) error {
+	return pc.do(ctx, factsAPI, http.MethodDelete, "role_assignments", input, nil)
+}
+
+// ListRelationshipTuples returns the relationship tuples matching the filter together with their total count.
+func (pc *PermitServiceImpl) ListRelationshipTuples(ctx context.Context, filter RelationshipTupleFilter) ([]RelationshipTuple, int, error) {
+	query := url.Values{}
+	if filter.Tenant != "" {
+		query.Set("tenant", filter.Tenant)
+	}
+	if filter.Subject != "" {
+		query.Set("subject", filter.Subject)
+	}
+	if filter.Object != "" {
+		query.Set("object", filter.Object)
+	}
+
+	return collectAll(ctx, newPager[RelationshipTuple](pc, factsAPI, "relationship_tuples/detailed", query))
+}
+
+// CreateRelationshipTuple creates a relationship tuple.
+func (pc *PermitServiceImpl) CreateRelationshipTuple(ctx context.Context, input RelationshipTupleCreate) (*RelationshipTuple, error) {
+	var tuple RelationshipTuple
+	if err := pc.do(ctx, factsAPI, http.MethodPost, "relationship_tuples", input, &tuple); err != nil {
+		return nil, err
+	}
+	return &tuple, nil
+}
@@ -0,0 +1,203 @@
+package permit
+
+import (
+	"context"
+	"errors"
+	"iam_services_main_v1/pkg/logger"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling Permit while its circuit breaker is open.
+var ErrCircuitOpen = errors.New("permit circuit breaker is open")
+
+// BreakerPolicy controls when a CircuitBreaker opens and how long it stays open.
+type BreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failed calls that opens the
+	// breaker. Values of zero or less disable the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before it lets one call
+	// through to probe whether Permit has recovered.
+	OpenTimeout time.Duration
+}
+
+// DefaultBreakerPolicy returns the breaker policy used when none is configured.
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every call through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails every call with ErrCircuitOpen.
+	BreakerOpen
+	// BreakerHalfOpen lets one probe call through and fails the others.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker stops calls to an unavailable Permit API after repeated
+// failures, so that requests fail fast instead of waiting for retries to run
+// out. A nil *CircuitBreaker lets every call through.
+type CircuitBreaker struct {
+	name   string
+	policy BreakerPolicy
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker returns a closed breaker with the given name, used in logs
+// and health reports.
+func NewCircuitBreaker(name string, policy BreakerPolicy) *CircuitBreaker {
+	return &CircuitBreaker{name: name, policy: policy}
+}
+
+// Name returns the name of the breaker.
+func (b *CircuitBreaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker. An open breaker whose
+// timeout has elapsed is reported as half-open.
+func (b *CircuitBreaker) State() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.policy.OpenTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a call may be made. Every allowed call must be
+// followed by a call to record.
+func (b *CircuitBreaker) allow() error {
+	if b == nil || b.policy.FailureThreshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.policy.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record counts the outcome of an allowed call.
+func (b *CircuitBreaker) record(err error) {
+	if b == nil || b.policy.FailureThreshold <= 0 {
+		return
+	}
+	outcome := breakerOutcome(err)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerHalfOpen:
+		b.probing = false
+		switch outcome {
+		case callSucceeded:
+			b.state = BreakerClosed
+			b.failures = 0
+			logger.LogInfo("Circuit breaker closed", "breaker", b.name)
+		case callFailed:
+			b.state = BreakerOpen
+			b.openedAt = time.Now()
+			logger.LogWarn("Circuit breaker reopened after a failed probe", "breaker", b.name, "error", err)
+		}
+	case BreakerClosed:
+		switch outcome {
+		case callSucceeded:
+			b.failures = 0
+		case callFailed:
+			b.failures++
+			if b.failures >= b.policy.FailureThreshold {
+				b.state = BreakerOpen
+				b.openedAt = time.Now()
+				logger.LogWarn("Circuit breaker opened", "breaker", b.name, "failures", b.failures, "error", err)
+			}
+		}
+	}
+}
+
+type callOutcome int
+
+const (
+	callSucceeded callOutcome = iota
+	callFailed
+	// callAbandoned is a call that says nothing about the health of Permit,
+	// e.g. because the caller gave up on it.
+	callAbandoned
+)
+
+// breakerOutcome classifies the error of a call. Permit answering with a client
+// error still shows it is available; throttling, server errors and transport
+// failures count against it.
+func breakerOutcome(err error) callOutcome {
+	if err == nil {
+		return callSucceeded
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrRateLimited) {
+		return callAbandoned
+	}
+	var apiErr *PermitAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode != http.StatusTooManyRequests && apiErr.StatusCode < 500 {
+		return callSucceeded
+	}
+	return callFailed
+}
+
+type staleReadsCtxKey struct{}
+
+// TrackStaleReads returns a context that records whether any Permit lookup made
+// with it was served from stale cached data, and a function that reports it.
+func TrackStaleReads(ctx context.Context) (context.Context, func() bool) {
+	stale := &atomic.Bool{}
+	return context.WithValue(ctx, staleReadsCtxKey{}, stale), stale.Load
+}
+
+// MarkStale records in ctx that a lookup was served from stale cached data.
+// Lookups shared by several callers, and made with a context of their own,
+// report it to each caller this way.
+func MarkStale(ctx context.Context) {
+	if stale, ok := ctx.Value(staleReadsCtxKey{}).(*atomic.Bool); ok {
+		stale.Store(true)
+	}
+}
@@ -0,0 +1,203 @@
+package permit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"iam_services_main_v1/internal/authz"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	upstreamDown := &PermitAPIError{StatusCode: http.StatusServiceUnavailable}
+
+	t.Run("Opens after consecutive failures and probes after the timeout", func(t *testing.T) {
+		breaker := NewCircuitBreaker("test", BreakerPolicy{FailureThreshold: 2, OpenTimeout: 20 * time.Millisecond})
+
+		assert.NoError(t, breaker.allow())
+		breaker.record(upstreamDown)
+		assert.NoError(t, breaker.allow())
+		breaker.record(nil)
+		assert.Equal(t, BreakerClosed, breaker.State(), "a success resets the failure count")
+
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, breaker.allow())
+			breaker.record(upstreamDown)
+		}
+		assert.Equal(t, BreakerOpen, breaker.State())
+		assert.ErrorIs(t, breaker.allow(), ErrCircuitOpen)
+
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, BreakerHalfOpen, breaker.State())
+		assert.NoError(t, breaker.allow())
+		assert.ErrorIs(t, breaker.allow(), ErrCircuitOpen, "only one probe at a time")
+
+		breaker.record(upstreamDown)
+		assert.Equal(t, BreakerOpen, breaker.State(), "a failed probe reopens the breaker")
+
+		time.Sleep(30 * time.Millisecond)
+		assert.NoError(t, breaker.allow())
+		breaker.record(nil)
+		assert.Equal(t, BreakerClosed, breaker.State())
+		assert.NoError(t, breaker.allow())
+	})
+
+	t.Run("Abandoned probe lets the next call probe", func(t *testing.T) {
+		breaker := NewCircuitBreaker("test", BreakerPolicy{FailureThreshold: 1})
+		assert.NoError(t, breaker.allow())
+		breaker.record(upstreamDown)
+
+		assert.NoError(t, breaker.allow())
+		breaker.record(context.Canceled)
+		assert.Equal(t, BreakerHalfOpen, breaker.State())
+		assert.NoError(t, breaker.allow())
+	})
+
+	t.Run("Nil and disabled breakers allow every call", func(t *testing.T) {
+		var nilBreaker *CircuitBreaker
+		assert.NoError(t, nilBreaker.allow())
+		nilBreaker.record(upstreamDown)
+		assert.Equal(t, BreakerClosed, nilBreaker.State())
+
+		disabled := NewCircuitBreaker("test", BreakerPolicy{})
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, disabled.allow())
+			disabled.record(upstreamDown)
+		}
+		assert.Equal(t, BreakerClosed, disabled.State())
+	})
+}
+
+func TestBreakerOutcome(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want callOutcome
+	}{
+		{name: "Success", err: nil, want: callSucceeded},
+		{name: "Client error", err: &PermitAPIError{StatusCode: http.StatusNotFound}, want: callSucceeded},
+		{name: "Throttled", err: &PermitAPIError{StatusCode: http.StatusTooManyRequests}, want: callFailed},
+		{name: "Server error", err: fmt.Errorf("wrapped: %w", &PermitAPIError{StatusCode: http.StatusBadGateway}), want: callFailed},
+		{name: "Transport error", err: errors.New("connection refused"), want: callFailed},
+		{name: "Deadline exceeded", err: context.DeadlineExceeded, want: callFailed},
+		{name: "Canceled", err: context.Canceled, want: callAbandoned},
+		{name: "Rate limited by the client", err: ErrRateLimited, want: callAbandoned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, breakerOutcome(tt.err))
+		})
+	}
+}
+
+func TestSendRawCircuitBreaker(t *testing.T) {
+	handler := &countingHandler{}
+	svc, server := newTestService(handler.ServeHTTP)
+	defer server.Close()
+	svc.PermitClient.Breaker = NewCircuitBreaker("test", BreakerPolicy{FailureThreshold: 2, OpenTimeout: time.Minute})
+	path := "/v2/facts/project/env/tenants/t1"
+
+	handler.fail(http.StatusServiceUnavailable)
+	for i := 0; i < 2; i++ {
+		_, err := svc.GetTenant(context.Background(), "t1")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 6, handler.count(http.MethodGet, path), "each failed call used every retry attempt")
+
+	start := time.Now()
+	_, err := svc.GetTenant(context.Background(), "t1")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	_, err = svc.UpdateTenant(context.Background(), "t1", TenantUpdate{})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, 6, handler.count(http.MethodGet, path))
+	assert.Equal(t, 0, handler.count(http.MethodPatch, path))
+}
+
+func TestCachedPermitServiceWhileBreakerOpen(t *testing.T) {
+	handler := &countingHandler{}
+	inner, server := newTestService(handler.ServeHTTP)
+	defer server.Close()
+	inner.PermitClient.Retry.MaxAttempts = 1
+	inner.PermitClient.Breaker = NewCircuitBreaker("test", BreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Minute})
+	svc := NewCachedPermitService(inner, CachePolicy{TTL: 10 * time.Millisecond, MaxEntries: 10})
+
+	_, err := svc.GetTenant(context.Background(), "t1")
+	assert.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	handler.fail(http.StatusServiceUnavailable)
+	_, err = svc.GetTenant(context.Background(), "t1")
+	assert.Error(t, err, "the failure that opens the breaker is reported")
+
+	t.Run("Expired entries are served and marked stale", func(t *testing.T) {
+		ctx, stale := TrackStaleReads(context.Background())
+		tenant, err := svc.GetTenant(ctx, "t1")
+		assert.NoError(t, err)
+		assert.Equal(t, "t1", tenant.Key)
+		assert.True(t, stale())
+	})
+
+	t.Run("Entries never fetched fail", func(t *testing.T) {
+		ctx, stale := TrackStaleReads(context.Background())
+		_, err := svc.GetTenant(ctx, "t2")
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.False(t, stale())
+	})
+
+	t.Run("Fresh reads and writes fail fast", func(t *testing.T) {
+		_, err := svc.GetTenant(WithFreshReads(context.Background()), "t1")
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		_, err = svc.UpdateTenant(context.Background(), "t1", TenantUpdate{})
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+	})
+
+	t.Run("Writes drop stale entries", func(t *testing.T) {
+		_, err := svc.GetTenant(context.Background(), "t1")
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+	})
+}
+
+func TestPermitSdkServiceWhileBreakerOpen(t *testing.T) {
+	ctx := context.Background()
+	svc, pdp := newTestSdkService(t, &DecisionCachePolicy{AllowTTL: time.Millisecond, DenyTTL: time.Millisecond, MaxEntries: 100})
+	svc.WithCircuitBreaker(NewCircuitBreaker("test", BreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Minute}))
+	pdp.set("alice", true)
+
+	allowed, err := svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	_, _ = svc.Check(ctx, "bob", "read", "account", "a1", "t1")
+	time.Sleep(5 * time.Millisecond)
+
+	pdp.fail(http.StatusBadGateway)
+	_, err = svc.Check(ctx, "alice", "update", "account", "a1", "t1")
+	assert.Error(t, err)
+	checks := pdp.checks.Load()
+
+	allowed, err = svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+	assert.NoError(t, err, "the last decision is reused")
+	assert.True(t, allowed)
+
+	allowed, err = svc.Check(ctx, "bob", "read", "account", "a1", "t1")
+	assert.ErrorIs(t, err, authz.ErrPermissionDenied)
+	assert.False(t, allowed)
+
+	_, err = svc.Check(ctx, "carol", "read", "account", "a1", "t1")
+	assert.ErrorIs(t, err, authz.ErrUnavailable)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	results, err := svc.BulkCheck(ctx, "alice", "t1", []authz.PermissionCheck{{Action: "read", ResourceType: "account", ResourceID: "a1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true}, results)
+
+	_, err = svc.ListAllowedActions(ctx, "alice", "account", "a1", "t1")
+	assert.ErrorIs(t, err, authz.ErrUnavailable)
+	assert.Equal(t, checks, pdp.checks.Load(), "no call reached the PDP")
+}
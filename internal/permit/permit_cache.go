@@ -0,0 +1,336 @@
+package permit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iam_services_main_v1/pkg/logger"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// CachePolicy controls the read-through cache in front of Permit entity lookups.
+type CachePolicy struct {
+	// TTL bounds how long tenants, users and resource instances are served from
+	// cache. Values of zero or less disable the cache.
+	TTL time.Duration
+	// SchemaTTL bounds how long resource types, actions and roles are served from cache.
+	SchemaTTL time.Duration
+	// MaxEntries caps the number of entries held for entities and for the schema.
+	MaxEntries int
+}
+
+// DefaultCachePolicy returns the cache policy used when none is configured.
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{
+		TTL:        30 * time.Second,
+		SchemaTTL:  5 * time.Minute,
+		MaxEntries: 10000,
+	}
+}
+
+// CachedPermitService serves GetTenant, GetUser, GetResourceInstance, GetResource
+// and ListResources from a TTL- and size-bounded cache, and passes every other
+// call through to the wrapped service.
+//
+// Writes made through the service invalidate the entries they affect, whether
+// or not Permit reported success, since a failed call may still have been
+// applied. Cached values are stored encoded, so callers may modify what they
+// get back.
+type CachedPermitService struct {
+	PermitService
+
+	entities *lruCache[[]byte]
+	schema   *lruCache[[]byte]
+}
+
+// NewCachedPermitService wraps inner with a read-through cache. It returns inner
+// unchanged when the policy disables caching.
+func NewCachedPermitService(inner PermitService, policy CachePolicy) PermitService {
+	if policy.TTL <= 0 {
+		return inner
+	}
+	if policy.SchemaTTL <= 0 {
+		policy.SchemaTTL = policy.TTL
+	}
+	if policy.MaxEntries < 1 {
+		policy.MaxEntries = DefaultCachePolicy().MaxEntries
+	}
+	return &CachedPermitService{
+		PermitService: inner,
+		entities:      newLRUCache[[]byte](policy.MaxEntries, policy.TTL),
+		schema:        newLRUCache[[]byte](policy.MaxEntries, policy.SchemaTTL),
+	}
+}
+
+type freshReadsCtxKey struct{}
+
+// WithFreshReads returns a context whose Permit lookups bypass the cache. The
+// fetched values still refresh it. Use it when reading an entity that is about
+// to be modified, so the write is not based on stale data.
+func WithFreshReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadsCtxKey{}, true)
+}
+
+// freshReads reports whether ctx asks for lookups to bypass the cache.
+func freshReads(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadsCtxKey{}).(bool)
+	return fresh
+}
+
+// lruCache holds values in an LRU and serves them for ttl after they were
+// stored. Older entries are kept until they are evicted or invalidated, so they
+// can still be served while Permit is unavailable. Its generation is bumped on
+// every invalidation so that a lookup racing with a write does not store the
+// value it read before the write.
+type lruCache[V any] struct {
+	lru        *lru.Cache[string, cacheEntry[V]]
+	ttl        time.Duration
+	generation atomic.Uint64
+}
+
+// cacheEntry is a cached value and the time it was stored.
+type cacheEntry[V any] struct {
+	value  V
+	stored time.Time
+}
+
+func newLRUCache[V any](size int, ttl time.Duration) *lruCache[V] {
+	// lru.New only fails for a size below one, which callers never pass.
+	cache, _ := lru.New[string, cacheEntry[V]](size)
+	return &lruCache[V]{lru: cache, ttl: ttl}
+}
+
+// get returns the value cached under key if it was stored less than ttl ago.
+func (c *lruCache[V]) get(key string) (V, bool) {
+	entry, ok := c.lru.Get(key)
+	if !ok || time.Since(entry.stored) >= c.ttl {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// getStale returns the value cached under key however old it is, and when it was stored.
+func (c *lruCache[V]) getStale(key string) (V, time.Time, bool) {
+	entry, ok := c.lru.Get(key)
+	return entry.value, entry.stored, ok
+}
+
+// addIfCurrent stores value under key unless the cache was invalidated since
+// generation was read.
+func (c *lruCache[V]) addIfCurrent(key string, value V, generation uint64) {
+	if c.generation.Load() == generation {
+		c.lru.Add(key, cacheEntry[V]{value: value, stored: time.Now()})
+	}
+}
+
+func (c *lruCache[V]) remove(key string) {
+	c.generation.Add(1)
+	c.lru.Remove(key)
+}
+
+// removeFunc drops every entry whose key satisfies match.
+func (c *lruCache[V]) removeFunc(match func(key string) bool) {
+	c.generation.Add(1)
+	for _, key := range c.lru.Keys() {
+		if match(key) {
+			c.lru.Remove(key)
+		}
+	}
+}
+
+func (c *lruCache[V]) purge() {
+	c.generation.Add(1)
+	c.lru.Purge()
+}
+
+// readThrough returns the value cached under key or loads and caches it with
+// fetch. Values are stored JSON-encoded so callers never share them. While the
+// circuit breaker in front of Permit is open, the last value fetched is served
+// however old it is and the read is marked stale in ctx.
+func readThrough[T any](ctx context.Context, c *lruCache[[]byte], key string, fetch func() (*T, error)) (*T, error) {
+	if !freshReads(ctx) {
+		if data, ok := c.get(key); ok {
+			var value T
+			if err := json.Unmarshal(data, &value); err == nil {
+				return &value, nil
+			}
+			c.lru.Remove(key)
+		}
+	}
+
+	generation := c.generation.Load()
+	value, err := fetch()
+	if err != nil {
+		if freshReads(ctx) || !errors.Is(err, ErrCircuitOpen) {
+			return nil, err
+		}
+		data, stored, ok := c.getStale(key)
+		if !ok {
+			return nil, err
+		}
+		var stale T
+		if json.Unmarshal(data, &stale) != nil {
+			return nil, err
+		}
+		logger.LogWarn("Serving stale Permit data", "key", key, "age", time.Since(stored).String())
+		MarkStale(ctx)
+		return &stale, nil
+	}
+	if data, err := json.Marshal(value); err == nil {
+		c.addIfCurrent(key, data, generation)
+	}
+	return value, nil
+}
+
+func tenantCacheKey(key string) string   { return "tenant/" + key }
+func userCacheKey(key string) string     { return "user/" + key }
+func instanceCacheKey(key string) string { return "resource_instance/" + key }
+func resourceCacheKey(key string) string { return "resource/" + key }
+
+const resourcesCacheKey = "resources"
+
+// resourceList is the cached form of a ListResources result.
+type resourceList struct {
+	Resources  []Resource `json:"resources"`
+	TotalCount int        `json:"total_count"`
+}
+
+// GetTenant returns the tenant with the given key, from cache when possible.
+func (s *CachedPermitService) GetTenant(ctx context.Context, key string) (*Tenant, error) {
+	return readThrough(ctx, s.entities, tenantCacheKey(key), func() (*Tenant, error) {
+		return s.PermitService.GetTenant(ctx, key)
+	})
+}
+
+// CreateTenant creates a tenant and drops any cached entry for its key.
+func (s *CachedPermitService) CreateTenant(ctx context.Context, input TenantCreate) (*Tenant, error) {
+	defer s.entities.remove(tenantCacheKey(input.Key))
+	return s.PermitService.CreateTenant(ctx, input)
+}
+
+// UpdateTenant patches a tenant and drops its cached entry.
+func (s *CachedPermitService) UpdateTenant(ctx context.Context, key string, input TenantUpdate) (*Tenant, error) {
+	defer s.entities.remove(tenantCacheKey(key))
+	return s.PermitService.UpdateTenant(ctx, key, input)
+}
+
+// DeleteTenant deletes a tenant. Permit cascades the deletion to the tenant's
+// resource instances and role assignments, so all cached entities are dropped.
+func (s *CachedPermitService) DeleteTenant(ctx context.Context, key string) error {
+	defer s.entities.purge()
+	return s.PermitService.DeleteTenant(ctx, key)
+}
+
+// GetUser returns the user with the given key, from cache when possible.
+func (s *CachedPermitService) GetUser(ctx context.Context, key string) (*User, error) {
+	return readThrough(ctx, s.entities, userCacheKey(key), func() (*User, error) {
+		return s.PermitService.GetUser(ctx, key)
+	})
+}
+
+// GetResourceInstance returns the resource instance with the given key, from cache when possible.
+func (s *CachedPermitService) GetResourceInstance(ctx context.Context, key string) (*ResourceInstance, error) {
+	return readThrough(ctx, s.entities, instanceCacheKey(key), func() (*ResourceInstance, error) {
+		return s.PermitService.GetResourceInstance(ctx, key)
+	})
+}
+
+// CreateResourceInstance creates a resource instance and drops any cached entry for its key.
+func (s *CachedPermitService) CreateResourceInstance(ctx context.Context, input ResourceInstanceCreate) (*ResourceInstance, error) {
+	defer s.entities.remove(instanceCacheKey(input.Key))
+	return s.PermitService.CreateResourceInstance(ctx, input)
+}
+
+// UpdateResourceInstance patches a resource instance and drops its cached entry.
+func (s *CachedPermitService) UpdateResourceInstance(ctx context.Context, key string, input ResourceInstanceUpdate) (*ResourceInstance, error) {
+	defer s.entities.remove(instanceCacheKey(key))
+	return s.PermitService.UpdateResourceInstance(ctx, key, input)
+}
+
+// DeleteResourceInstance deletes a resource instance and drops its cached entry.
+func (s *CachedPermitService) DeleteResourceInstance(ctx context.Context, key string) error {
+	defer s.entities.remove(instanceCacheKey(key))
+	return s.PermitService.DeleteResourceInstance(ctx, key)
+}
+
+// ListResources returns all resource types, from cache when possible.
+func (s *CachedPermitService) ListResources(ctx context.Context) ([]Resource, int, error) {
+	list, err := readThrough(ctx, s.schema, resourcesCacheKey, func() (*resourceList, error) {
+		resources, total, err := s.PermitService.ListResources(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &resourceList{Resources: resources, TotalCount: total}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return list.Resources, list.TotalCount, nil
+}
+
+// GetResource returns the resource type with the given key, from cache when possible.
+func (s *CachedPermitService) GetResource(ctx context.Context, key string) (*Resource, error) {
+	return readThrough(ctx, s.schema, resourceCacheKey(key), func() (*Resource, error) {
+		return s.PermitService.GetResource(ctx, key)
+	})
+}
+
+// CreateResource creates a resource type and drops the cached schema.
+func (s *CachedPermitService) CreateResource(ctx context.Context, input ResourceCreate) (*Resource, error) {
+	defer s.schema.purge()
+	return s.PermitService.CreateResource(ctx, input)
+}
+
+// UpdateResource patches a resource type and drops the cached schema.
+func (s *CachedPermitService) UpdateResource(ctx context.Context, key string, input ResourceUpdate) (*Resource, error) {
+	defer s.schema.purge()
+	return s.PermitService.UpdateResource(ctx, key, input)
+}
+
+// CreateResourceAction adds an action to a resource type and drops the cached schema.
+func (s *CachedPermitService) CreateResourceAction(ctx context.Context, resourceKey string, input ActionCreate) (*Action, error) {
+	defer s.schema.purge()
+	return s.PermitService.CreateResourceAction(ctx, resourceKey, input)
+}
+
+// CreateResourceRelation adds a relation to a resource type and drops the cached schema.
+func (s *CachedPermitService) CreateResourceRelation(ctx context.Context, resourceKey string, input RelationCreate) (*Relation, error) {
+	defer s.schema.purge()
+	return s.PermitService.CreateResourceRelation(ctx, resourceKey, input)
+}
+
+// CreateResourceRole adds a role to a resource type and drops the cached schema.
+func (s *CachedPermitService) CreateResourceRole(ctx context.Context, resourceKey string, input RoleCreate) (*Role, error) {
+	defer s.schema.purge()
+	return s.PermitService.CreateResourceRole(ctx, resourceKey, input)
+}
+
+// UpdateResourceRole patches a role and drops the cached schema.
+func (s *CachedPermitService) UpdateResourceRole(ctx context.Context, resourceKey, roleKey string, input RoleUpdate) (*Role, error) {
+	defer s.schema.purge()
+	return s.PermitService.UpdateResourceRole(ctx, resourceKey, roleKey, input)
+}
+
+// DeleteResourceRole removes a role. Permit also removes the role's assignments,
+// so cached users are dropped together with the schema.
+func (s *CachedPermitService) DeleteResourceRole(ctx context.Context, resourceKey, roleKey string) error {
+	defer s.entities.purge()
+	defer s.schema.purge()
+	return s.PermitService.DeleteResourceRole(ctx, resourceKey, roleKey)
+}
+
+// AssignRole grants a role and drops the user's cached entry.
+func (s *CachedPermitService) AssignRole(ctx context.Context, input RoleAssignmentCreate) (*RoleAssignment, error) {
+	defer s.entities.remove(userCacheKey(input.User))
+	return s.PermitService.AssignRole(ctx, input)
+}
+
+// UnassignRole revokes a role and drops the user's cached entry.
+func (s *CachedPermitService) UnassignRole(ctx context.Context, input RoleAssignmentCreate) error {
+	defer s.entities.remove(userCacheKey(input.User))
+	return s.PermitService.UnassignRole(ctx, input)
+}
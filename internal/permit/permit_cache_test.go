@@ -0,0 +1,181 @@
+package permit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingHandler answers Permit lookups with minimal documents and counts
+// the requests it receives per method and path.
+type countingHandler struct {
+	mu     sync.Mutex
+	counts map[string]int
+	status int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	if h.counts == nil {
+		h.counts = map[string]int{}
+	}
+	h.counts[r.Method+" "+r.URL.Path]++
+	status := h.status
+	h.mu.Unlock()
+
+	if status != 0 {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error_code":"NOT_FOUND","message":"not found"}`))
+		return
+	}
+	key := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	switch {
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/resources"):
+		_, _ = w.Write([]byte(`{"data":[{"key":"account","roles":{"viewer":{"key":"viewer"}}}],"total_count":1}`))
+	case r.Method == http.MethodGet:
+		_, _ = w.Write([]byte(`{"key":"` + key + `","attributes":{"name":"` + key + `"}}`))
+	default:
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func (h *countingHandler) count(method, path string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.counts[method+" "+path]
+}
+
+func (h *countingHandler) fail(status int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.status = status
+}
+
+func newCachedTestService(t *testing.T, policy CachePolicy) (PermitService, *countingHandler) {
+	t.Helper()
+	handler := &countingHandler{}
+	svc, server := newTestService(handler.ServeHTTP)
+	t.Cleanup(server.Close)
+	return NewCachedPermitService(svc, policy), handler
+}
+
+func TestCachedPermitService(t *testing.T) {
+	ctx := context.Background()
+	policy := CachePolicy{TTL: time.Minute, SchemaTTL: time.Minute, MaxEntries: 10}
+
+	t.Run("Repeated lookups are served from cache", func(t *testing.T) {
+		svc, handler := newCachedTestService(t, policy)
+
+		first, err := svc.GetTenant(ctx, "t1")
+		assert.NoError(t, err)
+		first.Attributes["name"] = "changed by caller"
+
+		second, err := svc.GetTenant(ctx, "t1")
+		assert.NoError(t, err)
+		assert.Equal(t, "t1", second.Attributes["name"])
+		assert.Equal(t, 1, handler.count(http.MethodGet, "/v2/facts/project/env/tenants/t1"))
+	})
+
+	t.Run("Writes invalidate the entity", func(t *testing.T) {
+		svc, handler := newCachedTestService(t, policy)
+		path := "/v2/facts/project/env/resource_instances/a1"
+
+		_, _ = svc.GetResourceInstance(ctx, "a1")
+		_, err := svc.UpdateResourceInstance(ctx, "a1", ResourceInstanceUpdate{Attributes: map[string]interface{}{}})
+		assert.NoError(t, err)
+		_, _ = svc.GetResourceInstance(ctx, "a1")
+		assert.Equal(t, 2, handler.count(http.MethodGet, path))
+
+		_ = svc.DeleteResourceInstance(ctx, "a1")
+		_, _ = svc.GetResourceInstance(ctx, "a1")
+		assert.Equal(t, 3, handler.count(http.MethodGet, path))
+	})
+
+	t.Run("Role assignments invalidate the user", func(t *testing.T) {
+		svc, handler := newCachedTestService(t, policy)
+		path := "/v2/facts/project/env/users/u1"
+
+		_, _ = svc.GetUser(ctx, "u1")
+		_, _ = svc.GetUser(ctx, "u1")
+		_, err := svc.AssignRole(ctx, RoleAssignmentCreate{User: "u1", Role: "viewer", Tenant: "t1"})
+		assert.NoError(t, err)
+		_, _ = svc.GetUser(ctx, "u1")
+		assert.Equal(t, 2, handler.count(http.MethodGet, path))
+	})
+
+	t.Run("Schema writes invalidate the schema", func(t *testing.T) {
+		svc, handler := newCachedTestService(t, policy)
+		path := "/v2/schema/project/env/resources"
+
+		resources, total, err := svc.ListResources(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Contains(t, resources[0].Roles, "viewer")
+		_, _, _ = svc.ListResources(ctx)
+		assert.Equal(t, 1, handler.count(http.MethodGet, path))
+
+		_, err = svc.UpdateResourceRole(ctx, "account", "viewer", RoleUpdate{Name: "Viewer"})
+		assert.NoError(t, err)
+		_, _, _ = svc.ListResources(ctx)
+		assert.Equal(t, 2, handler.count(http.MethodGet, path))
+	})
+
+	t.Run("Fresh reads bypass but refresh the cache", func(t *testing.T) {
+		svc, handler := newCachedTestService(t, policy)
+		path := "/v2/facts/project/env/tenants/t1"
+
+		_, _ = svc.GetTenant(ctx, "t1")
+		_, _ = svc.GetTenant(WithFreshReads(ctx), "t1")
+		_, _ = svc.GetTenant(ctx, "t1")
+		assert.Equal(t, 2, handler.count(http.MethodGet, path))
+	})
+
+	t.Run("Errors are not cached", func(t *testing.T) {
+		svc, handler := newCachedTestService(t, policy)
+		path := "/v2/facts/project/env/tenants/t1"
+
+		handler.fail(http.StatusNotFound)
+		_, err := svc.GetTenant(ctx, "t1")
+		assert.Error(t, err)
+
+		handler.fail(0)
+		tenant, err := svc.GetTenant(ctx, "t1")
+		assert.NoError(t, err)
+		assert.Equal(t, "t1", tenant.Key)
+		assert.Equal(t, 2, handler.count(http.MethodGet, path))
+	})
+
+	t.Run("Entries expire after the TTL", func(t *testing.T) {
+		svc, handler := newCachedTestService(t, CachePolicy{TTL: 20 * time.Millisecond, MaxEntries: 10})
+		path := "/v2/facts/project/env/tenants/t1"
+
+		_, _ = svc.GetTenant(ctx, "t1")
+		time.Sleep(50 * time.Millisecond)
+		_, _ = svc.GetTenant(ctx, "t1")
+		assert.Equal(t, 2, handler.count(http.MethodGet, path))
+	})
+
+	t.Run("Least recently used entries are evicted", func(t *testing.T) {
+		svc, handler := newCachedTestService(t, CachePolicy{TTL: time.Minute, MaxEntries: 2})
+
+		_, _ = svc.GetTenant(ctx, "t1")
+		_, _ = svc.GetTenant(ctx, "t2")
+		_, _ = svc.GetTenant(ctx, "t3")
+		_, _ = svc.GetTenant(ctx, "t1")
+		assert.Equal(t, 2, handler.count(http.MethodGet, "/v2/facts/project/env/tenants/t1"))
+		assert.Equal(t, 1, handler.count(http.MethodGet, "/v2/facts/project/env/tenants/t3"))
+	})
+
+	t.Run("Zero TTL disables the cache", func(t *testing.T) {
+		handler := &countingHandler{}
+		inner, server := newTestService(handler.ServeHTTP)
+		defer server.Close()
+
+		assert.Same(t, inner, NewCachedPermitService(inner, CachePolicy{}))
+	})
+}
@@ -0,0 +1,147 @@
+package permit
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DecisionCachePolicy controls the cache of PDP decisions kept by PermitSdkService.
+type DecisionCachePolicy struct {
+	// AllowTTL bounds how long an allow decision is reused. Values of zero or
+	// less disable caching of allow decisions.
+	AllowTTL time.Duration
+	// DenyTTL bounds how long a deny decision is reused. Values of zero or less
+	// disable caching of deny decisions.
+	DenyTTL time.Duration
+	// MaxEntries caps the number of allow and of deny decisions held in memory.
+	MaxEntries int
+}
+
+// DefaultDecisionCachePolicy returns the decision cache policy used when none is configured.
+func DefaultDecisionCachePolicy() DecisionCachePolicy {
+	return DecisionCachePolicy{
+		AllowTTL:   30 * time.Second,
+		DenyTTL:    5 * time.Second,
+		MaxEntries: 10000,
+	}
+}
+
+// DecisionCacheStats is a snapshot of the decision cache counters.
+type DecisionCacheStats struct {
+	// Hits is the number of checks answered from cache.
+	Hits uint64 `json:"hits"`
+	// Misses is the number of checks sent to the PDP.
+	Misses uint64 `json:"misses"`
+}
+
+// decisionCache holds allow and deny decisions in separate caches so each can
+// have its own TTL. Keys start with the user ID so a user's decisions can be
+// dropped together.
+type decisionCache struct {
+	allow  *lruCache[struct{}]
+	deny   *lruCache[struct{}]
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newDecisionCache(policy DecisionCachePolicy) *decisionCache {
+	if policy.MaxEntries < 1 {
+		policy.MaxEntries = DefaultDecisionCachePolicy().MaxEntries
+	}
+	c := &decisionCache{}
+	if policy.AllowTTL > 0 {
+		c.allow = newLRUCache[struct{}](policy.MaxEntries, policy.AllowTTL)
+	}
+	if policy.DenyTTL > 0 {
+		c.deny = newLRUCache[struct{}](policy.MaxEntries, policy.DenyTTL)
+	}
+	return c
+}
+
+// decisionKey identifies a check by user, action, resource type, resource ID and tenant.
+func decisionKey(userID, action, resourceType, resourceID, tenant string) string {
+	return strings.Join([]string{userID, action, resourceType, resourceID, tenant}, "\x00")
+}
+
+// decisionGeneration holds the generations of both caches, read before asking
+// the PDP so that a decision made before an invalidation is not stored after it.
+type decisionGeneration struct {
+	allow, deny uint64
+}
+
+// lookup returns the cached decision for key, counting a hit or a miss.
+func (c *decisionCache) lookup(key string) (allowed, found bool, gen decisionGeneration) {
+	if c.allow != nil {
+		if _, ok := c.allow.get(key); ok {
+			c.hits.Add(1)
+			return true, true, gen
+		}
+		gen.allow = c.allow.generation.Load()
+	}
+	if c.deny != nil {
+		if _, ok := c.deny.get(key); ok {
+			c.hits.Add(1)
+			return false, true, gen
+		}
+		gen.deny = c.deny.generation.Load()
+	}
+	c.misses.Add(1)
+	return false, false, gen
+}
+
+// lookupStale returns the most recent decision cached for key however old it
+// is. It is used when the PDP is unavailable.
+func (c *decisionCache) lookupStale(key string) (allowed, found bool) {
+	var allowedAt, deniedAt time.Time
+	if c.allow != nil {
+		_, allowedAt, allowed = c.allow.getStale(key)
+	}
+	if c.deny != nil {
+		var denied bool
+		_, deniedAt, denied = c.deny.getStale(key)
+		if denied && (!allowed || deniedAt.After(allowedAt)) {
+			return false, true
+		}
+	}
+	return allowed, allowed
+}
+
+// store records a decision made by the PDP.
+func (c *decisionCache) store(key string, allowed bool, gen decisionGeneration) {
+	switch {
+	case allowed && c.allow != nil:
+		c.allow.addIfCurrent(key, struct{}{}, gen.allow)
+	case !allowed && c.deny != nil:
+		c.deny.addIfCurrent(key, struct{}{}, gen.deny)
+	}
+}
+
+// invalidateUser drops every decision made for userID.
+func (c *decisionCache) invalidateUser(userID string) {
+	prefix := userID + "\x00"
+	match := func(key string) bool { return strings.HasPrefix(key, prefix) }
+	if c.allow != nil {
+		c.allow.removeFunc(match)
+	}
+	if c.deny != nil {
+		c.deny.removeFunc(match)
+	}
+}
+
+// invalidateAll drops every cached decision.
+func (c *decisionCache) invalidateAll() {
+	if c.allow != nil {
+		c.allow.purge()
+	}
+	if c.deny != nil {
+		c.deny.purge()
+	}
+}
+
+func (c *decisionCache) stats() DecisionCacheStats {
+	return DecisionCacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
@@ -0,0 +1,73 @@
+package permit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// PermitAPIError is returned when Permit answers a request with a non-2xx status.
+type PermitAPIError struct {
+	// StatusCode is the HTTP status returned by Permit.
+	StatusCode int
+	// Code is Permit's machine-readable error code, e.g. NOT_FOUND.
+	Code string
+	// Message is Permit's human-readable error message.
+	Message string
+	// RequestID identifies the failed request in Permit's logs, when provided.
+	RequestID string
+}
+
+func (e *PermitAPIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "permit API error: status %d", e.StatusCode)
+	if e.Code != "" {
+		fmt.Fprintf(&b, ", code %s", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request id %s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// permitErrorBody is the JSON error document Permit returns for failed requests.
+type permitErrorBody struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	ErrorCode string `json:"error_code"`
+	Message   string `json:"message"`
+	Detail    string `json:"detail"`
+}
+
+// newPermitAPIError builds a PermitAPIError from a failed response. Bodies that are
+// not Permit error documents are kept verbatim as the message.
+func newPermitAPIError(status int, header http.Header, body []byte) *PermitAPIError {
+	apiErr := &PermitAPIError{StatusCode: status}
+	if header != nil {
+		apiErr.RequestID = header.Get("X-Request-Id")
+	}
+
+	var doc permitErrorBody
+	if err := json.Unmarshal(body, &doc); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+		return apiErr
+	}
+
+	apiErr.Code = doc.ErrorCode
+	switch {
+	case doc.Message != "":
+		apiErr.Message = doc.Message
+	case doc.Detail != "":
+		apiErr.Message = doc.Detail
+	default:
+		apiErr.Message = doc.Title
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = doc.ID
+	}
+	return apiErr
+}
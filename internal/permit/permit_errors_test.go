@@ -0,0 +1,86 @@
+package permit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPermitAPIError(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status int
+		header http.Header
+		body   string
+		want   PermitAPIError
+	}{
+		{
+			name:   "Permit error document",
+			status: http.StatusNotFound,
+			header: http.Header{"X-Request-Id": []string{"req-1"}},
+			body:   `{"id":"err-1","title":"Not found","error_code":"NOT_FOUND","message":"The requested tenant was not found"}`,
+			want: PermitAPIError{
+				StatusCode: http.StatusNotFound,
+				Code:       "NOT_FOUND",
+				Message:    "The requested tenant was not found",
+				RequestID:  "req-1",
+			},
+		},
+		{
+			name:   "Falls back to error id and title",
+			status: http.StatusConflict,
+			body:   `{"id":"err-2","title":"Conflict","error_code":"DUPLICATE_ENTITY"}`,
+			want: PermitAPIError{
+				StatusCode: http.StatusConflict,
+				Code:       "DUPLICATE_ENTITY",
+				Message:    "Conflict",
+				RequestID:  "err-2",
+			},
+		},
+		{
+			name:   "Non JSON body",
+			status: http.StatusBadGateway,
+			body:   "upstream connect error\n",
+			want: PermitAPIError{
+				StatusCode: http.StatusBadGateway,
+				Message:    "upstream connect error",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newPermitAPIError(tc.status, tc.header, []byte(tc.body))
+			assert.Equal(t, tc.want, *got)
+		})
+	}
+}
+
+func TestPermitAPIErrorMessage(t *testing.T) {
+	err := &PermitAPIError{StatusCode: 404, Code: "NOT_FOUND", Message: "missing", RequestID: "req-1"}
+	assert.Equal(t, "permit API error: status 404, code NOT_FOUND: missing (request id req-1)", err.Error())
+
+	err = &PermitAPIError{StatusCode: 500}
+	assert.Equal(t, "permit API error: status 500", err.Error())
+}
+
+func TestTypedErrorFromService(t *testing.T) {
+	svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-42")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"error_code":"VALIDATION_ERROR","message":"key is invalid"}`))
+	})
+	defer server.Close()
+
+	_, err := svc.CreateTenant(context.Background(), TenantCreate{Key: "bad key"})
+
+	var apiErr *PermitAPIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.Equal(t, "VALIDATION_ERROR", apiErr.Code)
+	assert.Equal(t, "key is invalid", apiErr.Message)
+	assert.Equal(t, "req-42", apiErr.RequestID)
+}
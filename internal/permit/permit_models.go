@@ -0,0 +1,226 @@
+package permit
+
+// Entity holds the fields shared by every keyed object returned by the Permit API.
+type Entity struct {
+	ID         string                 `json:"id,omitempty"`
+	Key        string                 `json:"key"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt  string                 `json:"created_at,omitempty"`
+	UpdatedAt  string                 `json:"updated_at,omitempty"`
+}
+
+// Tenant represents a Permit tenant.
+type Tenant struct {
+	Entity
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// TenantCreate is the payload for creating a tenant.
+type TenantCreate struct {
+	Key         string                 `json:"key"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// TenantUpdate is the payload for updating a tenant.
+type TenantUpdate struct {
+	Name        *string                `json:"name,omitempty"`
+	Description *string                `json:"description,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// UserTenant describes a tenant a user is associated with.
+type UserTenant struct {
+	Tenant string   `json:"tenant"`
+	Roles  []string `json:"roles,omitempty"`
+	Status string   `json:"status,omitempty"`
+}
+
+// User represents a Permit user.
+type User struct {
+	Entity
+	Email             string       `json:"email"`
+	FirstName         string       `json:"first_name"`
+	LastName          string       `json:"last_name"`
+	AssociatedTenants []UserTenant `json:"associated_tenants,omitempty"`
+}
+
+// ResourceInstance represents an instance of a Permit resource.
+type ResourceInstance struct {
+	Entity
+	Tenant     string `json:"tenant"`
+	Resource   string `json:"resource"`
+	ResourceID string `json:"resource_id,omitempty"`
+	TenantID   string `json:"tenant_id,omitempty"`
+}
+
+// ResourceInstanceCreate is the payload for creating a resource instance.
+type ResourceInstanceCreate struct {
+	Key        string                 `json:"key"`
+	Tenant     string                 `json:"tenant"`
+	Resource   string                 `json:"resource"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// ResourceInstanceUpdate is the payload for updating a resource instance.
+type ResourceInstanceUpdate struct {
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// Action represents an action defined on a Permit resource.
+type Action struct {
+	ID          string                 `json:"id,omitempty"`
+	Key         string                 `json:"key,omitempty"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt   string                 `json:"created_at,omitempty"`
+	UpdatedAt   string                 `json:"updated_at,omitempty"`
+}
+
+// ActionCreate is the payload for creating a resource action.
+type ActionCreate struct {
+	Key         string                 `json:"key"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Role represents a role defined on a Permit resource.
+type Role struct {
+	ID          string                 `json:"id,omitempty"`
+	Key         string                 `json:"key"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Permissions []string               `json:"permissions,omitempty"`
+	Extends     []string               `json:"extends,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt   string                 `json:"created_at,omitempty"`
+	UpdatedAt   string                 `json:"updated_at,omitempty"`
+}
+
+// RoleCreate is the payload for creating a resource role.
+type RoleCreate struct {
+	Key         string                 `json:"key"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Permissions []string               `json:"permissions,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// RoleUpdate is the payload for updating a resource role.
+type RoleUpdate struct {
+	Name        string                 `json:"name,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Permissions []string               `json:"permissions,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Resource represents a Permit resource type together with its actions and roles.
+type Resource struct {
+	ID          string            `json:"id,omitempty"`
+	Key         string            `json:"key"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Actions     map[string]Action `json:"actions,omitempty"`
+	Roles       map[string]Role   `json:"roles,omitempty"`
+	CreatedAt   string            `json:"created_at,omitempty"`
+	UpdatedAt   string            `json:"updated_at,omitempty"`
+}
+
+// ResourceCreate is the payload for creating a resource type.
+type ResourceCreate struct {
+	Key         string            `json:"key"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Actions     map[string]Action `json:"actions"`
+}
+
+// ResourceUpdate is the payload for updating a resource type.
+type ResourceUpdate struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Relation is a relation defined on a Permit resource type, the object, to
+// instances of another resource type, the subject. Relationship tuples
+// instantiate it.
+type Relation struct {
+	ID              string `json:"id,omitempty"`
+	Key             string `json:"key"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	SubjectResource string `json:"subject_resource"`
+	ObjectResource  string `json:"object_resource,omitempty"`
+	CreatedAt       string `json:"created_at,omitempty"`
+	UpdatedAt       string `json:"updated_at,omitempty"`
+}
+
+// RelationCreate is the payload for creating a resource relation.
+type RelationCreate struct {
+	Key             string `json:"key"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	SubjectResource string `json:"subject_resource"`
+}
+
+// RoleAssignment represents a role granted to a user, optionally scoped to a resource instance.
+type RoleAssignment struct {
+	ID               string `json:"id,omitempty"`
+	User             string `json:"user"`
+	Role             string `json:"role"`
+	Tenant           string `json:"tenant"`
+	ResourceInstance string `json:"resource_instance,omitempty"`
+	UserID           string `json:"user_id,omitempty"`
+	RoleID           string `json:"role_id,omitempty"`
+	TenantID         string `json:"tenant_id,omitempty"`
+	CreatedAt        string `json:"created_at,omitempty"`
+}
+
+// RoleAssignmentCreate is the payload for assigning or unassigning a role.
+type RoleAssignmentCreate struct {
+	User             string `json:"user"`
+	Role             string `json:"role"`
+	Tenant           string `json:"tenant"`
+	ResourceInstance string `json:"resource_instance,omitempty"`
+}
+
+// RoleAssignmentFilter narrows the role assignments returned by ListRoleAssignments.
+type RoleAssignmentFilter struct {
+	User   string
+	Tenant string
+}
+
+// RelationshipTuple links a subject resource instance to an object resource instance.
+type RelationshipTuple struct {
+	ID        string `json:"id,omitempty"`
+	Subject   string `json:"subject"`
+	Relation  string `json:"relation"`
+	Object    string `json:"object"`
+	Tenant    string `json:"tenant"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// RelationshipTupleCreate is the payload for creating a relationship tuple.
+type RelationshipTupleCreate struct {
+	Subject  string `json:"subject"`
+	Relation string `json:"relation"`
+	Object   string `json:"object"`
+	Tenant   string `json:"tenant"`
+}
+
+// RelationshipTupleFilter narrows the tuples returned by ListRelationshipTuples.
+type RelationshipTupleFilter struct {
+	Tenant  string
+	Subject string
+	Object  string
+}
+
+// listResponse is the envelope Permit uses for list endpoints that support include_total_count.
+type listResponse[T any] struct {
+	Data       []T `json:"data"`
+	TotalCount int `json:"total_count"`
+	PageCount  int `json:"page_count"`
+}
@@ -0,0 +1,197 @@
+package permit
+
+import (
+	"context"
+	"iam_services_main_v1/pkg/logger"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// maxPerPage is the largest page size Permit accepts.
+const maxPerPage = 100
+
+// PagingPolicy controls how Permit list endpoints are paged.
+type PagingPolicy struct {
+	// PerPage is the page size requested from Permit, which caps it at 100.
+	PerPage int
+	// MaxItems is a hard cap on the number of items a single list call collects.
+	// Values below 1 disable the cap.
+	MaxItems int
+}
+
+// DefaultPagingPolicy returns the paging policy used when none is configured.
+func DefaultPagingPolicy() PagingPolicy {
+	return PagingPolicy{
+		PerPage:  100,
+		MaxItems: 10000,
+	}
+}
+
+// pagingPolicy returns the configured paging policy or the default one.
+func (c *PermitClient) pagingPolicy() PagingPolicy {
+	if c.Paging == nil {
+		return DefaultPagingPolicy()
+	}
+	return *c.Paging
+}
+
+// Page selects a window of a list: Limit items starting at the zero-based Offset.
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+// pager iterates over the pages of a Permit list endpoint using page, per_page
+// and include_total_count.
+type pager[T any] struct {
+	pc       *PermitServiceImpl
+	target   api
+	endpoint string
+	query    url.Values
+	policy   PagingPolicy
+
+	page      int
+	seen      int
+	total     int
+	pageCount int
+	items     []T
+	done      bool
+	truncated bool
+	err       error
+}
+
+// newPager returns a pager for endpoint on the given API. query holds the
+// endpoint's own filters; the paging parameters are added by the pager.
+func newPager[T any](pc *PermitServiceImpl, target api, endpoint string, query url.Values) *pager[T] {
+	policy := pc.PermitClient.pagingPolicy()
+	if policy.PerPage < 1 {
+		policy.PerPage = DefaultPagingPolicy().PerPage
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	return &pager[T]{
+		pc:       pc,
+		target:   target,
+		endpoint: endpoint,
+		query:    query,
+		policy:   policy,
+	}
+}
+
+// Next fetches the next page. It returns false once all pages have been read,
+// the hard cap has been reached, or a request failed; check Err afterwards.
+func (p *pager[T]) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+
+	p.page++
+	query := url.Values{}
+	for key, values := range p.query {
+		query[key] = values
+	}
+	query.Set("page", strconv.Itoa(p.page))
+	query.Set("per_page", strconv.Itoa(p.policy.PerPage))
+	query.Set("include_total_count", "true")
+
+	var resp listResponse[T]
+	if err := p.pc.do(ctx, p.target, http.MethodGet, p.endpoint+"?"+query.Encode(), nil, &resp); err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+
+	p.total = resp.TotalCount
+	p.pageCount = resp.PageCount
+	p.items = resp.Data
+	if p.policy.MaxItems > 0 && p.seen+len(p.items) >= p.policy.MaxItems {
+		if p.seen+len(p.items) > p.policy.MaxItems || p.hasMore() {
+			p.truncated = true
+			logger.LogError("Permit list truncated at hard cap", "endpoint", p.endpoint, "maxItems", p.policy.MaxItems, "totalCount", p.total)
+		}
+		p.items = p.items[:p.policy.MaxItems-p.seen]
+		p.done = true
+	}
+	p.seen += len(p.items)
+	if !p.hasMore() {
+		p.done = true
+	}
+	return len(p.items) > 0
+}
+
+// hasMore reports whether Permit has pages beyond the current one.
+func (p *pager[T]) hasMore() bool {
+	if len(p.items) == 0 {
+		return false
+	}
+	if p.pageCount > 0 {
+		return p.page < p.pageCount
+	}
+	return len(p.items) == p.policy.PerPage
+}
+
+// Items returns the items of the current page.
+func (p *pager[T]) Items() []T { return p.items }
+
+// TotalCount returns the total number of items Permit reported for the list.
+func (p *pager[T]) TotalCount() int { return p.total }
+
+// Truncated reports whether iteration stopped at the hard cap before the end of the list.
+func (p *pager[T]) Truncated() bool { return p.truncated }
+
+// Err returns the error that stopped iteration, if any.
+func (p *pager[T]) Err() error { return p.err }
+
+// collectPage reads the pages of p that overlap page and returns its items
+// together with the total count. When the window lines up with a page of its
+// own size, a single request is made.
+func collectPage[T any](ctx context.Context, p *pager[T], page Page) ([]T, int, error) {
+	// A page of no items still reads one item, for the total count.
+	limit := max(page.Limit, 1)
+	if limit <= maxPerPage && page.Offset%limit == 0 {
+		p.policy.PerPage = limit
+	} else if p.policy.PerPage > maxPerPage {
+		p.policy.PerPage = maxPerPage
+	}
+	p.policy.MaxItems = 0
+	p.page = page.Offset / p.policy.PerPage
+	skip := page.Offset % p.policy.PerPage
+
+	var items []T
+	for len(items) < skip+limit && p.Next(ctx) {
+		items = append(items, p.Items()...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, 0, err
+	}
+	total := p.TotalCount()
+	if len(items) > 0 {
+		total = max(total, page.Offset-skip+len(items))
+	}
+	if skip >= len(items) {
+		return []T{}, total, nil
+	}
+	items = items[skip:]
+	if len(items) > page.Limit {
+		items = items[:page.Limit]
+	}
+	return items, total, nil
+}
+
+// collectAll reads every page of p and returns the items together with the total count.
+func collectAll[T any](ctx context.Context, p *pager[T]) ([]T, int, error) {
+	var all []T
+	for p.Next(ctx) {
+		all = append(all, p.Items()...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, 0, err
+	}
+	total := p.TotalCount()
+	if total < len(all) {
+		total = len(all)
+	}
+	return all, total, nil
+}
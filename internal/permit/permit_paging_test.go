@@ -0,0 +1,178 @@
+package permit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pagedTenantsHandler serves total tenants in pages, honoring page and per_page.
+func pagedTenantsHandler(t *testing.T, total int, pages *[]int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "true", query.Get("include_total_count"))
+		page, _ := strconv.Atoi(query.Get("page"))
+		perPage, _ := strconv.Atoi(query.Get("per_page"))
+		*pages = append(*pages, page)
+
+		var items []string
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			items = append(items, fmt.Sprintf(`{"key":"t%d"}`, i))
+		}
+		pageCount := (total + perPage - 1) / perPage
+		_, _ = fmt.Fprintf(w, `{"data":[%s],"total_count":%d,"page_count":%d}`, strings.Join(items, ","), total, pageCount)
+	}
+}
+
+func TestListTenantsPaging(t *testing.T) {
+	testCases := []struct {
+		name      string
+		total     int
+		policy    PagingPolicy
+		wantItems int
+		wantTotal int
+		wantPages []int
+	}{
+		{
+			name:      "Collects every page",
+			total:     7,
+			policy:    PagingPolicy{PerPage: 3, MaxItems: 100},
+			wantItems: 7,
+			wantTotal: 7,
+			wantPages: []int{1, 2, 3},
+		},
+		{
+			name:      "Exact multiple of page size",
+			total:     6,
+			policy:    PagingPolicy{PerPage: 3, MaxItems: 100},
+			wantItems: 6,
+			wantTotal: 6,
+			wantPages: []int{1, 2},
+		},
+		{
+			name:      "Empty list",
+			total:     0,
+			policy:    PagingPolicy{PerPage: 3, MaxItems: 100},
+			wantItems: 0,
+			wantTotal: 0,
+			wantPages: []int{1},
+		},
+		{
+			name:      "Stops at hard cap and keeps the real total",
+			total:     10,
+			policy:    PagingPolicy{PerPage: 3, MaxItems: 5},
+			wantItems: 5,
+			wantTotal: 10,
+			wantPages: []int{1, 2},
+		},
+		{
+			name:      "Cap disabled",
+			total:     10,
+			policy:    PagingPolicy{PerPage: 4, MaxItems: 0},
+			wantItems: 10,
+			wantTotal: 10,
+			wantPages: []int{1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pages []int
+			svc, server := newTestService(pagedTenantsHandler(t, tc.total, &pages))
+			defer server.Close()
+			policy := tc.policy
+			svc.PermitClient.Paging = &policy
+
+			tenants, total, err := svc.ListTenants(context.Background())
+
+			assert.NoError(t, err)
+			assert.Len(t, tenants, tc.wantItems)
+			assert.Equal(t, tc.wantTotal, total)
+			assert.Equal(t, tc.wantPages, pages)
+			if tc.wantItems > 0 {
+				assert.Equal(t, "t0", tenants[0].Key)
+				assert.Equal(t, fmt.Sprintf("t%d", tc.wantItems-1), tenants[tc.wantItems-1].Key)
+			}
+		})
+	}
+}
+
+func TestListTenantsPage(t *testing.T) {
+	testCases := []struct {
+		name      string
+		page      Page
+		wantKeys  []string
+		wantPages []int
+	}{
+		{name: "Window aligned with its size", page: Page{Offset: 4, Limit: 2}, wantKeys: []string{"t4", "t5"}, wantPages: []int{3}},
+		{name: "Window across pages", page: Page{Offset: 2, Limit: 3}, wantKeys: []string{"t2", "t3", "t4"}, wantPages: []int{1, 2}},
+		{name: "Last partial page", page: Page{Offset: 8, Limit: 4}, wantKeys: []string{"t8", "t9"}, wantPages: []int{3}},
+		{name: "Past the end", page: Page{Offset: 20, Limit: 5}, wantKeys: []string{}, wantPages: []int{5}},
+		{name: "Empty window", page: Page{Offset: 4, Limit: 0}, wantKeys: []string{}, wantPages: []int{5}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pages []int
+			svc, server := newTestService(pagedTenantsHandler(t, 10, &pages))
+			defer server.Close()
+			svc.PermitClient.Paging = &PagingPolicy{PerPage: 4, MaxItems: 3}
+
+			tenants, total, err := svc.ListTenantsPage(context.Background(), tc.page)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 10, total)
+			keys := []string{}
+			for _, tenant := range tenants {
+				keys = append(keys, tenant.Key)
+			}
+			assert.Equal(t, tc.wantKeys, keys)
+			assert.Equal(t, tc.wantPages, pages)
+		})
+	}
+}
+
+func TestPagerStopsOnError(t *testing.T) {
+	svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"key":"a"},{"key":"b"}],"total_count":4,"page_count":2}`))
+	})
+	defer server.Close()
+	svc.PermitClient.Paging = &PagingPolicy{PerPage: 2, MaxItems: 100}
+
+	p := newPager[Tenant](svc, factsAPI, "tenants", nil)
+
+	assert.True(t, p.Next(context.Background()))
+	assert.Len(t, p.Items(), 2)
+	assert.False(t, p.Next(context.Background()))
+	assert.Error(t, p.Err())
+
+	tenants, total, err := collectAll(context.Background(), newPager[Tenant](svc, factsAPI, "tenants", nil))
+	assert.Error(t, err)
+	assert.Nil(t, tenants)
+	assert.Equal(t, 0, total)
+}
+
+func TestPagerKeepsEndpointFilters(t *testing.T) {
+	svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/facts/project/env/role_assignments", r.URL.Path)
+		assert.Equal(t, "t1", r.URL.Query().Get("tenant"))
+		assert.Equal(t, "1", r.URL.Query().Get("page"))
+		_, _ = w.Write([]byte(`{"data":[{"id":"r1","tenant":"t1"}],"total_count":1,"page_count":1}`))
+	})
+	defer server.Close()
+
+	assignments, total, err := svc.ListRoleAssignments(context.Background(), RoleAssignmentFilter{Tenant: "t1"})
+
+	assert.NoError(t, err)
+	assert.Len(t, assignments, 1)
+	assert.Equal(t, 1, total)
+}
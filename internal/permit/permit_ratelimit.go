@@ -0,0 +1,157 @@
+package permit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when a Permit request would have to wait for the
+// client-side rate limiter past its context deadline.
+var ErrRateLimited = errors.New("permit request rate limited by client")
+
+// RateLimit is a token bucket that refills at Rate requests per second and
+// holds up to Burst requests. A Rate of zero or less disables the limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitPolicy controls client-side rate limiting of Permit API calls. A
+// request waits for a token from Global and from the limit of its endpoint
+// class. Retried attempts take a token each.
+type RateLimitPolicy struct {
+	// Global applies to every request.
+	Global RateLimit
+	// FactsRead applies to GET requests to the facts API.
+	FactsRead RateLimit
+	// FactsWrite applies to POST, PATCH and DELETE requests to the facts API.
+	FactsWrite RateLimit
+	// Schema applies to every request to the schema API.
+	Schema RateLimit
+}
+
+// DefaultRateLimitPolicy returns the rate limit policy used when none is configured.
+func DefaultRateLimitPolicy() RateLimitPolicy {
+	return RateLimitPolicy{
+		Global: RateLimit{Rate: 50, Burst: 100},
+	}
+}
+
+// rateLimitPolicy returns the configured rate limit policy or the default one.
+func (c *PermitClient) rateLimitPolicy() RateLimitPolicy {
+	if c.RateLimit == nil {
+		return DefaultRateLimitPolicy()
+	}
+	return *c.RateLimit
+}
+
+// tokenBucket hands out tokens at a fixed rate. Tokens are reserved ahead of
+// time, so waiting callers are served in arrival order.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket for limit, or nil when limit is disabled.
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	burst := math.Max(float64(limit.Burst), 1)
+	return &tokenBucket{rate: limit.Rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was not used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// rateLimiter holds the buckets of a RateLimitPolicy.
+type rateLimiter struct {
+	global, factsRead, factsWrite, schema *tokenBucket
+}
+
+func newRateLimiter(policy RateLimitPolicy) *rateLimiter {
+	return &rateLimiter{
+		global:     newTokenBucket(policy.Global),
+		factsRead:  newTokenBucket(policy.FactsRead),
+		factsWrite: newTokenBucket(policy.FactsWrite),
+		schema:     newTokenBucket(policy.Schema),
+	}
+}
+
+// class returns the bucket of the endpoint class of a request.
+func (l *rateLimiter) class(target api, method string) *tokenBucket {
+	switch {
+	case target == schemaAPI:
+		return l.schema
+	case method == http.MethodGet:
+		return l.factsRead
+	default:
+		return l.factsWrite
+	}
+}
+
+// wait blocks until the request may be sent. It fails with ErrRateLimited
+// without waiting when the wait would outlive the context deadline.
+func (l *rateLimiter) wait(ctx context.Context, target api, method string) error {
+	if l == nil {
+		return nil
+	}
+
+	now := time.Now()
+	var reserved []*tokenBucket
+	var delay time.Duration
+	for _, bucket := range []*tokenBucket{l.global, l.class(target, method)} {
+		if bucket == nil {
+			continue
+		}
+		reserved = append(reserved, bucket)
+		delay = max(delay, bucket.reserve(now))
+	}
+	if delay == 0 {
+		return nil
+	}
+
+	release := func() {
+		for _, bucket := range reserved {
+			bucket.cancel()
+		}
+	}
+	if exceedsDeadline(ctx, delay) {
+		release()
+		return ErrRateLimited
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		release()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
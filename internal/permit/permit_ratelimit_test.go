@@ -0,0 +1,205 @@
+package permit
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	bucket := newTokenBucket(RateLimit{Rate: 10, Burst: 2})
+	now := bucket.last
+
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, bucket.reserve(now))
+
+	bucket.cancel()
+	assert.Equal(t, 200*time.Millisecond, bucket.reserve(now))
+
+	// Refilling never goes past the burst.
+	later := now.Add(time.Minute)
+	assert.Equal(t, time.Duration(0), bucket.reserve(later))
+	assert.Equal(t, time.Duration(0), bucket.reserve(later))
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve(later))
+
+	assert.Nil(t, newTokenBucket(RateLimit{Rate: 0, Burst: 10}))
+}
+
+func TestRateLimiterClass(t *testing.T) {
+	limiter := newRateLimiter(RateLimitPolicy{
+		FactsRead:  RateLimit{Rate: 1},
+		FactsWrite: RateLimit{Rate: 2},
+		Schema:     RateLimit{Rate: 3},
+	})
+
+	assert.Nil(t, limiter.global)
+	assert.Same(t, limiter.factsRead, limiter.class(factsAPI, http.MethodGet))
+	assert.Same(t, limiter.factsWrite, limiter.class(factsAPI, http.MethodPost))
+	assert.Same(t, limiter.factsWrite, limiter.class(factsAPI, http.MethodDelete))
+	assert.Same(t, limiter.schema, limiter.class(schemaAPI, http.MethodGet))
+	assert.Same(t, limiter.schema, limiter.class(schemaAPI, http.MethodPost))
+}
+
+func TestSendRawRateLimit(t *testing.T) {
+	t.Run("Waits for a token", func(t *testing.T) {
+		svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {})
+		defer server.Close()
+		svc.limiter = newRateLimiter(RateLimitPolicy{Global: RateLimit{Rate: 20, Burst: 1}})
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := svc.sendRaw(context.Background(), factsAPI, http.MethodPost, "tenants", []byte(`{}`))
+			assert.NoError(t, err)
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	})
+
+	t.Run("Fails fast when the wait outlives the deadline", func(t *testing.T) {
+		var attempts atomic.Int32
+		svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+		})
+		defer server.Close()
+		svc.limiter = newRateLimiter(RateLimitPolicy{FactsWrite: RateLimit{Rate: 0.1, Burst: 1}})
+
+		_, err := svc.sendRaw(context.Background(), factsAPI, http.MethodPost, "tenants", []byte(`{}`))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err = svc.sendRaw(ctx, factsAPI, http.MethodPost, "tenants", []byte(`{}`))
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+		// Reads are in another class and are not held back by writes.
+		_, err = svc.sendRaw(ctx, factsAPI, http.MethodGet, "tenants", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), attempts.Load())
+		assert.Equal(t, uint64(1), svc.RetryStats().Failed)
+	})
+}
+
+// sendConcurrently sends n identical requests at once and closes release once
+// the handler has received the first upstream request.
+func sendConcurrently(t *testing.T, svc *PermitServiceImpl, release chan struct{}, attempts *atomic.Int32, method string, n int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := svc.sendRaw(context.Background(), factsAPI, method, "tenants/abc", nil)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"key":"abc"}`, string(body))
+		}()
+	}
+
+	// Give every caller time to join the request in flight before answering it.
+	for attempts.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+func TestSendRawCoalescing(t *testing.T) {
+	newBlockingService := func() (*PermitServiceImpl, func(), chan struct{}, *atomic.Int32) {
+		release := make(chan struct{})
+		attempts := &atomic.Int32{}
+		svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			<-release
+			_, _ = w.Write([]byte(`{"key":"abc"}`))
+		})
+		return svc, server.Close, release, attempts
+	}
+
+	t.Run("Concurrent identical GETs share one request", func(t *testing.T) {
+		svc, closeServer, release, attempts := newBlockingService()
+		defer closeServer()
+
+		sendConcurrently(t, svc, release, attempts, http.MethodGet, 5)
+
+		assert.Equal(t, int32(1), attempts.Load())
+		stats := svc.RetryStats()
+		assert.Equal(t, uint64(1), stats.Requests)
+		assert.Equal(t, uint64(4), stats.Coalesced)
+	})
+
+	t.Run("Writes are never coalesced", func(t *testing.T) {
+		svc, closeServer, release, attempts := newBlockingService()
+		defer closeServer()
+
+		sendConcurrently(t, svc, release, attempts, http.MethodPatch, 3)
+
+		assert.Equal(t, int32(3), attempts.Load())
+		assert.Equal(t, uint64(0), svc.RetryStats().Coalesced)
+	})
+
+	t.Run("Caller gives up without cancelling the shared request", func(t *testing.T) {
+		svc, closeServer, release, attempts := newBlockingService()
+		defer closeServer()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+		_, err := svc.sendRaw(ctx, factsAPI, http.MethodGet, "tenants/abc", nil)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// A later caller joins the request that is still in flight.
+		sendConcurrently(t, svc, release, attempts, http.MethodGet, 1)
+		assert.Equal(t, int32(1), attempts.Load())
+		assert.Equal(t, uint64(1), svc.RetryStats().Coalesced)
+	})
+
+	t.Run("Callers that joined succeed when the first one gives up", func(t *testing.T) {
+		svc, closeServer, release, attempts := newBlockingService()
+		defer closeServer()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		leaderErr := make(chan error, 1)
+		go func() {
+			_, err := svc.sendRaw(ctx, factsAPI, http.MethodGet, "tenants/abc", nil)
+			leaderErr <- err
+		}()
+		for attempts.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		joined := make(chan []byte, 1)
+		go func() {
+			body, err := svc.sendRaw(context.Background(), factsAPI, http.MethodGet, "tenants/abc", nil)
+			assert.NoError(t, err)
+			joined <- body
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-leaderErr, context.Canceled)
+		close(release)
+		assert.Equal(t, `{"key":"abc"}`, string(<-joined))
+		assert.Equal(t, int32(1), attempts.Load())
+		assert.Equal(t, uint64(1), svc.RetryStats().Coalesced)
+	})
+
+	t.Run("Shared request is bounded by its own timeout", func(t *testing.T) {
+		svc, closeServer, release, _ := newBlockingService()
+		defer closeServer()
+		defer close(release)
+		timeout := sharedRequestTimeout
+		sharedRequestTimeout = 50 * time.Millisecond
+		defer func() { sharedRequestTimeout = timeout }()
+
+		start := time.Now()
+		_, err := svc.sendRaw(context.Background(), factsAPI, http.MethodGet, "tenants/abc", nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
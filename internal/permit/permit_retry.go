@@ -0,0 +1,191 @@
+package permit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	backoff "github.com/cenkalti/backoff/v4"
+)
+
+// IdempotencyKeyHeader is the header that marks a POST or PATCH request as safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy controls how the Permit client retries transient failures.
+//
+// Only network errors, 5xx and 429 responses are retried. POST and PATCH
+// requests are retried only with an idempotency key: the one of the context
+// (see WithIdempotencyKey), or else one derived from the request. A
+// Retry-After header from Permit overrides the computed backoff, and no retry
+// is attempted that would outlive the request context deadline.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 1 disable retries.
+	MaxAttempts int
+	// InitialInterval is the backoff before the first retry.
+	InitialInterval time.Duration
+	// MaxInterval caps the computed backoff between two attempts.
+	MaxInterval time.Duration
+	// MaxElapsedTime bounds the total time spent retrying a single request.
+	MaxElapsedTime time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     4,
+		InitialInterval: 200 * time.Millisecond,
+		MaxInterval:     5 * time.Second,
+		MaxElapsedTime:  30 * time.Second,
+	}
+}
+
+// newBackOff returns a fresh exponential backoff for a single request.
+func (p RetryPolicy) newBackOff() *backoff.ExponentialBackOff {
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = p.InitialInterval
+	bo.MaxInterval = p.MaxInterval
+	bo.MaxElapsedTime = p.MaxElapsedTime
+	bo.Reset()
+	return bo
+}
+
+// RetryStats is a snapshot of the retry counters of a Permit service.
+type RetryStats struct {
+	// Requests is the number of logical requests sent to Permit.
+	Requests uint64 `json:"requests"`
+	// Retries is the number of extra attempts made after a transient failure.
+	Retries uint64 `json:"retries"`
+	// Succeeded is the number of requests that eventually got a 2xx response.
+	Succeeded uint64 `json:"succeeded"`
+	// Recovered is the number of succeeded requests that needed at least one retry.
+	Recovered uint64 `json:"recovered"`
+	// Failed is the number of requests that failed without being retried.
+	Failed uint64 `json:"failed"`
+	// Exhausted is the number of requests that failed after retrying.
+	Exhausted uint64 `json:"exhausted"`
+	// Coalesced is the number of GET requests answered by an identical request
+	// already in flight instead of a request of their own.
+	Coalesced uint64 `json:"coalesced"`
+}
+
+// retryCounters holds the live counters behind RetryStats.
+type retryCounters struct {
+	requests  atomic.Uint64
+	retries   atomic.Uint64
+	succeeded atomic.Uint64
+	recovered atomic.Uint64
+	failed    atomic.Uint64
+	exhausted atomic.Uint64
+	coalesced atomic.Uint64
+}
+
+// record counts the outcome of a request that took the given number of attempts.
+func (c *retryCounters) record(attempts int, err error) {
+	switch {
+	case err == nil:
+		c.succeeded.Add(1)
+		if attempts > 1 {
+			c.recovered.Add(1)
+		}
+	case attempts > 1:
+		c.exhausted.Add(1)
+	default:
+		c.failed.Add(1)
+	}
+}
+
+func (c *retryCounters) snapshot() RetryStats {
+	return RetryStats{
+		Requests:  c.requests.Load(),
+		Retries:   c.retries.Load(),
+		Succeeded: c.succeeded.Load(),
+		Recovered: c.recovered.Load(),
+		Failed:    c.failed.Load(),
+		Exhausted: c.exhausted.Load(),
+		Coalesced: c.coalesced.Load(),
+	}
+}
+
+type idempotencyKeyCtxKey struct{}
+
+// WithIdempotencyKey returns a context whose Permit POST and PATCH requests carry
+// the given idempotency key instead of the one derived from each request.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+// idempotencyKey returns the idempotency key stored in ctx, if any.
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyCtxKey{}).(string)
+	return key
+}
+
+// withRequestIdempotencyKey returns ctx carrying an idempotency key for a POST
+// or PATCH request when it has none: a digest of the request's API, method,
+// endpoint and payload. Every attempt of the request sends the same key, so
+// Permit can tell a retried write from a new one.
+func withRequestIdempotencyKey(ctx context.Context, target api, method, endpoint string, payload []byte) context.Context {
+	if (method != http.MethodPost && method != http.MethodPatch) || idempotencyKey(ctx) != "" {
+		return ctx
+	}
+	sum := sha256.New()
+	fmt.Fprintf(sum, "%d %s %s\n", target, method, endpoint)
+	sum.Write(payload)
+	return WithIdempotencyKey(ctx, hex.EncodeToString(sum.Sum(nil)))
+}
+
+// canRetryMethod reports whether a request with the given method may be retried.
+func canRetryMethod(ctx context.Context, method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPatch:
+		return idempotencyKey(ctx) != ""
+	default:
+		return true
+	}
+}
+
+// isTransient reports whether a failed attempt is worth retrying.
+// A non-nil err is a transport failure; otherwise status is the response status.
+func isTransient(status int, err error) bool {
+	if err != nil {
+		var permanent *backoff.PermanentError
+		if errors.As(err, &permanent) {
+			return false
+		}
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// exceedsDeadline reports whether waiting for delay would outlive the context deadline.
+func exceedsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Now().Add(delay).After(deadline)
+}
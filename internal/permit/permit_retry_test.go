@@ -0,0 +1,207 @@
+package permit
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendRawRetries(t *testing.T) {
+	testCases := []struct {
+		name          string
+		method        string
+		ctx           func() context.Context
+		statuses      []int
+		wantErr       bool
+		wantAttempts  int32
+		wantRetries   uint64
+		wantRecovered uint64
+		wantExhausted uint64
+		wantFailed    uint64
+	}{
+		{
+			name:          "GET retried on 503 until success",
+			method:        http.MethodGet,
+			ctx:           context.Background,
+			statuses:      []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts:  3,
+			wantRetries:   2,
+			wantRecovered: 1,
+		},
+		{
+			name:          "GET retried on 429",
+			method:        http.MethodGet,
+			ctx:           context.Background,
+			statuses:      []int{http.StatusTooManyRequests, http.StatusOK},
+			wantAttempts:  2,
+			wantRetries:   1,
+			wantRecovered: 1,
+		},
+		{
+			name:         "GET not retried on 4xx",
+			method:       http.MethodGet,
+			ctx:          context.Background,
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			wantErr:      true,
+			wantAttempts: 1,
+			wantFailed:   1,
+		},
+		{
+			name:          "GET gives up after max attempts",
+			method:        http.MethodGet,
+			ctx:           context.Background,
+			statuses:      []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			wantErr:       true,
+			wantAttempts:  3,
+			wantRetries:   2,
+			wantExhausted: 1,
+		},
+		{
+			name:          "POST retried with the key derived from the request",
+			method:        http.MethodPost,
+			ctx:           context.Background,
+			statuses:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts:  2,
+			wantRetries:   1,
+			wantRecovered: 1,
+		},
+		{
+			name:          "PATCH retried on 429",
+			method:        http.MethodPatch,
+			ctx:           context.Background,
+			statuses:      []int{http.StatusTooManyRequests, http.StatusOK},
+			wantAttempts:  2,
+			wantRetries:   1,
+			wantRecovered: 1,
+		},
+		{
+			name:         "POST not retried on 4xx",
+			method:       http.MethodPost,
+			ctx:          context.Background,
+			statuses:     []int{http.StatusConflict, http.StatusOK},
+			wantErr:      true,
+			wantAttempts: 1,
+			wantFailed:   1,
+		},
+		{
+			name:   "POST with idempotency key retried",
+			method: http.MethodPost,
+			ctx: func() context.Context {
+				return WithIdempotencyKey(context.Background(), "key-1")
+			},
+			statuses:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts:  2,
+			wantRetries:   1,
+			wantRecovered: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			var keys []string
+			svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+				keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+				w.WriteHeader(tc.statuses[n-1])
+			})
+			defer server.Close()
+
+			_, err := svc.sendRaw(tc.ctx(), factsAPI, tc.method, "tenants", []byte(`{}`))
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantAttempts, attempts.Load())
+			switch {
+			case tc.method == http.MethodGet:
+				assert.Empty(t, keys[0])
+			case idempotencyKey(tc.ctx()) != "":
+				assert.Equal(t, "key-1", keys[0])
+			default:
+				assert.Len(t, keys[0], 64)
+			}
+			for _, key := range keys[1:] {
+				assert.Equal(t, keys[0], key, "every attempt sends the same key")
+			}
+
+			stats := svc.RetryStats()
+			assert.Equal(t, uint64(1), stats.Requests)
+			assert.Equal(t, tc.wantRetries, stats.Retries)
+			assert.Equal(t, tc.wantRecovered, stats.Recovered)
+			assert.Equal(t, tc.wantExhausted, stats.Exhausted)
+			assert.Equal(t, tc.wantFailed, stats.Failed)
+		})
+	}
+}
+
+func TestRequestIdempotencyKey(t *testing.T) {
+	key := func(method, endpoint, payload string) string {
+		return idempotencyKey(withRequestIdempotencyKey(context.Background(), factsAPI, method, endpoint, []byte(payload)))
+	}
+
+	assert.Equal(t, key(http.MethodPost, "tenants", `{"key":"t1"}`), key(http.MethodPost, "tenants", `{"key":"t1"}`))
+	assert.NotEqual(t, key(http.MethodPost, "tenants", `{"key":"t1"}`), key(http.MethodPost, "tenants", `{"key":"t2"}`))
+	assert.NotEqual(t, key(http.MethodPatch, "tenants/t1", `{}`), key(http.MethodPatch, "tenants/t2", `{}`))
+	assert.NotEqual(t, key(http.MethodPost, "tenants", `{}`), key(http.MethodPatch, "tenants", `{}`))
+	assert.Empty(t, key(http.MethodDelete, "tenants/t1", ""))
+
+	ctx := WithIdempotencyKey(context.Background(), "client-key")
+	assert.Equal(t, "client-key", idempotencyKey(withRequestIdempotencyKey(ctx, factsAPI, http.MethodPost, "tenants", nil)))
+}
+
+func TestSendRawRetryAfterBeyondDeadline(t *testing.T) {
+	var attempts atomic.Int32
+	svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+	svc.PermitClient.Retry.MaxElapsedTime = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	// A write, since a shared GET is bounded by its own timeout rather than by
+	// the caller's deadline.
+	start := time.Now()
+	_, err := svc.sendRaw(ctx, factsAPI, http.MethodPost, "tenants", []byte(`{}`))
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), attempts.Load())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, uint64(1), svc.RetryStats().Failed)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "Empty", value: "", want: 0, wantOK: false},
+		{name: "Seconds", value: "3", want: 3 * time.Second, wantOK: true},
+		{name: "Negative seconds", value: "-1", want: 0, wantOK: false},
+		{name: "HTTP date", value: now.Add(10 * time.Second).Format(http.TimeFormat), want: 10 * time.Second, wantOK: true},
+		{name: "HTTP date in the past", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, wantOK: true},
+		{name: "Invalid", value: "soon", want: 0, wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tc.value, now)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -3,8 +3,12 @@ package permit
 import (
 	"context"
 	"errors"
+	"fmt"
+	"iam_services_main_v1/internal/authz"
 	"iam_services_main_v1/pkg/logger"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/permitio/permit-golang/pkg/enforcement"
@@ -13,7 +17,9 @@ import (
 
 // PermitService provides authorization functionality using Permit.io
 type PermitSdkService struct {
-	client *permit.Client
+	client    *permit.Client
+	decisions *decisionCache
+	breaker   *CircuitBreaker
 }
 
 // NewPermitService creates a new PermitService instance
@@ -23,8 +29,89 @@ func NewPermitSdkService(permitClient *permit.Client) *PermitSdkService {
 	}
 }
 
+// WithDecisionCache makes Check reuse PDP decisions according to policy and returns s.
+func (s *PermitSdkService) WithDecisionCache(policy DecisionCachePolicy) *PermitSdkService {
+	s.decisions = newDecisionCache(policy)
+	return s
+}
+
+// WithCircuitBreaker stops PDP calls while breaker is open and returns s. Checks
+// are then answered from the decision cache however old its decisions are, and
+// fail with an error wrapping authz.ErrUnavailable when none is cached.
+func (s *PermitSdkService) WithCircuitBreaker(breaker *CircuitBreaker) *PermitSdkService {
+	s.breaker = breaker
+	return s
+}
+
+// callPDP makes a PDP call unless the circuit breaker is open and records its outcome.
+func (s *PermitSdkService) callPDP(call func() error) error {
+	if err := s.breaker.allow(); err != nil {
+		return fmt.Errorf("%w: %w", authz.ErrUnavailable, err)
+	}
+	err := call()
+	s.breaker.record(err)
+	return err
+}
+
+// DecisionCacheStats returns a snapshot of the decision cache hit and miss counters.
+func (s *PermitSdkService) DecisionCacheStats() DecisionCacheStats {
+	if s == nil || s.decisions == nil {
+		return DecisionCacheStats{}
+	}
+	return s.decisions.stats()
+}
+
+// InvalidateUserDecisions drops the cached decisions of a user whose role
+// assignments changed.
+func (s *PermitSdkService) InvalidateUserDecisions(userID string) {
+	if s == nil || s.decisions == nil {
+		return
+	}
+	s.decisions.invalidateUser(userID)
+}
+
+// InvalidateAllDecisions drops every cached decision, e.g. after a role's
+// permissions changed.
+func (s *PermitSdkService) InvalidateAllDecisions() {
+	if s == nil || s.decisions == nil {
+		return
+	}
+	s.decisions.invalidateAll()
+}
+
 // Check verifies if a user has permission to perform an action on a resource
 func (s *PermitSdkService) Check(ctx context.Context, userID, action, resourceType, resourceID, tenant string) (bool, error) {
+	if s.decisions == nil {
+		return s.check(userID, action, resourceType, resourceID, tenant)
+	}
+
+	key := decisionKey(userID, action, resourceType, resourceID, tenant)
+	allowed, found, gen := s.decisions.lookup(key)
+	if !found {
+		var err error
+		allowed, err = s.check(userID, action, resourceType, resourceID, tenant)
+		switch {
+		case errors.Is(err, ErrCircuitOpen):
+			stale, ok := s.decisions.lookupStale(key)
+			if !ok {
+				return false, err
+			}
+			logger.LogWarn("Serving stale permission decision", "user", userID, "action", action, "resource_type", resourceType, "resource_id", resourceID, "tenant", tenant, "permitted", stale)
+			allowed = stale
+		case err != nil && !errors.Is(err, authz.ErrPermissionDenied):
+			return false, err
+		default:
+			s.decisions.store(key, allowed, gen)
+		}
+	}
+	if !allowed {
+		return false, authz.ErrPermissionDenied
+	}
+	return true, nil
+}
+
+// check asks the PDP for a decision.
+func (s *PermitSdkService) check(userID, action, resourceType, resourceID, tenant string) (bool, error) {
 	// Build user object for permission check
 	user := enforcement.UserBuilder(userID).Build()
 
@@ -32,6 +119,31 @@ func (s *PermitSdkService) Check(ctx context.Context, userID, action, resourceTy
 	permitAction := enforcement.Action(action)
 
 	// Build resource object for permission check
+	resource := buildResource(resourceType, resourceID, tenant)
+
+	// Perform permission check with Permit.io
+	var permitted bool
+	err := s.callPDP(func() (err error) {
+		permitted, err = s.client.Check(user, permitAction, resource)
+		return err
+	})
+	if err != nil {
+		logger.LogError("Permission check failed", "error", err, "user", userID, "action", action, "resource_type", resourceType, "resource_id", resourceID, "tenant", tenant)
+		return false, err
+	}
+
+	if !permitted {
+		err = authz.ErrPermissionDenied
+		logger.LogWarn("Permission denied", "user", userID, "action", action, "resource_type", resourceType, "resource_id", resourceID, "tenant", tenant)
+		return false, err
+	}
+	logger.LogInfo("Permission check", "user", userID, "action", action, "resource_type", resourceType, "resource_id", resourceID, "tenant", tenant, "permitted", permitted)
+	return permitted, nil
+}
+
+// buildResource builds the resource of a permission check, scoped to tenant
+// and to resourceID when they are given.
+func buildResource(resourceType, resourceID, tenant string) enforcement.Resource {
 	resourceBuilder := enforcement.ResourceBuilder(resourceType)
 
 	// Add tenant if provided
@@ -44,22 +156,121 @@ func (s *PermitSdkService) Check(ctx context.Context, userID, action, resourceTy
 		resourceBuilder = resourceBuilder.WithKey(resourceID)
 	}
 
-	resource := resourceBuilder.Build()
+	return resourceBuilder.Build()
+}
 
-	// Perform permission check with Permit.io
-	permitted, err := s.client.Check(user, permitAction, resource)
+// BulkCheck asks the PDP for a decision on every check of userID in tenant
+// with a single request and returns the decisions in the order of checks.
+// Unlike Check, a denial is reported as false rather than as an error.
+// Decisions found in the decision cache are not sent to the PDP.
+func (s *PermitSdkService) BulkCheck(ctx context.Context, userID, tenant string, checks []authz.PermissionCheck) ([]bool, error) {
+	if len(checks) > authz.MaxBulkChecks {
+		return nil, authz.ErrTooManyChecks
+	}
+
+	results := make([]bool, len(checks))
+	keys := make([]string, len(checks))
+	gens := make([]decisionGeneration, len(checks))
+	pending := make([]int, 0, len(checks))
+	for i, check := range checks {
+		if s.decisions != nil {
+			keys[i] = decisionKey(userID, check.Action, check.ResourceType, check.ResourceID, tenant)
+			allowed, found, gen := s.decisions.lookup(keys[i])
+			if found {
+				results[i] = allowed
+				continue
+			}
+			gens[i] = gen
+		}
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	user := enforcement.UserBuilder(userID).Build()
+	requests := make([]enforcement.CheckRequest, len(pending))
+	for j, i := range pending {
+		check := checks[i]
+		resource := buildResource(check.ResourceType, check.ResourceID, tenant)
+		requests[j] = *enforcement.NewCheckRequest(user, enforcement.Action(check.Action), resource, map[string]string{})
+	}
+
+	var decisions []bool
+	err := s.callPDP(func() (err error) {
+		decisions, err = s.client.BulkCheck(requests...)
+		if err == nil && len(decisions) != len(requests) {
+			err = fmt.Errorf("PDP returned %d decisions for %d checks", len(decisions), len(requests))
+		}
+		return err
+	})
+	if errors.Is(err, ErrCircuitOpen) && s.decisions != nil {
+		for _, i := range pending {
+			allowed, found := s.decisions.lookupStale(keys[i])
+			if !found {
+				return nil, err
+			}
+			results[i] = allowed
+		}
+		logger.LogWarn("Serving stale permission decisions", "user", userID, "tenant", tenant, "checks", len(checks))
+		return results, nil
+	}
 	if err != nil {
-		logger.LogError("Permission check failed", "error", err, "user", userID, "action", action, "resource_type", resourceType, "resource_id", resourceID, "tenant", tenant)
-		return false, err
+		logger.LogError("Bulk permission check failed", "error", err, "user", userID, "tenant", tenant, "checks", len(requests))
+		return nil, err
+	}
+	for j, i := range pending {
+		results[i] = decisions[j]
+		if s.decisions != nil {
+			s.decisions.store(keys[i], decisions[j], gens[i])
+		}
 	}
+	logger.LogInfo("Bulk permission check", "user", userID, "tenant", tenant, "checks", len(checks), "sent", len(requests))
+	return results, nil
+}
 
-	if !permitted {
-		err = errors.New("permission denied")
-		logger.LogWarn("Permission denied", "user", userID, "action", action, "resource_type", resourceType, "resource_id", resourceID, "tenant", tenant)
-		return false, err
+// ListAllowedActions asks the PDP for the permissions of userID in tenant and
+// returns the actions they grant on the resource, sorted. Permissions from
+// tenant-wide roles apply to every resource of the type; permissions from
+// instance roles only to that instance.
+func (s *PermitSdkService) ListAllowedActions(ctx context.Context, userID, resourceType, resourceID, tenant string) ([]string, error) {
+	user := enforcement.UserBuilder(userID).Build()
+	opts := []enforcement.UserPermissionsOption{enforcement.WithResourceTypes([]string{resourceType})}
+	if tenant != "" {
+		opts = append(opts, enforcement.WithTenants([]string{tenant}))
 	}
-	logger.LogInfo("Permission check", "user", userID, "action", action, "resource_type", resourceType, "resource_id", resourceID, "tenant", tenant, "permitted", permitted)
-	return permitted, nil
+	var permissions enforcement.UserPermissions
+	err := s.callPDP(func() (err error) {
+		permissions, err = s.client.GetUserPermissionsWithOptions(user, opts...)
+		return err
+	})
+	if err != nil {
+		logger.LogError("Listing allowed actions failed", "error", err, "user", userID, "resource_type", resourceType, "resource_id", resourceID, "tenant", tenant)
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, granted := range permissions {
+		if tenant != "" && granted.Tenant.Key != tenant {
+			continue
+		}
+		if granted.Resource != nil && (granted.Resource.Type != resourceType || granted.Resource.Key != resourceID) {
+			continue
+		}
+		for _, permission := range granted.Permissions {
+			// Permissions are reported as "<resource type>:<action>".
+			if action, ok := strings.CutPrefix(permission, resourceType+":"); ok {
+				seen[action] = true
+			}
+		}
+	}
+
+	actions := make([]string, 0, len(seen))
+	for action := range seen {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions, nil
 }
 
 // PermissionRequest represents the JSON body for permission check requests
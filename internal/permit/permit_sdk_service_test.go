@@ -0,0 +1,295 @@
+package permit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"iam_services_main_v1/internal/authz"
+
+	permitsdkcfg "github.com/permitio/permit-golang/pkg/config"
+	"github.com/permitio/permit-golang/pkg/enforcement"
+	"github.com/permitio/permit-golang/pkg/permit"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPDP answers /allowed and /allowed/bulk with the decisions in allowed,
+// keyed by user, or by user and action, and counts the requests and the
+// checks it receives.
+type testPDP struct {
+	mu      sync.Mutex
+	allowed map[string]bool
+	status  int
+	// dropped is the number of decisions left out of bulk responses.
+	dropped int
+	checks  atomic.Int64
+	items   atomic.Int64
+}
+
+func (p *testPDP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.checks.Add(1)
+	var reqs []enforcement.CheckRequest
+	if strings.HasSuffix(r.URL.Path, "/bulk") {
+		_ = json.NewDecoder(r.Body).Decode(&reqs)
+	} else {
+		var req enforcement.CheckRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		reqs = append(reqs, req)
+	}
+	p.items.Add(int64(len(reqs)))
+
+	p.mu.Lock()
+	allows := make([]map[string]bool, len(reqs))
+	for i, req := range reqs {
+		allow := p.allowed[req.User.Key] || p.allowed[req.User.Key+":"+string(req.Action)]
+		allows[i] = map[string]bool{"allow": allow}
+	}
+	status := p.status
+	allows = allows[:len(allows)-min(p.dropped, len(allows))]
+	p.mu.Unlock()
+
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/bulk") {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"allow": allows})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(allows[0])
+}
+
+func (p *testPDP) set(user string, allow bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.allowed[user] = allow
+}
+
+func (p *testPDP) fail(status int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = status
+}
+
+func newTestSdkService(t *testing.T, policy *DecisionCachePolicy) (*PermitSdkService, *testPDP) {
+	t.Helper()
+	pdp := &testPDP{allowed: map[string]bool{}}
+	server := httptest.NewServer(pdp)
+	t.Cleanup(server.Close)
+
+	cfg := permitsdkcfg.NewConfigBuilder("token").WithPdpUrl(server.URL).Build()
+	svc := NewPermitSdkService(permit.New(cfg))
+	if policy != nil {
+		svc.WithDecisionCache(*policy)
+	}
+	return svc, pdp
+}
+
+func TestCheckDecisionCache(t *testing.T) {
+	ctx := context.Background()
+	policy := DecisionCachePolicy{AllowTTL: time.Minute, DenyTTL: time.Minute, MaxEntries: 100}
+
+	t.Run("Without cache every check reaches the PDP", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, nil)
+		pdp.set("alice", true)
+
+		for i := 0; i < 3; i++ {
+			allowed, err := svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+			assert.NoError(t, err)
+			assert.True(t, allowed)
+		}
+		assert.Equal(t, int64(3), pdp.checks.Load())
+		assert.Equal(t, DecisionCacheStats{}, svc.DecisionCacheStats())
+	})
+
+	t.Run("Allow and deny decisions are reused", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, &policy)
+		pdp.set("alice", true)
+
+		for i := 0; i < 3; i++ {
+			allowed, err := svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+			assert.NoError(t, err)
+			assert.True(t, allowed)
+
+			allowed, err = svc.Check(ctx, "bob", "read", "account", "a1", "t1")
+			assert.EqualError(t, err, "permission denied")
+			assert.False(t, allowed)
+		}
+		assert.Equal(t, int64(2), pdp.checks.Load())
+		assert.Equal(t, DecisionCacheStats{Hits: 4, Misses: 2}, svc.DecisionCacheStats())
+	})
+
+	t.Run("Decisions are keyed by every argument", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, &policy)
+		pdp.set("alice", true)
+
+		_, _ = svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+		_, _ = svc.Check(ctx, "alice", "update", "account", "a1", "t1")
+		_, _ = svc.Check(ctx, "alice", "read", "tenant", "a1", "t1")
+		_, _ = svc.Check(ctx, "alice", "read", "account", "a2", "t1")
+		_, _ = svc.Check(ctx, "alice", "read", "account", "a1", "t2")
+		assert.Equal(t, int64(5), pdp.checks.Load())
+	})
+
+	t.Run("Deny decisions expire on their own TTL", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, &DecisionCachePolicy{AllowTTL: time.Minute, DenyTTL: 20 * time.Millisecond, MaxEntries: 100})
+
+		allowed, _ := svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+		assert.False(t, allowed)
+		pdp.set("alice", true)
+		time.Sleep(50 * time.Millisecond)
+
+		allowed, err := svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		assert.Equal(t, int64(2), pdp.checks.Load())
+	})
+
+	t.Run("Invalidating a user drops only that user's decisions", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, &policy)
+		pdp.set("alice", true)
+		pdp.set("bob", true)
+		_, _ = svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+		_, _ = svc.Check(ctx, "bob", "read", "account", "a1", "t1")
+
+		pdp.set("alice", false)
+		svc.InvalidateUserDecisions("alice")
+
+		allowed, _ := svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+		assert.False(t, allowed)
+		allowed, _ = svc.Check(ctx, "bob", "read", "account", "a1", "t1")
+		assert.True(t, allowed)
+		assert.Equal(t, int64(3), pdp.checks.Load())
+	})
+
+	t.Run("Invalidating all decisions", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, &policy)
+		_, _ = svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+
+		pdp.set("alice", true)
+		svc.InvalidateAllDecisions()
+
+		allowed, _ := svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+		assert.True(t, allowed)
+		assert.Equal(t, int64(2), pdp.checks.Load())
+	})
+
+	t.Run("PDP errors are not cached", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, &policy)
+		pdp.fail(http.StatusInternalServerError)
+
+		_, err := svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+		assert.Error(t, err)
+		assert.NotEqual(t, "permission denied", err.Error())
+
+		pdp.fail(0)
+		pdp.set("alice", true)
+		allowed, err := svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("Invalidation is safe without a cache", func(t *testing.T) {
+		var svc *PermitSdkService
+		svc.InvalidateUserDecisions("alice")
+		svc.InvalidateAllDecisions()
+		assert.Equal(t, DecisionCacheStats{}, svc.DecisionCacheStats())
+	})
+}
+
+func TestBulkCheck(t *testing.T) {
+	ctx := context.Background()
+	checks := []authz.PermissionCheck{
+		{Action: "read", ResourceType: "account", ResourceID: "a1"},
+		{Action: "update", ResourceType: "account", ResourceID: "a1"},
+		{Action: "read", ResourceType: "tenant"},
+	}
+
+	t.Run("Decisions come back in order from one request", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, nil)
+		pdp.set("alice:read", true)
+
+		allowed, err := svc.BulkCheck(ctx, "alice", "t1", checks)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, false, true}, allowed)
+		assert.Equal(t, int64(1), pdp.checks.Load())
+		assert.Equal(t, int64(3), pdp.items.Load())
+	})
+
+	t.Run("Cached decisions are not sent again", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, &DecisionCachePolicy{AllowTTL: time.Minute, DenyTTL: time.Minute, MaxEntries: 100})
+		pdp.set("alice:read", true)
+
+		_, _ = svc.Check(ctx, "alice", "read", "account", "a1", "t1")
+		allowed, err := svc.BulkCheck(ctx, "alice", "t1", checks)
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, false, true}, allowed)
+		assert.Equal(t, int64(3), pdp.items.Load())
+
+		allowed, err = svc.BulkCheck(ctx, "alice", "t1", checks)
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, false, true}, allowed)
+		assert.Equal(t, int64(2), pdp.checks.Load())
+		assert.Equal(t, DecisionCacheStats{Hits: 4, Misses: 3}, svc.DecisionCacheStats())
+	})
+
+	t.Run("Empty batch does not reach the PDP", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, nil)
+
+		allowed, err := svc.BulkCheck(ctx, "alice", "t1", nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, allowed)
+		assert.Equal(t, int64(0), pdp.checks.Load())
+	})
+
+	t.Run("Oversized batch is rejected", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, nil)
+
+		_, err := svc.BulkCheck(ctx, "alice", "t1", make([]authz.PermissionCheck, authz.MaxBulkChecks+1))
+
+		assert.ErrorIs(t, err, authz.ErrTooManyChecks)
+		assert.Equal(t, int64(0), pdp.checks.Load())
+	})
+
+	t.Run("PDP errors fail the batch and are not cached", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, &DecisionCachePolicy{AllowTTL: time.Minute, DenyTTL: time.Minute, MaxEntries: 100})
+		pdp.fail(http.StatusInternalServerError)
+
+		_, err := svc.BulkCheck(ctx, "alice", "t1", checks)
+		assert.Error(t, err)
+
+		pdp.fail(0)
+		pdp.set("alice", true)
+		allowed, err := svc.BulkCheck(ctx, "alice", "t1", checks)
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, true, true}, allowed)
+	})
+
+	t.Run("Missing decisions fail the batch and are not cached", func(t *testing.T) {
+		svc, pdp := newTestSdkService(t, &DecisionCachePolicy{AllowTTL: time.Minute, DenyTTL: time.Minute, MaxEntries: 100})
+		pdp.set("alice", true)
+		pdp.mu.Lock()
+		pdp.dropped = 1
+		pdp.mu.Unlock()
+
+		allowed, err := svc.BulkCheck(ctx, "alice", "t1", checks)
+		assert.Error(t, err)
+		assert.Nil(t, allowed)
+
+		pdp.mu.Lock()
+		pdp.dropped = 0
+		pdp.mu.Unlock()
+		allowed, err = svc.BulkCheck(ctx, "alice", "t1", checks)
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, true, true}, allowed)
+		assert.Equal(t, int64(6), pdp.items.Load())
+	})
+}
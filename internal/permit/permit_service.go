@@ -3,11 +3,39 @@ package permit
 import "context"
 
 type PermitService interface {
-	APIExecute(ctx context.Context, method, endpoint string, payload interface{}) (interface{}, error)
+	ListTenants(ctx context.Context) ([]Tenant, int, error)
+	ListTenantsPage(ctx context.Context, page Page) ([]Tenant, int, error)
+	SearchTenants(ctx context.Context, search string) ([]Tenant, int, error)
+	GetTenant(ctx context.Context, key string) (*Tenant, error)
+	CreateTenant(ctx context.Context, input TenantCreate) (*Tenant, error)
+	UpdateTenant(ctx context.Context, key string, input TenantUpdate) (*Tenant, error)
+	DeleteTenant(ctx context.Context, key string) error
 
-	ExecuteGetAPI(ctx context.Context, method, endpoint string) ([]map[string]interface{}, error)
+	GetUser(ctx context.Context, key string) (*User, error)
 
-	GetSingleResource(ctx context.Context, method, endpoint string) (map[string]interface{}, error)
+	ListResourceInstances(ctx context.Context, tenant, resource string) ([]ResourceInstance, int, error)
+	ListResourceInstancesPage(ctx context.Context, tenant, resource string, page Page) ([]ResourceInstance, int, error)
+	GetResourceInstance(ctx context.Context, key string) (*ResourceInstance, error)
+	CreateResourceInstance(ctx context.Context, input ResourceInstanceCreate) (*ResourceInstance, error)
+	UpdateResourceInstance(ctx context.Context, key string, input ResourceInstanceUpdate) (*ResourceInstance, error)
+	DeleteResourceInstance(ctx context.Context, key string) error
 
-	SendRequest(ctx context.Context, method, endpoint string, payload interface{}) (map[string]interface{}, error)
+	ListResources(ctx context.Context) ([]Resource, int, error)
+	GetResource(ctx context.Context, key string) (*Resource, error)
+	CreateResource(ctx context.Context, input ResourceCreate) (*Resource, error)
+	UpdateResource(ctx context.Context, key string, input ResourceUpdate) (*Resource, error)
+	CreateResourceAction(ctx context.Context, resourceKey string, input ActionCreate) (*Action, error)
+	ListResourceRelations(ctx context.Context, resourceKey string) ([]Relation, int, error)
+	CreateResourceRelation(ctx context.Context, resourceKey string, input RelationCreate) (*Relation, error)
+	CreateResourceRole(ctx context.Context, resourceKey string, input RoleCreate) (*Role, error)
+	UpdateResourceRole(ctx context.Context, resourceKey, roleKey string, input RoleUpdate) (*Role, error)
+	DeleteResourceRole(ctx context.Context, resourceKey, roleKey string) error
+
+	ListRoleAssignments(ctx context.Context, filter RoleAssignmentFilter) ([]RoleAssignment, int, error)
+	ListRoleAssignmentsPage(ctx context.Context, filter RoleAssignmentFilter, page Page) ([]RoleAssignment, int, error)
+	AssignRole(ctx context.Context, input RoleAssignmentCreate) (*RoleAssignment, error)
+	UnassignRole(ctx context.Context, input RoleAssignmentCreate) error
+
+	ListRelationshipTuples(ctx context.Context, filter RelationshipTupleFilter) ([]RelationshipTuple, int, error)
+	CreateRelationshipTuple(ctx context.Context, input RelationshipTupleCreate) (*RelationshipTuple, error)
 }
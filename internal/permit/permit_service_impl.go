@@ -7,265 +7,278 @@ import (
 	"fmt"
 	"iam_services_main_v1/pkg/logger"
 	"io"
-	"log"
 	"net/http"
-	"strings"
 	"time"
 
 	backoff "github.com/cenkalti/backoff/v4"
+	"golang.org/x/sync/singleflight"
 )
 
 // PermitClient is a client for interacting with the Permit API.
+//
+// Permit serves tenants, users and resource instances from the facts API and
+// resource types, actions and roles from the schema API. Both base URLs are
+// fixed at construction and never modified, so a client is safe to share
+// across concurrent requests.
 type PermitClient struct {
-	BaseURL string
-	Headers map[string]string
-	Client  *http.Client
+	FactsURL  string
+	SchemaURL string
+	Headers   map[string]string
+	Client    *http.Client
+	// Retry is the retry policy for all Permit calls; nil means DefaultRetryPolicy.
+	Retry *RetryPolicy
+	// Paging is the paging policy for list endpoints; nil means DefaultPagingPolicy.
+	Paging *PagingPolicy
+	// RateLimit is the client-side rate limit policy; nil means DefaultRateLimitPolicy.
+	RateLimit *RateLimitPolicy
+	// Breaker stops calls while Permit is unavailable; nil disables it.
+	Breaker *CircuitBreaker
+}
+
+// api identifies which Permit API an operation is sent to.
+type api int
+
+const (
+	factsAPI api = iota
+	schemaAPI
+)
+
+// baseURL returns the base URL of the given Permit API.
+func (c *PermitClient) baseURL(target api) string {
+	if target == schemaAPI {
+		return c.SchemaURL
+	}
+	return c.FactsURL
+}
+
+// retryPolicy returns the configured retry policy or the default one.
+func (c *PermitClient) retryPolicy() RetryPolicy {
+	if c.Retry == nil {
+		return DefaultRetryPolicy()
+	}
+	return *c.Retry
 }
 
 type PermitServiceImpl struct {
 	PermitClient *PermitClient
+	retries      retryCounters
+	limiter      *rateLimiter
+	inflight     singleflight.Group
 }
 
 // NewPermitClient creates a new PermitClient.
 func NewPermitServiceImpl(permitClient *PermitClient) PermitService {
 	return &PermitServiceImpl{
 		PermitClient: permitClient,
+		limiter:      newRateLimiter(permitClient.rateLimitPolicy()),
 	}
 }
 
-// SendRequest sends an HTTP request without retry logic.
-func (pc *PermitServiceImpl) SendRequest(ctx context.Context, method, endpoint string, payload interface{}) (map[string]interface{}, error) {
-	var result map[string]interface{}
-	// Serialize payload to JSON
-	var body io.Reader
-	if payload != nil {
-		jsonData, err := json.Marshal(payload)
-		if err != nil {
-			logger.LogError("Failed to marshal payload", "error", err)
-			return nil, err
-		}
-		body = bytes.NewBuffer(jsonData)
-	}
+// RetryStats returns a snapshot of the retry counters for all Permit calls made by pc.
+func (pc *PermitServiceImpl) RetryStats() RetryStats {
+	return pc.retries.snapshot()
+}
 
-	// Change base URL for specific endpoints (roles/resources)
-	if strings.Contains(endpoint, "roles") || strings.Contains(endpoint, "resources") {
-		pc.PermitClient.BaseURL = strings.Replace(pc.PermitClient.BaseURL, "facts", "schema", 1)
-	} else {
-		pc.PermitClient.BaseURL = strings.Replace(pc.PermitClient.BaseURL, "schema", "facts", 1)
-	}
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", pc.PermitClient.BaseURL, endpoint), body)
+// do sends a request to the given Permit API and decodes the JSON response into out.
+// A nil out discards the response body.
+func (pc *PermitServiceImpl) do(ctx context.Context, target api, method, endpoint string, payload, out interface{}) error {
+	jsonData, err := marshalPayload(payload)
 	if err != nil {
-		logger.LogError("Failed to create HTTP request", "error", err)
-		return nil, err
+		return err
 	}
 
-	// Log URL
-	logger.LogInfo("Permit request URL", "url", req.URL.String())
-
-	// Add headers
-	for key, value := range pc.PermitClient.Headers {
-		req.Header.Set(key, value)
-	}
-
-	// Send the request
-	resp, err := pc.PermitClient.Client.Do(req)
+	respBody, err := pc.sendRaw(ctx, target, method, endpoint, jsonData)
 	if err != nil {
-		logger.LogError("HTTP request failed", "error", err)
-		return nil, err
+		return err
 	}
-	defer func() {
-		err := resp.Body.Close()
-		if err != nil {
-			logger.LogError("Failed to close response body", "error", err)
-		}
-	}()
 
-	// Check response status
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		fmt.Println("response body ", string(body))
-		logger.LogError("error occurred when calling Permit API", "status", resp.StatusCode, "body", string(body))
-		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	if out == nil || len(respBody) == 0 {
+		return nil
 	}
 
-	// Parse response body
-	respBody, err := io.ReadAll(resp.Body)
-	fmt.Println("response body is ", string(respBody))
-	if err != nil {
-		logger.LogError("Failed to read response body", "error", err)
-		return nil, err
+	if err := json.Unmarshal(respBody, out); err != nil {
+		logger.LogError("Failed to decode permit response", "endpoint", endpoint, "error", err)
+		return fmt.Errorf("failed to decode permit response for %s: %w", endpoint, err)
 	}
+	return nil
+}
 
-	if len(respBody) == 0 {
-		logger.LogInfo("Empty response body got from permit API")
+// marshalPayload serializes a request payload to JSON, returning nil for a nil payload.
+func marshalPayload(payload interface{}) ([]byte, error) {
+	if payload == nil {
 		return nil, nil
 	}
-
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		logger.LogError("Failed to unmarshal response", "error", err)
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		logger.LogError("Failed to marshal payload", "error", err)
 		return nil, err
 	}
-
-	return result, nil
-}
-
-type PermitBinding struct {
-	Id             string `json:"id"`
-	User           string `json:"user"`
-	Role           string `json:"role"`
-	Tenant         string `json:"tenant"`
-	UserId         string `json:"user_id"`
-	RoleId         string `json:"role_id"`
-	TenantId       string `json:"tenant_id"`
-	OrganizationId string `json:"organization_id"`
-	ProjectId      string `json:"project_id"`
-	EnvironmentId  string `json:"environment_id"`
-	CreatedAt      string `json:"created_at"`
+	return jsonData, nil
 }
 
-func (pc *PermitServiceImpl) sendGetRequest(ctx context.Context, method, endpoint string) ([]map[string]interface{}, error) {
-	var response []map[string]interface{}
-
-	operation := func() error {
-		req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", pc.PermitClient.BaseURL, endpoint), nil)
-		if err != nil {
-			log.Printf("Failed to create HTTP request: %v", err)
-			return backoff.Permanent(err)
-		}
-
-		// Add headers
-		for key, value := range pc.PermitClient.Headers {
-			req.Header.Set(key, value)
-		}
-
-		// Send the request
-		resp, err := pc.PermitClient.Client.Do(req)
-		if err != nil {
-			log.Printf("HTTP request failed: %v", err)
-			return err
-		}
-		defer func() {
-			err := resp.Body.Close()
-			if err != nil {
-				logger.LogError("Failed to close response body", "error", err)
-			}
-		}()
-
-		// Check response status
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			body, _ := io.ReadAll(resp.Body)
-			log.Printf("HTTP error: %d - %s", resp.StatusCode, string(body))
-			return fmt.Errorf("HTTP error: %d", resp.StatusCode)
-		}
-
-		// Parse response body
-		respBody, err := io.ReadAll(resp.Body)
-		if err != nil {
-			log.Printf("Failed to read response body: %v", err)
-			return backoff.Permanent(err)
-		}
+// sharedRequestTimeout bounds a GET request shared by concurrent callers,
+// which none of their contexts can cancel.
+var sharedRequestTimeout = time.Minute
+
+// sendRaw sends an HTTP request to the given Permit API and returns the raw response body.
+// POST and PATCH requests carry an idempotency key, so that they are retried.
+// Concurrent identical GET requests share one upstream request, so the returned
+// body must not be modified.
+func (pc *PermitServiceImpl) sendRaw(ctx context.Context, target api, method, endpoint string, payload []byte) ([]byte, error) {
+	if method != http.MethodGet {
+		return pc.send(withRequestIdempotencyKey(ctx, target, method, endpoint, payload), target, method, endpoint, payload)
+	}
 
-		if method == "GET" && len(respBody) == 0 {
-			log.Printf("Empty response body")
-			return nil
+	// Neither the cancellation nor the deadline of the first caller may fail the
+	// callers that joined it, so the shared request keeps only the first
+	// caller's values and is bounded by sharedRequestTimeout instead. Each
+	// caller still stops waiting when its own context is done.
+	key := fmt.Sprintf("%d %s", target, endpoint)
+	leader := false
+	result := pc.inflight.DoChan(key, func() (interface{}, error) {
+		leader = true
+		shared, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedRequestTimeout)
+		defer cancel()
+		return pc.send(shared, target, method, endpoint, payload)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if !leader {
+			pc.retries.coalesced.Add(1)
 		}
-
-		if err := json.Unmarshal(respBody, &response); err != nil {
-			log.Printf("Failed to unmarshal response: %v", err)
-			return backoff.Permanent(err)
+		if res.Err != nil {
+			return nil, res.Err
 		}
-
-		return nil
+		return res.Val.([]byte), nil
 	}
+}
 
-	// Use exponential backoff for retries
-	bo := backoff.NewExponentialBackOff()
-	bo.MaxElapsedTime = 30 * time.Second
-
-	if err := backoff.Retry(operation, bo); err != nil {
-		log.Printf("Request failed after retries: %v", err)
-		return response, err
+// send sends an HTTP request to the given Permit API unless the client's
+// circuit breaker is open, in which case it fails with ErrCircuitOpen at once.
+func (pc *PermitServiceImpl) send(ctx context.Context, target api, method, endpoint string, payload []byte) ([]byte, error) {
+	breaker := pc.PermitClient.Breaker
+	if err := breaker.allow(); err != nil {
+		logger.LogWarn("Not sending Permit request while the circuit breaker is open", "method", method, "endpoint", endpoint)
+		return nil, err
 	}
-
-	return response, nil
+	respBody, err := pc.sendWithRetry(ctx, target, method, endpoint, payload)
+	breaker.record(err)
+	return respBody, err
 }
 
-func (pc *PermitServiceImpl) fetchSingleResource(ctx context.Context, method, endpoint string) (map[string]interface{}, error) {
-	var response map[string]interface{}
+// sendWithRetry sends an HTTP request to the given Permit API, waiting for the
+// client-side rate limiter before every attempt. Transient failures are retried
+// according to the client's retry policy.
+func (pc *PermitServiceImpl) sendWithRetry(ctx context.Context, target api, method, endpoint string, payload []byte) ([]byte, error) {
+	policy := pc.PermitClient.retryPolicy()
+	retryable := canRetryMethod(ctx, method)
+	bo := policy.newBackOff()
+	pc.retries.requests.Add(1)
+
+	attempt := 1
+	for ; ; attempt++ {
+		if err := pc.limiter.wait(ctx, target, method); err != nil {
+			logger.LogError("Permit request not sent", "method", method, "endpoint", endpoint, "attempt", attempt, "error", err)
+			pc.retries.record(attempt, err)
+			return nil, err
+		}
 
-	operation := func() error {
-		req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", pc.PermitClient.BaseURL, endpoint), nil)
-		if err != nil {
-			log.Printf("Failed to create HTTP request: %v", err)
-			return backoff.Permanent(err)
+		respBody, status, header, err := pc.sendOnce(ctx, target, method, endpoint, payload)
+		if err == nil && status >= 200 && status < 300 {
+			pc.retries.record(attempt, nil)
+			return respBody, nil
 		}
 
-		// Add headers
-		for key, value := range pc.PermitClient.Headers {
-			req.Header.Set(key, value)
+		transient := isTransient(status, err)
+		if err == nil {
+			apiErr := newPermitAPIError(status, header, respBody)
+			logger.LogError("error occurred when calling Permit API", "status", status, "code", apiErr.Code, "requestId", apiErr.RequestID, "message", apiErr.Message)
+			err = apiErr
+		}
+		if !retryable || !transient || attempt >= policy.MaxAttempts {
+			pc.retries.record(attempt, err)
+			return nil, err
 		}
 
-		// Send the request
-		resp, err := pc.PermitClient.Client.Do(req)
-		if err != nil {
-			log.Printf("HTTP request failed: %v", err)
-			return err
+		delay := bo.NextBackOff()
+		if delay == backoff.Stop {
+			pc.retries.record(attempt, err)
+			return nil, err
 		}
-		defer func() {
-			err := resp.Body.Close()
-			if err != nil {
-				logger.LogError("Failed to close response body", "error", err)
+		if retryAfter, ok := parseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+			if policy.MaxElapsedTime > 0 && retryAfter > policy.MaxElapsedTime {
+				pc.retries.record(attempt, err)
+				return nil, err
 			}
-		}()
-
-		// Check response status
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			body, _ := io.ReadAll(resp.Body)
-			log.Printf("HTTP error: %d - %s", resp.StatusCode, string(body))
-			return fmt.Errorf("HTTP error: %d", resp.StatusCode)
+			delay = retryAfter
 		}
-
-		// Parse response body
-		respBody, err := io.ReadAll(resp.Body)
-		if err != nil {
-			log.Printf("Failed to read response body: %v", err)
-			return backoff.Permanent(err)
+		if exceedsDeadline(ctx, delay) {
+			logger.LogError("Not retrying Permit request past context deadline", "endpoint", endpoint, "attempt", attempt)
+			pc.retries.record(attempt, err)
+			return nil, err
 		}
 
-		if method == "GET" && len(respBody) == 0 {
-			log.Printf("Empty response body")
-			return nil
-		}
+		logger.LogInfo("Retrying Permit request", "method", method, "endpoint", endpoint, "attempt", attempt, "delay", delay.String(), "error", err)
+		pc.retries.retries.Add(1)
 
-		if err := json.Unmarshal(respBody, &response); err != nil {
-			log.Printf("Failed to unmarshal response: %v", err)
-			return backoff.Permanent(err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			pc.retries.record(attempt, err)
+			return nil, err
+		case <-timer.C:
 		}
-
-		return nil
 	}
+}
 
-	// Use exponential backoff for retries
-	bo := backoff.NewExponentialBackOff()
-	bo.MaxElapsedTime = 30 * time.Second
+// sendOnce performs a single HTTP round trip. A non-nil error means the request
+// could not be completed; otherwise the response status, headers and body are returned.
+func (pc *PermitServiceImpl) sendOnce(ctx context.Context, target api, method, endpoint string, payload []byte) ([]byte, int, http.Header, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
 
-	if err := backoff.Retry(operation, bo); err != nil {
-		log.Printf("Request failed after retries: %v", err)
-		return response, err
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", pc.PermitClient.baseURL(target), endpoint), body)
+	if err != nil {
+		logger.LogError("Failed to create HTTP request", "error", err)
+		return nil, 0, nil, backoff.Permanent(err)
 	}
 
-	return response, nil
-}
+	// Log URL
+	logger.LogInfo("Permit request URL", "url", req.URL.String())
 
-func (pc *PermitServiceImpl) APIExecute(ctx context.Context, method, endpoint string, payload interface{}) (interface{}, error) {
-	return pc.SendRequest(ctx, method, endpoint, payload)
-}
+	// Add headers
+	for key, value := range pc.PermitClient.Headers {
+		req.Header.Set(key, value)
+	}
+	if key := idempotencyKey(ctx); key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
 
-func (pc *PermitServiceImpl) ExecuteGetAPI(ctx context.Context, method, endpoint string) ([]map[string]interface{}, error) {
-	return pc.sendGetRequest(ctx, method, endpoint)
-}
+	// Send the request
+	resp, err := pc.PermitClient.Client.Do(req)
+	if err != nil {
+		logger.LogError("HTTP request failed", "error", err)
+		return nil, 0, nil, err
+	}
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			logger.LogError("Failed to close response body", "error", err)
+		}
+	}()
+
+	// Read response body
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.LogError("Failed to read response body", "error", err)
+		return nil, 0, nil, err
+	}
 
-func (pc *PermitServiceImpl) GetSingleResource(ctx context.Context, method, endpoint string) (map[string]interface{}, error) {
-	return pc.fetchSingleResource(ctx, method, endpoint)
+	return respBody, resp.StatusCode, resp.Header, nil
 }
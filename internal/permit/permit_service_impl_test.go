@@ -0,0 +1,134 @@
+package permit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService(handler http.HandlerFunc) (*PermitServiceImpl, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	svc := &PermitServiceImpl{
+		PermitClient: &PermitClient{
+			FactsURL:  server.URL + "/v2/facts/project/env",
+			SchemaURL: server.URL + "/v2/schema/project/env",
+			Headers:   map[string]string{"Content-Type": "application/json"},
+			Client:    server.Client(),
+			Retry: &RetryPolicy{
+				MaxAttempts:     3,
+				InitialInterval: time.Millisecond,
+				MaxInterval:     5 * time.Millisecond,
+				MaxElapsedTime:  time.Second,
+			},
+		},
+	}
+	return svc, server
+}
+
+func TestGetTenant(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v2/facts/project/env/tenants/abc", r.URL.Path)
+			_, _ = w.Write([]byte(`{"key":"abc","name":"Tenant","attributes":{"name":"Tenant"}}`))
+		})
+		defer server.Close()
+
+		tenant, err := svc.GetTenant(context.Background(), "abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", tenant.Key)
+		assert.Equal(t, "Tenant", tenant.Name)
+		assert.Equal(t, "Tenant", tenant.Attributes["name"])
+	})
+
+	t.Run("Malformed body returns error", func(t *testing.T) {
+		svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"key": 42, "attributes": "not a map"}`))
+		})
+		defer server.Close()
+
+		tenant, err := svc.GetTenant(context.Background(), "abc")
+
+		assert.Error(t, err)
+		assert.Nil(t, tenant)
+		assert.Contains(t, err.Error(), "failed to decode permit response")
+	})
+
+	t.Run("Non-2xx status returns error", func(t *testing.T) {
+		svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer server.Close()
+
+		tenant, err := svc.GetTenant(context.Background(), "abc")
+
+		assert.Error(t, err)
+		assert.Nil(t, tenant)
+	})
+}
+
+func TestListResourceInstances(t *testing.T) {
+	svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/facts/project/env/resource_instances/detailed", r.URL.Path)
+		assert.Equal(t, "t1", r.URL.Query().Get("tenant"))
+		assert.Equal(t, "account", r.URL.Query().Get("resource"))
+		_, _ = w.Write([]byte(`{"data":[{"key":"a1","tenant":"t1","resource":"account"}],"total_count":1}`))
+	})
+	defer server.Close()
+
+	instances, total, err := svc.ListResourceInstances(context.Background(), "t1", "account")
+
+	assert.NoError(t, err)
+	assert.Len(t, instances, 1)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "a1", instances[0].Key)
+	assert.Equal(t, "account", instances[0].Resource)
+}
+
+func TestConcurrentFactsAndSchemaRouting(t *testing.T) {
+	svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/facts/project/env/tenants/"):
+			_, _ = w.Write([]byte(`{"key":"abc","attributes":{}}`))
+		case r.URL.Path == "/v2/schema/project/env/resources":
+			_, _ = w.Write([]byte(`{"data":[{"key":"account","name":"Account"}],"total_count":1}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	defer server.Close()
+
+	const workers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*2)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.GetTenant(context.Background(), "abc"); err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, _, err := svc.ListResources(context.Background()); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+	assert.Equal(t, server.URL+"/v2/facts/project/env", svc.PermitClient.FactsURL)
+	assert.Equal(t, server.URL+"/v2/schema/project/env", svc.PermitClient.SchemaURL)
+}
@@ -0,0 +1,335 @@
+package permittest
+
+import (
+	"net/http"
+	"strings"
+
+	"iam_services_main_v1/internal/permit"
+
+	"github.com/google/uuid"
+)
+
+func (s *Server) listTenants(w http.ResponseWriter, r *http.Request) {
+	search := strings.ToLower(r.URL.Query().Get("search"))
+	s.mu.Lock()
+	var tenants []permit.Tenant
+	for _, tenant := range sortedValues(s.tenants) {
+		if strings.Contains(strings.ToLower(tenant.Name), search) || strings.Contains(strings.ToLower(tenant.Key), search) {
+			tenants = append(tenants, tenant)
+		}
+	}
+	s.mu.Unlock()
+	writePage(w, r, tenants)
+}
+
+func (s *Server) getTenant(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	tenant, ok := s.tenants[r.PathValue("key")]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "tenant not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, tenant)
+}
+
+func (s *Server) createTenant(w http.ResponseWriter, r *http.Request) {
+	var input permit.TenantCreate
+	if !decode(w, r, &input) {
+		return
+	}
+	if input.Key == "" {
+		writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "key is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.tenants[input.Key]; exists {
+		writeError(w, http.StatusConflict, "DUPLICATE_ENTITY", "tenant already exists")
+		return
+	}
+	tenant := permit.Tenant{
+		Entity:      permit.Entity{Key: input.Key, Attributes: input.Attributes},
+		Name:        input.Name,
+		Description: input.Description,
+	}
+	stamp(&tenant.Entity)
+	s.tenants[tenant.Key] = tenant
+	writeJSON(w, http.StatusOK, tenant)
+}
+
+func (s *Server) updateTenant(w http.ResponseWriter, r *http.Request) {
+	var input permit.TenantUpdate
+	if !decode(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tenant, ok := s.tenants[r.PathValue("key")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "tenant not found")
+		return
+	}
+	if input.Name != nil {
+		tenant.Name = *input.Name
+	}
+	if input.Description != nil {
+		tenant.Description = *input.Description
+	}
+	if input.Attributes != nil {
+		tenant.Attributes = input.Attributes
+	}
+	touch(&tenant.Entity)
+	s.tenants[tenant.Key] = tenant
+	writeJSON(w, http.StatusOK, tenant)
+}
+
+func (s *Server) deleteTenant(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := r.PathValue("key")
+	if _, ok := s.tenants[key]; !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "tenant not found")
+		return
+	}
+	delete(s.tenants, key)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getUser returns a user with its associated tenants derived from its role assignments.
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[r.PathValue("key")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+
+	user.AssociatedTenants = nil
+	index := map[string]int{}
+	for _, assignment := range s.assignments {
+		if assignment.User != user.Key {
+			continue
+		}
+		i, seen := index[assignment.Tenant]
+		if !seen {
+			i = len(user.AssociatedTenants)
+			index[assignment.Tenant] = i
+			user.AssociatedTenants = append(user.AssociatedTenants, permit.UserTenant{Tenant: assignment.Tenant, Status: "active"})
+		}
+		user.AssociatedTenants[i].Roles = append(user.AssociatedTenants[i].Roles, assignment.Role)
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+// listResourceInstances answers both resource_instances and resource_instances/detailed,
+// filtered by the tenant and resource query parameters.
+func (s *Server) listResourceInstances(w http.ResponseWriter, r *http.Request) {
+	tenant := r.URL.Query().Get("tenant")
+	resource := r.URL.Query().Get("resource")
+
+	s.mu.Lock()
+	var instances []permit.ResourceInstance
+	for _, instance := range sortedValues(s.instances) {
+		if (tenant == "" || instance.Tenant == tenant) && (resource == "" || instance.Resource == resource) {
+			instances = append(instances, instance)
+		}
+	}
+	s.mu.Unlock()
+	writePage(w, r, instances)
+}
+
+func (s *Server) getResourceInstance(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	instance, ok := s.instances[r.PathValue("key")]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource instance not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, instance)
+}
+
+func (s *Server) createResourceInstance(w http.ResponseWriter, r *http.Request) {
+	var input permit.ResourceInstanceCreate
+	if !decode(w, r, &input) {
+		return
+	}
+	if input.Key == "" || input.Resource == "" {
+		writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "key and resource are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.instances[input.Key]; exists {
+		writeError(w, http.StatusConflict, "DUPLICATE_ENTITY", "resource instance already exists")
+		return
+	}
+	instance := permit.ResourceInstance{
+		Entity:   permit.Entity{Key: input.Key, Attributes: input.Attributes},
+		Tenant:   input.Tenant,
+		Resource: input.Resource,
+	}
+	stamp(&instance.Entity)
+	s.instances[instance.Key] = instance
+	writeJSON(w, http.StatusOK, instance)
+}
+
+func (s *Server) updateResourceInstance(w http.ResponseWriter, r *http.Request) {
+	var input permit.ResourceInstanceUpdate
+	if !decode(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	instance, ok := s.instances[r.PathValue("key")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource instance not found")
+		return
+	}
+	instance.Attributes = input.Attributes
+	touch(&instance.Entity)
+	s.instances[instance.Key] = instance
+	writeJSON(w, http.StatusOK, instance)
+}
+
+func (s *Server) deleteResourceInstance(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := r.PathValue("key")
+	if _, ok := s.instances[key]; !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource instance not found")
+		return
+	}
+	delete(s.instances, key)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listRoleAssignments filters by the user, tenant, role and resource_instance query parameters.
+func (s *Server) listRoleAssignments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	s.mu.Lock()
+	var assignments []permit.RoleAssignment
+	for _, assignment := range s.assignments {
+		if matches(query.Get("user"), assignment.User) &&
+			matches(query.Get("tenant"), assignment.Tenant) &&
+			matches(query.Get("role"), assignment.Role) &&
+			matches(query.Get("resource_instance"), assignment.ResourceInstance) {
+			assignments = append(assignments, assignment)
+		}
+	}
+	s.mu.Unlock()
+	writePage(w, r, assignments)
+}
+
+func (s *Server) assignRole(w http.ResponseWriter, r *http.Request) {
+	var input permit.RoleAssignmentCreate
+	if !decode(w, r, &input) {
+		return
+	}
+	if input.User == "" || input.Role == "" || input.Tenant == "" {
+		writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "user, role and tenant are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findAssignment(input) >= 0 {
+		writeError(w, http.StatusConflict, "DUPLICATE_ENTITY", "role is already assigned")
+		return
+	}
+	assignment := permit.RoleAssignment{
+		ID:               uuid.NewString(),
+		User:             input.User,
+		Role:             input.Role,
+		Tenant:           input.Tenant,
+		ResourceInstance: input.ResourceInstance,
+	}
+	s.assignments = append(s.assignments, assignment)
+	writeJSON(w, http.StatusOK, assignment)
+}
+
+func (s *Server) unassignRole(w http.ResponseWriter, r *http.Request) {
+	var input permit.RoleAssignmentCreate
+	if !decode(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.findAssignment(input)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "role assignment not found")
+		return
+	}
+	s.assignments = append(s.assignments[:i], s.assignments[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findAssignment returns the index of the assignment matching input, or -1.
+func (s *Server) findAssignment(input permit.RoleAssignmentCreate) int {
+	for i, assignment := range s.assignments {
+		if assignment.User == input.User && assignment.Role == input.Role &&
+			assignment.Tenant == input.Tenant && assignment.ResourceInstance == input.ResourceInstance {
+			return i
+		}
+	}
+	return -1
+}
+
+// listRelationshipTuples filters by the tenant, subject, relation and object query parameters.
+func (s *Server) listRelationshipTuples(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	s.mu.Lock()
+	var tuples []permit.RelationshipTuple
+	for _, tuple := range s.tuples {
+		if matches(query.Get("tenant"), tuple.Tenant) &&
+			matches(query.Get("subject"), tuple.Subject) &&
+			matches(query.Get("relation"), tuple.Relation) &&
+			matches(query.Get("object"), tuple.Object) {
+			tuples = append(tuples, tuple)
+		}
+	}
+	s.mu.Unlock()
+	writePage(w, r, tuples)
+}
+
+func (s *Server) createRelationshipTuple(w http.ResponseWriter, r *http.Request) {
+	var input permit.RelationshipTupleCreate
+	if !decode(w, r, &input) {
+		return
+	}
+	if input.Subject == "" || input.Relation == "" || input.Object == "" {
+		writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "subject, relation and object are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tuple := range s.tuples {
+		if tuple.Subject == input.Subject && tuple.Relation == input.Relation &&
+			tuple.Object == input.Object && tuple.Tenant == input.Tenant {
+			writeError(w, http.StatusConflict, "DUPLICATE_ENTITY", "relationship tuple already exists")
+			return
+		}
+	}
+	tuple := permit.RelationshipTuple{
+		ID:       uuid.NewString(),
+		Subject:  input.Subject,
+		Relation: input.Relation,
+		Object:   input.Object,
+		Tenant:   input.Tenant,
+	}
+	s.tuples = append(s.tuples, tuple)
+	writeJSON(w, http.StatusOK, tuple)
+}
+
+// matches reports whether value satisfies an optional equality filter.
+func matches(filter, value string) bool {
+	return filter == "" || filter == value
+}
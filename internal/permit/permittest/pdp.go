@@ -0,0 +1,137 @@
+package permittest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/permitio/permit-golang/pkg/enforcement"
+)
+
+// checkResponse is the PDP answer to a single permission check.
+type checkResponse struct {
+	Allow bool `json:"allow"`
+}
+
+// allowed answers the PDP /allowed endpoint used by the Permit SDK's Check.
+func (s *Server) allowed(w http.ResponseWriter, r *http.Request) {
+	var req enforcement.CheckRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	allow := s.decide(req)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, checkResponse{Allow: allow})
+}
+
+// allowedBulk answers the PDP /allowed/bulk endpoint used by the Permit SDK's BulkCheck.
+func (s *Server) allowedBulk(w http.ResponseWriter, r *http.Request) {
+	var reqs []enforcement.CheckRequest
+	if !decode(w, r, &reqs) {
+		return
+	}
+
+	s.mu.Lock()
+	results := make([]checkResponse, len(reqs))
+	for i, req := range reqs {
+		results[i].Allow = s.decide(req)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{"allow": results})
+}
+
+// userPermissions answers the PDP /user-permissions endpoint used by the Permit
+// SDK's GetUserPermissions. Tenant-wide roles are reported under
+// "__tenant:<tenant>" and instance roles under "<type>:<key>", with permissions
+// in the "<type>:<action>" form.
+func (s *Server) userPermissions(w http.ResponseWriter, r *http.Request) {
+	var req enforcement.GetUserPermissionsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	result := enforcement.UserPermissions{}
+	for _, assignment := range s.assignments {
+		if assignment.User != req.User.Key || (len(req.Tenants) > 0 && !contains(req.Tenants, assignment.Tenant)) {
+			continue
+		}
+		key := "__tenant:" + assignment.Tenant
+		var resource *enforcement.ResourceDetails
+		if assignment.ResourceInstance != "" {
+			key = assignment.ResourceInstance
+			resourceType, resourceKey, _ := strings.Cut(assignment.ResourceInstance, ":")
+			resource = &enforcement.ResourceDetails{Type: resourceType, Key: resourceKey}
+		}
+		granted := result[key]
+		granted.Tenant = enforcement.TenantDetails{Key: assignment.Tenant}
+		granted.Resource = resource
+		granted.Roles = append(granted.Roles, assignment.Role)
+		for resourceKey, res := range s.resources {
+			if len(req.ResourceTypes) > 0 && !contains(req.ResourceTypes, resourceKey) {
+				continue
+			}
+			if resource != nil && resource.Type != resourceKey {
+				continue
+			}
+			role, ok := res.Roles[assignment.Role]
+			if !ok {
+				continue
+			}
+			for _, permission := range role.Permissions {
+				if !strings.Contains(permission, ":") {
+					permission = resourceKey + ":" + permission
+				}
+				granted.Permissions = append(granted.Permissions, permission)
+			}
+		}
+		result[key] = granted
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, result)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// decide grants a check when the user holds, in the resource's tenant, a role of
+// the resource type whose permissions include the action. Assignments scoped to
+// a resource instance only apply to that instance. The caller must hold s.mu.
+func (s *Server) decide(req enforcement.CheckRequest) bool {
+	tenant := req.Resource.Tenant
+	if tenant == "" {
+		tenant = enforcement.DefaultTenant
+	}
+	resourceKey, ok := s.findResource(req.Resource.Type)
+	if !ok {
+		return false
+	}
+	resource := s.resources[resourceKey]
+	action := string(req.Action)
+
+	for _, assignment := range s.assignments {
+		if assignment.User != req.User.Key || assignment.Tenant != tenant {
+			continue
+		}
+		if assignment.ResourceInstance != "" && assignment.ResourceInstance != req.Resource.Type+":"+req.Resource.Key {
+			continue
+		}
+		role, ok := resource.Roles[assignment.Role]
+		if !ok {
+			continue
+		}
+		for _, permission := range role.Permissions {
+			if permission == action || permission == resource.Key+":"+action {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,306 @@
+package permittest
+
+import (
+	"net/http"
+	"time"
+
+	"iam_services_main_v1/internal/permit"
+
+	"github.com/google/uuid"
+)
+
+// findResource returns the key of the resource type with the given key or ID.
+// The caller must hold s.mu.
+func (s *Server) findResource(ref string) (string, bool) {
+	if _, ok := s.resources[ref]; ok {
+		return ref, true
+	}
+	for key, resource := range s.resources {
+		if resource.ID == ref {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func (s *Server) listResources(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	resources := sortedValues(s.resources)
+	for i := range resources {
+		resources[i] = cloneResource(resources[i])
+	}
+	s.mu.Unlock()
+	writePage(w, r, resources)
+}
+
+func (s *Server) getResource(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.findResource(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.resources[key])
+}
+
+// cloneResource copies the action and role maps of a resource so it can be
+// encoded outside the lock.
+func cloneResource(resource permit.Resource) permit.Resource {
+	actions := make(map[string]permit.Action, len(resource.Actions))
+	for key, action := range resource.Actions {
+		actions[key] = action
+	}
+	roles := make(map[string]permit.Role, len(resource.Roles))
+	for key, role := range resource.Roles {
+		roles[key] = role
+	}
+	resource.Actions = actions
+	resource.Roles = roles
+	return resource
+}
+
+func (s *Server) createResource(w http.ResponseWriter, r *http.Request) {
+	var input permit.ResourceCreate
+	if !decode(w, r, &input) {
+		return
+	}
+	if input.Key == "" {
+		writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "key is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.resources[input.Key]; exists {
+		writeError(w, http.StatusConflict, "DUPLICATE_ENTITY", "resource already exists")
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	resource := permit.Resource{
+		ID:          uuid.NewString(),
+		Key:         input.Key,
+		Name:        input.Name,
+		Description: input.Description,
+		Actions:     map[string]permit.Action{},
+		Roles:       map[string]permit.Role{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for key, action := range input.Actions {
+		action.Key = key
+		if action.ID == "" {
+			action.ID = uuid.NewString()
+		}
+		resource.Actions[key] = action
+	}
+	s.resources[resource.Key] = resource
+	writeJSON(w, http.StatusOK, resource)
+}
+
+func (s *Server) updateResource(w http.ResponseWriter, r *http.Request) {
+	var input permit.ResourceUpdate
+	if !decode(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.findResource(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		return
+	}
+	resource := s.resources[key]
+	if input.Name != "" {
+		resource.Name = input.Name
+	}
+	if input.Description != "" {
+		resource.Description = input.Description
+	}
+	resource.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	s.resources[key] = resource
+	writeJSON(w, http.StatusOK, resource)
+}
+
+func (s *Server) createResourceAction(w http.ResponseWriter, r *http.Request) {
+	var input permit.ActionCreate
+	if !decode(w, r, &input) {
+		return
+	}
+	if input.Key == "" {
+		writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "key is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.findResource(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		return
+	}
+	resource := s.resources[key]
+	if _, exists := resource.Actions[input.Key]; exists {
+		writeError(w, http.StatusConflict, "DUPLICATE_ENTITY", "action already exists")
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	action := permit.Action{
+		ID:          uuid.NewString(),
+		Key:         input.Key,
+		Name:        input.Name,
+		Description: input.Description,
+		Attributes:  input.Attributes,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	resource.Actions[action.Key] = action
+	writeJSON(w, http.StatusOK, action)
+}
+
+func (s *Server) listResourceRelations(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	key, ok := s.findResource(r.PathValue("key"))
+	relations := sortedValues(s.relations[key])
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		return
+	}
+	writePage(w, r, relations)
+}
+
+func (s *Server) createResourceRelation(w http.ResponseWriter, r *http.Request) {
+	var input permit.RelationCreate
+	if !decode(w, r, &input) {
+		return
+	}
+	if input.Key == "" || input.SubjectResource == "" {
+		writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "key and subject_resource are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.findResource(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		return
+	}
+	subject, ok := s.findResource(input.SubjectResource)
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "subject resource not found")
+		return
+	}
+	if _, exists := s.relations[key][input.Key]; exists {
+		writeError(w, http.StatusConflict, "DUPLICATE_ENTITY", "relation already exists")
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	relation := permit.Relation{
+		ID:              uuid.NewString(),
+		Key:             input.Key,
+		Name:            input.Name,
+		Description:     input.Description,
+		SubjectResource: subject,
+		ObjectResource:  key,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if s.relations[key] == nil {
+		s.relations[key] = map[string]permit.Relation{}
+	}
+	s.relations[key][relation.Key] = relation
+	writeJSON(w, http.StatusOK, relation)
+}
+
+func (s *Server) createResourceRole(w http.ResponseWriter, r *http.Request) {
+	var input permit.RoleCreate
+	if !decode(w, r, &input) {
+		return
+	}
+	if input.Key == "" {
+		writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "key is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.findResource(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		return
+	}
+	resource := s.resources[key]
+	if _, exists := resource.Roles[input.Key]; exists {
+		writeError(w, http.StatusConflict, "DUPLICATE_ENTITY", "role already exists")
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	role := permit.Role{
+		ID:          uuid.NewString(),
+		Key:         input.Key,
+		Name:        input.Name,
+		Description: input.Description,
+		Permissions: input.Permissions,
+		Attributes:  input.Attributes,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	resource.Roles[role.Key] = role
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (s *Server) updateResourceRole(w http.ResponseWriter, r *http.Request) {
+	var input permit.RoleUpdate
+	if !decode(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.findResource(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		return
+	}
+	resource := s.resources[key]
+	role, ok := resource.Roles[r.PathValue("role")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "role not found")
+		return
+	}
+	if input.Name != "" {
+		role.Name = input.Name
+	}
+	if input.Description != "" {
+		role.Description = input.Description
+	}
+	if input.Permissions != nil {
+		role.Permissions = input.Permissions
+	}
+	if input.Attributes != nil {
+		role.Attributes = input.Attributes
+	}
+	role.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	resource.Roles[role.Key] = role
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (s *Server) deleteResourceRole(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.findResource(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		return
+	}
+	resource := s.resources[key]
+	if _, ok := resource.Roles[r.PathValue("role")]; !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "role not found")
+		return
+	}
+	delete(resource.Roles, r.PathValue("role"))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,443 @@
+// Package permittest provides an in-process stand-in for the parts of the Permit
+// API used by this service: the facts API, the schema API and the PDP.
+//
+// State is kept in memory and starts empty; seed it with the Add* methods or
+// through the API itself. The fake checks keys for uniqueness and the bearer
+// token on every request, but it does not enforce referential integrity
+// between tenants, resources and instances.
+package permittest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"iam_services_main_v1/internal/permit"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Project is the project ID the fake serves.
+	Project = "permittest-project"
+	// Environment is the environment ID the fake serves.
+	Environment = "permittest-env"
+	// Token is the API key the fake expects as a bearer token.
+	Token = "permittest-token"
+)
+
+// Request is a request received by the fake.
+type Request struct {
+	Method string
+	Path   string
+}
+
+// Server is an in-memory Permit API. The zero value is not usable; create one
+// with New or NewServer.
+type Server struct {
+	// URL is the base URL of the fake, without a trailing slash. NewServer sets
+	// it; callers serving the fake themselves must set it before calling Env
+	// or Client.
+	URL string
+
+	mux *http.ServeMux
+	srv *httptest.Server
+
+	mu          sync.Mutex
+	tenants     map[string]permit.Tenant
+	users       map[string]permit.User
+	instances   map[string]permit.ResourceInstance
+	resources   map[string]permit.Resource
+	relations   map[string]map[string]permit.Relation
+	assignments []permit.RoleAssignment
+	tuples      []permit.RelationshipTuple
+	requests    []Request
+	failures    int
+	failStatus  int
+}
+
+// New returns a fake that is not listening yet. It implements http.Handler.
+func New() *Server {
+	s := &Server{
+		mux:       http.NewServeMux(),
+		tenants:   map[string]permit.Tenant{},
+		users:     map[string]permit.User{},
+		instances: map[string]permit.ResourceInstance{},
+		resources: map[string]permit.Resource{},
+		relations: map[string]map[string]permit.Relation{},
+	}
+	s.routes()
+	return s
+}
+
+// NewServer starts a fake on a local port. Call Close when done.
+func NewServer() *Server {
+	s := New()
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down a server started by NewServer.
+func (s *Server) Close() {
+	if s.srv != nil {
+		s.srv.Close()
+	}
+}
+
+// FactsURL returns the facts API base URL of the fake.
+func (s *Server) FactsURL() string {
+	return fmt.Sprintf("%s/v2/facts/%s/%s", s.URL, Project, Environment)
+}
+
+// SchemaURL returns the schema API base URL of the fake.
+func (s *Server) SchemaURL() string {
+	return fmt.Sprintf("%s/v2/schema/%s/%s", s.URL, Project, Environment)
+}
+
+// Env returns the environment variables that point NewPermitClient and
+// initPermitSdkService at the fake.
+func (s *Server) Env() map[string]string {
+	return map[string]string{
+		"PERMIT_PDP_ENDPOINT": s.URL,
+		"PERMIT_PDP_URL":      s.URL,
+		"PERMIT_PROJECT":      Project,
+		"PERMIT_ENV":          Environment,
+		"PERMIT_TOKEN":        Token,
+	}
+}
+
+// Client returns a Permit client configured for the fake, with retries disabled.
+func (s *Server) Client() *permit.PermitClient {
+	return &permit.PermitClient{
+		FactsURL:  s.FactsURL(),
+		SchemaURL: s.SchemaURL(),
+		Headers: map[string]string{
+			"Authorization": "Bearer " + Token,
+			"Content-Type":  "application/json",
+		},
+		Client: &http.Client{Timeout: 10 * time.Second},
+		Retry:  &permit.RetryPolicy{MaxAttempts: 1},
+	}
+}
+
+// FailNext makes the next count requests fail with status and a Permit error document.
+func (s *Server) FailNext(count, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = count
+	s.failStatus = status
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests clears the request log.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// AddTenant stores a tenant as if it had been created through the API.
+func (s *Server) AddTenant(tenant permit.Tenant) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stamp(&tenant.Entity)
+	s.tenants[tenant.Key] = tenant
+}
+
+// AddUser stores a user. Its associated tenants are derived from role assignments.
+func (s *Server) AddUser(user permit.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stamp(&user.Entity)
+	s.users[user.Key] = user
+}
+
+// AddResourceInstance stores a resource instance.
+func (s *Server) AddResourceInstance(instance permit.ResourceInstance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stamp(&instance.Entity)
+	s.instances[instance.Key] = instance
+}
+
+// AddResource stores a resource type together with its actions and roles.
+func (s *Server) AddResource(resource permit.Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if resource.ID == "" {
+		resource.ID = uuid.NewString()
+	}
+	s.resources[resource.Key] = cloneResource(resource)
+}
+
+// AddRelation stores a relation on the resource type with the given key.
+func (s *Server) AddRelation(resourceKey string, relation permit.Relation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if relation.ID == "" {
+		relation.ID = uuid.NewString()
+	}
+	relation.ObjectResource = resourceKey
+	if s.relations[resourceKey] == nil {
+		s.relations[resourceKey] = map[string]permit.Relation{}
+	}
+	s.relations[resourceKey][relation.Key] = relation
+}
+
+// AddRoleAssignment grants a role to a user.
+func (s *Server) AddRoleAssignment(assignment permit.RoleAssignment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if assignment.ID == "" {
+		assignment.ID = uuid.NewString()
+	}
+	s.assignments = append(s.assignments, assignment)
+}
+
+// AddRelationshipTuple stores a relationship tuple.
+func (s *Server) AddRelationshipTuple(tuple permit.RelationshipTuple) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tuple.ID == "" {
+		tuple.ID = uuid.NewString()
+	}
+	s.tuples = append(s.tuples, tuple)
+}
+
+// Tenant returns the stored tenant with the given key.
+func (s *Server) Tenant(key string) (permit.Tenant, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tenant, ok := s.tenants[key]
+	return tenant, ok
+}
+
+// ResourceInstance returns the stored resource instance with the given key.
+func (s *Server) ResourceInstance(key string) (permit.ResourceInstance, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	instance, ok := s.instances[key]
+	return instance, ok
+}
+
+// Resource returns the stored resource type with the given key.
+func (s *Server) Resource(key string) (permit.Resource, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resource, ok := s.resources[key]
+	return cloneResource(resource), ok
+}
+
+// Relations returns the stored relations of the resource type with the given key.
+func (s *Server) Relations(resourceKey string) []permit.Relation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.relations[resourceKey])
+}
+
+// RoleAssignments returns the stored role assignments.
+func (s *Server) RoleAssignments() []permit.RoleAssignment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]permit.RoleAssignment(nil), s.assignments...)
+}
+
+// RelationshipTuples returns the stored relationship tuples.
+func (s *Server) RelationshipTuples() []permit.RelationshipTuple {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]permit.RelationshipTuple(nil), s.tuples...)
+}
+
+// ServeHTTP records the request, applies injected failures and the bearer
+// token check, and dispatches to the facts, schema or PDP handlers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path})
+	fail := s.failures > 0
+	status := s.failStatus
+	if fail {
+		s.failures--
+	}
+	s.mu.Unlock()
+
+	if fail {
+		writeError(w, status, "INJECTED_FAILURE", "injected failure")
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+Token {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "missing or invalid API key")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) routes() {
+	facts := "/v2/facts/{project}/{env}/"
+	s.handle("GET "+facts+"tenants", s.listTenants)
+	s.handle("POST "+facts+"tenants", s.createTenant)
+	s.handle("GET "+facts+"tenants/{key}", s.getTenant)
+	s.handle("PATCH "+facts+"tenants/{key}", s.updateTenant)
+	s.handle("DELETE "+facts+"tenants/{key}", s.deleteTenant)
+	s.handle("GET "+facts+"users/{key}", s.getUser)
+	s.handle("GET "+facts+"resource_instances", s.listResourceInstances)
+	s.handle("GET "+facts+"resource_instances/detailed", s.listResourceInstances)
+	s.handle("POST "+facts+"resource_instances", s.createResourceInstance)
+	s.handle("GET "+facts+"resource_instances/{key}", s.getResourceInstance)
+	s.handle("PATCH "+facts+"resource_instances/{key}", s.updateResourceInstance)
+	s.handle("DELETE "+facts+"resource_instances/{key}", s.deleteResourceInstance)
+	s.handle("GET "+facts+"role_assignments", s.listRoleAssignments)
+	s.handle("POST "+facts+"role_assignments", s.assignRole)
+	s.handle("DELETE "+facts+"role_assignments", s.unassignRole)
+	s.handle("GET "+facts+"relationship_tuples", s.listRelationshipTuples)
+	s.handle("GET "+facts+"relationship_tuples/detailed", s.listRelationshipTuples)
+	s.handle("POST "+facts+"relationship_tuples", s.createRelationshipTuple)
+
+	schema := "/v2/schema/{project}/{env}/"
+	s.handle("GET "+schema+"resources", s.listResources)
+	s.handle("POST "+schema+"resources", s.createResource)
+	s.handle("GET "+schema+"resources/{key}", s.getResource)
+	s.handle("PATCH "+schema+"resources/{key}", s.updateResource)
+	s.handle("POST "+schema+"resources/{key}/actions", s.createResourceAction)
+	s.handle("GET "+schema+"resources/{key}/relations", s.listResourceRelations)
+	s.handle("POST "+schema+"resources/{key}/relations", s.createResourceRelation)
+	s.handle("POST "+schema+"resources/{key}/roles", s.createResourceRole)
+	s.handle("PATCH "+schema+"resources/{key}/roles/{role}", s.updateResourceRole)
+	s.handle("DELETE "+schema+"resources/{key}/roles/{role}", s.deleteResourceRole)
+
+	s.mux.HandleFunc("POST /allowed", s.allowed)
+	s.mux.HandleFunc("POST /allowed/bulk", s.allowedBulk)
+	s.mux.HandleFunc("POST /user-permissions", s.userPermissions)
+
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+	})
+}
+
+// handle registers a facts or schema handler that only answers for the fake's
+// project and environment.
+func (s *Server) handle(pattern string, h http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("project") != Project || r.PathValue("env") != Environment {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "unknown project or environment")
+			return
+		}
+		h(w, r)
+	})
+}
+
+// stamp fills in the ID and timestamps of a newly stored entity.
+func stamp(entity *permit.Entity) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	if entity.ID == "" {
+		entity.ID = uuid.NewString()
+	}
+	if entity.CreatedAt == "" {
+		entity.CreatedAt = now
+	}
+	if entity.UpdatedAt == "" {
+		entity.UpdatedAt = entity.CreatedAt
+	}
+}
+
+// touch updates the UpdatedAt timestamp of a modified entity.
+func touch(entity *permit.Entity) {
+	entity.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+}
+
+// writeJSON writes v as a JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a Permit error document.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	requestID := uuid.NewString()
+	w.Header().Set("X-Request-Id", requestID)
+	writeJSON(w, status, map[string]string{
+		"id":         requestID,
+		"title":      http.StatusText(status),
+		"error_code": code,
+		"message":    message,
+	})
+}
+
+// decode reads the JSON request body into v, answering 422 when it is invalid.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writePage writes one page of items the way Permit list endpoints do: a bare
+// array, or an envelope with total_count and page_count when include_total_count is set.
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	query := r.URL.Query()
+	page, perPage := 1, 30
+	if v := query.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "page must be a positive integer")
+			return
+		}
+		page = n
+	}
+	if v := query.Get("per_page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 100 {
+			writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "per_page must be between 1 and 100")
+			return
+		}
+		perPage = n
+	}
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+	data := append(make([]T, 0, end-start), items[start:end]...)
+
+	if query.Get("include_total_count") != "true" {
+		writeJSON(w, http.StatusOK, data)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":        data,
+		"total_count": len(items),
+		"page_count":  (len(items) + perPage - 1) / perPage,
+	})
+}
+
+// sortedValues returns the values of m ordered by key.
+func sortedValues[T any](m map[string]T) []T {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]T, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, m[key])
+	}
+	return values
+}
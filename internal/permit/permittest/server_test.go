@@ -0,0 +1,292 @@
+package permittest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"iam_services_main_v1/internal/permit"
+
+	permitsdkcfg "github.com/permitio/permit-golang/pkg/config"
+	permitsdk "github.com/permitio/permit-golang/pkg/permit"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService(t *testing.T) (*Server, permit.PermitService) {
+	t.Helper()
+	fake := NewServer()
+	t.Cleanup(fake.Close)
+	return fake, permit.NewPermitServiceImpl(fake.Client())
+}
+
+func newTestSdkService(fake *Server) *permit.PermitSdkService {
+	cfg := permitsdkcfg.NewConfigBuilder(Token).WithPdpUrl(fake.URL).Build()
+	return permit.NewPermitSdkService(permitsdk.New(cfg))
+}
+
+func TestTenantLifecycle(t *testing.T) {
+	_, svc := newTestService(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateTenant(ctx, permit.TenantCreate{
+		Key:        "t1",
+		Name:       "Tenant 1",
+		Attributes: map[string]interface{}{"status": "ACTIVE"},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.NotEmpty(t, created.CreatedAt)
+
+	_, err = svc.CreateTenant(ctx, permit.TenantCreate{Key: "t1", Name: "Again"})
+	var apiErr *permit.PermitAPIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.Equal(t, "DUPLICATE_ENTITY", apiErr.Code)
+
+	name := "Renamed"
+	updated, err := svc.UpdateTenant(ctx, "t1", permit.TenantUpdate{Name: &name})
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Name)
+	assert.Equal(t, "ACTIVE", updated.Attributes["status"])
+
+	fetched, err := svc.GetTenant(ctx, "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", fetched.Name)
+
+	assert.NoError(t, svc.DeleteTenant(ctx, "t1"))
+
+	_, err = svc.GetTenant(ctx, "t1")
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.NotEmpty(t, apiErr.RequestID)
+}
+
+func TestListPaging(t *testing.T) {
+	fake, _ := newTestService(t)
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: key}, Name: key})
+	}
+
+	client := fake.Client()
+	client.Paging = &permit.PagingPolicy{PerPage: 2}
+	svc := permit.NewPermitServiceImpl(client)
+
+	tenants, total, err := svc.ListTenants(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, total)
+	assert.Len(t, tenants, 5)
+	assert.Equal(t, "a", tenants[0].Key)
+	assert.Equal(t, "e", tenants[4].Key)
+	assert.Len(t, fake.Requests(), 3)
+}
+
+func TestListResourceInstancesFilters(t *testing.T) {
+	fake, svc := newTestService(t)
+	fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: "i1"}, Tenant: "t1", Resource: "account"})
+	fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: "i2"}, Tenant: "t1", Resource: "cou"})
+	fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: "i3"}, Tenant: "t2", Resource: "account"})
+
+	instances, total, err := svc.ListResourceInstances(context.Background(), "t1", "account")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "i1", instances[0].Key)
+}
+
+func TestSearchTenants(t *testing.T) {
+	fake, svc := newTestService(t)
+	fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: "t1"}, Name: "Acme West"})
+	fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: "t2"}, Name: "Globex"})
+	fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: "acme-east"}, Name: "East"})
+
+	tenants, total, err := svc.SearchTenants(context.Background(), "ACME")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "acme-east", tenants[0].Key, "the key is searched too")
+	assert.Equal(t, "t1", tenants[1].Key)
+}
+
+func TestSchemaRoutes(t *testing.T) {
+	fake, svc := newTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.CreateResource(ctx, permit.ResourceCreate{
+		Key:     "account",
+		Name:    "Account",
+		Actions: map[string]permit.Action{"read": {Name: "read"}},
+	})
+	assert.NoError(t, err)
+	_, err = svc.CreateResourceAction(ctx, "account", permit.ActionCreate{Key: "update", Name: "update"})
+	assert.NoError(t, err)
+	_, err = svc.CreateResourceRole(ctx, "account", permit.RoleCreate{Key: "viewer", Name: "Viewer", Permissions: []string{"read"}})
+	assert.NoError(t, err)
+
+	resources, total, err := svc.ListResources(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Contains(t, resources[0].Actions, "update")
+	assert.Equal(t, []string{"read"}, resources[0].Roles["viewer"].Permissions)
+
+	updated, err := svc.UpdateResource(ctx, "account", permit.ResourceUpdate{Description: "Accounts"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Account", updated.Name)
+	assert.Equal(t, "Accounts", updated.Description)
+
+	_, err = svc.CreateResource(ctx, permit.ResourceCreate{Key: "tenant", Name: "Tenant"})
+	assert.NoError(t, err)
+	_, err = svc.CreateResourceRelation(ctx, "tenant", permit.RelationCreate{Key: "parent", Name: "Parent", SubjectResource: "account"})
+	assert.NoError(t, err)
+	_, err = svc.CreateResourceRelation(ctx, "tenant", permit.RelationCreate{Key: "owner", Name: "Owner", SubjectResource: "user"})
+	assert.Error(t, err, "the subject resource must exist")
+
+	relations, total, err := svc.ListResourceRelations(ctx, "tenant")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "account", relations[0].SubjectResource)
+	assert.Equal(t, "tenant", relations[0].ObjectResource)
+
+	for _, req := range fake.Requests() {
+		assert.Contains(t, req.Path, "/v2/schema/"+Project+"/"+Environment+"/resources")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	fake, svc := newTestService(t)
+	ctx := context.Background()
+	fake.AddResource(permit.Resource{
+		Key: "account",
+		Roles: map[string]permit.Role{
+			"viewer": {Key: "viewer", Permissions: []string{"read"}},
+			"owner":  {Key: "owner", Permissions: []string{"account:read", "account:update"}},
+		},
+	})
+	_, err := svc.AssignRole(ctx, permit.RoleAssignmentCreate{User: "alice", Role: "viewer", Tenant: "t1"})
+	assert.NoError(t, err)
+	_, err = svc.AssignRole(ctx, permit.RoleAssignmentCreate{User: "bob", Role: "owner", Tenant: "t1", ResourceInstance: "account:a1"})
+	assert.NoError(t, err)
+
+	sdk := newTestSdkService(fake)
+
+	tests := []struct {
+		name       string
+		user       string
+		action     string
+		resourceID string
+		tenant     string
+		allowed    bool
+	}{
+		{name: "Tenant-wide role", user: "alice", action: "read", resourceID: "a1", tenant: "t1", allowed: true},
+		{name: "Action not in role", user: "alice", action: "update", resourceID: "a1", tenant: "t1", allowed: false},
+		{name: "Other tenant", user: "alice", action: "read", resourceID: "a1", tenant: "t2", allowed: false},
+		{name: "Instance role on its instance", user: "bob", action: "update", resourceID: "a1", tenant: "t1", allowed: true},
+		{name: "Instance role on another instance", user: "bob", action: "update", resourceID: "a2", tenant: "t1", allowed: false},
+		{name: "Unknown user", user: "carol", action: "read", resourceID: "a1", tenant: "t1", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := sdk.Check(ctx, tt.user, tt.action, "account", tt.resourceID, tt.tenant)
+			assert.Equal(t, tt.allowed, allowed)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, "permission denied")
+			}
+		})
+	}
+
+	assert.NoError(t, svc.UnassignRole(ctx, permit.RoleAssignmentCreate{User: "alice", Role: "viewer", Tenant: "t1"}))
+	allowed, _ := sdk.Check(ctx, "alice", "read", "account", "a1", "t1")
+	assert.False(t, allowed)
+}
+
+func TestListAllowedActions(t *testing.T) {
+	fake, svc := newTestService(t)
+	ctx := context.Background()
+	fake.AddResource(permit.Resource{
+		Key: "account",
+		Roles: map[string]permit.Role{
+			"viewer": {Key: "viewer", Permissions: []string{"read"}},
+			"owner":  {Key: "owner", Permissions: []string{"account:read", "account:update", "account:delete"}},
+		},
+	})
+	fake.AddResource(permit.Resource{
+		Key:   "tenant",
+		Roles: map[string]permit.Role{"viewer": {Key: "viewer", Permissions: []string{"list"}}},
+	})
+	_, err := svc.AssignRole(ctx, permit.RoleAssignmentCreate{User: "alice", Role: "viewer", Tenant: "t1"})
+	assert.NoError(t, err)
+	_, err = svc.AssignRole(ctx, permit.RoleAssignmentCreate{User: "alice", Role: "owner", Tenant: "t1", ResourceInstance: "account:a1"})
+	assert.NoError(t, err)
+
+	sdk := newTestSdkService(fake)
+
+	tests := []struct {
+		name       string
+		resourceID string
+		tenant     string
+		expected   []string
+	}{
+		{name: "Instance with its own role", resourceID: "a1", tenant: "t1", expected: []string{"delete", "read", "update"}},
+		{name: "Other instance", resourceID: "a2", tenant: "t1", expected: []string{"read"}},
+		{name: "Whole type", resourceID: "*", tenant: "t1", expected: []string{"read"}},
+		{name: "Other tenant", resourceID: "a1", tenant: "t2", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions, err := sdk.ListAllowedActions(ctx, "alice", "account", tt.resourceID, tt.tenant)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actions)
+		})
+	}
+}
+
+func TestGetUserAssociatedTenants(t *testing.T) {
+	fake, svc := newTestService(t)
+	fake.AddUser(permit.User{Entity: permit.Entity{Key: "alice"}, Email: "alice@example.com"})
+	fake.AddRoleAssignment(permit.RoleAssignment{User: "alice", Role: "viewer", Tenant: "t1"})
+	fake.AddRoleAssignment(permit.RoleAssignment{User: "alice", Role: "admin", Tenant: "t1"})
+	fake.AddRoleAssignment(permit.RoleAssignment{User: "alice", Role: "viewer", Tenant: "t2"})
+
+	user, err := svc.GetUser(context.Background(), "alice")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []permit.UserTenant{
+		{Tenant: "t1", Roles: []string{"viewer", "admin"}, Status: "active"},
+		{Tenant: "t2", Roles: []string{"viewer"}, Status: "active"},
+	}, user.AssociatedTenants)
+}
+
+func TestFailNextAndAuth(t *testing.T) {
+	t.Run("Injected failures are retried", func(t *testing.T) {
+		fake, _ := newTestService(t)
+		fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: "t1"}})
+		client := fake.Client()
+		client.Retry = &permit.RetryPolicy{MaxAttempts: 3}
+		svc := permit.NewPermitServiceImpl(client)
+
+		fake.FailNext(2, http.StatusServiceUnavailable)
+		tenant, err := svc.GetTenant(context.Background(), "t1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "t1", tenant.Key)
+		assert.Len(t, fake.Requests(), 3)
+	})
+
+	t.Run("Wrong token is rejected", func(t *testing.T) {
+		fake, _ := newTestService(t)
+		client := fake.Client()
+		client.Headers = map[string]string{"Authorization": "Bearer wrong"}
+		svc := permit.NewPermitServiceImpl(client)
+
+		_, err := svc.GetTenant(context.Background(), "t1")
+
+		var apiErr *permit.PermitAPIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	})
+}
@@ -0,0 +1,254 @@
+package permitschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/pkg/logger"
+)
+
+// ChangeKind is the kind of a planned change.
+type ChangeKind string
+
+const (
+	CreateResource ChangeKind = "create resource"
+	UpdateResource ChangeKind = "update resource"
+	CreateAction   ChangeKind = "create action"
+	CreateRelation ChangeKind = "create relation"
+	CreateRole     ChangeKind = "create role"
+	UpdateRole     ChangeKind = "update role"
+)
+
+// Change is one write needed to bring Permit in line with a Schema. Payload is
+// the request body: a permit.ResourceCreate, ResourceUpdate, ActionCreate,
+// RelationCreate, RoleCreate or RoleUpdate depending on Kind.
+type Change struct {
+	Kind     ChangeKind
+	Resource string
+	// Key is the key of the action, relation or role. It is empty for
+	// resource changes.
+	Key     string
+	Payload any
+}
+
+func (c Change) String() string {
+	target := c.Resource
+	if c.Key != "" {
+		target += "/" + c.Key
+	}
+	switch payload := c.Payload.(type) {
+	case permit.ResourceCreate:
+		return fmt.Sprintf("%s %s (%s) with actions %v", c.Kind, target, payload.Name, sortedKeys(payload.Actions))
+	case permit.RelationCreate:
+		return fmt.Sprintf("%s %s to %s", c.Kind, target, payload.SubjectResource)
+	case permit.RoleCreate:
+		return fmt.Sprintf("%s %s (%s) with permissions %v", c.Kind, target, payload.Name, payload.Permissions)
+	case permit.RoleUpdate:
+		return fmt.Sprintf("%s %s (%s) with permissions %v", c.Kind, target, payload.Name, payload.Permissions)
+	default:
+		return fmt.Sprintf("%s %s", c.Kind, target)
+	}
+}
+
+// Plan is the ordered list of changes that brings Permit in line with a Schema.
+// Resource types come first, then their actions, relations and roles, so every
+// change only refers to things created before it.
+type Plan []Change
+
+func (p Plan) String() string {
+	if len(p) == 0 {
+		return "Permit schema is up to date"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d Permit schema change(s):", len(p))
+	for _, change := range p {
+		b.WriteString("\n  ")
+		b.WriteString(change.String())
+	}
+	return b.String()
+}
+
+// Diff compares the schema with the one in Permit and returns the changes that
+// Apply needs to make. It reads Permit bypassing any cache.
+func Diff(ctx context.Context, svc permit.PermitService, schema *Schema) (Plan, error) {
+	ctx = permit.WithFreshReads(ctx)
+	existing, _, err := svc.ListResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list resources: %w", err)
+	}
+	current := make(map[string]permit.Resource, len(existing))
+	for _, resource := range existing {
+		current[resource.Key] = resource
+	}
+
+	var resources, actions, relations, roles Plan
+	for _, spec := range schema.Resources {
+		resource, exists := current[spec.Key]
+		if !exists {
+			create := permit.ResourceCreate{Key: spec.Key, Name: spec.Name, Description: spec.Description, Actions: map[string]permit.Action{}}
+			for key, action := range spec.Actions {
+				create.Actions[key] = permit.Action{Name: action.Name, Description: action.Description}
+			}
+			resources = append(resources, Change{Kind: CreateResource, Resource: spec.Key, Payload: create})
+		} else {
+			if resource.Name != spec.Name || (spec.Description != "" && resource.Description != spec.Description) {
+				update := permit.ResourceUpdate{Name: spec.Name, Description: spec.Description}
+				resources = append(resources, Change{Kind: UpdateResource, Resource: spec.Key, Payload: update})
+			}
+			for _, key := range sortedKeys(spec.Actions) {
+				if _, ok := resource.Actions[key]; ok {
+					continue
+				}
+				action := spec.Actions[key]
+				create := permit.ActionCreate{Key: key, Name: action.Name, Description: action.Description}
+				actions = append(actions, Change{Kind: CreateAction, Resource: spec.Key, Key: key, Payload: create})
+			}
+		}
+
+		changes, err := diffRelations(ctx, svc, spec, exists)
+		if err != nil {
+			return nil, err
+		}
+		relations = append(relations, changes...)
+		roles = append(roles, diffRoles(spec, resource.Roles)...)
+	}
+
+	plan := append(resources, actions...)
+	plan = append(plan, relations...)
+	return append(plan, roles...), nil
+}
+
+// diffRelations returns the relations of spec missing from Permit. A relation
+// that exists with another subject cannot be changed without deleting it, so
+// it is reported as an error.
+func diffRelations(ctx context.Context, svc permit.PermitService, spec ResourceSpec, exists bool) (Plan, error) {
+	if len(spec.Relations) == 0 {
+		return nil, nil
+	}
+	current := map[string]permit.Relation{}
+	if exists {
+		existing, _, err := svc.ListResourceRelations(ctx, spec.Key)
+		if err != nil {
+			return nil, fmt.Errorf("list relations of %s: %w", spec.Key, err)
+		}
+		for _, relation := range existing {
+			current[relation.Key] = relation
+		}
+	}
+
+	var plan Plan
+	for _, key := range sortedKeys(spec.Relations) {
+		relation := spec.Relations[key]
+		if existing, ok := current[key]; ok {
+			if existing.SubjectResource != relation.Subject {
+				return nil, fmt.Errorf("relation %s/%s has subject %s in Permit but %s in the schema", spec.Key, key, existing.SubjectResource, relation.Subject)
+			}
+			continue
+		}
+		create := permit.RelationCreate{Key: key, Name: relation.Name, Description: relation.Description, SubjectResource: relation.Subject}
+		plan = append(plan, Change{Kind: CreateRelation, Resource: spec.Key, Key: key, Payload: create})
+	}
+	return plan, nil
+}
+
+// diffRoles returns the roles of spec missing from Permit or differing from it.
+func diffRoles(spec ResourceSpec, current map[string]permit.Role) Plan {
+	var plan Plan
+	for _, key := range sortedKeys(spec.Roles) {
+		role := spec.Roles[key]
+		permissions := slices.Sorted(slices.Values(role.Permissions))
+		existing, ok := current[key]
+		if !ok {
+			create := permit.RoleCreate{Key: key, Name: role.Name, Description: role.Description, Permissions: permissions}
+			plan = append(plan, Change{Kind: CreateRole, Resource: spec.Key, Key: key, Payload: create})
+			continue
+		}
+		if existing.Name != role.Name ||
+			(role.Description != "" && existing.Description != role.Description) ||
+			!slices.Equal(permissions, actionKeys(spec.Key, existing.Permissions)) {
+			update := permit.RoleUpdate{Name: role.Name, Description: role.Description, Permissions: permissions}
+			plan = append(plan, Change{Kind: UpdateRole, Resource: spec.Key, Key: key, Payload: update})
+		}
+	}
+	return plan
+}
+
+// actionKeys returns the sorted action keys of role permissions, which Permit
+// may report either as "action" or as "resource:action".
+func actionKeys(resourceKey string, permissions []string) []string {
+	keys := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		keys = append(keys, strings.TrimPrefix(permission, resourceKey+":"))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Apply makes the changes of plan in order and stops at the first failure.
+// Creating something that already exists counts as done, so that replicas
+// bootstrapping the same schema at once do not fail each other.
+func Apply(ctx context.Context, svc permit.PermitService, plan Plan) error {
+	for _, change := range plan {
+		err := apply(ctx, svc, change)
+		var apiErr *permit.PermitAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+			logger.LogInfo("Permit schema change already applied", "change", change.String())
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", change, err)
+		}
+		logger.LogInfo("Applied Permit schema change", "change", change.String())
+	}
+	return nil
+}
+
+func apply(ctx context.Context, svc permit.PermitService, change Change) error {
+	var err error
+	switch payload := change.Payload.(type) {
+	case permit.ResourceCreate:
+		_, err = svc.CreateResource(ctx, payload)
+	case permit.ResourceUpdate:
+		_, err = svc.UpdateResource(ctx, change.Resource, payload)
+	case permit.ActionCreate:
+		_, err = svc.CreateResourceAction(ctx, change.Resource, payload)
+	case permit.RelationCreate:
+		_, err = svc.CreateResourceRelation(ctx, change.Resource, payload)
+	case permit.RoleCreate:
+		_, err = svc.CreateResourceRole(ctx, change.Resource, payload)
+	case permit.RoleUpdate:
+		_, err = svc.UpdateResourceRole(ctx, change.Resource, change.Key, payload)
+	default:
+		err = fmt.Errorf("unsupported payload %T", change.Payload)
+	}
+	return err
+}
+
+// Bootstrap brings Permit in line with the schema and returns the plan it
+// followed. With dryRun it only computes and logs the plan.
+func Bootstrap(ctx context.Context, svc permit.PermitService, schema *Schema, dryRun bool) (Plan, error) {
+	plan, err := Diff(ctx, svc, schema)
+	if err != nil {
+		return nil, err
+	}
+	logger.LogInfo("Computed Permit schema plan", "changes", len(plan), "dryRun", dryRun)
+	if dryRun || len(plan) == 0 {
+		return plan, nil
+	}
+	return plan, Apply(ctx, svc, plan)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,139 @@
+package permitschema
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permit/permittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSchema() *Schema {
+	return &Schema{Resources: []ResourceSpec{
+		{
+			Key:  "tenant",
+			Name: "Tenant",
+			Actions: map[string]ActionSpec{
+				"tenant":       {Name: "Read tenant"},
+				"createtenant": {Name: "Create tenant"},
+			},
+			Relations: map[string]RelationSpec{"parent": {Name: "Parent", Subject: "account"}},
+			Roles: map[string]RoleSpec{
+				"viewer": {Name: "Viewer", Permissions: []string{"tenant"}},
+				"admin":  {Name: "Admin", Permissions: []string{"tenant", "createtenant"}},
+			},
+		},
+		{
+			Key:     "account",
+			Name:    "Account",
+			Actions: map[string]ActionSpec{"account": {Name: "Read account"}},
+		},
+	}}
+}
+
+func kinds(plan Plan) []string {
+	var out []string
+	for _, change := range plan {
+		out = append(out, string(change.Kind)+" "+change.Resource+"/"+change.Key)
+	}
+	return out
+}
+
+func TestBootstrap(t *testing.T) {
+	ctx := context.Background()
+	fake := permittest.NewServer()
+	defer fake.Close()
+	svc := permit.NewPermitServiceImpl(fake.Client())
+
+	t.Run("Dry run only reads", func(t *testing.T) {
+		plan, err := Bootstrap(ctx, svc, testSchema(), true)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"create resource tenant/",
+			"create resource account/",
+			"create relation tenant/parent",
+			"create role tenant/admin",
+			"create role tenant/viewer",
+		}, kinds(plan))
+		assert.Contains(t, plan.String(), "5 Permit schema change(s)")
+		for _, req := range fake.Requests() {
+			assert.Equal(t, http.MethodGet, req.Method)
+		}
+		_, exists := fake.Resource("tenant")
+		assert.False(t, exists)
+	})
+
+	t.Run("Creates the schema", func(t *testing.T) {
+		_, err := Bootstrap(ctx, svc, testSchema(), false)
+		assert.NoError(t, err)
+
+		tenant, exists := fake.Resource("tenant")
+		assert.True(t, exists)
+		assert.Contains(t, tenant.Actions, "createtenant")
+		assert.Equal(t, []string{"createtenant", "tenant"}, tenant.Roles["admin"].Permissions)
+		relations := fake.Relations("tenant")
+		assert.Len(t, relations, 1)
+		assert.Equal(t, "account", relations[0].SubjectResource)
+	})
+
+	t.Run("Is idempotent", func(t *testing.T) {
+		fake.ResetRequests()
+		plan, err := Bootstrap(ctx, svc, testSchema(), false)
+		assert.NoError(t, err)
+		assert.Empty(t, plan)
+		assert.Equal(t, "Permit schema is up to date", plan.String())
+		for _, req := range fake.Requests() {
+			assert.Equal(t, http.MethodGet, req.Method)
+		}
+	})
+
+	t.Run("Adds and updates without deleting", func(t *testing.T) {
+		fake.AddResource(permit.Resource{Key: "legacy", Name: "Legacy"})
+		schema := testSchema()
+		schema.Resources[0].Name = "Tenants"
+		schema.Resources[0].Actions["deletetenant"] = ActionSpec{Name: "Delete tenant"}
+		schema.Resources[0].Roles["admin"] = RoleSpec{Name: "Admin", Permissions: []string{"tenant", "createtenant", "deletetenant"}}
+		delete(schema.Resources[0].Roles, "viewer")
+
+		plan, err := Bootstrap(ctx, svc, schema, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"update resource tenant/",
+			"create action tenant/deletetenant",
+			"update role tenant/admin",
+		}, kinds(plan))
+
+		tenant, _ := fake.Resource("tenant")
+		assert.Equal(t, "Tenants", tenant.Name)
+		assert.Equal(t, []string{"createtenant", "deletetenant", "tenant"}, tenant.Roles["admin"].Permissions)
+		assert.Contains(t, tenant.Roles, "viewer")
+		_, exists := fake.Resource("legacy")
+		assert.True(t, exists)
+	})
+
+	t.Run("Relation with another subject is reported", func(t *testing.T) {
+		schema := testSchema()
+		schema.Resources[0].Relations["parent"] = RelationSpec{Name: "Parent", Subject: "tenant"}
+		_, err := Bootstrap(ctx, svc, schema, false)
+		assert.ErrorContains(t, err, "relation tenant/parent has subject account in Permit but tenant in the schema")
+	})
+}
+
+func TestApplyToleratesConcurrentCreates(t *testing.T) {
+	ctx := context.Background()
+	fake := permittest.NewServer()
+	defer fake.Close()
+	svc := permit.NewPermitServiceImpl(fake.Client())
+
+	plan, err := Diff(ctx, svc, testSchema())
+	assert.NoError(t, err)
+	assert.NoError(t, Apply(ctx, svc, plan))
+	assert.NoError(t, Apply(ctx, svc, plan), "changes another replica already made are skipped")
+
+	fake.FailNext(1, http.StatusInternalServerError)
+	err = Apply(ctx, svc, plan)
+	assert.ErrorContains(t, err, "create resource tenant")
+}
@@ -0,0 +1,109 @@
+// Package permitschema keeps the Permit schema the service depends on (resource
+// types, their actions, relations and roles) in a declarative file, and brings
+// a Permit environment in line with it.
+//
+// Bootstrapping only adds and updates: resource types, actions, relations and
+// roles that exist in Permit but not in the file are left alone.
+package permitschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Schema is the declared Permit schema.
+type Schema struct {
+	Resources []ResourceSpec `json:"resources"`
+}
+
+// ResourceSpec declares a resource type. Key is the resource type ID the
+// service uses, e.g. config.TenantResourceTypeID.
+type ResourceSpec struct {
+	Key         string                  `json:"key"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"`
+	Actions     map[string]ActionSpec   `json:"actions"`
+	Relations   map[string]RelationSpec `json:"relations,omitempty"`
+	Roles       map[string]RoleSpec     `json:"roles,omitempty"`
+}
+
+// ActionSpec declares an action of a resource type, keyed by the action key.
+type ActionSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// RelationSpec declares a relation from a resource type to instances of the
+// Subject resource type, keyed by the relation key.
+type RelationSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Subject     string `json:"subject"`
+}
+
+// RoleSpec declares a role of a resource type, keyed by the role key.
+// Permissions are action keys of the same resource type.
+type RoleSpec struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+// Load reads a Schema from the JSON file at path and validates it.
+func Load(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema file: %w", err)
+	}
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("parse schema file %s: %w", path, err)
+	}
+	if err := schema.Validate(); err != nil {
+		return nil, fmt.Errorf("parse schema file %s: %w", path, err)
+	}
+	return &schema, nil
+}
+
+// Validate checks that every resource type has a unique key and a name, and
+// that relations and roles only refer to declared resource types and actions.
+func (s *Schema) Validate() error {
+	declared := make(map[string]bool, len(s.Resources))
+	for i, resource := range s.Resources {
+		if resource.Key == "" || resource.Name == "" {
+			return fmt.Errorf("resource %d needs a key and a name", i)
+		}
+		if declared[resource.Key] {
+			return fmt.Errorf("resource %s is declared twice", resource.Key)
+		}
+		declared[resource.Key] = true
+	}
+
+	for _, resource := range s.Resources {
+		for key, action := range resource.Actions {
+			if key == "" || action.Name == "" {
+				return fmt.Errorf("resource %s: action %q needs a key and a name", resource.Key, key)
+			}
+		}
+		for key, relation := range resource.Relations {
+			if key == "" || relation.Name == "" {
+				return fmt.Errorf("resource %s: relation %q needs a key and a name", resource.Key, key)
+			}
+			if !declared[relation.Subject] {
+				return fmt.Errorf("resource %s: relation %s refers to undeclared resource %q", resource.Key, key, relation.Subject)
+			}
+		}
+		for key, role := range resource.Roles {
+			if key == "" || role.Name == "" {
+				return fmt.Errorf("resource %s: role %q needs a key and a name", resource.Key, key)
+			}
+			for _, permission := range role.Permissions {
+				if _, ok := resource.Actions[permission]; !ok {
+					return fmt.Errorf("resource %s: role %s grants undeclared action %q", resource.Key, key, permission)
+				}
+			}
+		}
+	}
+	return nil
+}
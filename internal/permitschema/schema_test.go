@@ -0,0 +1,102 @@
+package permitschema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"iam_services_main_v1/config"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSchema(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schema.json")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Valid file", func(t *testing.T) {
+		schema, err := Load(writeSchema(t, `{"resources":[{"key":"account","name":"Account",
+			"actions":{"read":{"name":"Read"}},
+			"relations":{"parent":{"name":"Parent","subject":"account"}},
+			"roles":{"viewer":{"name":"Viewer","permissions":["read"]}}}]}`))
+		assert.NoError(t, err)
+		assert.Len(t, schema.Resources, 1)
+		assert.Equal(t, []string{"read"}, schema.Resources[0].Roles["viewer"].Permissions)
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "Invalid JSON", content: `{"resources":`, wantErr: "parse schema file"},
+		{name: "Resource without key", content: `{"resources":[{"name":"Account"}]}`, wantErr: "resource 0 needs a key and a name"},
+		{
+			name:    "Duplicate resource",
+			content: `{"resources":[{"key":"account","name":"Account"},{"key":"account","name":"Again"}]}`,
+			wantErr: "resource account is declared twice",
+		},
+		{
+			name:    "Action without name",
+			content: `{"resources":[{"key":"account","name":"Account","actions":{"read":{}}}]}`,
+			wantErr: `action "read" needs a key and a name`,
+		},
+		{
+			name:    "Relation to undeclared resource",
+			content: `{"resources":[{"key":"account","name":"Account","relations":{"parent":{"name":"Parent","subject":"tenant"}}}]}`,
+			wantErr: `relation parent refers to undeclared resource "tenant"`,
+		},
+		{
+			name:    "Role granting undeclared action",
+			content: `{"resources":[{"key":"account","name":"Account","roles":{"viewer":{"name":"Viewer","permissions":["read"]}}}]}`,
+			wantErr: `role viewer grants undeclared action "read"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeSchema(t, tt.content))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+		assert.ErrorContains(t, err, "read schema file")
+	})
+}
+
+func TestDeclaredSchema(t *testing.T) {
+	schema, err := Load("../../config/permit_schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	declared := map[string]ResourceSpec{}
+	for _, resource := range schema.Resources {
+		declared[resource.Key] = resource
+		for key := range resource.Roles {
+			_, err := uuid.Parse(key)
+			assert.NoError(t, err, "role keys are UUIDs")
+		}
+	}
+
+	for _, key := range []string{
+		config.TenantResourceTypeID,
+		config.ClientOrgUnitResourceTypeID,
+		config.AccountResourceTypeID,
+		config.RoleResourceTypeID,
+		config.BindingResourceTypeID,
+		config.PermissionResourceTypeID,
+		config.RootResourceTypeID,
+	} {
+		assert.Contains(t, declared, key)
+	}
+	assert.Contains(t, declared[config.TenantResourceTypeID].Actions, "createtenant")
+	assert.Contains(t, declared[config.PermissionResourceTypeID].Actions, "checkpermission")
+	assert.Contains(t, declared[config.RootResourceTypeID].Actions, "createresourcetype")
+}
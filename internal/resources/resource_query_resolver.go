@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
-	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/authz"
 	"strings"
 
 	"iam_services_main_v1/pkg/logger"
@@ -14,7 +14,7 @@ import (
 )
 
 type ResourceQueryResolver struct {
-	PSC *permit.PermitSdkService
+	Authorizer authz.Authorizer
 }
 
 func (r *ResourceQueryResolver) Resource(ctx context.Context, id uuid.UUID) (models.OperationResult, error) {
@@ -96,14 +96,7 @@ func (r *ResourceQueryResolver) CheckPermission(ctx context.Context, input model
 		}, nil
 	}
 	// Check permission
-	resourceID := ""
-	if input.ResourceID != "" {
-		resourceID = input.ResourceID
-	}
-	// Check if input.Action contains "create"
-	if strings.Contains(strings.ToLower(input.Action), "create") {
-		resourceID = ""
-	}
+	resourceID := permissionResourceID(input)
 
 	// Log the permission check request
 	logger.LogInfo("GraphQL permission check request",
@@ -114,7 +107,7 @@ func (r *ResourceQueryResolver) CheckPermission(ctx context.Context, input model
 	)
 
 	// Check permission
-	allowed, err := r.PSC.Check(ctx, userID.String(), input.Action, input.ResourceType, resourceID, tenantID.String())
+	allowed, err := r.Authorizer.Check(ctx, userID.String(), input.Action, input.ResourceType, resourceID, tenantID.String())
 	if err != nil {
 		logger.LogError("Failed to check permissions", "error", err)
 		return &models.PermissionResponse{
@@ -129,3 +122,74 @@ func (r *ResourceQueryResolver) CheckPermission(ctx context.Context, input model
 		Error:   helpers.Ptr(""),
 	}, nil
 }
+
+// CheckPermissions is the resolver for the checkPermissions field. All inputs
+// are decided with a single bulk request to the PDP.
+func (r *ResourceQueryResolver) CheckPermissions(ctx context.Context, inputs []*models.PermissionInput) ([]*models.PermissionResponse, error) {
+	if len(inputs) > authz.MaxBulkChecks {
+		return nil, fmt.Errorf("checkPermissions accepts at most %d inputs, got %d", authz.MaxBulkChecks, len(inputs))
+	}
+
+	responses := make([]*models.PermissionResponse, len(inputs))
+	userID, tenantID, err := helpers.GetUserAndTenantID(ctx)
+	if err != nil {
+		logger.LogError("User ID not found in context during permission check")
+		for i := range responses {
+			responses[i] = &models.PermissionResponse{
+				Allowed: false,
+				Error:   helpers.Ptr("User ID & Tenant ID not found in context"),
+			}
+		}
+		return responses, nil
+	}
+
+	// Invalid inputs get their own error and are left out of the bulk check.
+	checks := make([]authz.PermissionCheck, 0, len(inputs))
+	positions := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		if input.Action == "" {
+			responses[i] = &models.PermissionResponse{
+				Allowed: false,
+				Error:   helpers.Ptr("Action cannot be empty"),
+			}
+			continue
+		}
+		checks = append(checks, authz.PermissionCheck{
+			Action:       input.Action,
+			ResourceType: input.ResourceType,
+			ResourceID:   permissionResourceID(*input),
+		})
+		positions = append(positions, i)
+	}
+
+	logger.LogInfo("GraphQL bulk permission check request", "user_id", userID, "checks", len(checks))
+
+	decisions, err := r.Authorizer.BulkCheck(ctx, userID.String(), tenantID.String(), checks)
+	if err != nil {
+		logger.LogError("Failed to check permissions", "error", err)
+		for _, i := range positions {
+			responses[i] = &models.PermissionResponse{
+				Allowed: false,
+				Error:   helpers.Ptr(fmt.Sprintf("Failed to check permissions: %s", err.Error())),
+			}
+		}
+		return responses, nil
+	}
+
+	for j, i := range positions {
+		responses[i] = &models.PermissionResponse{
+			Allowed: decisions[j],
+			Error:   helpers.Ptr(""),
+		}
+	}
+	return responses, nil
+}
+
+// permissionResourceID returns the resource ID to check input against. Create
+// actions are checked against the resource type, not an instance.
+func permissionResourceID(input models.PermissionInput) string {
+	if strings.Contains(strings.ToLower(input.Action), "create") {
+		return ""
+	}
+	return input.ResourceID
+}
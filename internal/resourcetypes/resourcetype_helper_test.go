@@ -2,108 +2,93 @@ package resourcetypes
 
 import (
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
-// createMockResourceResponse creates a mock resource response for testing
-func createMockResourceResponse() map[string]interface{} {
-	resourceID := uuid.New()
-	permission1ID := uuid.New()
-	permission2ID := uuid.New()
-
-	return map[string]interface{}{
-		"data": []interface{}{
-			map[string]interface{}{
-				"key":       resourceID.String(),
-				"name":      "Document",
-				"createdAt": "2025-03-20T19:08:06-05:00",
-				"updatedAt": "2025-03-20T19:08:06-05:00",
-				"actions": map[string]interface{}{
-					"read": map[string]interface{}{
-						"id":        permission1ID.String(),
-						"name":      "Read",
-						"createdAt": "2025-03-20T19:08:06-05:00",
-						"updatedAt": "2025-03-20T19:08:06-05:00",
-					},
-					"write": map[string]interface{}{
-						"id":        permission2ID.String(),
-						"name":      "Write",
-						"createdAt": "2025-03-20T19:08:06-05:00",
-						"updatedAt": "2025-03-20T19:08:06-05:00",
-					},
+// createMockResources creates mock resources for testing
+func createMockResources() []permit.Resource {
+	return []permit.Resource{
+		{
+			Key:       uuid.New().String(),
+			Name:      "Document",
+			CreatedAt: "2025-03-20T19:08:06-05:00",
+			UpdatedAt: "2025-03-20T19:08:06-05:00",
+			Actions: map[string]permit.Action{
+				"read": {
+					ID:        uuid.New().String(),
+					Name:      "Read",
+					CreatedAt: "2025-03-20T19:08:06-05:00",
+					UpdatedAt: "2025-03-20T19:08:06-05:00",
+				},
+				"write": {
+					ID:        uuid.New().String(),
+					Name:      "Write",
+					CreatedAt: "2025-03-20T19:08:06-05:00",
+					UpdatedAt: "2025-03-20T19:08:06-05:00",
 				},
 			},
-			map[string]interface{}{
-				"key":       uuid.New().String(),
-				"name":      "User",
-				"createdAt": "2025-03-20T19:08:06-05:00",
-				"updatedAt": "2025-03-20T19:08:06-05:00",
-				"actions": map[string]interface{}{
-					"create": map[string]interface{}{
-						"id":        uuid.New().String(),
-						"name":      "Create",
-						"createdAt": "2025-03-20T19:08:06-05:00",
-						"updatedAt": "2025-03-20T19:08:06-05:00",
-					},
-					"delete": map[string]interface{}{
-						"id":        uuid.New().String(),
-						"name":      "Delete",
-						"createdAt": "2025-03-20T19:08:06-05:00",
-						"updatedAt": "2025-03-20T19:08:06-05:00",
-					},
+		},
+		{
+			Key:       uuid.New().String(),
+			Name:      "User",
+			CreatedAt: "2025-03-20T19:08:06-05:00",
+			UpdatedAt: "2025-03-20T19:08:06-05:00",
+			Actions: map[string]permit.Action{
+				"create": {
+					ID:        uuid.New().String(),
+					Name:      "Create",
+					CreatedAt: "2025-03-20T19:08:06-05:00",
+					UpdatedAt: "2025-03-20T19:08:06-05:00",
+				},
+				"delete": {
+					ID:        uuid.New().String(),
+					Name:      "Delete",
+					CreatedAt: "2025-03-20T19:08:06-05:00",
+					UpdatedAt: "2025-03-20T19:08:06-05:00",
 				},
 			},
 		},
 	}
 }
 
-// createMockInvalidResponse creates an invalid mock response for testing error cases
-func createMockInvalidResponse() map[string]interface{} {
-	return map[string]interface{}{
-		"invalid_field": "invalid_data",
-	}
-}
-
 func TestMapResourceResponseToStruct(t *testing.T) {
 	tests := []struct {
 		name           string
-		resourceData   map[string]interface{}
+		resources      []permit.Resource
 		expectedCount  int
 		expectedError  bool
 		expectedErrMsg string
 	}{
 		{
 			name:          "Valid response with resources",
-			resourceData:  createMockResourceResponse(),
+			resources:     createMockResources(),
 			expectedCount: 2, // Two resources in the mock data
 			expectedError: false,
 		},
 		{
-			name:           "Invalid response - missing data field",
-			resourceData:   createMockInvalidResponse(),
-			expectedCount:  0,
-			expectedError:  true,
-			expectedErrMsg: "missing or invalid data field",
+			name:          "Empty response",
+			resources:     []permit.Resource{},
+			expectedCount: 0,
+			expectedError: false,
 		},
 		{
-			name: "Invalid response - invalid resource format",
-			resourceData: map[string]interface{}{
-				"data": []interface{}{
-					"not-a-map", // Invalid data format
-				},
+			name: "Invalid response - invalid resource key",
+			resources: []permit.Resource{
+				{Key: "not-a-uuid", Actions: map[string]permit.Action{}},
 			},
 			expectedCount:  0,
 			expectedError:  true,
-			expectedErrMsg: "invalid resource data format",
+			expectedErrMsg: "invalid resource type",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resources, err := MapResourceResponseToStruct(tt.resourceData)
+			resources, err := MapResourceResponseToStruct(tt.resources)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -113,142 +98,43 @@ func TestMapResourceResponseToStruct(t *testing.T) {
 				assert.Nil(t, resources)
 			} else {
 				assert.NoError(t, err)
-				assert.NotNil(t, resources)
 				assert.Equal(t, tt.expectedCount, len(resources))
 
 				// Verify the resources data
 				for _, resource := range resources {
-					// The resource should implement the Data interface
 					assert.NotNil(t, resource)
 
-					// Try to cast to ResourceType
 					resourceType, ok := resource.(models.ResourceType)
-					if ok {
-						assert.NotEqual(t, uuid.Nil, resourceType.ID)
-						assert.NotEmpty(t, resourceType.Name)
-						assert.NotEmpty(t, resourceType.CreatedAt)
-						assert.NotEmpty(t, resourceType.UpdatedAt)
-						assert.NotEmpty(t, resourceType.Permissions)
-					}
+					assert.True(t, ok)
+					assert.NotEqual(t, uuid.Nil, resourceType.ID)
+					assert.NotEmpty(t, resourceType.Name)
+					assert.NotEmpty(t, resourceType.CreatedAt)
+					assert.NotEmpty(t, resourceType.UpdatedAt)
+					assert.NotEmpty(t, resourceType.Permissions)
 				}
 			}
 		})
 	}
 }
 
-func TestExtractDataFromResponse(t *testing.T) {
-	t.Run("Valid data extraction", func(t *testing.T) {
-		response := createMockResourceResponse()
-		data, ok := extractDataFromResponse(response)
-		assert.True(t, ok)
-		assert.NotNil(t, data)
-		assert.Equal(t, 2, len(data))
-	})
-
-	t.Run("Missing data field", func(t *testing.T) {
-		response := map[string]interface{}{
-			"not_data": "something",
-		}
-		data, ok := extractDataFromResponse(response)
-		assert.False(t, ok)
-		assert.Nil(t, data)
-	})
-
-	t.Run("Data field is not a slice", func(t *testing.T) {
-		response := map[string]interface{}{
-			"data": "not a slice",
-		}
-		data, ok := extractDataFromResponse(response)
-		assert.False(t, ok)
-		assert.Nil(t, data)
-	})
-
-	t.Run("Data slice contains non-map items", func(t *testing.T) {
-		response := map[string]interface{}{
-			"data": []interface{}{
-				"string item",
-				map[string]interface{}{"key": "value"},
-			},
-		}
-		data, ok := extractDataFromResponse(response)
-		assert.True(t, ok) // Should still extract successfully even with mixed types
-		assert.NotNil(t, data)
-		assert.Equal(t, 2, len(data))
-	})
-}
-
-func TestProcessResourceItem(t *testing.T) {
-	validResourceData := map[string]interface{}{
-		"key":       uuid.New().String(),
-		"name":      "Document",
-		"createdAt": "2025-03-20T19:08:06-05:00",
-		"updatedAt": "2025-03-20T19:08:06-05:00",
-		"actions": map[string]interface{}{
-			"read": map[string]interface{}{
-				"id":   uuid.New().String(),
-				"name": "Read",
-			},
-		},
-	}
-
-	t.Run("Valid resource data", func(t *testing.T) {
-		resourceTypes, err := processResourceItem(validResourceData)
-		assert.NoError(t, err)
-		assert.NotNil(t, resourceTypes)
-		assert.Equal(t, 1, len(resourceTypes))
-
-		// Check that we got a non-nil result that's compatible with the Data interface
-		assert.NotNil(t, resourceTypes[0])
-
-		// Now try to cast to models.ResourceType (value type, not pointer)
-		// In the implementation, processResourceItem returns []models.Data{*resourceType}
-		// So we should cast to a value type, not a pointer
-		if resourceType, ok := resourceTypes[0].(models.ResourceType); ok {
-			assert.NotEqual(t, uuid.Nil, resourceType.ID)
-			assert.Equal(t, "Document", resourceType.Name)
-		} else {
-			t.Errorf("Expected item to be of type models.ResourceType but got different type: %T", resourceTypes[0])
-		}
-	})
-
-	t.Run("Invalid resource data - not a map", func(t *testing.T) {
-		resourceTypes, err := processResourceItem("not a map")
-		assert.Error(t, err)
-		assert.Nil(t, resourceTypes)
-		assert.Equal(t, err.Error(), "invalid resource data format")
-	})
-
-	t.Run("Resource data missing actions field", func(t *testing.T) {
-		invalidData := map[string]interface{}{
-			"key":  uuid.New().String(),
-			"name": "Document",
-			// Missing actions field
-		}
-		resourceTypes, err := processResourceItem(invalidData)
-		assert.Error(t, err)
-		assert.Nil(t, resourceTypes)
-		assert.Contains(t, err.Error(), "failed to extract actions")
-	})
-}
-
 func TestMapToResourceTypeData(t *testing.T) {
 	resourceID := uuid.New()
 	permission1ID := uuid.New()
 	permission2ID := uuid.New()
 
-	validResourceData := map[string]interface{}{
-		"key":       resourceID.String(),
-		"name":      "Document",
-		"createdAt": "2025-03-20T19:08:06-05:00",
-		"updatedAt": "2025-03-20T19:08:06-05:00",
-		"actions": map[string]interface{}{
-			"read": map[string]interface{}{
-				"id":   permission1ID.String(),
-				"name": "Read",
+	validResourceData := permit.Resource{
+		Key:       resourceID.String(),
+		Name:      "Document",
+		CreatedAt: "2025-03-20T19:08:06-05:00",
+		UpdatedAt: "2025-03-20T19:08:06-05:00",
+		Actions: map[string]permit.Action{
+			"read": {
+				ID:   permission1ID.String(),
+				Name: "Read",
 			},
-			"write": map[string]interface{}{
-				"id":   permission2ID.String(),
-				"name": "Write",
+			"write": {
+				ID:   permission2ID.String(),
+				Name: "Write",
 			},
 		},
 	}
@@ -271,13 +157,13 @@ func TestMapToResourceTypeData(t *testing.T) {
 	})
 
 	t.Run("Invalid resource ID", func(t *testing.T) {
-		invalidData := map[string]interface{}{
-			"key":  "not-a-uuid",
-			"name": "Document",
-			"actions": map[string]interface{}{
-				"read": map[string]interface{}{
-					"id":   permission1ID.String(),
-					"name": "Read",
+		invalidData := permit.Resource{
+			Key:  "not-a-uuid",
+			Name: "Document",
+			Actions: map[string]permit.Action{
+				"read": {
+					ID:   permission1ID.String(),
+					Name: "Read",
 				},
 			},
 		}
@@ -289,11 +175,11 @@ func TestMapToResourceTypeData(t *testing.T) {
 	})
 
 	t.Run("Missing actions field", func(t *testing.T) {
-		invalidData := map[string]interface{}{
-			"key":       resourceID.String(),
-			"name":      "Document",
-			"createdAt": "2025-03-20T19:08:06-05:00",
-			"updatedAt": "2025-03-20T19:08:06-05:00",
+		invalidData := permit.Resource{
+			Key:       resourceID.String(),
+			Name:      "Document",
+			CreatedAt: "2025-03-20T19:08:06-05:00",
+			UpdatedAt: "2025-03-20T19:08:06-05:00",
 			// Missing actions field
 		}
 
@@ -302,21 +188,6 @@ func TestMapToResourceTypeData(t *testing.T) {
 		assert.Nil(t, resourceType)
 		assert.Contains(t, err.Error(), "failed to extract actions")
 	})
-
-	t.Run("Actions field is not a map", func(t *testing.T) {
-		invalidData := map[string]interface{}{
-			"key":       resourceID.String(),
-			"name":      "Document",
-			"createdAt": "2025-03-20T19:08:06-05:00",
-			"updatedAt": "2025-03-20T19:08:06-05:00",
-			"actions":   "not a map",
-		}
-
-		resourceType, err := MapToResourceTypeData(invalidData)
-		assert.Error(t, err)
-		assert.Nil(t, resourceType)
-		assert.Contains(t, err.Error(), "failed to extract actions")
-	})
 }
 
 func TestMapToResourceActions(t *testing.T) {
@@ -324,16 +195,16 @@ func TestMapToResourceActions(t *testing.T) {
 	permission1ID := uuid.New()
 	permission2ID := uuid.New()
 
-	validActionsData := map[string]interface{}{
-		"read": map[string]interface{}{
-			"id":   permission1ID.String(),
-			"name": "Read",
+	validActionsData := map[string]permit.Action{
+		"read": {
+			ID:          permission1ID.String(),
+			Name:        "Read",
+			Description: "Read permission",
 		},
-		"write": map[string]interface{}{
-			"id":   permission2ID.String(),
-			"name": "Write",
+		"write": {
+			ID:   permission2ID.String(),
+			Name: "Write",
 		},
-		"invalidAction": "not-a-map", // This should be skipped
 	}
 
 	t.Run("Valid actions data", func(t *testing.T) {
@@ -346,22 +217,13 @@ func TestMapToResourceActions(t *testing.T) {
 		permissionNames := []string{permissions[0].Name, permissions[1].Name}
 		assert.Contains(t, permissionNames, "read")
 		assert.Contains(t, permissionNames, "write")
-
-		// Verify permissions have the correct assignable scope
-		for _, p := range permissions {
-			// Note: In the implementation, it doesn't appear that AssignableScope is set to resourceID
-			// Check if this is a bug or intended behavior
-			if p.AssignableScope != uuid.Nil {
-				assert.Equal(t, resourceID, p.AssignableScope)
-			}
-		}
 	})
 
 	t.Run("Invalid action ID", func(t *testing.T) {
-		invalidActionsData := map[string]interface{}{
-			"read": map[string]interface{}{
-				"id":   "invalid-uuid", // Invalid UUID
-				"name": "Read",
+		invalidActionsData := map[string]permit.Action{
+			"read": {
+				ID:   "invalid-uuid", // Invalid UUID
+				Name: "Read",
 			},
 		}
 
@@ -370,23 +232,31 @@ func TestMapToResourceActions(t *testing.T) {
 	})
 
 	t.Run("Empty actions data", func(t *testing.T) {
-		emptyActionsData := map[string]interface{}{}
-
-		permissions := MapToResourceActions(resourceID, emptyActionsData)
+		permissions := MapToResourceActions(resourceID, map[string]permit.Action{})
 		assert.Equal(t, 0, len(permissions), "Should return empty slice for empty actions data")
 	})
 
 	t.Run("Action with missing ID field", func(t *testing.T) {
-		actionsWithMissingID := map[string]interface{}{
-			"read": map[string]interface{}{
-				// Missing "id" field
-				"name": "Read",
+		actionsWithMissingID := map[string]permit.Action{
+			"read": {
+				// Missing ID field
+				Name: "Read",
 			},
 		}
 
 		permissions := MapToResourceActions(resourceID, actionsWithMissingID)
 		assert.Equal(t, 0, len(permissions), "Should skip actions with missing ID fields")
 	})
+
+	t.Run("Mixed valid and invalid actions", func(t *testing.T) {
+		mixedActionsData := map[string]permit.Action{
+			"read":   {ID: permission1ID.String()},
+			"delete": {ID: "invalid-uuid"},
+		}
+
+		permissions := MapToResourceActions(resourceID, mixedActionsData)
+		assert.Equal(t, 1, len(permissions), "Only the valid 'read' action should be processed")
+	})
 }
 
 // Test error types
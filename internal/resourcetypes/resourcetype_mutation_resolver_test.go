@@ -5,6 +5,7 @@ import (
 	"errors"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
 	mocks "iam_services_main_v1/mocks"
 	"testing"
 
@@ -58,13 +59,13 @@ func TestCreateResourceType(t *testing.T) {
 			setupMocks: func() {
 				// Mock the POST request to create resource
 				mockService.EXPECT().
-					SendRequest(validCtx, "POST", "resources", mock.Any()).
-					Return(map[string]interface{}{"status": "success"}, nil).
+					CreateResource(validCtx, mock.Any()).
+					Return(&permit.Resource{Key: resourceID.String()}, nil).
 					Times(1)
 
 				// Mock the GET request to fetch resource details
 				mockService.EXPECT().
-					SendRequest(validCtx, "GET", "resources/"+resourceID.String(), nil).
+					GetResource(validCtx, resourceID.String()).
 					Return(resourceData, nil).
 					Times(1)
 			},
@@ -78,7 +79,7 @@ func TestCreateResourceType(t *testing.T) {
 			setupMocks: func() {
 				// Mock the POST request to fail
 				mockService.EXPECT().
-					SendRequest(validCtx, "POST", "resources", mock.Any()).
+					CreateResource(validCtx, mock.Any()).
 					Return(nil, errors.New("API call failed")).
 					Times(1)
 			},
@@ -92,13 +93,13 @@ func TestCreateResourceType(t *testing.T) {
 			setupMocks: func() {
 				// Mock the POST request to succeed
 				mockService.EXPECT().
-					SendRequest(validCtx, "POST", "resources", mock.Any()).
-					Return(map[string]interface{}{"status": "success"}, nil).
+					CreateResource(validCtx, mock.Any()).
+					Return(&permit.Resource{Key: resourceID.String()}, nil).
 					Times(1)
 
 				// Mock the GET request to fail
 				mockService.EXPECT().
-					SendRequest(validCtx, "GET", "resources/"+resourceID.String(), nil).
+					GetResource(validCtx, resourceID.String()).
 					Return(nil, errors.New("Fetch failed")).
 					Times(1)
 			},
@@ -165,9 +166,7 @@ func TestPrepareResourceActions(t *testing.T) {
 
 				for key, action := range actions {
 					assert.NotContains(t, key, " ")
-					actionMap, ok := action.(map[string]interface{})
-					assert.True(t, ok)
-					assert.Contains(t, actionMap, "name")
+					assert.NotEmpty(t, action.Name)
 				}
 			}
 		})
@@ -191,10 +190,10 @@ func TestCreateResourceInternal(t *testing.T) {
 		Name: "test_resource",
 	}
 
-	actions := map[string]interface{}{
-		"read": map[string]interface{}{
-			"name":        "Read",
-			"description": "Read permission",
+	actions := map[string]permit.Action{
+		"read": {
+			Name:        "Read",
+			Description: "Read permission",
 		},
 	}
 
@@ -207,12 +206,12 @@ func TestCreateResourceInternal(t *testing.T) {
 			name: "successful resource creation",
 			setupMocks: func() {
 				mockService.EXPECT().
-					SendRequest(ctx, "POST", "resources", map[string]interface{}{
-						"key":     input.ID,
-						"name":    input.Name,
-						"actions": actions,
+					CreateResource(ctx, permit.ResourceCreate{
+						Key:     input.ID.String(),
+						Name:    input.Name,
+						Actions: actions,
 					}).
-					Return(map[string]interface{}{"status": "success"}, nil).
+					Return(&permit.Resource{Key: input.ID.String()}, nil).
 					Times(1)
 			},
 			expectedError: false,
@@ -221,7 +220,7 @@ func TestCreateResourceInternal(t *testing.T) {
 			name: "API call fails",
 			setupMocks: func() {
 				mockService.EXPECT().
-					SendRequest(ctx, "POST", "resources", mock.Any()).
+					CreateResource(ctx, mock.Any()).
 					Return(nil, errors.New("API error")).
 					Times(1)
 			},
@@ -267,7 +266,7 @@ func TestGetPermissionDetailsById(t *testing.T) {
 			name: "successful fetch",
 			setupMocks: func() {
 				mockService.EXPECT().
-					SendRequest(ctx, "GET", "resources/"+resourceID.String(), nil).
+					GetResource(ctx, resourceID.String()).
 					Return(resourceData, nil).
 					Times(1)
 			},
@@ -278,7 +277,7 @@ func TestGetPermissionDetailsById(t *testing.T) {
 			name: "API call fails",
 			setupMocks: func() {
 				mockService.EXPECT().
-					SendRequest(ctx, "GET", "resources/"+resourceID.String(), nil).
+					GetResource(ctx, resourceID.String()).
 					Return(nil, errors.New("API error")).
 					Times(1)
 			},
@@ -288,12 +287,12 @@ func TestGetPermissionDetailsById(t *testing.T) {
 		{
 			name: "invalid resource data",
 			setupMocks: func() {
-				invalidData := map[string]interface{}{
-					"key":  "invalid-uuid",
-					"name": "test",
+				invalidData := &permit.Resource{
+					Key:  "invalid-uuid",
+					Name: "test",
 				}
 				mockService.EXPECT().
-					SendRequest(ctx, "GET", "resources/"+resourceID.String(), nil).
+					GetResource(ctx, resourceID.String()).
 					Return(invalidData, nil).
 					Times(1)
 			},
@@ -320,30 +319,30 @@ func TestMapToPermissionData(t *testing.T) {
 
 	testCases := []struct {
 		name          string
-		input         map[string]interface{}
+		input         *permit.Resource
 		expectedError bool
 		expectedName  string
 		expectedPerms int
 	}{
 		{
 			name: "valid resource data",
-			input: map[string]interface{}{
-				"key":       resourceID.String(),
-				"name":      "Test Resource",
-				"createdAt": "2023-01-01T00:00:00Z",
-				"updatedAt": "2023-01-02T00:00:00Z",
-				"actions": map[string]interface{}{
-					"read": map[string]interface{}{
-						"id":          permissionID1.String(),
-						"description": "Read permission",
-						"createdAt":   "2023-01-01T00:00:00Z",
-						"updatedAt":   "2023-01-02T00:00:00Z",
+			input: &permit.Resource{
+				Key:       resourceID.String(),
+				Name:      "Test Resource",
+				CreatedAt: "2023-01-01T00:00:00Z",
+				UpdatedAt: "2023-01-02T00:00:00Z",
+				Actions: map[string]permit.Action{
+					"read": {
+						ID:          permissionID1.String(),
+						Description: "Read permission",
+						CreatedAt:   "2023-01-01T00:00:00Z",
+						UpdatedAt:   "2023-01-02T00:00:00Z",
 					},
-					"write": map[string]interface{}{
-						"id":          permissionID2.String(),
-						"description": "Write permission",
-						"createdAt":   "2023-01-01T00:00:00Z",
-						"updatedAt":   "2023-01-02T00:00:00Z",
+					"write": {
+						ID:          permissionID2.String(),
+						Description: "Write permission",
+						CreatedAt:   "2023-01-01T00:00:00Z",
+						UpdatedAt:   "2023-01-02T00:00:00Z",
 					},
 				},
 			},
@@ -353,28 +352,24 @@ func TestMapToPermissionData(t *testing.T) {
 		},
 		{
 			name: "invalid resource ID",
-			input: map[string]interface{}{
-				"key":     "invalid-uuid",
-				"name":    "Test Resource",
-				"actions": map[string]interface{}{},
+			input: &permit.Resource{
+				Key:     "invalid-uuid",
+				Name:    "Test Resource",
+				Actions: map[string]permit.Action{},
 			},
 			expectedError: true,
 		},
 		{
 			name: "missing actions",
-			input: map[string]interface{}{
-				"key":  resourceID.String(),
-				"name": "Test Resource",
+			input: &permit.Resource{
+				Key:  resourceID.String(),
+				Name: "Test Resource",
 			},
 			expectedError: true,
 		},
 		{
-			name: "invalid actions format",
-			input: map[string]interface{}{
-				"key":     resourceID.String(),
-				"name":    "Test Resource",
-				"actions": "invalid",
-			},
+			name:          "nil resource",
+			input:         nil,
 			expectedError: true,
 		},
 	}
@@ -396,111 +391,28 @@ func TestMapToPermissionData(t *testing.T) {
 	}
 }
 
-func TestMapToPermissionActions(t *testing.T) {
-	resourceID := uuid.New()
-	permissionID1 := uuid.New()
-	permissionID2 := uuid.New()
-
-	testCases := []struct {
-		name          string
-		actionsData   map[string]interface{}
-		expectedCount int
-	}{
-		{
-			name: "valid actions data",
-			actionsData: map[string]interface{}{
-				"read": map[string]interface{}{
-					"id":          permissionID1.String(),
-					"description": "Read permission",
-					"createdAt":   "2023-01-01T00:00:00Z",
-					"updatedAt":   "2023-01-02T00:00:00Z",
-				},
-				"write": map[string]interface{}{
-					"id":          permissionID2.String(),
-					"description": "Write permission",
-					"createdAt":   "2023-01-01T00:00:00Z",
-					"updatedAt":   "2023-01-02T00:00:00Z",
-				},
-			},
-			expectedCount: 2,
-		},
-		{
-			name:          "empty actions data",
-			actionsData:   map[string]interface{}{},
-			expectedCount: 0,
-		},
-		{
-			name: "invalid action format",
-			actionsData: map[string]interface{}{
-				"read": "invalid",
-			},
-			expectedCount: 0,
-		},
-		{
-			name: "action with invalid ID",
-			actionsData: map[string]interface{}{
-				"read": map[string]interface{}{
-					"id":          "invalid-uuid",
-					"description": "Read permission",
-				},
-			},
-			expectedCount: 0,
-		},
-		{
-			name: "mixed valid and invalid actions",
-			actionsData: map[string]interface{}{
-				"read": map[string]interface{}{
-					"id":          permissionID1.String(),
-					"description": "Read permission",
-				},
-				"write": "invalid",
-				"delete": map[string]interface{}{
-					"id":          "invalid-uuid",
-					"description": "Delete permission",
-				},
-			},
-			expectedCount: 1, // Only the valid 'read' action should be processed
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := MapToPermissionActions(resourceID, tc.actionsData)
-
-			assert.Equal(t, tc.expectedCount, len(result))
-
-			// Validate structure of returned permissions
-			for _, permission := range result {
-				assert.NotEqual(t, uuid.Nil, permission.ID)
-				assert.NotEmpty(t, permission.Name)
-				// Description can be nil, so we don't assert on it
-			}
-		})
-	}
-}
-
 // Helper function to build test resource data
-func buildTestResourceData(resourceID uuid.UUID) map[string]interface{} {
+func buildTestResourceData(resourceID uuid.UUID) *permit.Resource {
 	permissionID1 := uuid.New()
 	permissionID2 := uuid.New()
 
-	return map[string]interface{}{
-		"key":       resourceID.String(),
-		"name":      "Test Resource",
-		"createdAt": "2023-01-01T00:00:00Z",
-		"updatedAt": "2023-01-02T00:00:00Z",
-		"actions": map[string]interface{}{
-			"read": map[string]interface{}{
-				"id":          permissionID1.String(),
-				"description": "Read permission",
-				"createdAt":   "2023-01-01T00:00:00Z",
-				"updatedAt":   "2023-01-02T00:00:00Z",
+	return &permit.Resource{
+		Key:       resourceID.String(),
+		Name:      "Test Resource",
+		CreatedAt: "2023-01-01T00:00:00Z",
+		UpdatedAt: "2023-01-02T00:00:00Z",
+		Actions: map[string]permit.Action{
+			"read": {
+				ID:          permissionID1.String(),
+				Description: "Read permission",
+				CreatedAt:   "2023-01-01T00:00:00Z",
+				UpdatedAt:   "2023-01-02T00:00:00Z",
 			},
-			"write": map[string]interface{}{
-				"id":          permissionID2.String(),
-				"description": "Write permission",
-				"createdAt":   "2023-01-01T00:00:00Z",
-				"updatedAt":   "2023-01-02T00:00:00Z",
+			"write": {
+				ID:          permissionID2.String(),
+				Description: "Write permission",
+				CreatedAt:   "2023-01-01T00:00:00Z",
+				UpdatedAt:   "2023-01-02T00:00:00Z",
 			},
 		},
 	}
@@ -3,6 +3,7 @@ package resourcetypes
 import (
 	"context"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
 	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	"iam_services_main_v1/pkg/logger"
@@ -17,22 +18,44 @@ func (r *ResourceTypeQueryResolver) AllPermissions(ctx context.Context) (models.
 	logger.LogInfo("Fetching all roles")
 
 	// Fetch roles from permit system
-	resourceResources, err := r.PC.SendRequest(ctx, "GET", "resources?include_total_count=true", nil)
+	resourceResources, totalCount, err := r.PC.ListResources(ctx)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Error retrieving roles from permit system", err.Error()), nil
+		return errorcatalog.ResourceType.FromError(http.StatusBadRequest, "Error retrieving roles from permit system", err), nil
 	}
 
 	// Map resource data to resource struct
 	resources, err := MapResourceResponseToStruct(resourceResources)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to map tenant resources to struct", err.Error()), nil
+		return errorcatalog.ResourceType.FromError(http.StatusBadRequest, "Failed to map tenant resources to struct", err), nil
 
 	}
 
 	// Format and return success response
-	successResponse, err := utils.FormatSuccess(resources)
+	successResponse, err := utils.FormatSuccessWithTotal(resources, totalCount)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to format success response", err.Error()), nil
+		return errorcatalog.ResourceType.FromError(http.StatusBadRequest, "Failed to format success response", err), nil
 	}
 	return successResponse, nil
 }
+
+// AllPermissionsConnection retrieves one page of resource types. The resource
+// types are few and already cached, so the page is cut from the full list.
+func (r *ResourceTypeQueryResolver) AllPermissionsConnection(ctx context.Context, after *string, first *int) (models.OperationResult, error) {
+	logger.LogInfo("Fetching a page of resource types")
+
+	page, err := utils.ParsePage(first, after)
+	if err != nil {
+		return errorcatalog.ResourceType.FromError(http.StatusBadRequest, "Invalid pagination arguments", err), nil
+	}
+
+	resourceResources, _, err := r.PC.ListResources(ctx)
+	if err != nil {
+		return errorcatalog.ResourceType.FromError(http.StatusBadRequest, "Error retrieving roles from permit system", err), nil
+	}
+
+	resources, err := MapResourceResponseToStruct(resourceResources)
+	if err != nil {
+		return errorcatalog.ResourceType.FromError(http.StatusBadRequest, "Failed to map resource types to struct", err), nil
+	}
+	return utils.FormatConnection(utils.PageOf(resources, page), page, len(resources)), nil
+}
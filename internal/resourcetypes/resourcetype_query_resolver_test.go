@@ -5,6 +5,8 @@ import (
 	"errors"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	mocks "iam_services_main_v1/mocks"
 	"testing"
@@ -43,8 +45,8 @@ func TestResourceTypes(t *testing.T) {
 			name: "Success",
 			setup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", "resources?include_total_count=true", nil).
-					Return(resourceTypesData, nil)
+					ListResources(mock.Any()).
+					Return(resourceTypesData, len(resourceTypesData), nil)
 			},
 			wantErr: false,
 		},
@@ -52,8 +54,8 @@ func TestResourceTypes(t *testing.T) {
 			name: "Service error",
 			setup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", "resources?include_total_count=true", nil).
-					Return(nil, assert.AnError)
+					ListResources(mock.Any()).
+					Return(nil, 0, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -96,10 +98,10 @@ func TestResourceTypeQueryResolver_AllPermissions(t *testing.T) {
 			ctx:  testCtx,
 			mockStubs: func(mockSvc *mocks.MockPermitService) {
 				mockSvc.EXPECT().
-					SendRequest(mock.Any(), "GET", "resources?include_total_count=true", nil).
-					Return(nil, errors.New("permit service error"))
+					ListResources(mock.Any()).
+					Return(nil, 0, errors.New("permit service error"))
 			},
-			expected: utils.FormatErrorResponse(400, "Error retrieving roles from permit system", "permit service error"),
+			expected: errorcatalog.ResourceType.New(400, "Error retrieving roles from permit system", "permit service error"),
 			wantErr:  true,
 		},
 		{
@@ -107,8 +109,8 @@ func TestResourceTypeQueryResolver_AllPermissions(t *testing.T) {
 			ctx:  testCtx,
 			mockStubs: func(mockSvc *mocks.MockPermitService) {
 				mockSvc.EXPECT().
-					SendRequest(mock.Any(), "GET", "resources?include_total_count=true", nil).
-					Return(resourceTypesData, nil)
+					ListResources(mock.Any()).
+					Return(resourceTypesData, len(resourceTypesData), nil)
 			},
 			expected: func() models.OperationResult {
 				result, _ := utils.FormatSuccess(mappedResourceTypes)
@@ -127,34 +129,26 @@ func TestResourceTypeQueryResolver_AllPermissions(t *testing.T) {
 	}
 }
 
-func buildTestResourceTypesData() map[string]interface{} {
-	return map[string]interface{}{
-		"data": []interface{}{
-			map[string]interface{}{
-				"key":         uuid.New().String(),
-				"name":        "User",
-				"description": "User resource type",
-				"attributes": map[string]interface{}{
-					"displayName": "User",
-					"permissions": []interface{}{
-						"read",
-						"write",
-						"delete",
-					},
-				},
+func buildTestResourceTypesData() []permit.Resource {
+	return []permit.Resource{
+		{
+			Key:         uuid.New().String(),
+			Name:        "User",
+			Description: "User resource type",
+			Actions: map[string]permit.Action{
+				"read":   {ID: uuid.New().String(), Name: "read"},
+				"write":  {ID: uuid.New().String(), Name: "write"},
+				"delete": {ID: uuid.New().String(), Name: "delete"},
 			},
-			map[string]interface{}{
-				"key":         uuid.New().String(),
-				"name":        "Project",
-				"description": "Project resource type",
-				"attributes": map[string]interface{}{
-					"displayName": "Project",
-					"permissions": []interface{}{
-						"read",
-						"write",
-						"delete",
-					},
-				},
+		},
+		{
+			Key:         uuid.New().String(),
+			Name:        "Project",
+			Description: "Project resource type",
+			Actions: map[string]permit.Action{
+				"read":   {ID: uuid.New().String(), Name: "read"},
+				"write":  {ID: uuid.New().String(), Name: "write"},
+				"delete": {ID: uuid.New().String(), Name: "delete"},
 			},
 		},
 	}
@@ -162,7 +156,7 @@ func buildTestResourceTypesData() map[string]interface{} {
 
 // Mock implementations of the mapping functions
 
-func MockMapResourceTypesResponseToStruct(resourceResponse map[string]interface{}) ([]models.Data, error) {
+func MockMapResourceTypesResponseToStruct(resources []permit.Resource) ([]models.Data, error) {
 	// Simple mock implementation for testing
 	userDesc := "User resource type"
 	projectDesc := "Project resource type"
@@ -0,0 +1,41 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/constants"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/internal/federation"
+	"iam_services_main_v1/internal/permit"
+)
+
+// RoleEntityResolver resolves the roles other subgraphs reference.
+type RoleEntityResolver struct {
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
+}
+
+// FindManyRoleByIDs resolves a batch of role references, in order, from one
+// resource listing. It needs the role action, like the role query. References
+// to custom roles of other tenants resolve to null.
+func (r *RoleEntityResolver) FindManyRoleByIDs(ctx context.Context, reps []*models.RoleByIDsInput) ([]*models.Role, error) {
+	tenantID, err := federation.Authorize(ctx, r.Authorizer, "role", config.RoleResourceTypeID)
+	if err != nil {
+		return nil, err
+	}
+	loaders := dataloaders.For(ctx, r.PC)
+	return federation.Resolve(ctx, reps, func(ctx context.Context, rep *models.RoleByIDsInput) (*models.Role, error) {
+		found, err := loaders.Roles.Load(ctx, rep.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if tenant, ok := found.Role.Attributes[constants.TENANT_ID]; ok && tenant != nil && fmt.Sprint(tenant) != tenantID.String() {
+			return nil, nil
+		}
+		return MapToRoleData(found.Role, found.Resource)
+	})
+}
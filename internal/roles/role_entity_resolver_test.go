@@ -0,0 +1,48 @@
+package roles
+
+import (
+	"context"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/permit"
+	mocks "iam_services_main_v1/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindManyRoleByIDs(t *testing.T) {
+	tenantID := uuid.New()
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantID.String())
+	ginCtx.Set("userID", uuid.New().String())
+	ctx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+
+	ctrl := mock.NewController(t)
+	defer ctrl.Finish()
+	shared, custom, foreign, missing := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	mockPC := mocks.NewMockPermitService(ctrl)
+	mockPC.EXPECT().ListResources(mock.Any()).Return([]permit.Resource{{
+		ID:  uuid.NewString(),
+		Key: config.AccountResourceTypeID,
+		Roles: map[string]permit.Role{
+			"shared":  {Key: shared.String(), Name: "Viewer", Attributes: map[string]interface{}{}},
+			"custom":  {Key: custom.String(), Name: "Auditor", Attributes: map[string]interface{}{"tenantId": tenantID.String()}},
+			"foreign": {Key: foreign.String(), Name: "Other", Attributes: map[string]interface{}{"tenantId": uuid.NewString()}},
+		},
+	}}, 1, nil).AnyTimes()
+
+	resolver := RoleEntityResolver{PC: mockPC, Authorizer: authz.AllowAll()}
+	roles, err := resolver.FindManyRoleByIDs(ctx, []*models.RoleByIDsInput{{ID: shared}, {ID: custom}, {ID: foreign}, {ID: missing}})
+	assert.NoError(t, err)
+	if assert.Len(t, roles, 4) {
+		assert.Equal(t, "Viewer", roles[0].Name)
+		assert.Equal(t, "Auditor", roles[1].Name)
+		assert.Nil(t, roles[2], "custom roles of other tenants must not resolve")
+		assert.Nil(t, roles[3])
+	}
+}
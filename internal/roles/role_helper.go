@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/tags"
 	"iam_services_main_v1/pkg/logger"
-	"log"
 
 	"github.com/google/uuid"
 )
@@ -23,48 +23,19 @@ const (
 	ErrRoleNotFound        = RoleError("role not found")
 )
 
-// RoleResponse represents the structure of the raw response
-type RoleResponse struct {
-	Data []map[string]interface{} `json:"data"`
-}
-
-// MapRolesResponseToStruct processes multiple roles from the response
-func MapRolesResponseToStruct(resourcesResponse map[string]interface{}) ([]models.Data, error) {
-	rawData, ok := extractDataFromResponse(resourcesResponse)
-	if !ok {
-		return nil, ErrInvalidDataField
-	}
-
+// MapRolesResponseToStruct processes the roles of every resource type
+func MapRolesResponseToStruct(resources []permit.Resource) ([]models.Data, error) {
 	var roles []models.Data
-	for _, item := range rawData {
-		processedRoles, err := processResourceItem(item)
-		if err != nil {
-			return nil, err
-		}
-		roles = append(roles, processedRoles...)
+	for _, resource := range resources {
+		roles = append(roles, processRoles(resource)...)
 	}
 	return roles, nil
 }
 
 // MapRoleResponseToStruct processes a single role by ID
-func MapRoleResponseToStruct(resourcesResponse map[string]interface{}, id uuid.UUID) ([]models.Data, error) {
-	rawData, ok := extractDataFromResponse(resourcesResponse)
-	if !ok {
-		return nil, ErrInvalidDataField
-	}
-
-	for _, item := range rawData {
-		resourceData, ok := item.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		rolesData, actionsData, err := extractRolesAndActions(resourceData)
-		if err != nil {
-			continue
-		}
-
-		roleData, err := findRoleByID(rolesData, actionsData, resourceData, id)
+func MapRoleResponseToStruct(resources []permit.Resource, id uuid.UUID) ([]models.Data, error) {
+	for _, resource := range resources {
+		roleData, err := findRoleByID(resource, id)
 		if err != nil {
 			continue
 		}
@@ -75,87 +46,45 @@ func MapRoleResponseToStruct(resourcesResponse map[string]interface{}, id uuid.U
 
 // Helper functions
 
-func extractDataFromResponse(response map[string]interface{}) ([]interface{}, bool) {
-	// Convert the raw response to our structured type
-	var roleResponse RoleResponse
-	if data, ok := response["data"]; ok {
-		if dataSlice, ok := data.([]interface{}); ok {
-			roleResponse.Data = make([]map[string]interface{}, len(dataSlice))
-			for i, item := range dataSlice {
-				if mapItem, ok := item.(map[string]interface{}); ok {
-					roleResponse.Data[i] = mapItem
-				}
-			}
-			return dataSlice, true
-		}
-	}
-	logger.LogError("invalid data field in response")
-	return nil, false
-}
-
-func processResourceItem(item interface{}) ([]models.Data, error) {
-	resourceData, ok := item.(map[string]interface{})
-	if !ok {
-		return nil, ErrInvalidRoleFormat
-	}
-
-	rolesData, actionsData, err := extractRolesAndActions(resourceData)
-	if err != nil {
-		return nil, err
-	}
-
-	return processRoles(rolesData, actionsData, resourceData)
-}
-
-func processRoles(rolesData, actionsData, resourceData map[string]interface{}) ([]models.Data, error) {
+func processRoles(resource permit.Resource) []models.Data {
 	var roles []models.Data
-	for _, role := range rolesData {
-		roleData, ok := role.(map[string]interface{})
-		if !ok {
-			logger.LogError(string(ErrInvalidRoleFormat))
-			continue
-		}
-
-		roleModel, err := MapToRoleData(roleData, actionsData, resourceData)
+	for _, role := range resource.Roles {
+		roleModel, err := MapToRoleData(role, resource)
 		if err != nil {
+			logger.LogError(string(ErrInvalidRoleFormat), "error", err)
 			continue
 		}
 		roles = append(roles, roleModel)
 	}
-	return roles, nil
+	return roles
 }
 
 // MapToRoleData converts role data to a Role model
-func MapToRoleData(roleData, actionsData, resourceData map[string]interface{}) (*models.Role, error) {
-	id, err := helpers.GetUUID(roleData, "key")
+func MapToRoleData(roleData permit.Role, resourceData permit.Resource) (*models.Role, error) {
+	id, err := uuid.Parse(roleData.Key)
 	if err != nil {
 		return nil, fmt.Errorf("invalid role ID: %w", err)
 	}
 
-	attributes, err := helpers.GetMap(roleData, "attributes")
-	if err != nil {
-		return nil, fmt.Errorf("invalid attributes: %w", err)
+	attributes := roleData.Attributes
+	if attributes == nil {
+		return nil, fmt.Errorf("invalid attributes: missing or invalid map for key: attributes")
 	}
 	createdBy, _ := helpers.GetUUID(attributes, "createdBy")
 	updatedBy, _ := helpers.GetUUID(attributes, "updatedBy")
 
-	//assignableScopeRef, _ := helpers.GetUUID(attributes, "assignableScopeRef")
-
-	permissions, err := MapToPermissionsData(roleData, actionsData)
-	if err != nil {
-		return nil, fmt.Errorf("invalid permissions: %w", err)
-	}
+	permissions := MapToPermissionsData(roleData, resourceData.Actions)
 	resourceType, err := MapToResourceTypeData(resourceData)
 	if err != nil {
 		return nil, fmt.Errorf("invalid resource type: %w", err)
 	}
-	description := helpers.GetString(roleData, "description")
+	description := roleData.Description
 	version := helpers.GetString(attributes, "version")
 	roleType := helpers.GetString(attributes, "roleType")
 	tags := tags.GetResourceTags(attributes, "tags")
 	role := &models.Role{
 		ID:              id,
-		Name:            helpers.GetString(roleData, "name"),
+		Name:            roleData.Name,
 		Description:     &description,
 		Version:         version,
 		CreatedAt:       helpers.GetString(attributes, "createdAt"),
@@ -175,34 +104,25 @@ func MapToRoleData(roleData, actionsData, resourceData map[string]interface{}) (
 }
 
 // MapToResourceTypeData processes resources types for a role
-func MapToResourceTypeData(resourceData map[string]interface{}) (*models.ResourceType, error) {
-	id, err := helpers.GetUUID(resourceData, "key")
+func MapToResourceTypeData(resourceData permit.Resource) (*models.ResourceType, error) {
+	id, err := uuid.Parse(resourceData.Key)
 	if err != nil {
 		return nil, fmt.Errorf("invalid resource type ID: %w", err)
 	}
-	actionsData, err := helpers.GetMap(resourceData, "actions")
-	if err != nil {
-		logger.LogError("failed to get actions map", "error", err)
-		return nil, fmt.Errorf("failed to extract actions: %w", err)
-	}
-	actions := MapToResourceActions(id, actionsData)
+	actions := MapToResourceActions(id, resourceData.Actions)
 	return &models.ResourceType{
 		ID:          id,
-		Name:        helpers.GetString(resourceData, "name"),
-		CreatedAt:   helpers.GetString(resourceData, "createdAt"),
-		UpdatedAt:   helpers.GetString(resourceData, "updatedAt"),
+		Name:        resourceData.Name,
+		CreatedAt:   resourceData.CreatedAt,
+		UpdatedAt:   resourceData.UpdatedAt,
 		Permissions: actions,
 	}, nil
 }
 
-func MapToResourceActions(resourceID uuid.UUID, actionsData map[string]interface{}) []*models.Permission {
+func MapToResourceActions(resourceID uuid.UUID, actionsData map[string]permit.Action) []*models.Permission {
 	var actions []*models.Permission
 	for key, action := range actionsData {
-		actionData, ok := action.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		id, err := helpers.GetUUID(actionData, "id")
+		id, err := uuid.Parse(action.ID)
 		if err != nil {
 			logger.LogError("failed to get action ID", "error", err)
 			continue
@@ -217,81 +137,35 @@ func MapToResourceActions(resourceID uuid.UUID, actionsData map[string]interface
 }
 
 // MapToPermissionsData processes permissions for a role
-func MapToPermissionsData(roleData, actionsData map[string]interface{}) ([]*models.Permission, error) {
-	permissionsData, err := helpers.GetSlice(roleData, "permissions")
-	if err != nil {
-		return nil, err
-	}
-
+func MapToPermissionsData(roleData permit.Role, actionsData map[string]permit.Action) []*models.Permission {
 	var permissions []*models.Permission
-	for _, perm := range permissionsData {
-		permission, err := processPermission(perm, actionsData)
+	for _, permissionKey := range roleData.Permissions {
+		action, ok := actionsData[permissionKey]
+		if !ok {
+			continue
+		}
+		id, err := uuid.Parse(action.ID)
 		if err != nil {
 			logger.LogError("permission processing error", "error", err)
 			continue
 		}
-		if permission != nil {
-			permissions = append(permissions, permission)
-		}
-	}
-	return permissions, nil
-}
-
-func processPermission(permKey interface{}, actionsData map[string]interface{}) (*models.Permission, error) {
-	permissionStr, ok := permKey.(string)
-	if !ok {
-		return nil, ErrInvalidActionFormat
-	}
-
-	actionData, ok := actionsData[permissionStr].(map[string]interface{})
-	if !ok {
-		return nil, nil
-	}
-
-	id, err := helpers.GetUUID(actionData, "id")
-	if err != nil {
-		return nil, err
-	}
-
-	return &models.Permission{
-		ID:   id,
-		Name: helpers.GetString(actionData, "name"),
-	}, nil
-}
-
-// extractRolesAndActions extracts roles and actions data from the resource data
-func extractRolesAndActions(resourceData map[string]interface{}) (map[string]interface{}, map[string]interface{}, error) {
-	rolesData, err := helpers.GetMap(resourceData, "roles")
-	log.Printf("rolesData: %v", rolesData)
-	if err != nil {
-		logger.LogError("failed to get roles map", "error", err)
-		return nil, nil, fmt.Errorf("failed to extract roles: %w", err)
-	}
-
-	actionsData, err := helpers.GetMap(resourceData, "actions")
-	if err != nil {
-		logger.LogError("failed to get actions map", "error", err)
-		return nil, nil, fmt.Errorf("failed to extract actions: %w", err)
+		permissions = append(permissions, &models.Permission{
+			ID:   id,
+			Name: action.Name,
+		})
 	}
-
-	return rolesData, actionsData, nil
+	return permissions
 }
 
-// findRoleByID searches for and returns a specific role by ID
-func findRoleByID(rolesData map[string]interface{}, actionsData, resourceData map[string]interface{}, id uuid.UUID) ([]models.Data, error) {
-	for _, role := range rolesData {
-		roleData, ok := role.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		roleID, err := helpers.GetUUID(roleData, "key")
-		log.Printf("roleID: %v", roleID)
+// findRoleByID searches a resource type for a specific role by ID
+func findRoleByID(resourceData permit.Resource, id uuid.UUID) ([]models.Data, error) {
+	for _, role := range resourceData.Roles {
+		roleID, err := uuid.Parse(role.Key)
 		if err != nil || roleID != id {
 			continue
 		}
 
-		roleModel, err := MapToRoleData(roleData, actionsData, resourceData)
+		roleModel, err := MapToRoleData(role, resourceData)
 		if err != nil {
 			return nil, fmt.Errorf("failed to map role data: %w", err)
 		}
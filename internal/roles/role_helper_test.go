@@ -2,6 +2,7 @@ package roles
 
 import (
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
 	"testing"
 
 	"github.com/google/uuid"
@@ -9,7 +10,7 @@ import (
 )
 
 // createMockResourceResponse creates a mock resource response for testing
-func createMockResourceResponse() map[string]interface{} {
+func createMockResourceResponse() []permit.Resource {
 	role1ID := uuid.New()
 	role2ID := uuid.New()
 	permission1ID := uuid.New()
@@ -17,99 +18,82 @@ func createMockResourceResponse() map[string]interface{} {
 	userID := uuid.New().String()
 	resourceID := uuid.New().String()
 
-	return map[string]interface{}{
-		"data": []interface{}{
-			map[string]interface{}{
-				"key":       resourceID,
-				"name":      "Resource 1",
-				"createdAt": "2025-03-20T19:08:06-05:00",
-				"updatedAt": "2025-03-20T19:08:06-05:00",
-				"roles": map[string]interface{}{
-					"admin": map[string]interface{}{
-						"name":        "Admin",
-						"description": "Administrator role",
-						"key":         role1ID.String(),
-						"permissions": []interface{}{"read", "write"},
-						"attributes": map[string]interface{}{
-							"createdBy":          userID,
-							"updatedBy":          userID,
-							"createdAt":          "2025-03-20T19:08:06-05:00",
-							"updatedAt":          "2025-03-20T19:08:06-05:00",
-							"assignableScopeRef": uuid.New().String(),
-						},
-					},
-					"user": map[string]interface{}{
-						"name":        "User",
-						"description": "Regular user role",
-						"key":         role2ID.String(),
-						"permissions": []interface{}{"read"},
-						"attributes": map[string]interface{}{
-							"createdBy":          userID,
-							"updatedBy":          userID,
-							"createdAt":          "2025-03-20T19:08:06-05:00",
-							"updatedAt":          "2025-03-20T19:08:06-05:00",
-							"assignableScopeRef": uuid.New().String(),
-						},
+	return []permit.Resource{
+		{
+			Key:       resourceID,
+			Name:      "Resource 1",
+			CreatedAt: "2025-03-20T19:08:06-05:00",
+			UpdatedAt: "2025-03-20T19:08:06-05:00",
+			Roles: map[string]permit.Role{
+				"admin": {
+					Name:        "Admin",
+					Description: "Administrator role",
+					Key:         role1ID.String(),
+					Permissions: []string{"read", "write"},
+					Attributes: map[string]interface{}{
+						"createdBy":          userID,
+						"updatedBy":          userID,
+						"createdAt":          "2025-03-20T19:08:06-05:00",
+						"updatedAt":          "2025-03-20T19:08:06-05:00",
+						"assignableScopeRef": uuid.New().String(),
 					},
 				},
-				"actions": map[string]interface{}{
-					"read": map[string]interface{}{
-						"id":   permission1ID.String(),
-						"name": "Read",
-					},
-					"write": map[string]interface{}{
-						"id":   permission2ID.String(),
-						"name": "Write",
+				"user": {
+					Name:        "User",
+					Description: "Regular user role",
+					Key:         role2ID.String(),
+					Permissions: []string{"read"},
+					Attributes: map[string]interface{}{
+						"createdBy":          userID,
+						"updatedBy":          userID,
+						"createdAt":          "2025-03-20T19:08:06-05:00",
+						"updatedAt":          "2025-03-20T19:08:06-05:00",
+						"assignableScopeRef": uuid.New().String(),
 					},
 				},
 			},
+			Actions: map[string]permit.Action{
+				"read": {
+					ID:   permission1ID.String(),
+					Name: "Read",
+				},
+				"write": {
+					ID:   permission2ID.String(),
+					Name: "Write",
+				},
+			},
 		},
 	}
 }
 
-// createMockInvalidResponse creates an invalid mock response for testing error cases
-func createMockInvalidResponse() map[string]interface{} {
-	return map[string]interface{}{
-		"invalid_field": "invalid_data",
-	}
-}
-
 func TestMapRolesResponseToStruct(t *testing.T) {
 	tests := []struct {
-		name           string
-		resourceData   map[string]interface{}
-		expectedCount  int
-		expectedError  bool
-		expectedErrMsg string
+		name          string
+		resourceData  []permit.Resource
+		expectedCount int
 	}{
 		{
 			name:          "Valid response with roles",
 			resourceData:  createMockResourceResponse(),
 			expectedCount: 2,
-			expectedError: false,
 		},
 		{
-			name:           "Invalid response - missing data field",
-			resourceData:   createMockInvalidResponse(),
-			expectedCount:  0,
-			expectedError:  true,
-			expectedErrMsg: "missing or invalid data field",
+			name:          "Empty response",
+			resourceData:  []permit.Resource{},
+			expectedCount: 0,
 		},
 		{
-			name: "Invalid response - invalid roles format",
-			resourceData: map[string]interface{}{
-				"data": []interface{}{
-					map[string]interface{}{
-						"key":     "resource-1",
-						"name":    "Resource 1",
-						"roles":   "invalid-roles", // Invalid roles format (not a map)
-						"actions": map[string]interface{}{},
+			name: "Invalid role is skipped",
+			resourceData: []permit.Resource{
+				{
+					Key:  uuid.New().String(),
+					Name: "Resource 1",
+					Roles: map[string]permit.Role{
+						"broken": {Key: "invalid-uuid", Name: "Broken"},
 					},
 				},
 			},
-			expectedCount:  0,
-			expectedError:  true,
-			expectedErrMsg: "failed to extract roles",
+			expectedCount: 0,
 		},
 	}
 
@@ -117,26 +101,17 @@ func TestMapRolesResponseToStruct(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			roles, err := MapRolesResponseToStruct(tt.resourceData)
 
-			if tt.expectedError {
-				assert.Error(t, err)
-				if tt.expectedErrMsg != "" {
-					assert.Contains(t, err.Error(), tt.expectedErrMsg)
-				}
-				assert.Nil(t, roles)
-			} else {
-				assert.NoError(t, err)
-				assert.NotNil(t, roles)
-				assert.Equal(t, tt.expectedCount, len(roles))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCount, len(roles))
 
-				// Verify role fields
-				for _, role := range roles {
-					typedRole, ok := role.(*models.Role)
-					assert.True(t, ok, "Expected *models.Role type")
+			// Verify role fields
+			for _, role := range roles {
+				typedRole, ok := role.(*models.Role)
+				assert.True(t, ok, "Expected *models.Role type")
 
-					assert.NotEqual(t, uuid.Nil, typedRole.ID)
-					assert.NotEmpty(t, typedRole.Name)
-					assert.NotNil(t, typedRole.Description)
-				}
+				assert.NotEqual(t, uuid.Nil, typedRole.ID)
+				assert.NotEmpty(t, typedRole.Name)
+				assert.NotNil(t, typedRole.Description)
 			}
 		})
 	}
@@ -146,18 +121,12 @@ func TestMapRoleResponseToStruct(t *testing.T) {
 	mockResponse := createMockResourceResponse()
 
 	// Extract a role ID for testing
-	var roleID uuid.UUID
-	data := mockResponse["data"].([]interface{})[0].(map[string]interface{})
-	roles := data["roles"].(map[string]interface{})
-	adminRole := roles["admin"].(map[string]interface{})
-	id, _ := uuid.Parse(adminRole["key"].(string))
-	roleID = id
-
+	roleID, _ := uuid.Parse(mockResponse[0].Roles["admin"].Key)
 	nonExistentID := uuid.New()
 
 	tests := []struct {
 		name           string
-		resourceData   map[string]interface{}
+		resourceData   []permit.Resource
 		roleID         uuid.UUID
 		expectedError  bool
 		expectedErrMsg string
@@ -176,11 +145,11 @@ func TestMapRoleResponseToStruct(t *testing.T) {
 			expectedErrMsg: "role not found",
 		},
 		{
-			name:           "Invalid response - missing data field",
-			resourceData:   createMockInvalidResponse(),
+			name:           "Empty response",
+			resourceData:   nil,
 			roleID:         roleID,
 			expectedError:  true,
-			expectedErrMsg: "missing or invalid data field",
+			expectedErrMsg: "role not found",
 		},
 	}
 
@@ -215,12 +184,12 @@ func TestMapToRoleData(t *testing.T) {
 	permission2ID := uuid.New()
 	resourceID := uuid.New().String()
 
-	validRoleData := map[string]interface{}{
-		"name":        "Admin",
-		"description": "Administrator role",
-		"key":         roleID,
-		"permissions": []interface{}{"read", "write"},
-		"attributes": map[string]interface{}{
+	validRoleData := permit.Role{
+		Name:        "Admin",
+		Description: "Administrator role",
+		Key:         roleID,
+		Permissions: []string{"read", "write"},
+		Attributes: map[string]interface{}{
 			"createdBy":          userID,
 			"updatedBy":          userID,
 			"createdAt":          "2025-03-20T19:08:06-05:00",
@@ -229,32 +198,25 @@ func TestMapToRoleData(t *testing.T) {
 		},
 	}
 
-	actionsData := map[string]interface{}{
-		"read": map[string]interface{}{
-			"id":   permission1ID.String(),
-			"name": "Read",
-		},
-		"write": map[string]interface{}{
-			"id":   permission2ID.String(),
-			"name": "Write",
-		},
-	}
-
-	resourceData := map[string]interface{}{
-		"key":       resourceID,
-		"name":      "Resource 1",
-		"createdAt": "2025-03-20T19:08:06-05:00",
-		"updatedAt": "2025-03-20T19:08:06-05:00",
-		"actions": map[string]interface{}{
-			"read": map[string]interface{}{
-				"id":   permission1ID.String(),
-				"name": "Read",
+	resourceData := permit.Resource{
+		Key:       resourceID,
+		Name:      "Resource 1",
+		CreatedAt: "2025-03-20T19:08:06-05:00",
+		UpdatedAt: "2025-03-20T19:08:06-05:00",
+		Actions: map[string]permit.Action{
+			"read": {
+				ID:   permission1ID.String(),
+				Name: "Read",
+			},
+			"write": {
+				ID:   permission2ID.String(),
+				Name: "Write",
 			},
 		},
 	}
 
 	t.Run("Valid role data", func(t *testing.T) {
-		role, err := MapToRoleData(validRoleData, actionsData, resourceData)
+		role, err := MapToRoleData(validRoleData, resourceData)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, role)
@@ -267,20 +229,10 @@ func TestMapToRoleData(t *testing.T) {
 	})
 
 	t.Run("Invalid role ID", func(t *testing.T) {
-		invalidRoleData := map[string]interface{}{
-			"name":        "Admin",
-			"description": "Administrator role",
-			"key":         "invalid-uuid", // Invalid UUID
-			"permissions": []interface{}{"read", "write"},
-			"attributes": map[string]interface{}{
-				"createdBy": userID,
-				"updatedBy": userID,
-				"createdAt": "2025-03-20T19:08:06-05:00",
-				"updatedAt": "2025-03-20T19:08:06-05:00",
-			},
-		}
+		invalidRoleData := validRoleData
+		invalidRoleData.Key = "invalid-uuid"
 
-		role, err := MapToRoleData(invalidRoleData, actionsData, resourceData)
+		role, err := MapToRoleData(invalidRoleData, resourceData)
 
 		assert.Error(t, err)
 		assert.Nil(t, role)
@@ -288,15 +240,10 @@ func TestMapToRoleData(t *testing.T) {
 	})
 
 	t.Run("Missing attributes", func(t *testing.T) {
-		invalidRoleData := map[string]interface{}{
-			"name":        "Admin",
-			"description": "Administrator role",
-			"key":         roleID,
-			"permissions": []interface{}{"read", "write"},
-			// Missing attributes
-		}
+		invalidRoleData := validRoleData
+		invalidRoleData.Attributes = nil
 
-		role, err := MapToRoleData(invalidRoleData, actionsData, resourceData)
+		role, err := MapToRoleData(invalidRoleData, resourceData)
 
 		assert.Error(t, err)
 		assert.Nil(t, role)
@@ -304,11 +251,11 @@ func TestMapToRoleData(t *testing.T) {
 	})
 
 	t.Run("Invalid resource type", func(t *testing.T) {
-		invalidResourceData := map[string]interface{}{
-			"key": "invalid-uuid", // Invalid resource ID
+		invalidResourceData := permit.Resource{
+			Key: "invalid-uuid", // Invalid resource ID
 		}
 
-		role, err := MapToRoleData(validRoleData, actionsData, invalidResourceData)
+		role, err := MapToRoleData(validRoleData, invalidResourceData)
 
 		assert.Error(t, err)
 		assert.Nil(t, role)
@@ -320,15 +267,15 @@ func TestMapToResourceTypeData(t *testing.T) {
 	permission1ID := uuid.New()
 	resourceID := uuid.New()
 
-	validResourceData := map[string]interface{}{
-		"key":       resourceID.String(),
-		"name":      "Resource 1",
-		"createdAt": "2025-03-20T19:08:06-05:00",
-		"updatedAt": "2025-03-20T19:08:06-05:00",
-		"actions": map[string]interface{}{
-			"read": map[string]interface{}{
-				"id":   permission1ID.String(),
-				"name": "Read",
+	validResourceData := permit.Resource{
+		Key:       resourceID.String(),
+		Name:      "Resource 1",
+		CreatedAt: "2025-03-20T19:08:06-05:00",
+		UpdatedAt: "2025-03-20T19:08:06-05:00",
+		Actions: map[string]permit.Action{
+			"read": {
+				ID:   permission1ID.String(),
+				Name: "Read",
 			},
 		},
 	}
@@ -347,10 +294,9 @@ func TestMapToResourceTypeData(t *testing.T) {
 	})
 
 	t.Run("Invalid resource ID", func(t *testing.T) {
-		invalidResourceData := map[string]interface{}{
-			"key":     "invalid-uuid",
-			"name":    "Invalid Resource",
-			"actions": map[string]interface{}{},
+		invalidResourceData := permit.Resource{
+			Key:  "invalid-uuid",
+			Name: "Invalid Resource",
 		}
 
 		resourceType, err := MapToResourceTypeData(invalidResourceData)
@@ -361,17 +307,16 @@ func TestMapToResourceTypeData(t *testing.T) {
 	})
 
 	t.Run("Missing actions", func(t *testing.T) {
-		invalidResourceData := map[string]interface{}{
-			"key":  resourceID.String(),
-			"name": "Resource Without Actions",
-			// Missing actions field
+		resourceData := permit.Resource{
+			Key:  resourceID.String(),
+			Name: "Resource Without Actions",
 		}
 
-		resourceType, err := MapToResourceTypeData(invalidResourceData)
+		resourceType, err := MapToResourceTypeData(resourceData)
 
-		assert.Error(t, err)
-		assert.Nil(t, resourceType)
-		assert.Contains(t, err.Error(), "failed to extract actions")
+		assert.NoError(t, err)
+		assert.NotNil(t, resourceType)
+		assert.Empty(t, resourceType.Permissions)
 	})
 }
 
@@ -380,16 +325,15 @@ func TestMapToResourceActions(t *testing.T) {
 	permission1ID := uuid.New()
 	permission2ID := uuid.New()
 
-	actionsData := map[string]interface{}{
-		"read": map[string]interface{}{
-			"id":   permission1ID.String(),
-			"name": "Read",
+	actionsData := map[string]permit.Action{
+		"read": {
+			ID:   permission1ID.String(),
+			Name: "Read",
 		},
-		"write": map[string]interface{}{
-			"id":   permission2ID.String(),
-			"name": "Write",
+		"write": {
+			ID:   permission2ID.String(),
+			Name: "Write",
 		},
-		"invalidAction": "not-a-map", // This should be skipped
 	}
 
 	t.Run("Valid actions data", func(t *testing.T) {
@@ -410,10 +354,10 @@ func TestMapToResourceActions(t *testing.T) {
 	})
 
 	t.Run("Invalid action ID", func(t *testing.T) {
-		invalidActionsData := map[string]interface{}{
-			"read": map[string]interface{}{
-				"id":   "invalid-uuid", // Invalid UUID
-				"name": "Read",
+		invalidActionsData := map[string]permit.Action{
+			"read": {
+				ID:   "invalid-uuid", // Invalid UUID
+				Name: "Read",
 			},
 		}
 
@@ -424,9 +368,7 @@ func TestMapToResourceActions(t *testing.T) {
 	})
 
 	t.Run("Empty actions data", func(t *testing.T) {
-		emptyActionsData := map[string]interface{}{}
-
-		permissions := MapToResourceActions(resourceID, emptyActionsData)
+		permissions := MapToResourceActions(resourceID, map[string]permit.Action{})
 		assert.Equal(t, 0, len(permissions))
 	})
 }
@@ -435,25 +377,24 @@ func TestMapToPermissionsData(t *testing.T) {
 	permission1ID := uuid.New()
 	permission2ID := uuid.New()
 
-	validRoleData := map[string]interface{}{
-		"permissions": []interface{}{"read", "write"},
+	validRoleData := permit.Role{
+		Permissions: []string{"read", "write"},
 	}
 
-	validActionsData := map[string]interface{}{
-		"read": map[string]interface{}{
-			"id":   permission1ID.String(),
-			"name": "Read",
+	validActionsData := map[string]permit.Action{
+		"read": {
+			ID:   permission1ID.String(),
+			Name: "Read",
 		},
-		"write": map[string]interface{}{
-			"id":   permission2ID.String(),
-			"name": "Write",
+		"write": {
+			ID:   permission2ID.String(),
+			Name: "Write",
 		},
 	}
 
 	t.Run("Valid permissions data", func(t *testing.T) {
-		permissions, err := MapToPermissionsData(validRoleData, validActionsData)
+		permissions := MapToPermissionsData(validRoleData, validActionsData)
 
-		assert.NoError(t, err)
 		assert.NotNil(t, permissions)
 		assert.Equal(t, 2, len(permissions))
 
@@ -464,181 +405,71 @@ func TestMapToPermissionsData(t *testing.T) {
 	})
 
 	t.Run("Missing permissions in role data", func(t *testing.T) {
-		invalidRoleData := map[string]interface{}{
-			// Missing permissions field
-		}
-
-		permissions, err := MapToPermissionsData(invalidRoleData, validActionsData)
+		permissions := MapToPermissionsData(permit.Role{}, validActionsData)
 
-		assert.Error(t, err)
-		assert.Nil(t, permissions)
-	})
-
-	t.Run("Invalid permission format in actions data", func(t *testing.T) {
-		invalidActionsData := map[string]interface{}{
-			"read": "invalid-action-data", // Not a map
-		}
-
-		roleData := map[string]interface{}{
-			"permissions": []interface{}{"read"},
-		}
-
-		permissions, err := MapToPermissionsData(roleData, invalidActionsData)
-
-		// This should not error but return nil permission for the invalid action
-		assert.NoError(t, err)
 		assert.Empty(t, permissions)
 	})
 
-	t.Run("Non-string permission key", func(t *testing.T) {
-		roleData := map[string]interface{}{
-			"permissions": []interface{}{123}, // Non-string permission
+	t.Run("Invalid action ID", func(t *testing.T) {
+		invalidActionsData := map[string]permit.Action{
+			"read": {ID: "invalid-uuid", Name: "Read"},
 		}
 
-		permissions, err := MapToPermissionsData(roleData, validActionsData)
+		permissions := MapToPermissionsData(permit.Role{Permissions: []string{"read"}}, invalidActionsData)
 
-		assert.NoError(t, err)
-		assert.Empty(t, permissions) // Should filter out invalid permission
+		assert.Empty(t, permissions)
 	})
 
 	t.Run("Unknown permission key", func(t *testing.T) {
-		roleData := map[string]interface{}{
-			"permissions": []interface{}{"unknown"}, // Not in actionsData
+		roleData := permit.Role{
+			Permissions: []string{"unknown"}, // Not in actionsData
 		}
 
-		permissions, err := MapToPermissionsData(roleData, validActionsData)
+		permissions := MapToPermissionsData(roleData, validActionsData)
 
-		assert.NoError(t, err)
 		assert.Empty(t, permissions) // Should filter out unknown permission
 	})
 }
 
-func TestExtractRolesAndActions(t *testing.T) {
-	validResourceData := map[string]interface{}{
-		"roles": map[string]interface{}{
-			"admin": map[string]interface{}{
-				"name": "Admin",
-			},
-		},
-		"actions": map[string]interface{}{
-			"read": map[string]interface{}{
-				"name": "Read",
-			},
-		},
-	}
-
-	t.Run("Valid resource data", func(t *testing.T) {
-		roles, actions, err := extractRolesAndActions(validResourceData)
-
-		assert.NoError(t, err)
-		assert.NotNil(t, roles)
-		assert.NotNil(t, actions)
-		assert.Contains(t, roles, "admin")
-		assert.Contains(t, actions, "read")
-	})
-
-	t.Run("Missing roles", func(t *testing.T) {
-		invalidData := map[string]interface{}{
-			"actions": map[string]interface{}{},
-		}
-
-		roles, actions, err := extractRolesAndActions(invalidData)
-
-		assert.Error(t, err)
-		assert.Nil(t, roles)
-		assert.Nil(t, actions)
-		assert.Contains(t, err.Error(), "failed to extract roles")
-	})
-
-	t.Run("Missing actions", func(t *testing.T) {
-		invalidData := map[string]interface{}{
-			"roles": map[string]interface{}{},
-		}
-
-		roles, actions, err := extractRolesAndActions(invalidData)
-
-		assert.Error(t, err)
-		assert.Nil(t, roles)
-		assert.Nil(t, actions)
-		assert.Contains(t, err.Error(), "failed to extract actions")
-	})
-
-	t.Run("Invalid roles format", func(t *testing.T) {
-		invalidData := map[string]interface{}{
-			"roles":   "not-a-map",
-			"actions": map[string]interface{}{},
-		}
-
-		roles, actions, err := extractRolesAndActions(invalidData)
-
-		assert.Error(t, err)
-		assert.Nil(t, roles)
-		assert.Nil(t, actions)
-		assert.Contains(t, err.Error(), "failed to extract roles")
-	})
-
-	t.Run("Invalid actions format", func(t *testing.T) {
-		invalidData := map[string]interface{}{
-			"roles":   map[string]interface{}{},
-			"actions": "not-a-map",
-		}
-
-		roles, actions, err := extractRolesAndActions(invalidData)
-
-		assert.Error(t, err)
-		assert.Nil(t, roles)
-		assert.Nil(t, actions)
-		assert.Contains(t, err.Error(), "failed to extract actions")
-	})
-}
-
 func TestFindRoleByID(t *testing.T) {
 	roleID := uuid.New()
 	nonExistentID := uuid.New()
 	userID := uuid.New().String()
 	resourceID := uuid.New().String()
 
-	rolesData := map[string]interface{}{
-		"admin": map[string]interface{}{
-			"name":        "Admin",
-			"description": "Administrator role",
-			"key":         roleID.String(),
-			"permissions": []interface{}{"read", "write"},
-			"attributes": map[string]interface{}{
-				"createdBy": userID,
-				"updatedBy": userID,
-				"createdAt": "2025-03-20T19:08:06-05:00",
-				"updatedAt": "2025-03-20T19:08:06-05:00",
+	resourceData := permit.Resource{
+		Key:       resourceID,
+		Name:      "Resource 1",
+		CreatedAt: "2025-03-20T19:08:06-05:00",
+		UpdatedAt: "2025-03-20T19:08:06-05:00",
+		Roles: map[string]permit.Role{
+			"admin": {
+				Name:        "Admin",
+				Description: "Administrator role",
+				Key:         roleID.String(),
+				Permissions: []string{"read", "write"},
+				Attributes: map[string]interface{}{
+					"createdBy": userID,
+					"updatedBy": userID,
+					"createdAt": "2025-03-20T19:08:06-05:00",
+					"updatedAt": "2025-03-20T19:08:06-05:00",
+				},
 			},
 		},
-	}
-
-	actionsData := map[string]interface{}{
-		"read": map[string]interface{}{
-			"id":   uuid.New().String(),
-			"name": "Read",
-		},
-		"write": map[string]interface{}{
-			"id":   uuid.New().String(),
-			"name": "Write",
-		},
-	}
-
-	resourceData := map[string]interface{}{
-		"key":       resourceID,
-		"name":      "Resource 1",
-		"createdAt": "2025-03-20T19:08:06-05:00",
-		"updatedAt": "2025-03-20T19:08:06-05:00",
-		"actions": map[string]interface{}{
-			"read": map[string]interface{}{
-				"id":   uuid.New().String(),
-				"name": "Read",
+		Actions: map[string]permit.Action{
+			"read": {
+				ID:   uuid.New().String(),
+				Name: "Read",
+			},
+			"write": {
+				ID:   uuid.New().String(),
+				Name: "Write",
 			},
 		},
 	}
 
 	t.Run("Existing role ID", func(t *testing.T) {
-		roles, err := findRoleByID(rolesData, actionsData, resourceData, roleID)
+		roles, err := findRoleByID(resourceData, roleID)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, roles)
@@ -650,68 +481,44 @@ func TestFindRoleByID(t *testing.T) {
 	})
 
 	t.Run("Non-existent role ID", func(t *testing.T) {
-		roles, err := findRoleByID(rolesData, actionsData, resourceData, nonExistentID)
+		roles, err := findRoleByID(resourceData, nonExistentID)
 
 		assert.Error(t, err)
 		assert.Nil(t, roles)
 		assert.Contains(t, err.Error(), "role not found")
 	})
 
-	t.Run("Invalid role data format", func(t *testing.T) {
-		invalidRolesData := map[string]interface{}{
-			"admin": "invalid-role-data", // Not a map
+	t.Run("Role with invalid ID format", func(t *testing.T) {
+		invalidResourceData := resourceData
+		invalidResourceData.Roles = map[string]permit.Role{
+			"admin": {
+				Name:        "Admin",
+				Description: "Administrator role",
+				Key:         "invalid-uuid", // Invalid UUID
+				Permissions: []string{"read", "write"},
+			},
 		}
 
-		roles, err := findRoleByID(invalidRolesData, actionsData, resourceData, roleID)
+		roles, err := findRoleByID(invalidResourceData, roleID)
 
 		assert.Error(t, err)
 		assert.Nil(t, roles)
 		assert.Contains(t, err.Error(), "role not found")
 	})
 
-	t.Run("Role with invalid ID format", func(t *testing.T) {
-		invalidRolesData := map[string]interface{}{
-			"admin": map[string]interface{}{
-				"name":        "Admin",
-				"description": "Administrator role",
-				"key":         "invalid-uuid", // Invalid UUID
-				"permissions": []interface{}{"read", "write"},
+	t.Run("Matching role with missing attributes", func(t *testing.T) {
+		invalidResourceData := resourceData
+		invalidResourceData.Roles = map[string]permit.Role{
+			"admin": {
+				Name: "Admin",
+				Key:  roleID.String(),
 			},
 		}
 
-		roles, err := findRoleByID(invalidRolesData, actionsData, resourceData, roleID)
+		roles, err := findRoleByID(invalidResourceData, roleID)
 
 		assert.Error(t, err)
 		assert.Nil(t, roles)
-		assert.Contains(t, err.Error(), "role not found")
-	})
-}
-
-// Added missing test cases for edge scenarios and untested branches in helper functions.
-func TestExtractDataFromResponse_AdditionalCases(t *testing.T) {
-	invalidResponse := map[string]interface{}{
-		"data": "invalid-data-format",
-	}
-
-	t.Run("Invalid data format", func(t *testing.T) {
-		data, ok := extractDataFromResponse(invalidResponse)
-		assert.False(t, ok)
-		assert.Nil(t, data)
-	})
-}
-
-func TestProcessPermission_AdditionalCases(t *testing.T) {
-	invalidPermission := 123 // Non-string permission key
-	validActionsData := map[string]interface{}{
-		"read": map[string]interface{}{
-			"id":   uuid.New().String(),
-			"name": "Read",
-		},
-	}
-
-	t.Run("Non-string permission key", func(t *testing.T) {
-		permission, err := processPermission(invalidPermission, validActionsData)
-		assert.Error(t, err)
-		assert.Nil(t, permission)
+		assert.Contains(t, err.Error(), "failed to map role data")
 	})
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/errorcatalog"
 	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	"iam_services_main_v1/internal/validations"
@@ -17,7 +19,8 @@ import (
 
 // RoleMutationResolver handles role-related mutations.
 type RoleMutationResolver struct {
-	PC permit.PermitService
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
 }
 
 // CreateRole creates a new role in the system.
@@ -38,18 +41,18 @@ func (r *RoleMutationResolver) CreateRole(ctx context.Context, input models.Crea
 	// Get user and tenant context
 	userID, tenantID, err := helpers.GetUserAndTenantID(ctx)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "failed to fetch user and tenant IDs", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "failed to fetch user and tenant IDs", err), nil
 	}
 
 	// Validate input
 	if err := validateCreateRoleInput(input); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "input validation failed", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "input validation failed", err), nil
 
 	}
 
 	// Create role in permit system
 	if err := r.createRoleInPermit(ctx, input, userID, tenantID); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "permit role creation failed", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "permit role creation failed", err), nil
 
 	}
 
@@ -80,17 +83,19 @@ func (r *RoleMutationResolver) UpdateRole(ctx context.Context, input models.Upda
 	// Get user and tenant context
 	userID, tenantID, err := helpers.GetUserAndTenantID(ctx)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "failed to fetch user and tenant IDs", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "failed to fetch user and tenant IDs", err), nil
 	}
 	// Validate Input
 	if err := r.validateUpdateRoleInput(input); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Error validating update role input", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Error validating update role input", err), nil
 	}
 
 	// Update role in permit system
 	if err := r.updateRoleInPermit(ctx, input, userID, tenantID); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "permit role creation failed", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "permit role creation failed", err), nil
 	}
+	// The role's permissions may have changed for every user holding it
+	authz.InvalidateAllDecisions(r.Authorizer)
 
 	// Fetch and return created role
 	return r.getCreatedRole(ctx, input.ID)
@@ -109,13 +114,15 @@ func (r *RoleMutationResolver) UpdateRole(ctx context.Context, input models.Upda
 func (r *RoleMutationResolver) DeleteRole(ctx context.Context, input models.DeleteRoleInput) (models.OperationResult, error) {
 	// Validate required input fields
 	if err := validateDeleteRoleInput(input); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Error validating delete role input", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Error validating delete role input", err), nil
 	}
 
 	// Delete role from permit system
 	if err := r.deleteRoleFromPermit(ctx, input); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Error deleting role in permit", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Error deleting role in permit", err), nil
 	}
+	// Permit removes the role's assignments together with the role
+	authz.InvalidateAllDecisions(r.Authorizer)
 
 	// Return success response
 	return utils.FormatSuccess([]models.Data{})
@@ -128,15 +135,13 @@ func (r *RoleMutationResolver) createRoleInPermit(ctx context.Context, input mod
 		return fmt.Errorf("metadata preparation failed: %w", err)
 	}
 
-	permitMap := map[string]interface{}{
-		"name":        input.Name,
-		"description": input.Description,
-		"key":         input.ID,
-		"attributes":  metadata,
-		"permissions": input.Permissions,
-	}
-
-	_, err = r.PC.SendRequest(ctx, "POST", fmt.Sprintf("resources/%s/roles", input.AssignableScopeRef), permitMap)
+	_, err = r.PC.CreateResourceRole(ctx, input.AssignableScopeRef.String(), permit.RoleCreate{
+		Key:         input.ID.String(),
+		Name:        input.Name,
+		Description: derefString(input.Description),
+		Permissions: input.Permissions,
+		Attributes:  metadata,
+	})
 	return err
 }
 
@@ -147,14 +152,12 @@ func (r *RoleMutationResolver) updateRoleInPermit(ctx context.Context, input mod
 		return fmt.Errorf("metadata preparation failed: %w", err)
 	}
 
-	permitMap := map[string]interface{}{
-		"name":        input.Name,
-		"description": input.Description,
-		"attributes":  metadata,
-		"permissions": input.Permissions,
-	}
-
-	_, err = r.PC.SendRequest(ctx, "PATCH", fmt.Sprintf("resources/%s/roles/%s", input.AssignableScopeRef, input.ID), permitMap)
+	_, err = r.PC.UpdateResourceRole(ctx, input.AssignableScopeRef.String(), input.ID.String(), permit.RoleUpdate{
+		Name:        input.Name,
+		Description: derefString(input.Description),
+		Permissions: input.Permissions,
+		Attributes:  metadata,
+	})
 	return err
 }
 
@@ -164,7 +167,7 @@ func (r *RoleMutationResolver) getCreatedRole(ctx context.Context, roleID uuid.U
 	roleResolver := &RoleQueryResolver{PC: r.PC}
 	data, err := roleResolver.Role(ctx, roleID)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "role fetch failed", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "role fetch failed", err), nil
 	}
 	return data, nil
 }
@@ -182,9 +185,15 @@ func validateDeleteRoleInput(input models.DeleteRoleInput) error {
 
 // Helper function to delete role from permit system
 func (r *RoleMutationResolver) deleteRoleFromPermit(ctx context.Context, input models.DeleteRoleInput) error {
-	endpoint := fmt.Sprintf("resources/%s/roles/%s", input.AssignableScopeRef, input.ID)
-	_, err := r.PC.SendRequest(ctx, "DELETE", endpoint, nil)
-	return err
+	return r.PC.DeleteResourceRole(ctx, input.AssignableScopeRef.String(), input.ID.String())
+}
+
+// derefString returns the value of an optional string, or an empty string when it is nil
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
 }
 
 // validateUpdateRoleInput checks if the provided UpdateRoleInput is valid by ensuring required fields are not empty
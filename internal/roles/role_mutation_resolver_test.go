@@ -5,6 +5,7 @@ import (
 	"errors"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
 	mocks "iam_services_main_v1/mocks"
 	"testing"
 	"time"
@@ -99,7 +100,7 @@ func TestCreateRole(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", mock.Any(), mock.Any()).
+					CreateResourceRole(mock.Any(), scopeRef.String(), mock.Any()).
 					Return(nil, errors.New("permit error")).MaxTimes(1)
 			},
 			wantErr: true,
@@ -110,12 +111,12 @@ func TestCreateRole(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"created": true}, nil).MaxTimes(1)
+					CreateResourceRole(mock.Any(), scopeRef.String(), mock.Any()).
+					Return(&permit.Role{Key: validID.String()}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
-					Return(nil, errors.New("get role error")).MaxTimes(1)
+					ListResources(mock.Any()).
+					Return(nil, 0, errors.New("get role error")).MaxTimes(1)
 			},
 			wantErr: true,
 		},
@@ -125,12 +126,12 @@ func TestCreateRole(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "POST", mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"key": validID.String()}, nil).MaxTimes(1)
+					CreateResourceRole(mock.Any(), scopeRef.String(), mock.Any()).
+					Return(&permit.Role{Key: validID.String()}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
-					Return(roleData, nil).MaxTimes(1)
+					ListResources(mock.Any()).
+					Return(roleData, len(roleData), nil).MaxTimes(1)
 			},
 			wantErr: false,
 		},
@@ -207,7 +208,7 @@ func TestUpdateRole(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					UpdateResourceRole(mock.Any(), scopeRef.String(), validID.String(), mock.Any()).
 					Return(nil, errors.New("update error")).MaxTimes(1)
 			},
 			wantErr: true,
@@ -218,12 +219,12 @@ func TestUpdateRole(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"updated": true}, nil).MaxTimes(1)
+					UpdateResourceRole(mock.Any(), scopeRef.String(), validID.String(), mock.Any()).
+					Return(&permit.Role{Key: validID.String()}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), nil).
-					Return(nil, errors.New("get updated role error")).MaxTimes(1)
+					ListResources(mock.Any()).
+					Return(nil, 0, errors.New("get updated role error")).MaxTimes(1)
 			},
 			wantErr: true,
 		},
@@ -233,12 +234,12 @@ func TestUpdateRole(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"updated": true}, nil).MaxTimes(1)
+					UpdateResourceRole(mock.Any(), scopeRef.String(), validID.String(), mock.Any()).
+					Return(&permit.Role{Key: validID.String()}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), nil).
-					Return(roleData, nil).MaxTimes(1)
+					ListResources(mock.Any()).
+					Return(roleData, len(roleData), nil).MaxTimes(1)
 			},
 			wantErr: false,
 		},
@@ -303,8 +304,8 @@ func TestDeleteRole(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "DELETE", mock.Any(), nil).
-					Return(nil, errors.New("delete error")).MaxTimes(1)
+					DeleteResourceRole(mock.Any(), scopeRef.String(), validID.String()).
+					Return(errors.New("delete error")).MaxTimes(1)
 			},
 			wantErr: true,
 		},
@@ -314,8 +315,8 @@ func TestDeleteRole(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "DELETE", mock.Any(), nil).
-					Return(map[string]interface{}{"deleted": true}, nil).MaxTimes(1)
+					DeleteResourceRole(mock.Any(), scopeRef.String(), validID.String()).
+					Return(nil).MaxTimes(1)
 			},
 			wantErr: false,
 		},
@@ -400,24 +401,24 @@ func TestPrepareMetadataForUpdateInput(t *testing.T) {
 	})
 }
 
-func buildTestMutationRolesData() map[string]interface{} {
-	return map[string]interface{}{
-		"data": []interface{}{
-			map[string]interface{}{
-				"key":         uuid.New().String(),
-				"name":        "Admin Role",
-				"description": "Administrator role with full access",
-				"attributes": map[string]interface{}{
-					"displayName": "Administrator",
-					"permissions": []interface{}{
-						"read:all",
-						"write:all",
-						"delete:all",
+func buildTestMutationRolesData() []permit.Resource {
+	return []permit.Resource{
+		{
+			Key:  uuid.New().String(),
+			Name: "Admin Resource",
+			Roles: map[string]permit.Role{
+				"admin": {
+					Key:         uuid.New().String(),
+					Name:        "Admin Role",
+					Description: "Administrator role with full access",
+					Permissions: []string{"read:all", "write:all", "delete:all"},
+					Attributes: map[string]interface{}{
+						"displayName": "Administrator",
+						"createdAt":   time.Now().Format(time.RFC3339),
+						"updatedAt":   time.Now().Format(time.RFC3339),
+						"createdBy":   uuid.New().String(),
+						"updatedBy":   uuid.New().String(),
 					},
-					"createdAt": time.Now().Format(time.RFC3339),
-					"updatedAt": time.Now().Format(time.RFC3339),
-					"createdBy": uuid.New().String(),
-					"updatedBy": uuid.New().String(),
 				},
 			},
 		},
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
 	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	"iam_services_main_v1/pkg/logger"
@@ -39,25 +40,25 @@ func (r *RoleQueryResolver) Role(ctx context.Context, id uuid.UUID) (models.Oper
 	// Validate ID
 	if id == uuid.Nil {
 		err := fmt.Errorf("invalid role ID: %s", id)
-		return utils.FormatErrorResponse(http.StatusBadRequest, "invalid role ID", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "invalid role ID", err), nil
 	}
 
 	// Fetch role from permit system
-	data, err := r.PC.SendRequest(ctx, "GET", "resources?include_total_count=true", nil)
+	data, _, err := r.PC.ListResources(ctx)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Error retrieving roles from permit system", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Error retrieving roles from permit system", err), nil
 	}
 
 	// Map role data to Role struct
 	role, err := MapRoleResponseToStruct(data, id)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Error mapping role data", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Error mapping role data", err), nil
 	}
 
 	// Format and return success response
 	successResponse, err := utils.FormatSuccess(role)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to format success response", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Failed to format success response", err), nil
 
 	}
 	return successResponse, nil
@@ -80,22 +81,46 @@ func (r *RoleQueryResolver) Roles(ctx context.Context) (models.OperationResult,
 	logger.LogInfo("Fetching all roles")
 
 	// Fetch roles from permit system
-	roleResources, err := r.PC.SendRequest(ctx, "GET", "resources?include_total_count=true", nil)
+	roleResources, _, err := r.PC.ListResources(ctx)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Error retrieving roles from permit system", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Error retrieving roles from permit system", err), nil
 	}
 
 	// Map role data to Role struct
 	roles, err := MapRolesResponseToStruct(roleResources)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to map tenant resources to struct", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Failed to map tenant resources to struct", err), nil
 
 	}
 
-	// Format and return success response
-	successResponse, err := utils.FormatSuccess(roles)
+	// Format and return success response. Roles are nested in resource types,
+	// so the total is the number of roles across every resource type page.
+	successResponse, err := utils.FormatSuccessWithTotal(roles, len(roles))
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to format success response", err.Error()), nil
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Failed to format success response", err), nil
 	}
 	return successResponse, nil
 }
+
+// RolesConnection retrieves one page of roles. Roles are nested in resource
+// types, so every resource type is read and the page is cut from the roles
+// across all of them.
+func (r *RoleQueryResolver) RolesConnection(ctx context.Context, after *string, first *int) (models.OperationResult, error) {
+	logger.LogInfo("Fetching a page of roles")
+
+	page, err := utils.ParsePage(first, after)
+	if err != nil {
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Invalid pagination arguments", err), nil
+	}
+
+	roleResources, _, err := r.PC.ListResources(ctx)
+	if err != nil {
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Error retrieving roles from permit system", err), nil
+	}
+
+	roles, err := MapRolesResponseToStruct(roleResources)
+	if err != nil {
+		return errorcatalog.Role.FromError(http.StatusBadRequest, "Failed to map role resources to struct", err), nil
+	}
+	return utils.FormatConnection(utils.PageOf(roles, page), page, len(roles)), nil
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	mocks "iam_services_main_v1/mocks"
 	"testing"
@@ -44,8 +46,8 @@ func TestRole(t *testing.T) {
 			name: "Success",
 			setup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
-					Return(roleData, nil)
+					ListResources(mock.Any()).
+					Return(roleData, len(roleData), nil)
 			},
 			wantErr: false,
 		},
@@ -53,8 +55,8 @@ func TestRole(t *testing.T) {
 			name: "Service error",
 			setup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
-					Return(nil, assert.AnError)
+					ListResources(mock.Any()).
+					Return(nil, 0, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -84,8 +86,8 @@ func TestRoles(t *testing.T) {
 			name: "Success",
 			setup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", "resources?include_total_count=true", nil).
-					Return(rolesData, nil)
+					ListResources(mock.Any()).
+					Return(rolesData, len(rolesData), nil)
 			},
 			wantErr: false,
 		},
@@ -93,8 +95,8 @@ func TestRoles(t *testing.T) {
 			name: "Service error",
 			setup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", "resources?include_total_count=true", nil).
-					Return(nil, assert.AnError)
+					ListResources(mock.Any()).
+					Return(nil, 0, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -109,6 +111,31 @@ func TestRoles(t *testing.T) {
 	}
 }
 
+func TestRolesConnection(t *testing.T) {
+	resolver, mockService, ctx := setupTest(t)
+	rolesData := buildTestRolesData()
+	mockService.EXPECT().
+		ListResources(mock.Any()).
+		Return(rolesData, len(rolesData), nil).
+		Times(2)
+
+	first := 1
+	result, err := resolver.RolesConnection(ctx, nil, &first)
+	assert.NoError(t, err)
+	conn, ok := result.(*models.Connection)
+	assert.True(t, ok)
+	assert.Len(t, conn.Edges, 1)
+	assert.Equal(t, 2, conn.TotalCount)
+	assert.True(t, conn.PageInfo.HasNextPage)
+
+	result, err = resolver.RolesConnection(ctx, conn.PageInfo.EndCursor, &first)
+	assert.NoError(t, err)
+	conn = result.(*models.Connection)
+	assert.Len(t, conn.Edges, 1)
+	assert.False(t, conn.PageInfo.HasNextPage)
+	assert.True(t, conn.PageInfo.HasPreviousPage)
+}
+
 func TestRoleQueryResolver_Role(t *testing.T) {
 	ctrl := mock.NewController(t)
 	defer ctrl.Finish()
@@ -152,8 +179,8 @@ func TestRoleQueryResolver_Role(t *testing.T) {
 			ctx:   testCtx,
 			mockStubs: func(mockSvc *mocks.MockPermitService) {
 				mockSvc.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
-					Return(nil, errors.New("permit service error"))
+					ListResources(mock.Any()).
+					Return(nil, 0, errors.New("permit service error"))
 			},
 			wantErr: true,
 		},
@@ -163,8 +190,8 @@ func TestRoleQueryResolver_Role(t *testing.T) {
 			ctx:   testCtx,
 			mockStubs: func(mockSvc *mocks.MockPermitService) {
 				mockSvc.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
-					Return(roleData, nil)
+					ListResources(mock.Any()).
+					Return(roleData, len(roleData), nil)
 			},
 			wantErr: false,
 		},
@@ -207,10 +234,10 @@ func TestRoleQueryResolver_Roles(t *testing.T) {
 			ctx:  testCtx,
 			mockStubs: func(mockSvc *mocks.MockPermitService) {
 				mockSvc.EXPECT().
-					SendRequest(mock.Any(), "GET", "resources?include_total_count=true", nil).
-					Return(nil, errors.New("permit service error"))
+					ListResources(mock.Any()).
+					Return(nil, 0, errors.New("permit service error"))
 			},
-			expected: utils.FormatErrorResponse(400, "Error retrieving roles from permit system", "permit service error"),
+			expected: errorcatalog.Role.New(400, "Error retrieving roles from permit system", "permit service error"),
 			wantErr:  true,
 		},
 		{
@@ -218,8 +245,8 @@ func TestRoleQueryResolver_Roles(t *testing.T) {
 			ctx:  testCtx,
 			mockStubs: func(mockSvc *mocks.MockPermitService) {
 				mockSvc.EXPECT().
-					SendRequest(mock.Any(), "GET", "resources?include_total_count=true", nil).
-					Return(roleData, nil)
+					ListResources(mock.Any()).
+					Return(roleData, len(roleData), nil)
 			},
 			expected: func() models.OperationResult {
 				result, _ := utils.FormatSuccess(mappedRoles)
@@ -238,31 +265,28 @@ func TestRoleQueryResolver_Roles(t *testing.T) {
 	}
 }
 
-func buildTestRolesData() map[string]interface{} {
-	return map[string]interface{}{
-		"data": []interface{}{
-			map[string]interface{}{
-				"key":         uuid.New().String(),
-				"name":        "Admin Role",
-				"description": "Administrator role with full access",
-				"attributes": map[string]interface{}{
-					"displayName": "Administrator",
-					"permissions": []interface{}{
-						"read:all",
-						"write:all",
-						"delete:all",
+func buildTestRolesData() []permit.Resource {
+	return []permit.Resource{
+		{
+			Key:  uuid.New().String(),
+			Name: "Test Resource",
+			Roles: map[string]permit.Role{
+				"admin": {
+					Key:         uuid.New().String(),
+					Name:        "Admin Role",
+					Description: "Administrator role with full access",
+					Permissions: []string{"read:all", "write:all", "delete:all"},
+					Attributes: map[string]interface{}{
+						"displayName": "Administrator",
 					},
 				},
-			},
-			map[string]interface{}{
-				"key":         uuid.New().String(),
-				"name":        "User Role",
-				"description": "Standard user role with limited access",
-				"attributes": map[string]interface{}{
-					"displayName": "Standard User",
-					"permissions": []interface{}{
-						"read:own",
-						"write:own",
+				"user": {
+					Key:         uuid.New().String(),
+					Name:        "User Role",
+					Description: "Standard user role with limited access",
+					Permissions: []string{"read:own", "write:own"},
+					Attributes: map[string]interface{}{
+						"displayName": "Standard User",
 					},
 				},
 			},
@@ -272,7 +296,7 @@ func buildTestRolesData() map[string]interface{} {
 
 // Mock implementations of the mapping functions
 
-func MockMapRoleResponseToStruct(resourceResponse map[string]interface{}, id uuid.UUID) ([]models.Data, error) {
+func MockMapRoleResponseToStruct(resources []permit.Resource, id uuid.UUID) ([]models.Data, error) {
 	// Simple mock implementation for testing
 	description := "Test Role Description"
 	return []models.Data{
@@ -283,7 +307,7 @@ func MockMapRoleResponseToStruct(resourceResponse map[string]interface{}, id uui
 		},
 	}, nil
 }
-func MockMapRolesResponseToStruct(resourcesResponse map[string]interface{}) ([]models.Data, error) {
+func MockMapRolesResponseToStruct(resources []permit.Resource) ([]models.Data, error) {
 	// Simple mock implementation for testing
 	adminDesc := "Administrator role with full access"
 	userDesc := "Standard user role with limited access"
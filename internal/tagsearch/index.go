@@ -0,0 +1,463 @@
+// Package tagsearch finds the tenants, accounts, client organization units and
+// roles that carry a set of tags.
+//
+// The Index is built lazily from Permit, one tenant at a time, and rebuilt once
+// its TTL has passed. Writes made through the PermitService returned by
+// NewIndexingService update it immediately, so the TTL only bounds how long
+// changes made outside of this service go unnoticed.
+package tagsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/constants"
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/tags"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Entity types that can be searched, named after their GraphQL types.
+const (
+	TypeTenant                 = "Tenant"
+	TypeAccount                = "Account"
+	TypeClientOrganizationUnit = "ClientOrganizationUnit"
+	TypeRole                   = "Role"
+)
+
+// Types lists every entity type that can be searched.
+var Types = []string{TypeAccount, TypeClientOrganizationUnit, TypeRole, TypeTenant}
+
+// rolesScope is the scope holding the roles of every tenant. Tenant keys are
+// never empty.
+const rolesScope = ""
+
+// Policy controls how long the index of a tenant is trusted.
+type Policy struct {
+	// TTL bounds how long a tenant's index is used before it is rebuilt from
+	// Permit. Values of zero or less rebuild it on every search.
+	TTL time.Duration
+}
+
+// DefaultPolicy returns the policy used when none is configured.
+func DefaultPolicy() Policy {
+	return Policy{TTL: 5 * time.Minute}
+}
+
+// Ref identifies an indexed entity by its type and Permit key.
+type Ref struct {
+	Type string
+	Key  string
+}
+
+// Query describes a tag search.
+type Query struct {
+	// Tags are matched by key and value.
+	Tags []models.Tags
+	// Any matches entities carrying at least one of Tags instead of all of them.
+	Any bool
+	// Types restricts the search to these entity types. Empty means all types.
+	Types []string
+}
+
+type entry struct {
+	// tenant is the tenant of a role, or "" for roles shared by every tenant.
+	tenant string
+	tags   []models.Tags
+}
+
+// scope is the index of one tenant, or of the roles of every tenant.
+type scope struct {
+	builtAt time.Time
+	stale   bool
+	entries map[Ref]entry
+	byTag   map[models.Tags]map[Ref]struct{}
+}
+
+func newScope(builtAt time.Time) *scope {
+	return &scope{builtAt: builtAt, entries: map[Ref]entry{}, byTag: map[models.Tags]map[Ref]struct{}{}}
+}
+
+func (s *scope) put(ref Ref, e entry) {
+	s.remove(ref)
+	s.entries[ref] = e
+	for _, tag := range e.tags {
+		if s.byTag[tag] == nil {
+			s.byTag[tag] = map[Ref]struct{}{}
+		}
+		s.byTag[tag][ref] = struct{}{}
+	}
+}
+
+func (s *scope) remove(ref Ref) {
+	old, ok := s.entries[ref]
+	if !ok {
+		return
+	}
+	delete(s.entries, ref)
+	for _, tag := range old.tags {
+		delete(s.byTag[tag], ref)
+		if len(s.byTag[tag]) == 0 {
+			delete(s.byTag, tag)
+		}
+	}
+}
+
+// write is a change applied to the index while a scope may be being rebuilt.
+// It is replayed onto the rebuilt scope so the rebuild cannot undo it.
+type write struct {
+	seq   uint64
+	scope string
+	apply func(s *scope)
+}
+
+// Index is an in-memory inverted index from tags to the entities carrying them.
+// It is safe for concurrent use.
+type Index struct {
+	pc     permit.PermitService
+	policy Policy
+	now    func() time.Time
+	builds singleflight.Group
+
+	mu       sync.Mutex
+	scopes   map[string]*scope
+	seq      uint64
+	inflight int
+	journal  []write
+}
+
+// NewIndex returns an empty index that reads the entities to index from pc.
+func NewIndex(pc permit.PermitService, policy Policy) *Index {
+	return &Index{pc: pc, policy: policy, now: time.Now, scopes: map[string]*scope{}}
+}
+
+// Search returns the entities of tenant matching q, ordered by type and key.
+// Roles shared by every tenant are included. The returned entities should be
+// re-read from Permit, as the index may lag behind changes made elsewhere.
+func (idx *Index) Search(ctx context.Context, tenant string, q Query) ([]Ref, error) {
+	if tenant == "" {
+		return nil, errors.New("tag search requires a tenant")
+	}
+	types := map[string]bool{}
+	for _, t := range q.Types {
+		types[t] = true
+	}
+	wanted := func(t string) bool { return len(types) == 0 || types[t] }
+
+	var scopes []string
+	if wanted(TypeTenant) || wanted(TypeAccount) || wanted(TypeClientOrganizationUnit) {
+		scopes = append(scopes, tenant)
+	}
+	if wanted(TypeRole) {
+		scopes = append(scopes, rolesScope)
+	}
+	for _, key := range scopes {
+		if err := idx.ensure(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	refs := []Ref{}
+	for _, key := range scopes {
+		s := idx.scopes[key]
+		if s == nil {
+			continue
+		}
+		for ref := range match(s, q) {
+			if !wanted(ref.Type) {
+				continue
+			}
+			if e := s.entries[ref]; key == rolesScope && e.tenant != "" && e.tenant != tenant {
+				continue
+			}
+			refs = append(refs, ref)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Type != refs[j].Type {
+			return refs[i].Type < refs[j].Type
+		}
+		return refs[i].Key < refs[j].Key
+	})
+	return refs, nil
+}
+
+// match returns the entities of s carrying all, or with q.Any at least one,
+// of the tags of q.
+func match(s *scope, q Query) map[Ref]struct{} {
+	result := map[Ref]struct{}{}
+	for i, tag := range q.Tags {
+		refs := s.byTag[tag]
+		switch {
+		case q.Any:
+			for ref := range refs {
+				result[ref] = struct{}{}
+			}
+		case i == 0:
+			for ref := range refs {
+				result[ref] = struct{}{}
+			}
+		default:
+			for ref := range result {
+				if _, ok := refs[ref]; !ok {
+					delete(result, ref)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// buildTimeout bounds a build of a scope, which the end of the contexts of the
+// searches waiting for it does not stop.
+var buildTimeout = time.Minute
+
+// ensure builds the scope with the given key unless a fresh one is held.
+// Concurrent searches share one build, made with the values of the first
+// search's context but bounded by buildTimeout instead of its deadline. Each
+// search stops waiting when its own context ends, and records in it whether
+// the build read stale cached data.
+func (idx *Index) ensure(ctx context.Context, key string) error {
+	idx.mu.Lock()
+	s := idx.scopes[key]
+	fresh := s != nil && !s.stale && idx.now().Sub(s.builtAt) < idx.policy.TTL
+	idx.mu.Unlock()
+	if fresh {
+		return nil
+	}
+
+	result := idx.builds.DoChan(key, func() (interface{}, error) {
+		shared, cancel := context.WithTimeout(context.WithoutCancel(ctx), buildTimeout)
+		defer cancel()
+		shared, stale := permit.TrackStaleReads(shared)
+		err := idx.build(shared, key)
+		return stale(), err
+	})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-result:
+		if stale, _ := res.Val.(bool); stale {
+			permit.MarkStale(ctx)
+		}
+		return res.Err
+	}
+}
+
+// build reads the scope with the given key from Permit and swaps it in,
+// replaying the writes made while it was read.
+func (idx *Index) build(ctx context.Context, key string) error {
+	idx.mu.Lock()
+	since := idx.seq
+	idx.inflight++
+	idx.mu.Unlock()
+	defer func() {
+		idx.mu.Lock()
+		idx.inflight--
+		if idx.inflight == 0 {
+			idx.journal = nil
+		}
+		idx.mu.Unlock()
+	}()
+
+	s := newScope(idx.now())
+	var err error
+	if key == rolesScope {
+		err = idx.readRoles(ctx, s)
+	} else {
+		err = idx.readTenant(ctx, key, s)
+	}
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, w := range idx.journal {
+		if w.seq > since && w.scope == key {
+			w.apply(s)
+		}
+	}
+	idx.scopes[key] = s
+	return nil
+}
+
+func (idx *Index) readTenant(ctx context.Context, tenant string, s *scope) error {
+	t, err := idx.pc.GetTenant(ctx, tenant)
+	switch {
+	case isNotFound(err):
+		// Only the tenant's own tags are missing; its instances are still listed.
+	case err != nil:
+		return fmt.Errorf("reading tenant %s: %w", tenant, err)
+	case t != nil:
+		s.put(Ref{Type: TypeTenant, Key: t.Key}, entry{tags: tagsOf(t.Attributes)})
+	}
+
+	for _, resource := range []string{config.AccountResourceTypeID, constants.CORG_RESOURCE_TYPE_ID} {
+		instances, _, err := idx.pc.ListResourceInstances(ctx, tenant, resource)
+		if err != nil {
+			return fmt.Errorf("listing resource instances of tenant %s: %w", tenant, err)
+		}
+		for i := range instances {
+			if ref, ok := instanceRef(&instances[i]); ok {
+				s.put(ref, entry{tags: tagsOf(instances[i].Attributes)})
+			}
+		}
+	}
+	return nil
+}
+
+func (idx *Index) readRoles(ctx context.Context, s *scope) error {
+	resources, _, err := idx.pc.ListResources(ctx)
+	if err != nil {
+		return fmt.Errorf("listing roles: %w", err)
+	}
+	for _, resource := range resources {
+		for _, role := range resource.Roles {
+			s.put(Ref{Type: TypeRole, Key: role.Key}, roleEntry(&role))
+		}
+	}
+	return nil
+}
+
+// record applies a change to the scope with the given key, if it is held, and
+// journals it for the builds in flight.
+func (idx *Index) record(key string, apply func(s *scope)) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.seq++
+	if s := idx.scopes[key]; s != nil {
+		apply(s)
+	}
+	if idx.inflight > 0 {
+		idx.journal = append(idx.journal, write{seq: idx.seq, scope: key, apply: apply})
+	}
+}
+
+// PutTenant indexes the tags of t.
+func (idx *Index) PutTenant(t *permit.Tenant) {
+	if t == nil {
+		return
+	}
+	ref, e := Ref{Type: TypeTenant, Key: t.Key}, entry{tags: tagsOf(t.Attributes)}
+	idx.record(t.Key, func(s *scope) { s.put(ref, e) })
+}
+
+// PutResourceInstance indexes the tags of an account or client organization
+// unit. Other resource instances are ignored.
+func (idx *Index) PutResourceInstance(instance *permit.ResourceInstance) {
+	if instance == nil || instance.Tenant == "" {
+		return
+	}
+	ref, ok := instanceRef(instance)
+	if !ok {
+		return
+	}
+	e := entry{tags: tagsOf(instance.Attributes)}
+	idx.record(instance.Tenant, func(s *scope) { s.put(ref, e) })
+}
+
+// PutRole indexes the tags of role.
+func (idx *Index) PutRole(role *permit.Role) {
+	if role == nil {
+		return
+	}
+	ref, e := Ref{Type: TypeRole, Key: role.Key}, roleEntry(role)
+	idx.record(rolesScope, func(s *scope) { s.put(ref, e) })
+}
+
+// RemoveResourceInstance drops the account or client organization unit with
+// the given key.
+func (idx *Index) RemoveResourceInstance(key string) {
+	for _, tenant := range idx.scopesHolding(key) {
+		idx.record(tenant, func(s *scope) {
+			s.remove(Ref{Type: TypeAccount, Key: key})
+			s.remove(Ref{Type: TypeClientOrganizationUnit, Key: key})
+		})
+	}
+}
+
+// RemoveRole drops the role with the given key.
+func (idx *Index) RemoveRole(key string) {
+	idx.record(rolesScope, func(s *scope) { s.remove(Ref{Type: TypeRole, Key: key}) })
+}
+
+// Invalidate makes the next search of tenant rebuild its index. Deleting a
+// tenant in Permit deletes its resource instances, so it is also used for that.
+func (idx *Index) Invalidate(tenant string) {
+	idx.record(tenant, func(s *scope) { s.stale = true })
+}
+
+// InvalidateRoles makes the next search rebuild the index of roles.
+func (idx *Index) InvalidateRoles() {
+	idx.Invalidate(rolesScope)
+}
+
+// InvalidateResourceInstance makes the next search of the tenant holding the
+// resource instance with the given key rebuild its index.
+func (idx *Index) InvalidateResourceInstance(key string) {
+	for _, tenant := range idx.scopesHolding(key) {
+		idx.Invalidate(tenant)
+	}
+}
+
+// scopesHolding returns the tenants whose index holds a resource instance
+// with the given key.
+func (idx *Index) scopesHolding(key string) []string {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	var keys []string
+	for scopeKey, s := range idx.scopes {
+		if scopeKey == rolesScope {
+			continue
+		}
+		_, account := s.entries[Ref{Type: TypeAccount, Key: key}]
+		_, unit := s.entries[Ref{Type: TypeClientOrganizationUnit, Key: key}]
+		if account || unit {
+			keys = append(keys, scopeKey)
+		}
+	}
+	return keys
+}
+
+func instanceRef(instance *permit.ResourceInstance) (Ref, bool) {
+	switch instance.Resource {
+	case config.AccountResourceTypeID:
+		return Ref{Type: TypeAccount, Key: instance.Key}, true
+	case constants.CORG_RESOURCE_TYPE_ID:
+		return Ref{Type: TypeClientOrganizationUnit, Key: instance.Key}, true
+	default:
+		return Ref{}, false
+	}
+}
+
+func roleEntry(role *permit.Role) entry {
+	e := entry{tags: tagsOf(role.Attributes)}
+	if tenant, ok := role.Attributes[constants.TENANT_ID]; ok && tenant != nil {
+		e.tenant = fmt.Sprint(tenant)
+	}
+	return e
+}
+
+func tagsOf(attributes map[string]interface{}) []models.Tags {
+	var result []models.Tags
+	for _, tag := range tags.GetResourceTags(attributes, constants.CORG_TAGS) {
+		result = append(result, *tag)
+	}
+	return result
+}
+
+func isNotFound(err error) bool {
+	var apiErr *permit.PermitAPIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,248 @@
+package tagsearch
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/constants"
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permit/permittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tagged returns attributes carrying the tags given as key, value pairs.
+func tagged(pairs ...string) map[string]interface{} {
+	tags := []interface{}{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		tags = append(tags, map[string]interface{}{"key": pairs[i], "value": pairs[i+1]})
+	}
+	return map[string]interface{}{"tags": tags}
+}
+
+func withTenant(attributes map[string]interface{}, tenant string) map[string]interface{} {
+	attributes[constants.TENANT_ID] = tenant
+	return attributes
+}
+
+// countRequests returns how many requests the fake received for paths containing part.
+func countRequests(fake *permittest.Server, method, part string) int {
+	count := 0
+	for _, req := range fake.Requests() {
+		if req.Method == method && strings.Contains(req.Path, part) {
+			count++
+		}
+	}
+	return count
+}
+
+// seed stores two tenants and their tagged accounts, units and roles in fake.
+func seed(fake *permittest.Server) {
+	fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: "t1", Attributes: tagged("cost-center", "1234")}, Name: "Tenant 1"})
+	fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: "t2", Attributes: tagged("cost-center", "1234")}, Name: "Tenant 2"})
+	fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: "a1", Attributes: tagged("cost-center", "1234", "env", "prod")}, Tenant: "t1", Resource: config.AccountResourceTypeID})
+	fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: "a2", Attributes: tagged("cost-center", "1234")}, Tenant: "t2", Resource: config.AccountResourceTypeID})
+	fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: "u1", Attributes: tagged("env", "prod")}, Tenant: "t1", Resource: constants.CORG_RESOURCE_TYPE_ID})
+	fake.AddResource(permit.Resource{Key: "tenant", Roles: map[string]permit.Role{
+		"shared": {Key: "shared", Attributes: tagged("cost-center", "1234")},
+		"own":    {Key: "own", Attributes: withTenant(tagged("env", "prod"), "t1")},
+		"other":  {Key: "other", Attributes: withTenant(tagged("cost-center", "1234"), "t2")},
+	}})
+}
+
+func TestIndexSearch(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	seed(fake)
+	idx := NewIndex(permit.NewPermitServiceImpl(fake.Client()), DefaultPolicy())
+
+	costCenter := models.Tags{Key: "cost-center", Value: "1234"}
+	prod := models.Tags{Key: "env", Value: "prod"}
+	testcases := []struct {
+		name  string
+		query Query
+		want  []Ref
+	}{
+		{
+			name:  "Single tag",
+			query: Query{Tags: []models.Tags{costCenter}},
+			want:  []Ref{{TypeAccount, "a1"}, {TypeRole, "shared"}, {TypeTenant, "t1"}},
+		},
+		{
+			name:  "All tags",
+			query: Query{Tags: []models.Tags{costCenter, prod}},
+			want:  []Ref{{TypeAccount, "a1"}},
+		},
+		{
+			name:  "Any tag",
+			query: Query{Tags: []models.Tags{costCenter, prod}, Any: true},
+			want:  []Ref{{TypeAccount, "a1"}, {TypeClientOrganizationUnit, "u1"}, {TypeRole, "own"}, {TypeRole, "shared"}, {TypeTenant, "t1"}},
+		},
+		{
+			name:  "Types",
+			query: Query{Tags: []models.Tags{prod}, Types: []string{TypeClientOrganizationUnit, TypeRole}},
+			want:  []Ref{{TypeClientOrganizationUnit, "u1"}, {TypeRole, "own"}},
+		},
+		{
+			name:  "Value must match",
+			query: Query{Tags: []models.Tags{{Key: "env", Value: "dev"}}},
+			want:  []Ref{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			refs, err := idx.Search(context.Background(), "t1", tc.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, refs)
+		})
+	}
+
+	t.Run("Other tenants see their own entities", func(t *testing.T) {
+		refs, err := idx.Search(context.Background(), "t2", Query{Tags: []models.Tags{costCenter}})
+		assert.NoError(t, err)
+		assert.Equal(t, []Ref{{TypeAccount, "a2"}, {TypeRole, "other"}, {TypeRole, "shared"}, {TypeTenant, "t2"}}, refs)
+	})
+
+	t.Run("Unknown tenants have no entities of their own", func(t *testing.T) {
+		refs, err := idx.Search(context.Background(), "missing", Query{Tags: []models.Tags{costCenter}})
+		assert.NoError(t, err)
+		assert.Equal(t, []Ref{{TypeRole, "shared"}}, refs)
+	})
+
+	t.Run("Tenant is required", func(t *testing.T) {
+		_, err := idx.Search(context.Background(), "", Query{Tags: []models.Tags{costCenter}})
+		assert.Error(t, err)
+	})
+}
+
+func TestIndexRebuild(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	seed(fake)
+	idx := NewIndex(permit.NewPermitServiceImpl(fake.Client()), Policy{TTL: time.Minute})
+	now := time.Now()
+	idx.now = func() time.Time { return now }
+	query := Query{Tags: []models.Tags{{Key: "team", Value: "iam"}}, Types: []string{TypeAccount}}
+
+	refs, err := idx.Search(context.Background(), "t1", query)
+	assert.NoError(t, err)
+	assert.Empty(t, refs)
+
+	fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: "a3", Attributes: tagged("team", "iam")}, Tenant: "t1", Resource: config.AccountResourceTypeID})
+	fake.ResetRequests()
+
+	t.Run("Fresh index is not rebuilt", func(t *testing.T) {
+		refs, err := idx.Search(context.Background(), "t1", query)
+		assert.NoError(t, err)
+		assert.Empty(t, refs)
+		assert.Empty(t, fake.Requests())
+	})
+
+	t.Run("Expired index is rebuilt", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		refs, err := idx.Search(context.Background(), "t1", query)
+		assert.NoError(t, err)
+		assert.Equal(t, []Ref{{TypeAccount, "a3"}}, refs)
+	})
+
+	t.Run("Failed rebuild is reported", func(t *testing.T) {
+		idx.Invalidate("t1")
+		fake.FailNext(1, http.StatusInternalServerError)
+		_, err := idx.Search(context.Background(), "t1", query)
+		assert.Error(t, err)
+
+		refs, err := idx.Search(context.Background(), "t1", query)
+		assert.NoError(t, err)
+		assert.Equal(t, []Ref{{TypeAccount, "a3"}}, refs)
+	})
+}
+
+// hookedService runs hook before listing resources.
+type hookedService struct {
+	permit.PermitService
+	hook func()
+}
+
+func (s *hookedService) ListResources(ctx context.Context) ([]permit.Resource, int, error) {
+	s.hook()
+	return s.PermitService.ListResources(ctx)
+}
+
+func TestIndexKeepsWritesMadeDuringRebuild(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	fake.AddResource(permit.Resource{Key: "tenant", Roles: map[string]permit.Role{
+		"stale": {Key: "stale", Attributes: tagged("env", "prod")},
+	}})
+
+	var idx *Index
+	svc := &hookedService{PermitService: permit.NewPermitServiceImpl(fake.Client())}
+	svc.hook = func() {
+		// Written after the rebuild started, so the listing may predate it.
+		idx.PutRole(&permit.Role{Key: "fresh", Attributes: tagged("env", "prod")})
+		idx.RemoveRole("stale")
+	}
+	idx = NewIndex(svc, DefaultPolicy())
+
+	refs, err := idx.Search(context.Background(), "t1", Query{Tags: []models.Tags{{Key: "env", Value: "prod"}}, Types: []string{TypeRole}})
+	assert.NoError(t, err)
+	assert.Equal(t, []Ref{{TypeRole, "fresh"}}, refs)
+	assert.Empty(t, idx.journal, "the journal is dropped once no rebuild is in flight")
+}
+
+func TestIndexBuildOutlivesTheFirstSearch(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	fake.AddResource(permit.Resource{Key: "tenant", Roles: map[string]permit.Role{
+		"admin": {Key: "admin", Attributes: tagged("env", "prod")},
+	}})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	svc := &hookedService{PermitService: permit.NewPermitServiceImpl(fake.Client())}
+	svc.hook = func() {
+		close(started)
+		<-release
+	}
+	idx := NewIndex(&staleService{svc}, DefaultPolicy())
+	query := Query{Tags: []models.Tags{{Key: "env", Value: "prod"}}, Types: []string{TypeRole}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := idx.Search(ctx, "t1", query)
+		first <- err
+	}()
+	<-started
+
+	joined := make(chan []Ref, 1)
+	joinedCtx, stale := permit.TrackStaleReads(context.Background())
+	go func() {
+		refs, err := idx.Search(joinedCtx, "t1", query)
+		assert.NoError(t, err)
+		joined <- refs
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+	close(release)
+	assert.Equal(t, []Ref{{TypeRole, "admin"}}, <-joined)
+	assert.True(t, stale(), "every search waiting for a build learns that it read stale data")
+}
+
+// staleService reports every resource listing as read from stale cached data.
+type staleService struct {
+	permit.PermitService
+}
+
+func (s *staleService) ListResources(ctx context.Context) ([]permit.Resource, int, error) {
+	permit.MarkStale(ctx)
+	return s.PermitService.ListResources(ctx)
+}
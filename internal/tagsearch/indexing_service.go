@@ -0,0 +1,118 @@
+package tagsearch
+
+import (
+	"context"
+
+	"iam_services_main_v1/internal/permit"
+)
+
+// IndexingPermitService keeps an Index up to date with the tenants, resource
+// instances and roles written through it, and passes every call through to the
+// wrapped service.
+//
+// Successful writes update the index from the entity Permit returns. Failed
+// writes may still have been applied, so they make the affected part of the
+// index rebuild on its next search instead.
+type IndexingPermitService struct {
+	permit.PermitService
+
+	index *Index
+}
+
+// NewIndexingService wraps inner so its writes update index.
+func NewIndexingService(inner permit.PermitService, index *Index) permit.PermitService {
+	return &IndexingPermitService{PermitService: inner, index: index}
+}
+
+// CreateTenant creates a tenant and indexes its tags.
+func (s *IndexingPermitService) CreateTenant(ctx context.Context, input permit.TenantCreate) (*permit.Tenant, error) {
+	tenant, err := s.PermitService.CreateTenant(ctx, input)
+	if err != nil {
+		s.index.Invalidate(input.Key)
+		return tenant, err
+	}
+	s.index.PutTenant(tenant)
+	return tenant, nil
+}
+
+// UpdateTenant patches a tenant and re-indexes its tags.
+func (s *IndexingPermitService) UpdateTenant(ctx context.Context, key string, input permit.TenantUpdate) (*permit.Tenant, error) {
+	tenant, err := s.PermitService.UpdateTenant(ctx, key, input)
+	if err != nil {
+		s.index.Invalidate(key)
+		return tenant, err
+	}
+	s.index.PutTenant(tenant)
+	return tenant, nil
+}
+
+// DeleteTenant deletes a tenant. Permit cascades the deletion to the tenant's
+// resource instances, so the tenant's whole index is rebuilt on its next search.
+func (s *IndexingPermitService) DeleteTenant(ctx context.Context, key string) error {
+	defer s.index.Invalidate(key)
+	return s.PermitService.DeleteTenant(ctx, key)
+}
+
+// CreateResourceInstance creates a resource instance and indexes its tags.
+func (s *IndexingPermitService) CreateResourceInstance(ctx context.Context, input permit.ResourceInstanceCreate) (*permit.ResourceInstance, error) {
+	instance, err := s.PermitService.CreateResourceInstance(ctx, input)
+	if err != nil {
+		s.index.Invalidate(input.Tenant)
+		return instance, err
+	}
+	s.index.PutResourceInstance(instance)
+	return instance, nil
+}
+
+// UpdateResourceInstance patches a resource instance and re-indexes its tags.
+func (s *IndexingPermitService) UpdateResourceInstance(ctx context.Context, key string, input permit.ResourceInstanceUpdate) (*permit.ResourceInstance, error) {
+	instance, err := s.PermitService.UpdateResourceInstance(ctx, key, input)
+	if err != nil {
+		s.index.InvalidateResourceInstance(key)
+		return instance, err
+	}
+	s.index.PutResourceInstance(instance)
+	return instance, nil
+}
+
+// DeleteResourceInstance deletes a resource instance and drops it from the index.
+func (s *IndexingPermitService) DeleteResourceInstance(ctx context.Context, key string) error {
+	if err := s.PermitService.DeleteResourceInstance(ctx, key); err != nil {
+		s.index.InvalidateResourceInstance(key)
+		return err
+	}
+	s.index.RemoveResourceInstance(key)
+	return nil
+}
+
+// CreateResourceRole adds a role to a resource type and indexes its tags.
+func (s *IndexingPermitService) CreateResourceRole(ctx context.Context, resourceKey string, input permit.RoleCreate) (*permit.Role, error) {
+	role, err := s.PermitService.CreateResourceRole(ctx, resourceKey, input)
+	if err != nil {
+		s.index.InvalidateRoles()
+		return role, err
+	}
+	s.index.PutRole(role)
+	return role, nil
+}
+
+// UpdateResourceRole patches a role and re-indexes its tags.
+func (s *IndexingPermitService) UpdateResourceRole(ctx context.Context, resourceKey, roleKey string, input permit.RoleUpdate) (*permit.Role, error) {
+	role, err := s.PermitService.UpdateResourceRole(ctx, resourceKey, roleKey, input)
+	if err != nil {
+		s.index.InvalidateRoles()
+		return role, err
+	}
+	s.index.PutRole(role)
+	return role, nil
+}
+
+// DeleteResourceRole removes a role and drops it from the index.
+func (s *IndexingPermitService) DeleteResourceRole(ctx context.Context, resourceKey, roleKey string) error {
+	if err := s.PermitService.DeleteResourceRole(ctx, resourceKey, roleKey); err != nil {
+		s.index.InvalidateRoles()
+		return err
+	}
+	s.index.RemoveRole(roleKey)
+	return nil
+}
@@ -0,0 +1,74 @@
+package tagsearch
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permit/permittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexingService(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	seed(fake)
+	inner := permit.NewPermitServiceImpl(fake.Client())
+	idx := NewIndex(inner, DefaultPolicy())
+	svc := NewIndexingService(inner, idx)
+	ctx := context.Background()
+
+	team := models.Tags{Key: "team", Value: "iam"}
+	search := func(t *testing.T) []Ref {
+		refs, err := idx.Search(ctx, "t1", Query{Tags: []models.Tags{team}})
+		assert.NoError(t, err)
+		return refs
+	}
+	assert.Empty(t, search(t))
+	fake.ResetRequests()
+
+	t.Run("Writes update the index without a rebuild", func(t *testing.T) {
+		_, err := svc.CreateResourceInstance(ctx, permit.ResourceInstanceCreate{Key: "a3", Tenant: "t1", Resource: config.AccountResourceTypeID, Attributes: tagged("team", "iam")})
+		assert.NoError(t, err)
+		_, err = svc.UpdateTenant(ctx, "t1", permit.TenantUpdate{Attributes: tagged("team", "iam")})
+		assert.NoError(t, err)
+		_, err = svc.CreateResourceRole(ctx, "tenant", permit.RoleCreate{Key: "r3", Attributes: tagged("team", "iam")})
+		assert.NoError(t, err)
+		assert.Equal(t, []Ref{{TypeAccount, "a3"}, {TypeRole, "r3"}, {TypeTenant, "t1"}}, search(t))
+
+		_, err = svc.UpdateResourceInstance(ctx, "a3", permit.ResourceInstanceUpdate{Attributes: tagged("team", "billing")})
+		assert.NoError(t, err)
+		assert.NoError(t, svc.DeleteResourceRole(ctx, "tenant", "r3"))
+		assert.Equal(t, []Ref{{TypeTenant, "t1"}}, search(t))
+
+		assert.Zero(t, countRequests(fake, http.MethodGet, "resource_instances"), "the index was not rebuilt")
+	})
+
+	t.Run("Deletes drop entities", func(t *testing.T) {
+		_, err := svc.UpdateResourceInstance(ctx, "a3", permit.ResourceInstanceUpdate{Attributes: tagged("team", "iam")})
+		assert.NoError(t, err)
+		assert.Contains(t, search(t), Ref{TypeAccount, "a3"})
+
+		assert.NoError(t, svc.DeleteResourceInstance(ctx, "a3"))
+		assert.NotContains(t, search(t), Ref{TypeAccount, "a3"})
+	})
+
+	t.Run("Failed writes rebuild the index", func(t *testing.T) {
+		fake.ResetRequests()
+		fake.FailNext(1, http.StatusInternalServerError)
+		_, err := svc.UpdateResourceInstance(ctx, "a1", permit.ResourceInstanceUpdate{Attributes: tagged("team", "iam")})
+		assert.Error(t, err)
+
+		assert.Equal(t, []Ref{{TypeTenant, "t1"}}, search(t))
+		assert.Equal(t, 2, countRequests(fake, http.MethodGet, "resource_instances"), "accounts and units were listed again")
+	})
+
+	t.Run("Deleting a tenant rebuilds its index", func(t *testing.T) {
+		assert.NoError(t, svc.DeleteTenant(ctx, "t1"))
+		assert.Empty(t, search(t))
+	})
+}
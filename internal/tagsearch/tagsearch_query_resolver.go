@@ -0,0 +1,250 @@
+package tagsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/accounts"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/clientorganizationunits"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/roles"
+	"iam_services_main_v1/internal/tenants"
+	"iam_services_main_v1/internal/utils"
+	"iam_services_main_v1/pkg/logger"
+)
+
+// TagSearchQueryResolver resolves searches for the entities carrying a set of tags.
+type TagSearchQueryResolver struct {
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
+	Index      *Index
+}
+
+// readChecks are the checks a caller must pass to be returned the entities of
+// each type: those of the queries reading them. The tenant check is made on
+// the caller's tenant.
+var readChecks = map[string]authz.PermissionCheck{
+	TypeTenant:                 {Action: "tenant", ResourceType: config.TenantResourceTypeID},
+	TypeAccount:                {Action: "accounts", ResourceType: config.AccountResourceTypeID},
+	TypeClientOrganizationUnit: {Action: "clientOrganizationUnits", ResourceType: config.ClientOrgUnitResourceTypeID},
+	TypeRole:                   {Action: "roles", ResourceType: config.RoleResourceTypeID},
+}
+
+// SearchByTags returns the tenants, accounts, client organization units and
+// roles of the tenant in the context that carry the tags of match. With mode
+// ANY an entity carrying any one of the tags matches. types restricts the
+// search to the given GraphQL type names. Entities of the types the caller may
+// not read are left out.
+//
+// Candidates are taken from the index and re-read from Permit, so entities
+// deleted or re-tagged since they were indexed are left out.
+func (r *TagSearchQueryResolver) SearchByTags(ctx context.Context, match []*models.TagInput, mode *models.TagMatchModeEnum, types []string) (models.OperationResult, error) {
+	logger.LogInfo("Searching entities by tags", "tags", len(match), "types", types)
+
+	if r.Index == nil {
+		return errorcatalog.Tag.New(http.StatusServiceUnavailable, "Tag search is not available", "the tag index is not configured"), nil
+	}
+	q, err := newQuery(match, mode, types)
+	if err != nil {
+		return errorcatalog.Tag.FromError(http.StatusBadRequest, "Invalid tag search", err), nil
+	}
+	userID, tenantID, err := helpers.GetUserAndTenantID(ctx)
+	if err != nil {
+		return errorcatalog.Tag.FromError(http.StatusBadRequest, "Failed to get user and tenant ID", err), nil
+	}
+	tenant := tenantID.String()
+
+	refs, err := r.Index.Search(ctx, tenant, q)
+	if err != nil {
+		return errorcatalog.Tag.FromError(http.StatusBadRequest, "Failed to search tags", err), nil
+	}
+	refs, err = r.readable(ctx, userID.String(), tenant, refs)
+	if err != nil {
+		return errorcatalog.Tag.FromError(http.StatusForbidden, "Failed to authorize tagged entities", err), nil
+	}
+
+	data, err := r.load(ctx, tenant, q, refs)
+	if err != nil {
+		return errorcatalog.Tag.FromError(http.StatusBadRequest, "Failed to read tagged entities from permit", err), nil
+	}
+
+	response, _ := utils.FormatSuccessWithTotal(data, len(data))
+	return response, nil
+}
+
+func newQuery(match []*models.TagInput, mode *models.TagMatchModeEnum, types []string) (Query, error) {
+	if len(match) == 0 {
+		return Query{}, errors.New("at least one tag to match is required")
+	}
+	q := Query{Any: mode != nil && *mode == models.TagMatchModeEnumAny, Types: types}
+	for _, tag := range match {
+		if tag == nil {
+			continue
+		}
+		q.Tags = append(q.Tags, models.Tags{Key: tag.Key, Value: tag.Value})
+	}
+	for _, t := range types {
+		if !slices.Contains(Types, t) {
+			return Query{}, fmt.Errorf("unknown type %q, expected one of %v", t, Types)
+		}
+	}
+	return q, nil
+}
+
+// readable returns the refs of the types userID may read in tenant, checking
+// every type of refs in one BulkCheck. When the checks fail for any reason
+// other than an unavailable authorizer, every ref is dropped.
+func (r *TagSearchQueryResolver) readable(ctx context.Context, userID, tenant string, refs []Ref) ([]Ref, error) {
+	if r.Authorizer == nil {
+		return nil, errors.New("no authorizer is configured")
+	}
+	var types []string
+	for _, ref := range refs {
+		if !slices.Contains(types, ref.Type) {
+			types = append(types, ref.Type)
+		}
+	}
+	if len(types) == 0 {
+		return refs, nil
+	}
+	checks := make([]authz.PermissionCheck, len(types))
+	for i, t := range types {
+		checks[i] = readChecks[t]
+		checks[i].Action = strings.ToLower(checks[i].Action)
+		checks[i].ResourceID = "*"
+		if t == TypeTenant {
+			checks[i].ResourceID = tenant
+		}
+	}
+	allowed, err := r.Authorizer.BulkCheck(ctx, userID, tenant, checks)
+	if errors.Is(err, authz.ErrUnavailable) {
+		return nil, errorcatalog.Authorization.Err(http.StatusServiceUnavailable, err)
+	}
+	if err == nil && len(allowed) != len(checks) {
+		err = fmt.Errorf("got %d decisions for %d checks", len(allowed), len(checks))
+	}
+	if err != nil {
+		logger.LogInfo("Tagged entities denied", "types", types, "error", err)
+		return []Ref{}, nil
+	}
+
+	readable := []Ref{}
+	for _, ref := range refs {
+		if allowed[slices.Index(types, ref.Type)] {
+			readable = append(readable, ref)
+		}
+	}
+	return readable, nil
+}
+
+// load reads the entities of refs through the request's dataloaders, keeping
+// the order of refs. Entities that no longer exist, have left the tenant or no
+// longer match q are dropped.
+func (r *TagSearchQueryResolver) load(ctx context.Context, tenant string, q Query, refs []Ref) ([]models.Data, error) {
+	loaders := dataloaders.For(ctx, r.PC)
+	found := make([]models.Data, len(refs))
+	errs := make([]error, len(refs))
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found[i], errs[i] = loadRef(ctx, loaders, tenant, q, ref)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	data := []models.Data{}
+	for _, item := range found {
+		if item != nil {
+			data = append(data, item)
+		}
+	}
+	return data, nil
+}
+
+// loadRef returns the entity of ref, or nil when it should not be returned.
+func loadRef(ctx context.Context, loaders *dataloaders.Loaders, tenant string, q Query, ref Ref) (models.Data, error) {
+	var (
+		data models.Data
+		err  error
+	)
+	switch ref.Type {
+	case TypeTenant:
+		t, loadErr := loaders.Tenants.Load(ctx, ref.Key)
+		if isNotFound(loadErr) {
+			return nil, nil
+		} else if loadErr != nil {
+			return nil, loadErr
+		}
+		if t.Key != tenant || !q.matches(tagsOf(t.Attributes)) {
+			return nil, nil
+		}
+		data, err = tenants.MapTenantData(&t.Entity)
+
+	case TypeAccount, TypeClientOrganizationUnit:
+		instance, loadErr := loaders.ResourceInstances.Load(ctx, ref.Key)
+		if isNotFound(loadErr) {
+			return nil, nil
+		} else if loadErr != nil {
+			return nil, loadErr
+		}
+		if current, ok := instanceRef(instance); !ok || current != ref || instance.Tenant != tenant || !q.matches(tagsOf(instance.Attributes)) {
+			return nil, nil
+		}
+		if ref.Type == TypeAccount {
+			var mapped []models.Data
+			if mapped, err = accounts.MapAccountResponseToStruct(instance); err == nil {
+				data = mapped[0]
+			}
+		} else {
+			data, err = clientorganizationunits.BuildOrgUnit(instance)
+		}
+
+	case TypeRole:
+		rr, loadErr := loaders.Roles.Load(ctx, ref.Key)
+		if errors.Is(loadErr, dataloaders.ErrRoleNotFound) {
+			return nil, nil
+		} else if loadErr != nil {
+			return nil, loadErr
+		}
+		if e := roleEntry(&rr.Role); (e.tenant != "" && e.tenant != tenant) || !q.matches(e.tags) {
+			return nil, nil
+		}
+		data, err = roles.MapToRoleData(rr.Role, rr.Resource)
+	}
+
+	if err != nil {
+		logger.LogError("Skipping tagged entity that cannot be mapped", "type", ref.Type, "key", ref.Key, "error", err)
+		return nil, nil
+	}
+	return data, nil
+}
+
+// matches reports whether an entity carrying tags matches q.
+func (q Query) matches(tags []models.Tags) bool {
+	for _, want := range q.Tags {
+		found := slices.Contains(tags, want)
+		if q.Any && found {
+			return true
+		}
+		if !q.Any && !found {
+			return false
+		}
+	}
+	return !q.Any
+}
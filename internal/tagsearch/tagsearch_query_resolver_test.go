@@ -0,0 +1,176 @@
+package tagsearch
+
+import (
+	"context"
+	"testing"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/constants"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/internal/permit"
+	"iam_services_main_v1/internal/permit/permittest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func tenantContext(tenantID string) context.Context {
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantID)
+	ginCtx.Set("userID", uuid.NewString())
+	return dataloaders.WithLoaders(context.WithValue(context.Background(), config.GinContextKey, ginCtx))
+}
+
+func withName(attributes map[string]interface{}, name string) map[string]interface{} {
+	attributes[constants.NAME] = name
+	return attributes
+}
+
+func TestSearchByTags(t *testing.T) {
+	fake := permittest.NewServer()
+	defer fake.Close()
+	pc := permit.NewPermitServiceImpl(fake.Client())
+	idx := NewIndex(pc, DefaultPolicy())
+	resolver := &TagSearchQueryResolver{PC: NewIndexingService(pc, idx), Authorizer: authz.AllowAll(), Index: idx}
+
+	tenantID, otherTenantID := uuid.NewString(), uuid.NewString()
+	accountID, retaggedID, deletedID, unitID, roleID := uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString()
+
+	tenantAttributes := withName(tagged("cost-center", "1234"), "Acme")
+	tenantAttributes["contactInfo"] = map[string]interface{}{}
+	fake.AddTenant(permit.Tenant{Entity: permit.Entity{Key: tenantID, Attributes: tenantAttributes}, Name: "Acme"})
+	for key, name := range map[string]string{accountID: "Billing", retaggedID: "Retagged", deletedID: "Deleted"} {
+		fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: key, Attributes: withName(tagged("cost-center", "1234"), name)}, Tenant: tenantID, Resource: config.AccountResourceTypeID})
+	}
+	unitAttributes := withTenant(withName(tagged("cost-center", "1234", "env", "prod"), "Platform"), tenantID)
+	unitAttributes[constants.KEY] = unitID
+	fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: unitID, Attributes: unitAttributes}, Tenant: tenantID, Resource: constants.CORG_RESOURCE_TYPE_ID})
+	fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: uuid.NewString(), Attributes: withName(tagged("cost-center", "1234"), "Elsewhere")}, Tenant: otherTenantID, Resource: config.AccountResourceTypeID})
+	fake.AddResource(permit.Resource{Key: config.TenantResourceTypeID, Name: "Tenant", Roles: map[string]permit.Role{
+		roleID: {Key: roleID, Name: "Auditor", Attributes: tagged("cost-center", "1234")},
+	}})
+
+	names := func(res models.OperationResult) []string {
+		success, ok := res.(*models.SuccessResponse)
+		if !assert.True(t, ok, "expected a success response, got %#v", res) {
+			return nil
+		}
+		result := []string{}
+		for _, item := range success.Data {
+			switch item := item.(type) {
+			case *models.Tenant:
+				result = append(result, item.Name)
+			case *models.Account:
+				result = append(result, item.Name)
+			case *models.ClientOrganizationUnit:
+				result = append(result, item.Name)
+			case *models.Role:
+				result = append(result, item.Name)
+			}
+		}
+		assert.Equal(t, len(result), *success.TotalCount)
+		return result
+	}
+	costCenter := []*models.TagInput{{Key: "cost-center", Value: "1234"}}
+
+	t.Run("Matches every type of the tenant", func(t *testing.T) {
+		res, err := resolver.SearchByTags(tenantContext(tenantID), costCenter, nil, nil)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Billing", "Retagged", "Deleted"}, names(res)[:3])
+		assert.Equal(t, []string{"Platform", "Auditor", "Acme"}, names(res)[3:])
+	})
+
+	t.Run("Entities changed since indexing are re-checked", func(t *testing.T) {
+		fake.AddResourceInstance(permit.ResourceInstance{Entity: permit.Entity{Key: retaggedID, Attributes: withName(tagged("cost-center", "9999"), "Retagged")}, Tenant: tenantID, Resource: config.AccountResourceTypeID})
+		assert.NoError(t, pc.DeleteResourceInstance(context.Background(), deletedID))
+
+		res, err := resolver.SearchByTags(tenantContext(tenantID), costCenter, nil, []string{TypeAccount})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Billing"}, names(res))
+	})
+
+	t.Run("Any tag", func(t *testing.T) {
+		anyTag := models.TagMatchModeEnumAny
+		match := []*models.TagInput{{Key: "env", Value: "prod"}, {Key: "missing", Value: "tag"}}
+
+		res, err := resolver.SearchByTags(tenantContext(tenantID), match, &anyTag, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Platform"}, names(res))
+
+		allTags := models.TagMatchModeEnumAll
+		res, err = resolver.SearchByTags(tenantContext(tenantID), match, &allTags, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, names(res))
+	})
+
+	t.Run("Other tenants only see their own entities and shared roles", func(t *testing.T) {
+		res, err := resolver.SearchByTags(tenantContext(otherTenantID), costCenter, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Elsewhere", "Auditor"}, names(res))
+	})
+
+	t.Run("Types the caller may not read are left out", func(t *testing.T) {
+		noAccounts := &authz.Policy{Rules: []authz.Rule{
+			{ResourceType: config.TenantResourceTypeID, Actions: []string{"tenant"}},
+			{ResourceType: config.ClientOrgUnitResourceTypeID, Actions: []string{"clientorganizationunits"}},
+			{ResourceType: config.RoleResourceTypeID, Actions: []string{"roles"}},
+		}}
+		resolver := &TagSearchQueryResolver{PC: pc, Authorizer: noAccounts, Index: idx}
+
+		res, err := resolver.SearchByTags(tenantContext(tenantID), costCenter, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Platform", "Auditor", "Acme"}, names(res))
+
+		res, err = resolver.SearchByTags(tenantContext(tenantID), costCenter, nil, []string{TypeAccount})
+		assert.NoError(t, err)
+		assert.Empty(t, names(res))
+	})
+
+	t.Run("A tenant rule only grants its own tenant", func(t *testing.T) {
+		ownTenant := &authz.Policy{Rules: []authz.Rule{
+			{ResourceType: config.TenantResourceTypeID, ResourceID: otherTenantID, Actions: []string{"tenant"}},
+		}}
+		resolver := &TagSearchQueryResolver{PC: pc, Authorizer: ownTenant, Index: idx}
+
+		res, err := resolver.SearchByTags(tenantContext(tenantID), costCenter, nil, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, names(res))
+	})
+
+	errorCode := func(res models.OperationResult) string {
+		resErr, ok := res.(*models.ResponseError)
+		if !assert.True(t, ok, "expected an error response, got %#v", res) {
+			return ""
+		}
+		return resErr.ErrorCode
+	}
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		res, err := resolver.SearchByTags(tenantContext(tenantID), nil, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "IAM-TAG-400", errorCode(res))
+
+		res, err = resolver.SearchByTags(tenantContext(tenantID), costCenter, nil, []string{"Group"})
+		assert.NoError(t, err)
+		assert.Equal(t, "IAM-TAG-400", errorCode(res))
+
+		res, err = resolver.SearchByTags(context.Background(), costCenter, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "IAM-TAG-400", errorCode(res))
+	})
+
+	t.Run("Without an index", func(t *testing.T) {
+		res, err := (&TagSearchQueryResolver{PC: pc}).SearchByTags(tenantContext(tenantID), costCenter, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "IAM-TAG-503", errorCode(res))
+	})
+
+	t.Run("Without an authorizer", func(t *testing.T) {
+		res, err := (&TagSearchQueryResolver{PC: pc, Index: idx}).SearchByTags(tenantContext(tenantID), costCenter, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "IAM-TAG-403", errorCode(res))
+	})
+}
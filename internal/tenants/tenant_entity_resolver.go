@@ -0,0 +1,47 @@
+package tenants
+
+import (
+	"context"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/internal/federation"
+	"iam_services_main_v1/internal/permit"
+)
+
+// TenantEntityResolver resolves the tenants other subgraphs reference.
+type TenantEntityResolver struct {
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
+}
+
+// FindManyTenantByIDs resolves a batch of tenant references, in order. Like
+// the tenant query, it needs the tenant action on each tenant; the references
+// to tenants the caller may not read resolve to nil.
+func (r *TenantEntityResolver) FindManyTenantByIDs(ctx context.Context, reps []*models.TenantByIDsInput) ([]*models.Tenant, error) {
+	ids := make([]string, len(reps))
+	for i, rep := range reps {
+		ids[i] = rep.ID.String()
+	}
+	allowed, err := federation.AuthorizeEach(ctx, r.Authorizer, "tenant", config.TenantResourceTypeID, ids)
+	if err != nil {
+		return nil, err
+	}
+	readable := make(map[*models.TenantByIDsInput]bool, len(reps))
+	for i, rep := range reps {
+		readable[rep] = allowed[i]
+	}
+	loaders := dataloaders.For(ctx, r.PC)
+	return federation.Resolve(ctx, reps, func(ctx context.Context, rep *models.TenantByIDsInput) (*models.Tenant, error) {
+		if !readable[rep] {
+			return nil, nil
+		}
+		tenant, err := loaders.Tenants.Load(ctx, rep.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		return MapTenantData(&tenant.Entity)
+	})
+}
@@ -0,0 +1,80 @@
+package tenants
+
+import (
+	"context"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/federation"
+	"iam_services_main_v1/internal/permit"
+	mocks "iam_services_main_v1/mocks"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindManyTenantByIDs(t *testing.T) {
+	callerTenant := uuid.New()
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", callerTenant.String())
+	ginCtx.Set("userID", uuid.New().String())
+	ctx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+	found, missing := uuid.New(), uuid.New()
+
+	t.Run("Resolves the tenants in order", func(t *testing.T) {
+		ctrl := mock.NewController(t)
+		defer ctrl.Finish()
+		mockPC := mocks.NewMockPermitService(ctrl)
+		mockPC.EXPECT().GetTenant(mock.Any(), found.String()).Return(&permit.Tenant{Entity: permit.Entity{
+			Key:        found.String(),
+			Attributes: map[string]interface{}{"name": "Acme", "contactInfo": map[string]interface{}{}},
+		}}, nil)
+		mockPC.EXPECT().GetTenant(mock.Any(), missing.String()).Return(nil, &permit.PermitAPIError{StatusCode: http.StatusNotFound})
+
+		resolver := TenantEntityResolver{PC: mockPC, Authorizer: authz.AllowAll()}
+		tenants, err := resolver.FindManyTenantByIDs(ctx, []*models.TenantByIDsInput{{ID: missing}, {ID: found}})
+		assert.NoError(t, err)
+		if assert.Len(t, tenants, 2) {
+			assert.Nil(t, tenants[0])
+			assert.Equal(t, found, tenants[1].ID)
+			assert.Equal(t, "Acme", tenants[1].Name)
+		}
+	})
+
+	t.Run("References to tenants the caller may not read resolve to null", func(t *testing.T) {
+		ctrl := mock.NewController(t)
+		defer ctrl.Finish()
+		mockPC := mocks.NewMockPermitService(ctrl)
+		mockPC.EXPECT().GetTenant(mock.Any(), callerTenant.String()).Return(&permit.Tenant{Entity: permit.Entity{
+			Key:        callerTenant.String(),
+			Attributes: map[string]interface{}{"name": "Own", "contactInfo": map[string]interface{}{}},
+		}}, nil)
+		// Only the caller's own tenant is readable; the foreign one is never fetched.
+		policy := &authz.Policy{Rules: []authz.Rule{{Actions: []string{"tenant"}, ResourceType: config.TenantResourceTypeID, ResourceID: callerTenant.String()}}}
+
+		resolver := TenantEntityResolver{PC: mockPC, Authorizer: policy}
+		tenants, err := resolver.FindManyTenantByIDs(ctx, []*models.TenantByIDsInput{{ID: found}, {ID: callerTenant}})
+		assert.NoError(t, err)
+		if assert.Len(t, tenants, 2) {
+			assert.Nil(t, tenants[0])
+			assert.Equal(t, "Own", tenants[1].Name)
+		}
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		ctrl := mock.NewController(t)
+		defer ctrl.Finish()
+		resolver := TenantEntityResolver{PC: mocks.NewMockPermitService(ctrl), Authorizer: authz.DenyAll()}
+		tenants, err := resolver.FindManyTenantByIDs(ctx, []*models.TenantByIDsInput{{ID: found}})
+		assert.NoError(t, err)
+		assert.Equal(t, []*models.Tenant{nil}, tenants)
+
+		resolver.Authorizer = nil
+		_, err = resolver.FindManyTenantByIDs(ctx, []*models.TenantByIDsInput{{ID: found}})
+		assert.ErrorIs(t, err, federation.ErrForbidden)
+	})
+}
@@ -5,26 +5,18 @@ import (
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/tags"
 	"iam_services_main_v1/pkg/logger"
-)
 
-// MapTenantsResponseToStruct maps a response containing resource data to a slice of models.Data structs.
-func MapTenantsResponseToStruct(resourcesResponse map[string]interface{}) ([]models.Data, error) {
-	rawData, ok := resourcesResponse["data"].([]interface{})
-	if !ok {
-		logger.LogError("invalid data field in resourcesResponse")
-		return nil, fmt.Errorf("missing or invalid data field")
-	}
+	"github.com/google/uuid"
+)
 
+// MapTenantsResponseToStruct maps a list of Permit tenants to a slice of models.Data structs.
+func MapTenantsResponseToStruct(tenantsResponse []permit.Tenant) ([]models.Data, error) {
 	var Tenants []models.Data
-	for _, item := range rawData {
-		TenantData, ok := item.(map[string]interface{})
-		if !ok {
-			logger.LogError("invalid Tenant data format in response")
-			continue
-		}
-		Tenant, err := MapTenantData(TenantData)
+	for i := range tenantsResponse {
+		Tenant, err := MapTenantData(&tenantsResponse[i].Entity)
 		if err != nil {
 			logger.LogError("error mapping Tenant data", "error", err)
 			return nil, err
@@ -34,9 +26,12 @@ func MapTenantsResponseToStruct(resourcesResponse map[string]interface{}) ([]mod
 	return Tenants, nil
 }
 
-// MapTenantResponseToStruct maps a resource response represented as a map to a slice of models.Data.
-func MapTenantResponseToStruct(resourceResponse map[string]interface{}) ([]models.Data, error) {
-	Tenant, err := MapTenantData(resourceResponse)
+// MapTenantResponseToStruct maps a single Permit tenant to a slice of models.Data.
+func MapTenantResponseToStruct(tenantResponse *permit.Tenant) ([]models.Data, error) {
+	if tenantResponse == nil {
+		return nil, fmt.Errorf("missing tenant data")
+	}
+	Tenant, err := MapTenantData(&tenantResponse.Entity)
 	if err != nil {
 		logger.LogError("error mapping Tenant data in MapTenantResponseToStruct", "error", err)
 		return nil, err
@@ -44,17 +39,20 @@ func MapTenantResponseToStruct(resourceResponse map[string]interface{}) ([]model
 	return []models.Data{Tenant}, nil
 }
 
-// mapTenantData maps a resource response to an Tenant model.
-func MapTenantData(TenantData map[string]interface{}) (*models.Tenant, error) {
+// MapTenantData maps a Permit tenant or tenant resource instance to a Tenant model.
+func MapTenantData(TenantData *permit.Entity) (*models.Tenant, error) {
 	logger.LogInfo("Mapping Tenant data to struct : ", "TenantData", TenantData)
-	id, err := helpers.GetUUID(TenantData, "key")
+	if TenantData == nil {
+		return nil, fmt.Errorf("missing tenant data")
+	}
+	id, err := uuid.Parse(TenantData.Key)
 	if err != nil {
 		logger.LogError("failed to get UUID from Tenant data", "error", err)
-		return nil, err
+		return nil, fmt.Errorf("invalid or missing UUID for key: key")
 	}
 
-	attributes, ok := TenantData["attributes"].(map[string]interface{})
-	if !ok {
+	attributes := TenantData.Attributes
+	if attributes == nil {
 		logger.LogError("failed to get attributes map from Tenant data", "error", "missing or invalid map for key: attributes")
 		return nil, fmt.Errorf("missing or invalid map for key: attributes")
 	}
@@ -74,10 +72,8 @@ func MapTenantData(TenantData map[string]interface{}) (*models.Tenant, error) {
 	}
 	contactInfo, _ := mapContactInfo(attributes)
 	tags := tags.GetResourceTags(attributes, "tags")
-	createdAt := helpers.GetString(TenantData, "created_at")
-	createdAt, _ = helpers.ConvertToZFormat(createdAt)
-	updatedAt := helpers.GetString(TenantData, "updated_at")
-	updatedAt, _ = helpers.ConvertToZFormat(updatedAt)
+	createdAt, _ := helpers.ConvertToZFormat(TenantData.CreatedAt)
+	updatedAt, _ := helpers.ConvertToZFormat(TenantData.UpdatedAt)
 	return &models.Tenant{
 		ID:           id,
 		Type:         config.Tenant,
@@ -2,21 +2,22 @@ package tenants
 
 import (
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
-func createValidTenantResponse() map[string]interface{} {
+func createValidTenantResponse() *permit.Tenant {
 	tenantID := uuid.New()
 	userID := uuid.New()
 
-	return map[string]interface{}{
-		"key":        tenantID.String(),
-		"created_at": "2023-01-01T00:00:00Z",
-		"updated_at": "2023-01-02T00:00:00Z",
-		"attributes": map[string]interface{}{
+	return &permit.Tenant{Entity: permit.Entity{
+		Key:       tenantID.String(),
+		CreatedAt: "2023-01-01T00:00:00Z",
+		UpdatedAt: "2023-01-02T00:00:00Z",
+		Attributes: map[string]interface{}{
 			"id":          tenantID.String(),
 			"name":        "Test Tenant",
 			"description": "A test tenant",
@@ -44,23 +45,17 @@ func createValidTenantResponse() map[string]interface{} {
 				},
 			},
 		},
-	}
+	}}
 }
 
-func createTenantsListResponse() map[string]interface{} {
+func createTenantsListResponse() []permit.Tenant {
 	tenant1 := createValidTenantResponse()
 	tenant2 := createValidTenantResponse()
 
 	// Modify tenant2 to have different values
-	tenant2Attrs := tenant2["attributes"].(map[string]interface{})
-	tenant2Attrs["name"] = "Second Tenant"
+	tenant2.Attributes["name"] = "Second Tenant"
 
-	return map[string]interface{}{
-		"data": []interface{}{
-			tenant1,
-			tenant2,
-		},
-	}
+	return []permit.Tenant{*tenant1, *tenant2}
 }
 
 func TestMapTenantsResponseToStruct(t *testing.T) {
@@ -79,47 +74,23 @@ func TestMapTenantsResponseToStruct(t *testing.T) {
 		assert.Len(t, tenants[0].(*models.Tenant).Tags, 2)
 	})
 
-	t.Run("Invalid data field", func(t *testing.T) {
-		// Setup - missing data field
-		response := map[string]interface{}{
-			"not_data": []interface{}{},
-		}
-
+	t.Run("Empty list", func(t *testing.T) {
 		// Execute
-		tenants, err := MapTenantsResponseToStruct(response)
+		tenants, err := MapTenantsResponseToStruct([]permit.Tenant{})
 
 		// Verify
-		assert.Error(t, err)
-		assert.Nil(t, tenants)
-		assert.Contains(t, err.Error(), "missing or invalid data field")
-	})
-
-	t.Run("Invalid tenant data format", func(t *testing.T) {
-		// Setup - data contains non-map item
-		response := map[string]interface{}{
-			"data": []interface{}{
-				"not a map",
-			},
-		}
-
-		// Execute
-		tenants, err := MapTenantsResponseToStruct(response)
-
-		// Verify
-		assert.NoError(t, err) // This won't error out, it will just log and continue
+		assert.NoError(t, err)
 		assert.Empty(t, tenants)
 	})
 
 	t.Run("Error mapping tenant data", func(t *testing.T) {
 		// Setup - tenant missing key field
-		response := map[string]interface{}{
-			"data": []interface{}{
-				map[string]interface{}{
-					"attributes": map[string]interface{}{
-						"name": "Invalid Tenant",
-					},
+		response := []permit.Tenant{
+			{Entity: permit.Entity{
+				Attributes: map[string]interface{}{
+					"name": "Invalid Tenant",
 				},
-			},
+			}},
 		}
 
 		// Execute
@@ -150,13 +121,22 @@ func TestMapTenantResponseToStruct(t *testing.T) {
 		assert.Len(t, tenant.Tags, 2)
 	})
 
+	t.Run("Nil tenant", func(t *testing.T) {
+		// Execute
+		tenants, err := MapTenantResponseToStruct(nil)
+
+		// Verify
+		assert.Error(t, err)
+		assert.Nil(t, tenants)
+	})
+
 	t.Run("Error mapping tenant data", func(t *testing.T) {
 		// Setup - tenant missing key field
-		response := map[string]interface{}{
-			"attributes": map[string]interface{}{
+		response := &permit.Tenant{Entity: permit.Entity{
+			Attributes: map[string]interface{}{
 				"name": "Invalid Tenant",
 			},
-		}
+		}}
 
 		// Execute
 		tenants, err := MapTenantResponseToStruct(response)
@@ -174,20 +154,20 @@ func TestMapTenantData(t *testing.T) {
 		tenantData := createValidTenantResponse()
 
 		// Execute
-		tenant, err := MapTenantData(tenantData)
+		tenant, err := MapTenantData(&tenantData.Entity)
 
 		// Verify
 		assert.NoError(t, err)
 		assert.NotNil(t, tenant)
 
 		// Verify all fields are correctly mapped
-		assert.Equal(t, tenantData["key"], tenant.ID.String())
+		assert.Equal(t, tenantData.Key, tenant.ID.String())
 
-		attributes := tenantData["attributes"].(map[string]interface{})
+		attributes := tenantData.Attributes
 		assert.Equal(t, attributes["name"], tenant.Name)
 		assert.Equal(t, attributes["description"], *tenant.Description)
-		assert.Equal(t, tenantData["created_at"], tenant.CreatedAt)
-		assert.Equal(t, tenantData["updated_at"], tenant.UpdatedAt)
+		assert.Equal(t, tenantData.CreatedAt, tenant.CreatedAt)
+		assert.Equal(t, tenantData.UpdatedAt, tenant.UpdatedAt)
 		assert.Equal(t, attributes["createdBy"], tenant.CreatedBy.String())
 		assert.Equal(t, attributes["updatedBy"], tenant.UpdatedBy.String())
 
@@ -201,9 +181,8 @@ func TestMapTenantData(t *testing.T) {
 
 	t.Run("Missing key field", func(t *testing.T) {
 		// Setup
-		tenantData := map[string]interface{}{
-			"not_key":    uuid.New().String(),
-			"attributes": map[string]interface{}{},
+		tenantData := &permit.Entity{
+			Attributes: map[string]interface{}{},
 		}
 
 		// Execute
@@ -217,9 +196,8 @@ func TestMapTenantData(t *testing.T) {
 
 	t.Run("Missing attributes", func(t *testing.T) {
 		// Setup
-		tenantData := map[string]interface{}{
-			"key":            uuid.New().String(),
-			"not_attributes": map[string]interface{}{},
+		tenantData := &permit.Entity{
+			Key: uuid.New().String(),
 		}
 
 		// Execute
@@ -233,9 +211,9 @@ func TestMapTenantData(t *testing.T) {
 
 	t.Run("Missing contact info", func(t *testing.T) {
 		// Setup
-		tenantData := map[string]interface{}{
-			"key": uuid.New().String(),
-			"attributes": map[string]interface{}{
+		tenantData := &permit.Entity{
+			Key: uuid.New().String(),
+			Attributes: map[string]interface{}{
 				"name": "Test Tenant",
 				// Missing contactInfo
 			},
@@ -7,6 +7,9 @@ import (
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
 	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/events"
 	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	"iam_services_main_v1/pkg/logger"
@@ -16,8 +19,10 @@ import (
 )
 
 type TenantMutationResolver struct {
-	PC  permit.PermitService
-	PSC *permit.PermitSdkService
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
+	// Events receives the changes made, for subscriptions. It may be nil.
+	Events *events.Bus
 }
 
 // CreateTenant resolver for adding a new Tenant
@@ -25,66 +30,70 @@ func (t *TenantMutationResolver) CreateTenant(ctx context.Context, input models.
 	//Map and validate the tenant creation input data
 	_, err := ValidateCreateTenantInput(input)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Invalid input data", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Invalid input data", err), nil
 	}
 
 	// Prepare metadata for the tenant from the input data
 	metadata, err := t.prepareMetadata(ctx, input)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to prepare metadata in create tenant", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to prepare metadata in create tenant", err), nil
 	}
 
-	if _, err = t.PC.SendRequest(ctx, "POST", "tenants", map[string]interface{}{
-		"name":       input.Name,
-		"key":        input.ID,
-		"attributes": metadata,
+	if _, err = t.PC.CreateTenant(ctx, permit.TenantCreate{
+		Key:        input.ID.String(),
+		Name:       input.Name,
+		Attributes: metadata,
 	}); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to create tenant in permit system", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to create tenant in permit system", err), nil
 	}
 
 	// Create resource instance
-	if _, err = t.PC.SendRequest(ctx, "POST", "resource_instances", map[string]interface{}{
-		"key":        input.ID,
-		"resource":   config.TenantResourceTypeID,
-		"tenant":     input.ID,
-		"attributes": metadata,
+	if _, err = t.PC.CreateResourceInstance(ctx, permit.ResourceInstanceCreate{
+		Key:        input.ID.String(),
+		Resource:   config.TenantResourceTypeID,
+		Tenant:     input.ID.String(),
+		Attributes: metadata,
 	}); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to create resource instance in permit system", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to create resource instance in permit system", err), nil
 	}
 
 	// Fetch and return created tenant
-	return t.getCreatedTenant(ctx, input.ID)
+	res, err := t.getCreatedTenant(ctx, input.ID)
+	t.Events.TenantChanged(models.ChangeTypeEnumCreated, input.ID, res)
+	return res, err
 }
 
 // UpdateTenant resolver for updating a Tenant
 func (t *TenantMutationResolver) UpdateTenant(ctx context.Context, input models.UpdateTenantInput) (models.OperationResult, error) {
 	if input.ID == uuid.Nil {
 		err := errors.New("Tenant ID is required")
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Tenant ID is required", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Tenant ID is required", err), nil
 	}
 
 	// Fetch existing tenant data
 	existingTenant, err := t.getExistingTenant(ctx, input.ID)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get existing tenant data", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to get existing tenant data", err), nil
 	}
 
 	// Merge existing data with updates
 	updatedMetadata, err := t.mergeTenantData(ctx, existingTenant, input)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to prepare metadata in update tenant", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to prepare metadata in update tenant", err), nil
 	}
 
 	// Update tenant in permit
-	if _, err := t.PC.SendRequest(ctx, "PATCH", fmt.Sprintf("tenants/%s", input.ID), map[string]interface{}{
-		"name":       input.Name,
-		"attributes": updatedMetadata,
+	if _, err := t.PC.UpdateTenant(ctx, input.ID.String(), permit.TenantUpdate{
+		Name:       input.Name,
+		Attributes: updatedMetadata,
 	}); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to update tenant in permit system", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to update tenant in permit system", err), nil
 	}
 
 	// Fetch and return created tenant
-	return t.getCreatedTenant(ctx, input.ID)
+	res, err := t.getCreatedTenant(ctx, input.ID)
+	t.Events.TenantChanged(models.ChangeTypeEnumUpdated, input.ID, res)
+	return res, err
 }
 
 // DeleteTenant resolver for deleting a Tenant
@@ -92,12 +101,15 @@ func (t *TenantMutationResolver) DeleteTenant(ctx context.Context, input models.
 	// Check if ID is provided
 	if input.ID == uuid.Nil {
 		err := errors.New("Tenant ID is required")
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Tenant ID is required", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Tenant ID is required", err), nil
 	}
 	// Delete from permit
-	if _, err := t.PC.SendRequest(ctx, "DELETE", fmt.Sprintf("tenants/%s", input.ID), nil); err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to delete tenant from permit", err.Error()), nil
+	if err := t.PC.DeleteTenant(ctx, input.ID.String()); err != nil {
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to delete tenant from permit", err), nil
 	}
+	// Permit removes the tenant's role assignments together with the tenant
+	authz.InvalidateAllDecisions(t.Authorizer)
+	t.Events.TenantChanged(models.ChangeTypeEnumDeleted, input.ID, nil)
 
 	return utils.FormatSuccess([]models.Data{})
 }
@@ -138,25 +150,23 @@ func (t *TenantMutationResolver) getCreatedTenant(ctx context.Context, tenantID
 	tenantResolver := &TenantQueryResolver{PC: t.PC}
 	data, err := tenantResolver.Tenant(ctx, tenantID)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to fetch created tenant", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to fetch created tenant", err), nil
 	}
 	return data, nil
 }
 
 func (t *TenantMutationResolver) getExistingTenant(ctx context.Context, id uuid.UUID) (map[string]interface{}, error) {
-	resourceURL := fmt.Sprintf("tenants/%s", id)
-	tenantResource, err := t.PC.SendRequest(ctx, "GET", resourceURL, nil)
+	tenantResource, err := t.PC.GetTenant(permit.WithFreshReads(ctx), id.String())
 	if err != nil {
 		logger.LogError("Failed to fetch tenant from Permit", "error", err)
 		return nil, fmt.Errorf("failed to fetch tenant: %w", err)
 	}
 
-	attributes, err := helpers.GetMap(tenantResource, "attributes")
-	if err != nil {
-		logger.LogError("Invalid tenant data structure", "error", err)
-		return nil, fmt.Errorf("invalid tenant data structure: %s", err)
+	if tenantResource.Attributes == nil {
+		logger.LogError("Invalid tenant data structure", "error", "missing attributes")
+		return nil, fmt.Errorf("invalid tenant data structure: missing or invalid map for key: attributes")
 	}
-	return attributes, nil
+	return tenantResource.Attributes, nil
 }
 
 func (t *TenantMutationResolver) mergeTenantData(ctx context.Context, existing map[string]interface{}, input models.UpdateTenantInput) (map[string]interface{}, error) {
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	mocks "iam_services_main_v1/mocks"
 	"testing"
@@ -63,7 +65,7 @@ func TestCreateTenant(t *testing.T) {
 			ctx:       validCtx,
 			input:     invalidInput,
 			mockSetup: func() {},
-			output:    buildErrorResponse(400, "Invalid input data", "Invalid input data"),
+			output:    errorcatalog.Tenant.New(400, "Invalid input data", "Invalid input data"),
 		},
 		{
 			name:  "Missing user ID in context",
@@ -72,7 +74,7 @@ func TestCreateTenant(t *testing.T) {
 			mockSetup: func() {
 				// No mock calls expected
 			},
-			output: buildErrorResponse(400, "Failed to prepare metadata in create tenant", "error getting user ID from context"),
+			output: errorcatalog.Tenant.New(400, "Failed to prepare metadata in create tenant", "error getting user ID from context"),
 		},
 		{
 			name:  "Error creating tenant in permit",
@@ -80,10 +82,10 @@ func TestCreateTenant(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					CreateTenant(mock.Any(), mock.Any()).
 					Return(nil, errors.New("permit error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Failed to create tenant in permit system", "permit error"),
+			output: errorcatalog.Tenant.New(400, "Failed to create tenant in permit system", "permit error"),
 		},
 		{
 			name:  "Error creating resource instance",
@@ -91,14 +93,14 @@ func TestCreateTenant(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"key": validID.String()}, nil).MaxTimes(1)
+					CreateTenant(mock.Any(), mock.Any()).
+					Return(&permit.Tenant{Entity: permit.Entity{Key: validID.String()}}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					CreateResourceInstance(mock.Any(), mock.Any()).
 					Return(nil, errors.New("resource instance error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Failed to create resource instance in permit system", "resource instance error"),
+			output: errorcatalog.Tenant.New(400, "Failed to create resource instance in permit system", "resource instance error"),
 		},
 		{
 			name:  "Error getting created tenant",
@@ -106,18 +108,18 @@ func TestCreateTenant(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"key": validID.String()}, nil).MaxTimes(1)
+					CreateTenant(mock.Any(), mock.Any()).
+					Return(&permit.Tenant{Entity: permit.Entity{Key: validID.String()}}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"created": true}, nil).MaxTimes(1)
+					CreateResourceInstance(mock.Any(), mock.Any()).
+					Return(&permit.ResourceInstance{Entity: permit.Entity{Key: validID.String()}}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(nil, errors.New("get tenant error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Failed to fetch created tenant", "get tenant error"),
+			output: errorcatalog.Tenant.New(400, "Failed to fetch created tenant", "get tenant error"),
 		},
 		{
 			name:  "Success",
@@ -125,15 +127,15 @@ func TestCreateTenant(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"key": validID.String()}, nil).MaxTimes(1)
+					CreateTenant(mock.Any(), mock.Any()).
+					Return(&permit.Tenant{Entity: permit.Entity{Key: validID.String()}}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"created": true}, nil).MaxTimes(1)
+					CreateResourceInstance(mock.Any(), mock.Any()).
+					Return(&permit.ResourceInstance{Entity: permit.Entity{Key: validID.String()}}, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(tenant, nil).MaxTimes(1)
 			},
 			output: buildSuccessResponse(mappedTenant),
@@ -205,7 +207,7 @@ func TestUpdateTenant(t *testing.T) {
 			ctx:       validCtx,
 			input:     invalidInput,
 			mockSetup: func() {},
-			output:    buildErrorResponse(400, "Invalid input data", "Invalid input data"),
+			output:    errorcatalog.Tenant.New(400, "Invalid input data", "Invalid input data"),
 		},
 		{
 			name:  "Error getting existing tenant",
@@ -213,10 +215,10 @@ func TestUpdateTenant(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(nil, errors.New("get tenant error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Error getting existing tenant", "Error getting existing tenant"),
+			output: errorcatalog.Tenant.New(400, "Error getting existing tenant", "Error getting existing tenant"),
 		},
 		{
 			name:  "Error updating tenant",
@@ -224,14 +226,14 @@ func TestUpdateTenant(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(tenantData, nil).MaxTimes(1)
 
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					UpdateTenant(mock.Any(), mock.Any(), mock.Any()).
 					Return(nil, errors.New("update error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Error updating tenant", "Error updating tenant"),
+			output: errorcatalog.Tenant.New(400, "Error updating tenant", "Error updating tenant"),
 		},
 		{
 			name:  "Error getting updated tenant",
@@ -240,20 +242,20 @@ func TestUpdateTenant(t *testing.T) {
 			mockSetup: func() {
 				// First call for getExistingTenant
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(tenantData, nil).MaxTimes(1)
 
 				// Call for updating tenant
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"updated": true}, nil).MaxTimes(1)
+					UpdateTenant(mock.Any(), mock.Any(), mock.Any()).
+					Return(tenantData, nil).MaxTimes(1)
 
 				// Second call for getCreatedTenant
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(nil, errors.New("get updated tenant error")).MaxTimes(1)
 			},
-			output: buildErrorResponse(400, "Error getting updated tenant", "Error getting updated tenant"),
+			output: errorcatalog.Tenant.New(400, "Error getting updated tenant", "Error getting updated tenant"),
 		},
 		{
 			name:  "Success",
@@ -262,17 +264,17 @@ func TestUpdateTenant(t *testing.T) {
 			mockSetup: func() {
 				// First call for getExistingTenant
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(tenantData, nil).MaxTimes(1)
 
 				// Call for updating tenant
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
-					Return(map[string]interface{}{"updated": true}, nil)
+					UpdateTenant(mock.Any(), mock.Any(), mock.Any()).
+					Return(tenantData, nil)
 
 				// Second call for getCreatedTenant
 				mockService.EXPECT().
-					SendRequest(mock.Any(), mock.Any(), mock.Any(), mock.Any()).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(tenantData, nil).MaxTimes(1)
 			},
 			output: buildSuccessResponse(mappedTenant),
@@ -320,7 +322,7 @@ func TestDeleteTenant(t *testing.T) {
 			ctx:       validCtx,
 			input:     invalidInput,
 			mockSetup: func() {},
-			output:    buildErrorResponse(400, "Invalid ID", "Invalid ID"),
+			output:    errorcatalog.Tenant.New(400, "Invalid ID", "Invalid ID"),
 		},
 		{
 			name:  "Error deleting tenant",
@@ -328,10 +330,10 @@ func TestDeleteTenant(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "DELETE", mock.Any(), nil).
-					Return(nil, errors.New("delete error"))
+					DeleteTenant(mock.Any(), validID.String()).
+					Return(errors.New("delete error"))
 			},
-			output: buildErrorResponse(400, "Error deleting tenant", "Error deleting tenant"),
+			output: errorcatalog.Tenant.New(400, "Error deleting tenant", "Error deleting tenant"),
 		},
 		{
 			name:  "Success",
@@ -339,8 +341,8 @@ func TestDeleteTenant(t *testing.T) {
 			input: validInput,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "DELETE", mock.Any(), nil).
-					Return(map[string]interface{}{"deleted": true}, nil)
+					DeleteTenant(mock.Any(), validID.String()).
+					Return(nil)
 			},
 			output: buildSuccessResponse([]models.Data{}),
 		},
@@ -446,10 +448,6 @@ func buildSuccessResponse(data []models.Data) models.OperationResult {
 	return response
 }
 
-func buildErrorResponse(statusCode int, message string, details string) models.OperationResult {
-	return utils.FormatErrorResponse(statusCode, message, details)
-}
-
 func prepareValidInput() models.CreateTenantInput {
 	// Setup test input
 	description := "Test Description"
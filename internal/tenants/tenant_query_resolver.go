@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/filters"
 	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	"iam_services_main_v1/pkg/logger"
@@ -14,44 +17,43 @@ import (
 
 // TenantQueryResolver handles database queries and permission checks for Tenant-related operations using GORM and Permit.io client
 type TenantQueryResolver struct {
-	PC  permit.PermitService
-	PSC *permit.PermitSdkService
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
 }
 
-// Tenants retrieves all Tenant resources for a given tenant.
+// Tenants retrieves all Tenant resources matching filter, ordered by orderBy.
 // It performs the following operations:
-// 1. Extracts the tenant ID from the context
-// 2. Queries the permit service for Tenant resources associated with the tenant
-// 3. Maps the response data to Tenant structures
+// 1. Validates the filter
+// 2. Queries the permit service for Tenant resources, narrowed by name where Permit can
+// 3. Maps the response data to Tenant structures and applies the filter and ordering
 // 4. Returns formatted operation result containing the Tenants
 //
 // Parameters:
 //   - ctx: The context.Context for the request
+//   - filter: Optional filter the tenants must match
+//   - orderBy: Optional ordering of the tenants
 //
 // Returns:
 //   - models.OperationResult: Contains either the Tenants data or error details
 //   - error: Any error encountered during processing
-func (r *TenantQueryResolver) Tenants(ctx context.Context) (models.OperationResult, error) {
+func (r *TenantQueryResolver) Tenants(ctx context.Context, filter *models.TenantFilter, orderBy *models.OrganizationOrder) (models.OperationResult, error) {
 	logger.LogInfo("Fetching all tenants")
 
-	// Fetch tenants from permit system
-	resourceURL := "tenants?include_total_count=true"
-	tenantResources, err := r.PC.SendRequest(ctx, "GET", resourceURL, nil)
-	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get tenant resources from permit", err.Error()), nil
+	orgFilter := filters.ForTenants(filter)
+	if err := filters.Validate(orgFilter); err != nil {
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Invalid tenant filter", err), nil
 	}
 
-	// Map tenant data to Tenant struct
-	tenants, err := MapTenantsResponseToStruct(tenantResources)
-
+	// Fetch, filter and order tenants
+	tenants, totalCount, err := r.fetchTenants(ctx, orgFilter, orderBy)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to map tenant resources to struct", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to get tenant resources from permit", err), nil
 	}
 
 	// Format and return success response
-	successResponse, err := utils.FormatSuccess(tenants)
+	successResponse, err := utils.FormatSuccessWithTotal(tenants, totalCount)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to format success response", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to format success response", err), nil
 	}
 	return successResponse, nil
 
@@ -79,37 +81,96 @@ func (r *TenantQueryResolver) Tenant(ctx context.Context, id uuid.UUID) (models.
 	// Validate ID
 	if id == uuid.Nil {
 		err := fmt.Errorf("invalid tenant ID: %s", id)
-		return utils.FormatErrorResponse(http.StatusBadRequest, "invalid tenant ID", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "invalid tenant ID", err), nil
 	}
 
 	// Fetch tenant from permit system
 	tenantResource, err := r.FetchTenant(ctx, id)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to get tenant resources from permit", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to get tenant resources from permit", err), nil
 	}
 
 	//	Map tenant data to Tenant struct
 	tenant, err := MapTenantResponseToStruct(tenantResource)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to map tenant resources to struct", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to map tenant resources to struct", err), nil
 	}
 
 	// Format and return success response
 	successResponse, err := utils.FormatSuccess(tenant)
 	if err != nil {
-		return utils.FormatErrorResponse(http.StatusBadRequest, "Failed to format success response", err.Error()), nil
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to format success response", err), nil
 	}
 	return successResponse, nil
 }
 
-// fetchAccount retrieves account details by UUID from the resource instance.
-func (r *TenantQueryResolver) FetchTenant(ctx context.Context, id uuid.UUID) (map[string]interface{}, error) {
-	// Fetch account resources from permit
-	resourceURL := fmt.Sprintf("tenants/%s", id)
-	accountResource, err := r.PC.SendRequest(ctx, "GET", resourceURL, nil)
+// FetchTenant retrieves tenant details by UUID from Permit.
+func (r *TenantQueryResolver) FetchTenant(ctx context.Context, id uuid.UUID) (*permit.Tenant, error) {
+	return r.PC.GetTenant(ctx, id.String())
+}
+
+// fetchTenants reads the tenants matching filter from Permit, ordered by
+// orderBy. Permit narrows the listing by name where it can and the rest of the
+// filter is applied here, so a filtered list is counted here too.
+func (r *TenantQueryResolver) fetchTenants(ctx context.Context, filter *models.OrganizationFilter, orderBy *models.OrganizationOrder) ([]models.Data, int, error) {
+	var tenantResources []permit.Tenant
+	var totalCount int
+	var err error
+	if search := filters.Search(filter); search != "" {
+		tenantResources, totalCount, err = r.PC.SearchTenants(ctx, search)
+	} else {
+		tenantResources, totalCount, err = r.PC.ListTenants(ctx)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tenants, err := MapTenantsResponseToStruct(tenantResources)
+	if err != nil {
+		return nil, 0, err
+	}
+	tenants, err = filters.Apply(tenants, filter, orderBy)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter != nil {
+		totalCount = len(tenants)
+	}
+	return tenants, totalCount, nil
+}
+
+// TenantsConnection retrieves one page of the tenants matching filter, ordered
+// by orderBy. Without a filter or ordering the page is read from Permit's own
+// paging, so only the requested tenants are fetched. Otherwise the page is cut
+// from the filtered and ordered list.
+func (r *TenantQueryResolver) TenantsConnection(ctx context.Context, after *string, filter *models.TenantFilter, first *int, orderBy *models.OrganizationOrder) (models.OperationResult, error) {
+	logger.LogInfo("Fetching a page of tenants")
+
+	page, err := utils.ParsePage(first, after)
 	if err != nil {
-		return nil, err
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Invalid pagination arguments", err), nil
+	}
+
+	if filter != nil || orderBy != nil {
+		orgFilter := filters.ForTenants(filter)
+		if err := filters.Validate(orgFilter); err != nil {
+			return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Invalid tenant filter", err), nil
+		}
+		tenants, totalCount, err := r.fetchTenants(ctx, orgFilter, orderBy)
+		if err != nil {
+			return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to get tenant resources from permit", err), nil
+		}
+		return utils.FormatConnection(utils.PageOf(tenants, page), page, totalCount), nil
 	}
 
-	return accountResource, nil
+	tenantResources, totalCount, err := r.PC.ListTenantsPage(ctx, page)
+	if err != nil {
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to get tenant resources from permit", err), nil
+	}
+
+	tenants, err := MapTenantsResponseToStruct(tenantResources)
+	if err != nil {
+		return errorcatalog.Tenant.FromError(http.StatusBadRequest, "Failed to map tenant resources to struct", err), nil
+	}
+	return utils.FormatConnection(tenants, page, totalCount), nil
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
+	"iam_services_main_v1/internal/permit"
 	"iam_services_main_v1/internal/utils"
 	mocks "iam_services_main_v1/mocks"
 	"testing"
@@ -34,9 +36,9 @@ func TestTenant(t *testing.T) {
 	validID := uuid.New()
 
 	// Valid tenant data
-	tenantData := map[string]interface{}{
-		"key": validID.String(),
-		"attributes": map[string]interface{}{
+	tenantData := &permit.Tenant{Entity: permit.Entity{
+		Key: validID.String(),
+		Attributes: map[string]interface{}{
 			"name": "test tenant",
 			"contactInfo": map[string]interface{}{
 				"email":       "test@example.com",
@@ -50,7 +52,7 @@ func TestTenant(t *testing.T) {
 				},
 			},
 		},
-	}
+	}}
 
 	tests := []struct {
 		name    string
@@ -61,7 +63,7 @@ func TestTenant(t *testing.T) {
 			name: "Success",
 			setup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(tenantData, nil)
 			},
 			wantErr: false,
@@ -70,7 +72,7 @@ func TestTenant(t *testing.T) {
 			name: "Service error",
 			setup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(nil, assert.AnError)
 			},
 			wantErr: true,
@@ -95,26 +97,24 @@ func TestTenants(t *testing.T) {
 	resolver, mockService, ctx := setupTest(t)
 
 	// Valid tenants data
-	tenantsData := map[string]interface{}{
-		"data": []interface{}{
-			map[string]interface{}{
-				"key": uuid.New().String(),
-				"attributes": map[string]interface{}{
-					"name": "test tenant",
-					"contactInfo": map[string]interface{}{
-						"email":       "test@example.com",
-						"phoneNumber": "1234567890",
-						"address": map[string]interface{}{
-							"street":  "123 Test St",
-							"city":    "Test City",
-							"state":   "TS",
-							"zipcode": "12345",
-							"country": "Test Country",
-						},
+	tenantsData := []permit.Tenant{
+		{Entity: permit.Entity{
+			Key: uuid.New().String(),
+			Attributes: map[string]interface{}{
+				"name": "test tenant",
+				"contactInfo": map[string]interface{}{
+					"email":       "test@example.com",
+					"phoneNumber": "1234567890",
+					"address": map[string]interface{}{
+						"street":  "123 Test St",
+						"city":    "Test City",
+						"state":   "TS",
+						"zipcode": "12345",
+						"country": "Test Country",
 					},
 				},
 			},
-		},
+		}},
 	}
 
 	tests := []struct {
@@ -126,8 +126,8 @@ func TestTenants(t *testing.T) {
 			name: "Success",
 			setup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
-					Return(tenantsData, nil)
+					ListTenants(mock.Any()).
+					Return(tenantsData, len(tenantsData), nil)
 			},
 			wantErr: false,
 		},
@@ -135,8 +135,8 @@ func TestTenants(t *testing.T) {
 			name: "Service error",
 			setup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
-					Return(nil, assert.AnError)
+					ListTenants(mock.Any()).
+					Return(nil, 0, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -145,7 +145,7 @@ func TestTenants(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			result, err := resolver.Tenants(ctx)
+			result, err := resolver.Tenants(ctx, nil, nil)
 			if tt.wantErr {
 				assert.NotNil(t, result) // Error response should still be returned
 			} else {
@@ -156,6 +156,151 @@ func TestTenants(t *testing.T) {
 	}
 }
 
+func TestTenantsTotalCount(t *testing.T) {
+	resolver, mockService, ctx := setupTest(t)
+	tenants := []permit.Tenant{*buildTestTenantsData()}
+
+	mockService.EXPECT().
+		ListTenants(mock.Any()).
+		Return(tenants, 250, nil)
+
+	result, err := resolver.Tenants(ctx, nil, nil)
+
+	assert.NoError(t, err)
+	successResp, ok := result.(*models.SuccessResponse)
+	assert.True(t, ok)
+	assert.Len(t, successResp.Data, 1)
+	assert.Equal(t, 250, *successResp.TotalCount)
+}
+
+func TestTenantsFilter(t *testing.T) {
+	resolver, mockService, ctx := setupTest(t)
+	acme, globex := buildTestTenantsData(), buildTestTenantsData()
+	acme.Attributes["name"] = "Acme"
+	acme.Attributes["status"] = "SUSPANDED"
+	globex.Attributes["name"] = "Acme Globex"
+	nameContains, active := "acme", models.StatusTypeEnumActive
+
+	t.Run("Name is searched in permit and the rest filtered here", func(t *testing.T) {
+		mockService.EXPECT().
+			SearchTenants(mock.Any(), "acme").
+			Return([]permit.Tenant{*acme, *globex}, 2, nil)
+
+		result, err := resolver.Tenants(ctx, &models.TenantFilter{NameContains: &nameContains, Status: &active}, nil)
+
+		assert.NoError(t, err)
+		successResp, ok := result.(*models.SuccessResponse)
+		assert.True(t, ok)
+		assert.Len(t, successResp.Data, 1)
+		assert.Equal(t, "Acme Globex", successResp.Data[0].(*models.Tenant).Name)
+		assert.Equal(t, 1, *successResp.TotalCount)
+	})
+
+	t.Run("Ordered connection is cut from the full list", func(t *testing.T) {
+		mockService.EXPECT().
+			ListTenants(mock.Any()).
+			Return([]permit.Tenant{*globex, *acme}, 2, nil)
+		first := 1
+		order := &models.OrganizationOrder{Field: models.OrganizationOrderFieldEnumName, Direction: models.OrderDirectionEnumAsc}
+
+		result, err := resolver.TenantsConnection(ctx, nil, nil, &first, order)
+
+		assert.NoError(t, err)
+		conn, ok := result.(*models.Connection)
+		assert.True(t, ok)
+		assert.Equal(t, 2, conn.TotalCount)
+		assert.Equal(t, "Acme", conn.Edges[0].Node.(*models.Tenant).Name)
+		assert.True(t, conn.PageInfo.HasNextPage)
+	})
+
+	t.Run("Invalid time range is rejected before permit is called", func(t *testing.T) {
+		from := "last week"
+		result, err := resolver.Tenants(ctx, &models.TenantFilter{CreatedAt: &models.TimeRangeInput{From: &from}}, nil)
+
+		assert.NoError(t, err)
+		errResp, ok := result.(*models.ResponseError)
+		assert.True(t, ok)
+		assert.Equal(t, "IAM-TENANT-400", errResp.ErrorCode)
+	})
+}
+
+func TestTenantsConnection(t *testing.T) {
+	resolver, mockService, ctx := setupTest(t)
+	tenants := []permit.Tenant{*buildTestTenantsData(), *buildTestTenantsData()}
+	first, after := 2, utils.EncodeCursor(3)
+
+	t.Run("Reads the page from permit", func(t *testing.T) {
+		mockService.EXPECT().
+			ListTenantsPage(mock.Any(), permit.Page{Offset: 4, Limit: 2}).
+			Return(tenants, 10, nil)
+
+		result, err := resolver.TenantsConnection(ctx, &after, nil, &first, nil)
+
+		assert.NoError(t, err)
+		conn, ok := result.(*models.Connection)
+		assert.True(t, ok)
+		assert.Len(t, conn.Edges, 2)
+		assert.Equal(t, utils.EncodeCursor(4), conn.Edges[0].Cursor)
+		assert.Equal(t, 10, conn.TotalCount)
+		assert.True(t, conn.PageInfo.HasNextPage)
+		assert.True(t, conn.PageInfo.HasPreviousPage)
+	})
+
+	t.Run("Invalid arguments are rejected before permit is called", func(t *testing.T) {
+		tooMany := utils.MaxPageSize + 1
+		result, err := resolver.TenantsConnection(ctx, nil, nil, &tooMany, nil)
+
+		assert.NoError(t, err)
+		errResp, ok := result.(*models.ResponseError)
+		assert.True(t, ok)
+		assert.Equal(t, "IAM-TENANT-400", errResp.ErrorCode)
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		mockService.EXPECT().
+			ListTenantsPage(mock.Any(), permit.Page{Limit: utils.DefaultPageSize}).
+			Return(nil, 0, assert.AnError)
+
+		result, err := resolver.TenantsConnection(ctx, nil, nil, nil, nil)
+
+		assert.NoError(t, err)
+		_, ok := result.(*models.ResponseError)
+		assert.True(t, ok)
+	})
+}
+
+func TestTenantNotFoundVersusUnavailable(t *testing.T) {
+	resolver, mockService, ctx := setupTest(t)
+
+	testCases := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{name: "Tenant does not exist", err: &permit.PermitAPIError{StatusCode: 404}, wantCode: "IAM-TENANT-404", wantStatus: 404},
+		{name: "Permit is down", err: &permit.PermitAPIError{StatusCode: 500}, wantCode: "IAM-TENANT-503", wantStatus: 503, wantRetryable: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService.EXPECT().
+				GetTenant(mock.Any(), mock.Any()).
+				Return(nil, tc.err)
+
+			result, err := resolver.Tenant(ctx, uuid.New())
+
+			assert.NoError(t, err)
+			errorResp, ok := result.(*models.ResponseError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.wantCode, errorResp.ErrorCode)
+			assert.Equal(t, tc.wantStatus, errorResp.HTTPStatus)
+			assert.Equal(t, tc.wantRetryable, errorResp.Retryable)
+		})
+	}
+}
+
 func TestTenantQueryResolver_Tenant(t *testing.T) {
 	ctrl := mock.NewController(t)
 	defer ctrl.Finish()
@@ -186,7 +331,7 @@ func TestTenantQueryResolver_Tenant(t *testing.T) {
 			mockStubs: func(mockSvc mocks.MockPermitService) {
 				// No mock calls expected
 			},
-			output: utils.FormatErrorResponse(400, "invalid tenant ID", "invalid tenant ID: 00000000-0000-0000-0000-000000000000"),
+			output: errorcatalog.Tenant.New(400, "invalid tenant ID", "invalid tenant ID: 00000000-0000-0000-0000-000000000000"),
 		},
 		{
 			name:  "Test GetTenant when permit service returns error",
@@ -194,10 +339,10 @@ func TestTenantQueryResolver_Tenant(t *testing.T) {
 			ctx:   testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(nil, errors.New("permit service error"))
 			},
-			output: utils.FormatErrorResponse(400, "Failed to get tenant resources from permit", "permit service error"),
+			output: errorcatalog.Tenant.New(400, "Failed to get tenant resources from permit", "permit service error"),
 		},
 		{
 			name:  "Test GetTenant success",
@@ -205,7 +350,7 @@ func TestTenantQueryResolver_Tenant(t *testing.T) {
 			ctx:   testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(tenant, nil)
 			},
 			output: func() models.OperationResult {
@@ -238,9 +383,7 @@ func TestTenantQueryResolver_Tenants(t *testing.T) {
 	testCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
 
 	tenant := buildTestTenantsData()
-	tenants := map[string]interface{}{
-		"data": []interface{}{tenant},
-	}
+	tenants := []permit.Tenant{*tenant}
 	mappedTenants, _ := MapTenantsResponseToStruct(tenants)
 
 	testcases := []struct {
@@ -254,18 +397,18 @@ func TestTenantQueryResolver_Tenants(t *testing.T) {
 			ctx:  testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
-					Return(nil, errors.New("permit service error"))
+					ListTenants(mock.Any()).
+					Return(nil, 0, errors.New("permit service error"))
 			},
-			output: utils.FormatErrorResponse(400, "Failed to get tenant resources from permit", "permit service error"),
+			output: errorcatalog.Tenant.New(400, "Failed to get tenant resources from permit", "permit service error"),
 		},
 		{
 			name: "Test GetTenants success",
 			ctx:  testCtx,
 			mockStubs: func(mockSvc mocks.MockPermitService) {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
-					Return(tenants, nil)
+					ListTenants(mock.Any()).
+					Return(tenants, len(tenants), nil)
 			},
 			output: func() models.OperationResult {
 				result, _ := utils.FormatSuccessResponse(mappedTenants)
@@ -277,7 +420,7 @@ func TestTenantQueryResolver_Tenants(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockStubs(*mockService)
-			result, _ := objUnderTest.Tenants(tc.ctx)
+			result, _ := objUnderTest.Tenants(tc.ctx, nil, nil)
 			assert.NotNil(t, result)
 		})
 	}
@@ -307,7 +450,7 @@ func TestTenantQueryResolver_FetchTenant(t *testing.T) {
 			id:   testID,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(testTenant, nil)
 			},
 			wantErr: false,
@@ -318,7 +461,7 @@ func TestTenantQueryResolver_FetchTenant(t *testing.T) {
 			id:   testID,
 			mockSetup: func() {
 				mockService.EXPECT().
-					SendRequest(mock.Any(), "GET", mock.Any(), nil).
+					GetTenant(mock.Any(), mock.Any()).
 					Return(nil, errors.New("service error"))
 			},
 			wantErr: true,
@@ -346,13 +489,13 @@ func createTestContext() context.Context {
 	return context.WithValue(context.Background(), config.GinContextKey, ginCtx)
 }
 
-func buildTestTenantsData() map[string]interface{} {
+func buildTestTenantsData() *permit.Tenant {
 	id := uuid.New()
-	return map[string]interface{}{
-		"key":        id.String(),
-		"created_at": time.Now().String(),
-		"updated_at": time.Now().String(),
-		"attributes": map[string]interface{}{
+	return &permit.Tenant{Entity: permit.Entity{
+		Key:       id.String(),
+		CreatedAt: time.Now().String(),
+		UpdatedAt: time.Now().String(),
+		Attributes: map[string]interface{}{
 			"name":        "test-tenant",
 			"description": "Test Tenant",
 			"createdBy":   uuid.New().String(),
@@ -369,5 +512,5 @@ func buildTestTenantsData() map[string]interface{} {
 				},
 			},
 		},
-	}
+	}}
 }
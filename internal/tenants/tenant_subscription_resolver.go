@@ -0,0 +1,31 @@
+package tenants
+
+import (
+	"context"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/events"
+	"iam_services_main_v1/pkg/logger"
+)
+
+// TenantSubscriptionResolver streams changes to the caller's tenant
+type TenantSubscriptionResolver struct {
+	Events     *events.Bus
+	Authorizer authz.Authorizer
+}
+
+// TenantChanged returns the changes made to the caller's tenant from now on,
+// provided the caller may read it.
+func (r *TenantSubscriptionResolver) TenantChanged(ctx context.Context) (<-chan *models.TenantChangeEvent, error) {
+	tenantID, _, err := events.Authorize(ctx, r.Events, r.Authorizer, authz.PermissionCheck{
+		Action:       "tenant",
+		ResourceType: config.TenantResourceTypeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.LogInfo("Subscribed to tenant changes", "tenantId", tenantID)
+	return events.Stream[*models.TenantChangeEvent](ctx, r.Events, events.TopicTenant, tenantID.String(), nil), nil
+}
@@ -0,0 +1,84 @@
+package tenants
+
+import (
+	"context"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/events"
+	mocks "iam_services_main_v1/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantChanged(t *testing.T) {
+	tenantID := uuid.New()
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantID.String())
+	ginCtx.Set("userID", uuid.New().String())
+	validCtx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+
+	t.Run("Streams changes to the caller's tenant", func(t *testing.T) {
+		bus := events.NewBus(events.DefaultBuffer)
+		ctx, cancel := context.WithCancel(validCtx)
+		defer cancel()
+		resolver := TenantSubscriptionResolver{Events: bus, Authorizer: authz.AllowAll()}
+
+		ch, err := resolver.TenantChanged(ctx)
+		assert.NoError(t, err)
+
+		bus.TenantChanged(models.ChangeTypeEnumUpdated, uuid.New(), nil)
+		bus.TenantChanged(models.ChangeTypeEnumDeleted, tenantID, nil)
+		select {
+		case event := <-ch:
+			assert.Equal(t, tenantID, event.TenantID)
+			assert.Equal(t, models.ChangeTypeEnumDeleted, event.Change)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the tenant change")
+		}
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		resolver := TenantSubscriptionResolver{Events: events.NewBus(events.DefaultBuffer), Authorizer: authz.DenyAll()}
+		_, err := resolver.TenantChanged(validCtx)
+		assert.ErrorIs(t, err, events.ErrForbidden)
+	})
+
+	t.Run("Without a bus", func(t *testing.T) {
+		resolver := TenantSubscriptionResolver{Authorizer: authz.AllowAll()}
+		_, err := resolver.TenantChanged(validCtx)
+		assert.ErrorIs(t, err, events.ErrUnavailable)
+	})
+}
+
+func TestDeleteTenantPublishesChange(t *testing.T) {
+	ctrl := mock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPermitService(ctrl)
+	bus := events.NewBus(events.DefaultBuffer)
+	resolver := TenantMutationResolver{PC: mockService, Events: bus}
+
+	tenantID := uuid.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := events.Stream[*models.TenantChangeEvent](ctx, bus, events.TopicTenant, tenantID.String(), nil)
+
+	mockService.EXPECT().DeleteTenant(mock.Any(), tenantID.String()).Return(nil)
+	_, err := resolver.DeleteTenant(ctx, models.DeleteInput{ID: tenantID})
+	assert.NoError(t, err)
+
+	select {
+	case event := <-ch:
+		assert.Equal(t, models.ChangeTypeEnumDeleted, event.Change)
+		assert.Equal(t, tenantID, event.TenantID)
+		assert.Nil(t, event.Tenant)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the tenant change")
+	}
+}
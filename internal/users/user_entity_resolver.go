@@ -0,0 +1,48 @@
+package users
+
+import (
+	"context"
+
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/dataloaders"
+	"iam_services_main_v1/internal/federation"
+	"iam_services_main_v1/internal/permit"
+)
+
+// UserEntityResolver resolves the users other subgraphs reference.
+type UserEntityResolver struct {
+	PC         permit.PermitService
+	Authorizer authz.Authorizer
+}
+
+// FindManyUserByIDs resolves a batch of user references, in order. Users are
+// only exposed as the principals of bindings, so it needs the binding action.
+// References to users not associated with the caller's tenant resolve to null.
+func (r *UserEntityResolver) FindManyUserByIDs(ctx context.Context, reps []*models.UserByIDsInput) ([]*models.User, error) {
+	tenantID, err := federation.Authorize(ctx, r.Authorizer, "binding", config.BindingResourceTypeID)
+	if err != nil {
+		return nil, err
+	}
+	loaders := dataloaders.For(ctx, r.PC)
+	return federation.Resolve(ctx, reps, func(ctx context.Context, rep *models.UserByIDsInput) (*models.User, error) {
+		user, err := loaders.Users.Load(ctx, rep.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if !associated(user, tenantID.String()) {
+			return nil, nil
+		}
+		return mapUser(user)
+	})
+}
+
+func associated(user *permit.User, tenant string) bool {
+	for _, t := range user.AssociatedTenants {
+		if t.Tenant == tenant {
+			return true
+		}
+	}
+	return false
+}
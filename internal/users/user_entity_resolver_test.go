@@ -0,0 +1,59 @@
+package users
+
+import (
+	"context"
+	"iam_services_main_v1/config"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/authz"
+	"iam_services_main_v1/internal/federation"
+	"iam_services_main_v1/internal/permit"
+	mocks "iam_services_main_v1/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindManyUserByIDs(t *testing.T) {
+	tenantID := uuid.New()
+	ginCtx := &gin.Context{}
+	ginCtx.Set("tenantID", tenantID.String())
+	ginCtx.Set("userID", uuid.New().String())
+	ctx := context.WithValue(context.Background(), config.GinContextKey, ginCtx)
+
+	t.Run("Resolves the users of the caller's tenant", func(t *testing.T) {
+		ctrl := mock.NewController(t)
+		defer ctrl.Finish()
+		member, outsider := uuid.New(), uuid.New()
+		mockPC := mocks.NewMockPermitService(ctrl)
+		mockPC.EXPECT().GetUser(mock.Any(), member.String()).Return(&permit.User{
+			Entity:            permit.Entity{Key: member.String()},
+			FirstName:         "Ada",
+			LastName:          "Lovelace",
+			AssociatedTenants: []permit.UserTenant{{Tenant: tenantID.String()}},
+		}, nil)
+		mockPC.EXPECT().GetUser(mock.Any(), outsider.String()).Return(&permit.User{
+			Entity:            permit.Entity{Key: outsider.String()},
+			AssociatedTenants: []permit.UserTenant{{Tenant: uuid.NewString()}},
+		}, nil)
+
+		resolver := UserEntityResolver{PC: mockPC, Authorizer: authz.AllowAll()}
+		users, err := resolver.FindManyUserByIDs(ctx, []*models.UserByIDsInput{{ID: member}, {ID: outsider}})
+		assert.NoError(t, err)
+		if assert.Len(t, users, 2) {
+			assert.Equal(t, "Ada Lovelace", users[0].Name)
+			assert.Nil(t, users[1], "users of other tenants must not resolve")
+		}
+	})
+
+	t.Run("Needs the binding action", func(t *testing.T) {
+		ctrl := mock.NewController(t)
+		defer ctrl.Finish()
+		policy := &authz.Policy{Rules: []authz.Rule{{Actions: []string{"tenant"}}}}
+		resolver := UserEntityResolver{PC: mocks.NewMockPermitService(ctrl), Authorizer: policy}
+		_, err := resolver.FindManyUserByIDs(ctx, []*models.UserByIDsInput{{ID: uuid.New()}})
+		assert.ErrorIs(t, err, federation.ErrForbidden)
+	})
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"iam_services_main_v1/gql/models"
-	"iam_services_main_v1/helpers"
+	"iam_services_main_v1/internal/dataloaders"
 	"iam_services_main_v1/internal/permit"
 
 	"github.com/google/uuid"
@@ -16,27 +16,29 @@ type UserResolver struct {
 }
 
 // GetUser retrieves a user by their UUID from the resource provider.
-// It constructs the endpoint URL using the user ID, makes a GET request,
-// and maps the response to a models.User object.
+// It fetches the user from Permit and maps the response to a models.User object.
 // If the request fails, it returns the error.
 func (r *UserResolver) GetUser(ctx context.Context, id uuid.UUID) (*models.User, error) {
-	url := fmt.Sprintf("users/%s", id.String())
-	user, err := r.PC.GetSingleResource(ctx, "GET", url)
+	user, err := dataloaders.For(ctx, r.PC).Users.Load(ctx, id.String())
 	if err != nil {
 		return nil, err
 	}
-	firstName := helpers.GetString(user, "first_name")
-	lastName := helpers.GetString(user, "last_name")
-	email := helpers.GetString(user, "email")
-	createdAt := helpers.GetString(user, "created_at")
-	updatedAt := helpers.GetString(user, "updated_at")
+	return mapUser(user)
+}
+
+// mapUser maps a Permit user to a User model.
+func mapUser(user *permit.User) (*models.User, error) {
+	userID, err := uuid.Parse(user.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user key %q: %w", user.Key, err)
+	}
 	return &models.User{
-		ID:        uuid.MustParse(user["key"].(string)),
-		FirstName: firstName,
-		LastName:  lastName,
-		Name:      firstName + " " + lastName,
-		Email:     email,
-		UpdatedAt: updatedAt,
-		CreatedAt: createdAt,
+		ID:        userID,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Name:      user.FirstName + " " + user.LastName,
+		Email:     user.Email,
+		UpdatedAt: user.UpdatedAt,
+		CreatedAt: user.CreatedAt,
 	}, nil
 }
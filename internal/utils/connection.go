@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultPageSize is the number of items of a connection page when first is not given.
+	DefaultPageSize = 20
+	// MaxPageSize is the largest first a connection accepts.
+	MaxPageSize = 100
+
+	cursorPrefix = "offset:"
+)
+
+// ErrInvalidPageArgs is returned for a negative or too large first, or a cursor
+// that was not issued by this service.
+var ErrInvalidPageArgs = errors.New("invalid pagination arguments")
+
+// EncodeCursor returns the opaque cursor of the item at offset in a list.
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// decodeCursor returns the offset of the item a cursor points at.
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, fmt.Errorf("%w: malformed cursor %q", ErrInvalidPageArgs, cursor)
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("%w: malformed cursor %q", ErrInvalidPageArgs, cursor)
+	}
+	return offset, nil
+}
+
+// ParsePage converts the first and after arguments of a connection field into
+// the window of the list to fetch.
+func ParsePage(first *int, after *string) (permit.Page, error) {
+	page := permit.Page{Limit: DefaultPageSize}
+	if first != nil {
+		if *first < 0 || *first > MaxPageSize {
+			return permit.Page{}, fmt.Errorf("%w: first must be between 0 and %d", ErrInvalidPageArgs, MaxPageSize)
+		}
+		page.Limit = *first
+	}
+	if after != nil && *after != "" {
+		offset, err := decodeCursor(*after)
+		if err != nil {
+			return permit.Page{}, err
+		}
+		page.Offset = offset + 1
+	}
+	return page, nil
+}
+
+// PageOf returns the items of a list held in memory that fall in page.
+func PageOf[T any](items []T, page permit.Page) []T {
+	if page.Offset >= len(items) {
+		return nil
+	}
+	end := min(page.Offset+page.Limit, len(items))
+	return items[page.Offset:end]
+}
+
+// FormatConnection formats one page of a list in the `OperationResult` union.
+// data holds the items of page and totalCount the length of the whole list.
+func FormatConnection(data []models.Data, page permit.Page, totalCount int) models.OperationResult {
+	edges := make([]*models.Edge, 0, len(data))
+	for i, node := range data {
+		edges = append(edges, &models.Edge{Cursor: EncodeCursor(page.Offset + i), Node: node})
+	}
+	pageInfo := &models.PageInfo{
+		HasNextPage:     page.Offset+len(data) < totalCount,
+		HasPreviousPage: page.Offset > 0,
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = &edges[0].Cursor
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+	return &models.Connection{
+		IsSuccess:  true,
+		Message:    "Operation successful",
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: totalCount,
+	}
+}
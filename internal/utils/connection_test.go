@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"testing"
+
+	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/permit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePage(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	strPtr := func(v string) *string { return &v }
+
+	testcases := []struct {
+		name    string
+		first   *int
+		after   *string
+		want    permit.Page
+		wantErr bool
+	}{
+		{name: "Defaults", want: permit.Page{Limit: DefaultPageSize}},
+		{name: "First", first: intPtr(5), want: permit.Page{Limit: 5}},
+		{name: "After", first: intPtr(5), after: strPtr(EncodeCursor(9)), want: permit.Page{Offset: 10, Limit: 5}},
+		{name: "Empty after", after: strPtr(""), want: permit.Page{Limit: DefaultPageSize}},
+		{name: "Negative first", first: intPtr(-1), wantErr: true},
+		{name: "First above maximum", first: intPtr(MaxPageSize + 1), wantErr: true},
+		{name: "Malformed cursor", after: strPtr("not-a-cursor"), wantErr: true},
+		{name: "Foreign cursor", after: strPtr("b2Zmc2V0OmFiYw"), wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := ParsePage(tc.first, tc.after)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPageArgs)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, page)
+		})
+	}
+}
+
+func TestPageOf(t *testing.T) {
+	items := []int{0, 1, 2, 3, 4}
+
+	assert.Equal(t, []int{1, 2}, PageOf(items, permit.Page{Offset: 1, Limit: 2}))
+	assert.Equal(t, []int{3, 4}, PageOf(items, permit.Page{Offset: 3, Limit: 10}))
+	assert.Empty(t, PageOf(items, permit.Page{Offset: 5, Limit: 2}))
+}
+
+func TestFormatConnection(t *testing.T) {
+	t.Run("Middle page", func(t *testing.T) {
+		data := append(createTestData(), createTestData()...)
+		res := FormatConnection(data, permit.Page{Offset: 2, Limit: 2}, 6)
+
+		conn, ok := res.(*models.Connection)
+		assert.True(t, ok)
+		assert.True(t, conn.IsSuccess)
+		assert.Equal(t, 6, conn.TotalCount)
+		assert.Len(t, conn.Edges, 2)
+		assert.Equal(t, EncodeCursor(2), conn.Edges[0].Cursor)
+		assert.Equal(t, EncodeCursor(3), conn.Edges[1].Cursor)
+		assert.Equal(t, &models.PageInfo{
+			HasNextPage:     true,
+			HasPreviousPage: true,
+			StartCursor:     &conn.Edges[0].Cursor,
+			EndCursor:       &conn.Edges[1].Cursor,
+		}, conn.PageInfo)
+
+		next, err := ParsePage(nil, conn.PageInfo.EndCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, next.Offset, "the end cursor continues after the page")
+	})
+
+	t.Run("Past the end", func(t *testing.T) {
+		res := FormatConnection(nil, permit.Page{Offset: 10, Limit: 2}, 6)
+
+		conn := res.(*models.Connection)
+		assert.NotNil(t, conn.Edges)
+		assert.Empty(t, conn.Edges)
+		assert.False(t, conn.PageInfo.HasNextPage)
+		assert.Nil(t, conn.PageInfo.StartCursor)
+		assert.Nil(t, conn.PageInfo.EndCursor)
+	})
+}
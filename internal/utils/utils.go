@@ -2,8 +2,8 @@ package utils
 
 import (
 	"fmt"
-	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
+	"iam_services_main_v1/internal/errorcatalog"
 	"iam_services_main_v1/pkg/logger"
 	"net/http"
 )
@@ -30,23 +30,18 @@ func FormatSuccessResponse(data []models.Data) (models.OperationResult, error) {
 	successResponse, err := FormatSuccess(data)
 	if err != nil {
 		logger.LogError("Failed to format success response", "error", err)
-		return FormatErrorResponse(http.StatusBadRequest, "Failed to format success response", err.Error()), nil
+		return errorcatalog.Common.New(http.StatusBadRequest, "Failed to format success response", err.Error()), nil
 	}
 	return successResponse, nil
 }
 
-// FormatErrorResponse formats an error response in the `OperationResult` union
-func FormatErrorResponse(errorCode int, message, errDetails string) models.OperationResult {
-	var details *string
-	if errDetails != "" {
-		details = &errDetails
-	}
-	logger.LogError(message, "status", fmt.Sprint(errorCode), "error", errDetails)
-	return &models.ResponseError{
-		IsSuccess:     false,
-		Message:       config.GenericErrorMessage,
-		SystemMessage: message,
-		ErrorCode:     fmt.Sprint(errorCode),
-		ErrorDetails:  details,
-	}
+// FormatSuccessWithTotal formats a successful list response in the `OperationResult` union,
+// carrying the total number of items reported by Permit.
+func FormatSuccessWithTotal(data []models.Data, totalCount int) (models.OperationResult, error) {
+	return &models.SuccessResponse{
+		IsSuccess:  true,
+		Message:    "Operation successful",
+		Data:       data,
+		TotalCount: &totalCount,
+	}, nil
 }
@@ -1,10 +1,8 @@
 package utils
 
 import (
-	"fmt"
 	"testing"
 
-	"iam_services_main_v1/config"
 	"iam_services_main_v1/gql/models"
 
 	"github.com/google/uuid"
@@ -92,50 +90,6 @@ func TestFormatSuccess(t *testing.T) {
 	}
 }
 
-func TestFormatErrorResponse(t *testing.T) {
-	testcases := []struct {
-		name        string
-		code        int
-		msg         string
-		details     string
-		wantMsg     string
-		wantDetails *string
-	}{
-		{
-			name:        "Basic error",
-			code:        400,
-			msg:         "Test error",
-			details:     "Error details",
-			wantMsg:     config.GenericErrorMessage,
-			wantDetails: ptr("Error details"),
-		},
-		{
-			name:        "Empty details",
-			code:        500,
-			msg:         "Server error",
-			details:     "",
-			wantMsg:     config.GenericErrorMessage,
-			wantDetails: nil,
-		},
-	}
-
-	for _, tc := range testcases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := FormatErrorResponse(tc.code, tc.msg, tc.details)
-			assert.NotNil(t, result)
-
-			errorResp, ok := result.(*models.ResponseError)
-			assert.True(t, ok)
-
-			assert.Equal(t, tc.wantMsg, errorResp.Message)
-			assert.Equal(t, tc.msg, errorResp.SystemMessage)
-			assert.Equal(t, fmt.Sprint(tc.code), errorResp.ErrorCode)
-			assert.Equal(t, tc.wantDetails, errorResp.ErrorDetails)
-			assert.False(t, errorResp.IsSuccess)
-		})
-	}
-}
-
 func TestFormatSuccessResponse(t *testing.T) {
 	testcases := []struct {
 		name     string
@@ -180,7 +134,16 @@ func TestFormatSuccessResponse(t *testing.T) {
 	}
 }
 
-// Helper function to create string pointer
-func ptr(s string) *string {
-	return &s
+func TestFormatSuccessWithTotal(t *testing.T) {
+	data := createTestData()
+
+	result, err := FormatSuccessWithTotal(data, 42)
+
+	assert.NoError(t, err)
+	successResp, ok := result.(*models.SuccessResponse)
+	assert.True(t, ok)
+	assert.True(t, successResp.IsSuccess)
+	assert.Equal(t, data, successResp.Data)
+	assert.NotNil(t, successResp.TotalCount)
+	assert.Equal(t, 42, *successResp.TotalCount)
 }
@@ -1,11 +1,12 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: C:\Users\MSamsan1\projects\test_framework\mcp_iam\internal\permit\permit_service.go
+// Source: internal/permit/permit_service.go
 
 // Package mocks is a generated GoMock package.
 package mocks
 
 import (
 	context "context"
+	permit "iam_services_main_v1/internal/permit"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -34,62 +35,458 @@ func (m *MockPermitService) EXPECT() *MockPermitServiceMockRecorder {
 	return m.recorder
 }
 
-// APIExecute mocks base method.
-func (m *MockPermitService) APIExecute(ctx context.Context, method, endpoint string, payload interface{}) (interface{}, error) {
+// AssignRole mocks base method.
+func (m *MockPermitService) AssignRole(ctx context.Context, input permit.RoleAssignmentCreate) (*permit.RoleAssignment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "APIExecute", ctx, method, endpoint, payload)
-	ret0, _ := ret[0].(interface{})
+	ret := m.ctrl.Call(m, "AssignRole", ctx, input)
+	ret0, _ := ret[0].(*permit.RoleAssignment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// APIExecute indicates an expected call of APIExecute.
-func (mr *MockPermitServiceMockRecorder) APIExecute(ctx, method, endpoint, payload interface{}) *gomock.Call {
+// AssignRole indicates an expected call of AssignRole.
+func (mr *MockPermitServiceMockRecorder) AssignRole(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIExecute", reflect.TypeOf((*MockPermitService)(nil).APIExecute), ctx, method, endpoint, payload)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockPermitService)(nil).AssignRole), ctx, input)
 }
 
-// ExecuteGetAPI mocks base method.
-func (m *MockPermitService) ExecuteGetAPI(ctx context.Context, method, endpoint string) ([]map[string]interface{}, error) {
+// CreateRelationshipTuple mocks base method.
+func (m *MockPermitService) CreateRelationshipTuple(ctx context.Context, input permit.RelationshipTupleCreate) (*permit.RelationshipTuple, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExecuteGetAPI", ctx, method, endpoint)
-	ret0, _ := ret[0].([]map[string]interface{})
+	ret := m.ctrl.Call(m, "CreateRelationshipTuple", ctx, input)
+	ret0, _ := ret[0].(*permit.RelationshipTuple)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ExecuteGetAPI indicates an expected call of ExecuteGetAPI.
-func (mr *MockPermitServiceMockRecorder) ExecuteGetAPI(ctx, method, endpoint interface{}) *gomock.Call {
+// CreateRelationshipTuple indicates an expected call of CreateRelationshipTuple.
+func (mr *MockPermitServiceMockRecorder) CreateRelationshipTuple(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteGetAPI", reflect.TypeOf((*MockPermitService)(nil).ExecuteGetAPI), ctx, method, endpoint)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRelationshipTuple", reflect.TypeOf((*MockPermitService)(nil).CreateRelationshipTuple), ctx, input)
 }
 
-// GetSingleResource mocks base method.
-func (m *MockPermitService) GetSingleResource(ctx context.Context, method, endpoint string) (map[string]interface{}, error) {
+// CreateResource mocks base method.
+func (m *MockPermitService) CreateResource(ctx context.Context, input permit.ResourceCreate) (*permit.Resource, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSingleResource", ctx, method, endpoint)
-	ret0, _ := ret[0].(map[string]interface{})
+	ret := m.ctrl.Call(m, "CreateResource", ctx, input)
+	ret0, _ := ret[0].(*permit.Resource)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSingleResource indicates an expected call of GetSingleResource.
-func (mr *MockPermitServiceMockRecorder) GetSingleResource(ctx, method, endpoint interface{}) *gomock.Call {
+// CreateResource indicates an expected call of CreateResource.
+func (mr *MockPermitServiceMockRecorder) CreateResource(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSingleResource", reflect.TypeOf((*MockPermitService)(nil).GetSingleResource), ctx, method, endpoint)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResource", reflect.TypeOf((*MockPermitService)(nil).CreateResource), ctx, input)
 }
 
-// SendRequest mocks base method.
-func (m *MockPermitService) SendRequest(ctx context.Context, method, endpoint string, payload interface{}) (map[string]interface{}, error) {
+// CreateResourceAction mocks base method.
+func (m *MockPermitService) CreateResourceAction(ctx context.Context, resourceKey string, input permit.ActionCreate) (*permit.Action, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendRequest", ctx, method, endpoint, payload)
-	ret0, _ := ret[0].(map[string]interface{})
+	ret := m.ctrl.Call(m, "CreateResourceAction", ctx, resourceKey, input)
+	ret0, _ := ret[0].(*permit.Action)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SendRequest indicates an expected call of SendRequest.
-func (mr *MockPermitServiceMockRecorder) SendRequest(ctx, method, endpoint, payload interface{}) *gomock.Call {
+// CreateResourceAction indicates an expected call of CreateResourceAction.
+func (mr *MockPermitServiceMockRecorder) CreateResourceAction(ctx, resourceKey, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRequest", reflect.TypeOf((*MockPermitService)(nil).SendRequest), ctx, method, endpoint, payload)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResourceAction", reflect.TypeOf((*MockPermitService)(nil).CreateResourceAction), ctx, resourceKey, input)
+}
+
+// CreateResourceInstance mocks base method.
+func (m *MockPermitService) CreateResourceInstance(ctx context.Context, input permit.ResourceInstanceCreate) (*permit.ResourceInstance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResourceInstance", ctx, input)
+	ret0, _ := ret[0].(*permit.ResourceInstance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateResourceInstance indicates an expected call of CreateResourceInstance.
+func (mr *MockPermitServiceMockRecorder) CreateResourceInstance(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResourceInstance", reflect.TypeOf((*MockPermitService)(nil).CreateResourceInstance), ctx, input)
+}
+
+// CreateResourceRelation mocks base method.
+func (m *MockPermitService) CreateResourceRelation(ctx context.Context, resourceKey string, input permit.RelationCreate) (*permit.Relation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResourceRelation", ctx, resourceKey, input)
+	ret0, _ := ret[0].(*permit.Relation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateResourceRelation indicates an expected call of CreateResourceRelation.
+func (mr *MockPermitServiceMockRecorder) CreateResourceRelation(ctx, resourceKey, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResourceRelation", reflect.TypeOf((*MockPermitService)(nil).CreateResourceRelation), ctx, resourceKey, input)
+}
+
+// CreateResourceRole mocks base method.
+func (m *MockPermitService) CreateResourceRole(ctx context.Context, resourceKey string, input permit.RoleCreate) (*permit.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResourceRole", ctx, resourceKey, input)
+	ret0, _ := ret[0].(*permit.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateResourceRole indicates an expected call of CreateResourceRole.
+func (mr *MockPermitServiceMockRecorder) CreateResourceRole(ctx, resourceKey, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResourceRole", reflect.TypeOf((*MockPermitService)(nil).CreateResourceRole), ctx, resourceKey, input)
+}
+
+// CreateTenant mocks base method.
+func (m *MockPermitService) CreateTenant(ctx context.Context, input permit.TenantCreate) (*permit.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTenant", ctx, input)
+	ret0, _ := ret[0].(*permit.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTenant indicates an expected call of CreateTenant.
+func (mr *MockPermitServiceMockRecorder) CreateTenant(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTenant", reflect.TypeOf((*MockPermitService)(nil).CreateTenant), ctx, input)
+}
+
+// DeleteResourceInstance mocks base method.
+func (m *MockPermitService) DeleteResourceInstance(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteResourceInstance", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteResourceInstance indicates an expected call of DeleteResourceInstance.
+func (mr *MockPermitServiceMockRecorder) DeleteResourceInstance(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResourceInstance", reflect.TypeOf((*MockPermitService)(nil).DeleteResourceInstance), ctx, key)
+}
+
+// DeleteResourceRole mocks base method.
+func (m *MockPermitService) DeleteResourceRole(ctx context.Context, resourceKey, roleKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteResourceRole", ctx, resourceKey, roleKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteResourceRole indicates an expected call of DeleteResourceRole.
+func (mr *MockPermitServiceMockRecorder) DeleteResourceRole(ctx, resourceKey, roleKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResourceRole", reflect.TypeOf((*MockPermitService)(nil).DeleteResourceRole), ctx, resourceKey, roleKey)
+}
+
+// DeleteTenant mocks base method.
+func (m *MockPermitService) DeleteTenant(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTenant", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTenant indicates an expected call of DeleteTenant.
+func (mr *MockPermitServiceMockRecorder) DeleteTenant(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTenant", reflect.TypeOf((*MockPermitService)(nil).DeleteTenant), ctx, key)
+}
+
+// GetResource mocks base method.
+func (m *MockPermitService) GetResource(ctx context.Context, key string) (*permit.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResource", ctx, key)
+	ret0, _ := ret[0].(*permit.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResource indicates an expected call of GetResource.
+func (mr *MockPermitServiceMockRecorder) GetResource(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResource", reflect.TypeOf((*MockPermitService)(nil).GetResource), ctx, key)
+}
+
+// GetResourceInstance mocks base method.
+func (m *MockPermitService) GetResourceInstance(ctx context.Context, key string) (*permit.ResourceInstance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceInstance", ctx, key)
+	ret0, _ := ret[0].(*permit.ResourceInstance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceInstance indicates an expected call of GetResourceInstance.
+func (mr *MockPermitServiceMockRecorder) GetResourceInstance(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceInstance", reflect.TypeOf((*MockPermitService)(nil).GetResourceInstance), ctx, key)
+}
+
+// GetTenant mocks base method.
+func (m *MockPermitService) GetTenant(ctx context.Context, key string) (*permit.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenant", ctx, key)
+	ret0, _ := ret[0].(*permit.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenant indicates an expected call of GetTenant.
+func (mr *MockPermitServiceMockRecorder) GetTenant(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenant", reflect.TypeOf((*MockPermitService)(nil).GetTenant), ctx, key)
+}
+
+// GetUser mocks base method.
+func (m *MockPermitService) GetUser(ctx context.Context, key string) (*permit.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, key)
+	ret0, _ := ret[0].(*permit.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockPermitServiceMockRecorder) GetUser(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockPermitService)(nil).GetUser), ctx, key)
+}
+
+// ListRelationshipTuples mocks base method.
+func (m *MockPermitService) ListRelationshipTuples(ctx context.Context, filter permit.RelationshipTupleFilter) ([]permit.RelationshipTuple, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRelationshipTuples", ctx, filter)
+	ret0, _ := ret[0].([]permit.RelationshipTuple)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRelationshipTuples indicates an expected call of ListRelationshipTuples.
+func (mr *MockPermitServiceMockRecorder) ListRelationshipTuples(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRelationshipTuples", reflect.TypeOf((*MockPermitService)(nil).ListRelationshipTuples), ctx, filter)
+}
+
+// ListResourceInstances mocks base method.
+func (m *MockPermitService) ListResourceInstances(ctx context.Context, tenant, resource string) ([]permit.ResourceInstance, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceInstances", ctx, tenant, resource)
+	ret0, _ := ret[0].([]permit.ResourceInstance)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListResourceInstances indicates an expected call of ListResourceInstances.
+func (mr *MockPermitServiceMockRecorder) ListResourceInstances(ctx, tenant, resource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceInstances", reflect.TypeOf((*MockPermitService)(nil).ListResourceInstances), ctx, tenant, resource)
+}
+
+// ListResourceInstancesPage mocks base method.
+func (m *MockPermitService) ListResourceInstancesPage(ctx context.Context, tenant, resource string, page permit.Page) ([]permit.ResourceInstance, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceInstancesPage", ctx, tenant, resource, page)
+	ret0, _ := ret[0].([]permit.ResourceInstance)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListResourceInstancesPage indicates an expected call of ListResourceInstancesPage.
+func (mr *MockPermitServiceMockRecorder) ListResourceInstancesPage(ctx, tenant, resource, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceInstancesPage", reflect.TypeOf((*MockPermitService)(nil).ListResourceInstancesPage), ctx, tenant, resource, page)
+}
+
+// ListResourceRelations mocks base method.
+func (m *MockPermitService) ListResourceRelations(ctx context.Context, resourceKey string) ([]permit.Relation, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceRelations", ctx, resourceKey)
+	ret0, _ := ret[0].([]permit.Relation)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListResourceRelations indicates an expected call of ListResourceRelations.
+func (mr *MockPermitServiceMockRecorder) ListResourceRelations(ctx, resourceKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceRelations", reflect.TypeOf((*MockPermitService)(nil).ListResourceRelations), ctx, resourceKey)
+}
+
+// ListResources mocks base method.
+func (m *MockPermitService) ListResources(ctx context.Context) ([]permit.Resource, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResources", ctx)
+	ret0, _ := ret[0].([]permit.Resource)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListResources indicates an expected call of ListResources.
+func (mr *MockPermitServiceMockRecorder) ListResources(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResources", reflect.TypeOf((*MockPermitService)(nil).ListResources), ctx)
+}
+
+// ListRoleAssignments mocks base method.
+func (m *MockPermitService) ListRoleAssignments(ctx context.Context, filter permit.RoleAssignmentFilter) ([]permit.RoleAssignment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoleAssignments", ctx, filter)
+	ret0, _ := ret[0].([]permit.RoleAssignment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRoleAssignments indicates an expected call of ListRoleAssignments.
+func (mr *MockPermitServiceMockRecorder) ListRoleAssignments(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoleAssignments", reflect.TypeOf((*MockPermitService)(nil).ListRoleAssignments), ctx, filter)
+}
+
+// ListRoleAssignmentsPage mocks base method.
+func (m *MockPermitService) ListRoleAssignmentsPage(ctx context.Context, filter permit.RoleAssignmentFilter, page permit.Page) ([]permit.RoleAssignment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoleAssignmentsPage", ctx, filter, page)
+	ret0, _ := ret[0].([]permit.RoleAssignment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRoleAssignmentsPage indicates an expected call of ListRoleAssignmentsPage.
+func (mr *MockPermitServiceMockRecorder) ListRoleAssignmentsPage(ctx, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoleAssignmentsPage", reflect.TypeOf((*MockPermitService)(nil).ListRoleAssignmentsPage), ctx, filter, page)
+}
+
+// ListTenants mocks base method.
+func (m *MockPermitService) ListTenants(ctx context.Context) ([]permit.Tenant, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTenants", ctx)
+	ret0, _ := ret[0].([]permit.Tenant)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTenants indicates an expected call of ListTenants.
+func (mr *MockPermitServiceMockRecorder) ListTenants(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTenants", reflect.TypeOf((*MockPermitService)(nil).ListTenants), ctx)
+}
+
+// ListTenantsPage mocks base method.
+func (m *MockPermitService) ListTenantsPage(ctx context.Context, page permit.Page) ([]permit.Tenant, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTenantsPage", ctx, page)
+	ret0, _ := ret[0].([]permit.Tenant)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTenantsPage indicates an expected call of ListTenantsPage.
+func (mr *MockPermitServiceMockRecorder) ListTenantsPage(ctx, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTenantsPage", reflect.TypeOf((*MockPermitService)(nil).ListTenantsPage), ctx, page)
+}
+
+// SearchTenants mocks base method.
+func (m *MockPermitService) SearchTenants(ctx context.Context, search string) ([]permit.Tenant, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTenants", ctx, search)
+	ret0, _ := ret[0].([]permit.Tenant)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchTenants indicates an expected call of SearchTenants.
+func (mr *MockPermitServiceMockRecorder) SearchTenants(ctx, search interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTenants", reflect.TypeOf((*MockPermitService)(nil).SearchTenants), ctx, search)
+}
+
+// UnassignRole mocks base method.
+func (m *MockPermitService) UnassignRole(ctx context.Context, input permit.RoleAssignmentCreate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignRole", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignRole indicates an expected call of UnassignRole.
+func (mr *MockPermitServiceMockRecorder) UnassignRole(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignRole", reflect.TypeOf((*MockPermitService)(nil).UnassignRole), ctx, input)
+}
+
+// UpdateResource mocks base method.
+func (m *MockPermitService) UpdateResource(ctx context.Context, key string, input permit.ResourceUpdate) (*permit.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResource", ctx, key, input)
+	ret0, _ := ret[0].(*permit.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateResource indicates an expected call of UpdateResource.
+func (mr *MockPermitServiceMockRecorder) UpdateResource(ctx, key, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResource", reflect.TypeOf((*MockPermitService)(nil).UpdateResource), ctx, key, input)
+}
+
+// UpdateResourceInstance mocks base method.
+func (m *MockPermitService) UpdateResourceInstance(ctx context.Context, key string, input permit.ResourceInstanceUpdate) (*permit.ResourceInstance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResourceInstance", ctx, key, input)
+	ret0, _ := ret[0].(*permit.ResourceInstance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateResourceInstance indicates an expected call of UpdateResourceInstance.
+func (mr *MockPermitServiceMockRecorder) UpdateResourceInstance(ctx, key, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResourceInstance", reflect.TypeOf((*MockPermitService)(nil).UpdateResourceInstance), ctx, key, input)
+}
+
+// UpdateResourceRole mocks base method.
+func (m *MockPermitService) UpdateResourceRole(ctx context.Context, resourceKey, roleKey string, input permit.RoleUpdate) (*permit.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResourceRole", ctx, resourceKey, roleKey, input)
+	ret0, _ := ret[0].(*permit.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateResourceRole indicates an expected call of UpdateResourceRole.
+func (mr *MockPermitServiceMockRecorder) UpdateResourceRole(ctx, resourceKey, roleKey, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResourceRole", reflect.TypeOf((*MockPermitService)(nil).UpdateResourceRole), ctx, resourceKey, roleKey, input)
+}
+
+// UpdateTenant mocks base method.
+func (m *MockPermitService) UpdateTenant(ctx context.Context, key string, input permit.TenantUpdate) (*permit.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTenant", ctx, key, input)
+	ret0, _ := ret[0].(*permit.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTenant indicates an expected call of UpdateTenant.
+func (mr *MockPermitServiceMockRecorder) UpdateTenant(ctx, key, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenant", reflect.TypeOf((*MockPermitService)(nil).UpdateTenant), ctx, key, input)
 }
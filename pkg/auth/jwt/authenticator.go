@@ -186,9 +186,23 @@ func (a *Authenticator) ValidateToken(tokenString string) (jwt.MapClaims, error)
 	return nil, errors.New("no valid keys found in JWKS")
 }
 
+// Authenticate validates the token of authorization, a value of the
+// authorization header such as "Bearer <token>", and returns its claims
+func (a *Authenticator) Authenticate(authorization string) (jwt.MapClaims, error) {
+	tokenString, err := a.extractToken(authorization)
+	if err != nil {
+		return nil, err
+	}
+	return a.ValidateToken(tokenString)
+}
+
 // extractTokenFromHeader extracts the token from the authorization header
 func (a *Authenticator) extractTokenFromHeader(r *http.Request) (string, error) {
-	authHeader := r.Header.Get(a.tokenHeaderName)
+	return a.extractToken(r.Header.Get(a.tokenHeaderName))
+}
+
+// extractToken extracts the token from a value of the authorization header
+func (a *Authenticator) extractToken(authHeader string) (string, error) {
 	if authHeader == "" {
 		return "", errors.New("authorization header is missing")
 	}
@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 // GinMiddleware returns a Gin middleware function that validates JWT tokens
@@ -21,20 +22,24 @@ func (a *Authenticator) GinMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Set user identity in context
-		if userID, ok := claims[a.userClaimField]; ok {
-			c.Set("userID", userID)
-		}
-
 		// This assumes that the tenant ID is passed in the header "X-Tenant-ID"
-		tenantID := c.GetHeader("X-Tenant-ID")
+		a.SetIdentity(c, claims, c.GetHeader("X-Tenant-ID"))
+		c.Next()
+	}
+}
 
-		if tenantID != "" {
-			c.Set("tenantID", tenantID)
-		}
+// SetIdentity sets the user of the validated claims, the tenant, when not
+// empty, and the claims in the Gin context
+func (a *Authenticator) SetIdentity(c *gin.Context, claims jwt.MapClaims, tenantID string) {
+	// Set user identity in context
+	if userID, ok := claims[a.userClaimField]; ok {
+		c.Set("userID", userID)
+	}
 
-		// Store all claims in context for additional use if needed
-		c.Set("claims", claims)
-		c.Next()
+	if tenantID != "" {
+		c.Set("tenantID", tenantID)
 	}
+
+	// Store all claims in context for additional use if needed
+	c.Set("claims", claims)
 }
@@ -0,0 +1,86 @@
+// Package jwttest provides an OpenID provider for tests: it serves the OpenID
+// configuration and signing keys an Authenticator fetches, and signs tokens
+// with its key.
+package jwttest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"iam_services_main_v1/pkg/auth/jwt"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Server is an OpenID provider serving one signing key.
+type Server struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer() *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "jwttest"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+
+	s := &Server{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwt.OpenIDConfig{JWKSURI: s.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]interface{}{{"kid": "jwttest", "x5c": []string{base64.StdEncoding.EncodeToString(cert)}}},
+		})
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Authenticator returns an Authenticator trusting the tokens of s and reading
+// the user from the "sub" claim.
+func (s *Server) Authenticator() *jwt.Authenticator {
+	options := jwt.DefaultOptions()
+	options.OpenIDConfigURL = s.URL + "/.well-known/openid-configuration"
+	options.UserClaimField = "sub"
+	options.HTTPClient = s.Client()
+	authenticator, err := jwt.NewAuthenticator(options)
+	if err != nil {
+		panic(err)
+	}
+	return authenticator
+}
+
+// Token returns a token of user signed by s, valid for an hour.
+func (s *Server) Token(user string) string {
+	token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, gojwt.MapClaims{
+		"sub": user,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "jwttest"
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
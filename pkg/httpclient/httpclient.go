@@ -0,0 +1,188 @@
+// Package httpclient builds the HTTP clients used for outbound calls, with a
+// configurable CA bundle, optional client certificate for mTLS and minimum TLS
+// version. Certificate files are reloaded when they change on disk.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"iam_services_main_v1/pkg/logger"
+)
+
+// Options configures the TLS settings of outbound connections.
+type Options struct {
+	// CAFile is a PEM bundle of certificate authorities trusted in addition to
+	// the system roots.
+	CAFile string
+	// CertFile and KeyFile hold the PEM client certificate and key presented
+	// for mTLS. Both or neither must be set.
+	CertFile string
+	KeyFile  string
+	// MinVersion is the lowest TLS version accepted. Zero means TLS 1.2.
+	MinVersion uint16
+	// ReloadInterval bounds how often the certificate files are checked for
+	// changes. Values of zero or less disable reloading.
+	ReloadInterval time.Duration
+}
+
+// DefaultReloadInterval is the ReloadInterval used when none is configured.
+const DefaultReloadInterval = time.Minute
+
+// Factory creates HTTP clients that share one transport. The transport is
+// rebuilt when the configured certificate files change, so every client picks
+// up rotated certificates without a restart.
+type Factory struct {
+	options   Options
+	transport atomic.Pointer[http.Transport]
+
+	mu        sync.Mutex
+	modTimes  map[string]time.Time
+	checkedAt time.Time
+}
+
+// NewFactory loads the certificate files in options and returns a Factory.
+func NewFactory(options Options) (*Factory, error) {
+	if (options.CertFile == "") != (options.KeyFile == "") {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+	if options.MinVersion == 0 {
+		options.MinVersion = tls.VersionTLS12
+	}
+
+	f := &Factory{options: options}
+	transport, modTimes, err := f.buildTransport()
+	if err != nil {
+		return nil, err
+	}
+	f.transport.Store(transport)
+	f.modTimes = modTimes
+	f.checkedAt = time.Now()
+	return f, nil
+}
+
+// Client returns an HTTP client with the given timeout that uses the shared transport.
+func (f *Factory) Client(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: f, Timeout: timeout}
+}
+
+// RoundTrip sends req with the current transport after reloading the
+// certificate files if they changed.
+func (f *Factory) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.reloadIfChanged()
+	return f.transport.Load().RoundTrip(req)
+}
+
+// reloadIfChanged rebuilds the transport when a certificate file changed since
+// the last check. A failed reload is logged and the previous transport is kept.
+func (f *Factory) reloadIfChanged() {
+	if f.options.ReloadInterval <= 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if time.Since(f.checkedAt) < f.options.ReloadInterval {
+		return
+	}
+	f.checkedAt = time.Now()
+
+	changed := false
+	for path, modTime := range f.modTimes {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Equal(modTime) {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return
+	}
+
+	transport, modTimes, err := f.buildTransport()
+	if err != nil {
+		logger.LogError("Failed to reload TLS certificates, keeping the previous ones", "error", err)
+		return
+	}
+	previous := f.transport.Swap(transport)
+	f.modTimes = modTimes
+	previous.CloseIdleConnections()
+	logger.LogInfo("Reloaded TLS certificates for outbound connections")
+}
+
+// buildTransport reads the certificate files and returns a transport that uses
+// them, together with the modification times of the files read.
+func (f *Factory) buildTransport() (*http.Transport, map[string]time.Time, error) {
+	modTimes := map[string]time.Time{}
+	read := func(path string) ([]byte, error) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[path] = info.ModTime()
+		return os.ReadFile(path)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: f.options.MinVersion}
+
+	if f.options.CAFile != "" {
+		pem, err := read(f.options.CAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("no certificates found in CA bundle %s", f.options.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if f.options.CertFile != "" {
+		certPEM, err := read(f.options.CertFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read client certificate: %w", err)
+		}
+		keyPEM, err := read(f.options.KeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read client key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, modTimes, nil
+}
+
+// ParseTLSVersion converts "1.0", "1.1", "1.2" or "1.3" to the matching
+// crypto/tls version. An empty string returns zero.
+func ParseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "":
+		return 0, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q", version)
+	}
+}
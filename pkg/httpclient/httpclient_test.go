@@ -0,0 +1,257 @@
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCA issues certificates for the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key signed by the CA.
+func (ca *testCA) issue(t *testing.T, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// newTLSServer starts a server with a certificate from ca. When clientCA is
+// set the server requires a client certificate issued by it.
+func newTLSServer(t *testing.T, ca, clientCA *testCA, maxVersion uint16) *httptest.Server {
+	t.Helper()
+	certPEM, keyPEM := ca.issue(t, x509.ExtKeyUsageServerAuth)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}, MaxVersion: maxVersion}
+	if clientCA != nil {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCA.cert)
+		server.TLS.ClientCAs = pool
+		server.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func get(factory *Factory, url string) error {
+	resp, err := factory.Client(5 * time.Second).Get(url)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func TestFactory(t *testing.T) {
+	dir := t.TempDir()
+	serverCA := newTestCA(t)
+	caFile := writeFile(t, dir, "ca.pem", serverCA.pem)
+
+	t.Run("Servers signed by the CA bundle are trusted", func(t *testing.T) {
+		server := newTLSServer(t, serverCA, nil, 0)
+
+		untrusting, err := NewFactory(Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Error(t, get(untrusting, server.URL))
+
+		trusting, err := NewFactory(Options{CAFile: caFile})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NoError(t, get(trusting, server.URL))
+	})
+
+	t.Run("Client certificate is presented for mTLS", func(t *testing.T) {
+		clientCA := newTestCA(t)
+		server := newTLSServer(t, serverCA, clientCA, 0)
+		certPEM, keyPEM := clientCA.issue(t, x509.ExtKeyUsageClientAuth)
+
+		withoutCert, err := NewFactory(Options{CAFile: caFile})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Error(t, get(withoutCert, server.URL))
+
+		withCert, err := NewFactory(Options{
+			CAFile:   caFile,
+			CertFile: writeFile(t, dir, "client.pem", certPEM),
+			KeyFile:  writeFile(t, dir, "client-key.pem", keyPEM),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NoError(t, get(withCert, server.URL))
+	})
+
+	t.Run("Minimum TLS version is enforced", func(t *testing.T) {
+		server := newTLSServer(t, serverCA, nil, tls.VersionTLS12)
+
+		factory, err := NewFactory(Options{CAFile: caFile, MinVersion: tls.VersionTLS13})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Error(t, get(factory, server.URL))
+	})
+
+	t.Run("Rotated CA bundle is picked up", func(t *testing.T) {
+		server := newTLSServer(t, serverCA, nil, 0)
+		rotating := writeFile(t, dir, "rotating.pem", newTestCA(t).pem)
+
+		factory, err := NewFactory(Options{CAFile: rotating, ReloadInterval: time.Nanosecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Error(t, get(factory, server.URL))
+
+		writeFile(t, dir, "rotating.pem", serverCA.pem)
+		later := time.Now().Add(time.Minute)
+		assert.NoError(t, os.Chtimes(rotating, later, later))
+		assert.NoError(t, get(factory, server.URL))
+	})
+
+	t.Run("Broken rotation keeps the previous certificates", func(t *testing.T) {
+		server := newTLSServer(t, serverCA, nil, 0)
+		rotating := writeFile(t, dir, "broken.pem", serverCA.pem)
+
+		factory, err := NewFactory(Options{CAFile: rotating, ReloadInterval: time.Nanosecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		writeFile(t, dir, "broken.pem", []byte("not a certificate"))
+		later := time.Now().Add(time.Minute)
+		assert.NoError(t, os.Chtimes(rotating, later, later))
+		assert.NoError(t, get(factory, server.URL))
+	})
+}
+
+func TestNewFactoryErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		options Options
+		err     string
+	}{
+		{name: "Certificate without key", options: Options{CertFile: "client.pem"}, err: "client certificate and key must be set together"},
+		{name: "Missing CA bundle", options: Options{CAFile: filepath.Join(dir, "missing.pem")}, err: "read CA bundle"},
+		{name: "Empty CA bundle", options: Options{CAFile: writeFile(t, dir, "empty.pem", []byte("nothing here"))}, err: "no certificates found in CA bundle"},
+		{name: "Invalid client certificate", options: Options{
+			CertFile: writeFile(t, dir, "cert.pem", []byte("bad")),
+			KeyFile:  writeFile(t, dir, "key.pem", []byte("bad")),
+		}, err: "load client certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFactory(tt.options)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected uint16
+		wantErr  bool
+	}{
+		{input: "", expected: 0},
+		{input: "1.2", expected: tls.VersionTLS12},
+		{input: "1.3", expected: tls.VersionTLS13},
+		{input: "TLS1.3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			version, err := ParseTLSVersion(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, version)
+		})
+	}
+}